@@ -4,9 +4,19 @@ import (
 	"os"
 
 	"github.com/gshaibi/kubectl-create-resource/pkg/cmd"
+	buildversion "github.com/gshaibi/kubectl-create-resource/pkg/version"
+)
+
+// Build information, set with -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+// (the variables goreleaser sets by default)
+var (
+	version string
+	commit  string
+	date    string
 )
 
 func main() {
+	buildversion.Set(version, commit, date)
 	if err := cmd.Execute(); err != nil {
 		os.Exit(1)
 	}
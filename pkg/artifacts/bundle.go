@@ -0,0 +1,161 @@
+package artifacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Bundle writes the artifacts of a single run into a directory. All methods are
+// no-ops on a nil Bundle, so callers don't need to check whether --artifacts-dir was set.
+type Bundle struct {
+	dir      string
+	warnings []string
+	errors   []string
+}
+
+// New creates the artifacts directory and returns a Bundle writing into it.
+// An empty dir returns a nil Bundle.
+func New(dir string) (*Bundle, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+	return &Bundle{dir: dir}, nil
+}
+
+// Dir returns the artifacts directory
+func (b *Bundle) Dir() string {
+	if b == nil {
+		return ""
+	}
+	return b.dir
+}
+
+// WriteManifest records the generated manifest (manifest.yaml)
+func (b *Bundle) WriteManifest(obj *unstructured.Unstructured) {
+	b.writeObject("manifest.yaml", obj)
+}
+
+// WriteManifests records several generated manifests as one multi-document file
+func (b *Bundle) WriteManifests(objs []*unstructured.Unstructured) {
+	if b == nil {
+		return
+	}
+	var docs []string
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			b.Warn(fmt.Sprintf("failed to record manifest: %v", err))
+			return
+		}
+		docs = append(docs, string(data))
+	}
+	b.writeFile("manifest.yaml", []byte(strings.Join(docs, "---\n")))
+}
+
+// WriteValues records the collected field values (values.yaml)
+func (b *Bundle) WriteValues(values map[string]interface{}) {
+	b.writeYAML("values.yaml", values)
+}
+
+// WriteAnswers records the values entered at interactive prompts (answers.yaml)
+func (b *Bundle) WriteAnswers(answers map[string]interface{}) {
+	if len(answers) == 0 {
+		return
+	}
+	b.writeYAML("answers.yaml", answers)
+}
+
+// WritePreflight records the server-side dry-run result (preflight.yaml)
+func (b *Bundle) WritePreflight(obj *unstructured.Unstructured, err error) {
+	if err != nil {
+		b.writeFile("preflight-error.txt", []byte(err.Error()+"\n"))
+		return
+	}
+	b.writeObject("preflight.yaml", obj)
+}
+
+// WriteResponse records the object returned by the server on create (response.yaml)
+func (b *Bundle) WriteResponse(obj *unstructured.Unstructured) {
+	b.writeObject("response.yaml", obj)
+}
+
+// Warn records a warning for the summary
+func (b *Bundle) Warn(msg string) {
+	if b == nil {
+		return
+	}
+	b.warnings = append(b.warnings, msg)
+}
+
+// Fail records the error that ended the run
+func (b *Bundle) Fail(err error) {
+	if b == nil || err == nil {
+		return
+	}
+	b.errors = append(b.errors, err.Error())
+}
+
+// Close writes the warnings summary (warnings.txt) including apiWarnings received from the server
+func (b *Bundle) Close(apiWarnings []string) {
+	if b == nil {
+		return
+	}
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("# Run finished at %s", time.Now().Format(time.RFC3339)))
+	for _, w := range b.warnings {
+		lines = append(lines, "warning: "+w)
+	}
+	for _, w := range apiWarnings {
+		lines = append(lines, "server warning: "+w)
+	}
+	for _, e := range b.errors {
+		lines = append(lines, "error: "+e)
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "no warnings")
+	}
+	b.writeFile("warnings.txt", []byte(strings.Join(lines, "\n")+"\n"))
+
+	fmt.Fprintf(os.Stderr, "Artifacts written to %s\n", b.dir)
+}
+
+// writeObject writes an unstructured object as YAML
+func (b *Bundle) writeObject(name string, obj *unstructured.Unstructured) {
+	if b == nil || obj == nil {
+		return
+	}
+	b.writeYAML(name, obj.Object)
+}
+
+// writeYAML marshals v as YAML into the named file
+func (b *Bundle) writeYAML(name string, v interface{}) {
+	if b == nil {
+		return
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		b.Warn(fmt.Sprintf("failed to record %s: %v", name, err))
+		return
+	}
+	b.writeFile(name, data)
+}
+
+// writeFile writes data into the named file, recording failures as warnings
+func (b *Bundle) writeFile(name string, data []byte) {
+	if b == nil {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(b.dir, name), data, 0o600); err != nil {
+		b.warnings = append(b.warnings, fmt.Sprintf("failed to write %s: %v", name, err))
+	}
+}
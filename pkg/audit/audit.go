@@ -0,0 +1,158 @@
+// Package audit keeps an append-only log of the creates, dry runs and deletes
+// made with kubectl-create-resource, for teams that need to trace manual changes.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/config"
+)
+
+// Operations recorded in the audit log
+const (
+	OperationCreate       = "create"
+	OperationDryRun       = "dry-run"
+	OperationServerDryRun = "server-dry-run"
+	OperationDelete       = "delete"
+)
+
+// Results of an operation
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Record is one audited operation
+type Record struct {
+	Time         time.Time `json:"time"`
+	User         string    `json:"user,omitempty"`         // Local user running the command
+	Impersonated string    `json:"impersonated,omitempty"` // User impersonated with --as
+	Context      string    `json:"context,omitempty"`
+	Server       string    `json:"server,omitempty"`
+	Group        string    `json:"group,omitempty"`
+	Version      string    `json:"version"`
+	Resource     string    `json:"resource"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Name         string    `json:"name"`
+	Operation    string    `json:"operation"`
+	Result       string    `json:"result"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// DefaultPath returns the audit log location
+func DefaultPath() string {
+	return filepath.Join(config.Dir(), "audit.jsonl")
+}
+
+// Append adds r to the audit log, filling in the time and local user
+func Append(path string, r Record) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	if r.User == "" {
+		if u, err := user.Current(); err == nil {
+			r.User = u.Username
+		}
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Tail returns the last n records of the audit log, oldest first, and the
+// offset the log was read up to. A missing log yields no records.
+func Tail(path string, n int) ([]Record, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer f.Close()
+
+	records, offset, err := readRecords(f, 0)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	if len(records) > n {
+		records = records[len(records)-n:]
+	}
+	return records, offset, nil
+}
+
+// Follow calls fn for each record appended to the audit log after offset,
+// checking for new records every interval. It only returns on errors.
+func Follow(path string, offset int64, interval time.Duration, fn func(Record)) error {
+	for {
+		f, err := os.Open(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+		if err == nil {
+			var records []Record
+			records, offset, err = readRecords(f, offset)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("failed to read audit log %s: %w", path, err)
+			}
+			for _, r := range records {
+				fn(r)
+			}
+		}
+		time.Sleep(interval)
+	}
+}
+
+// readRecords reads the complete lines of f from offset, returning the records
+// and the offset after the last complete line
+func readRecords(f *os.File, offset int64) ([]Record, int64, error) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	var records []Record
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// A partial line is still being written, read it next time
+			return records, offset, nil
+		}
+		if err != nil {
+			return nil, offset, err
+		}
+		offset += int64(len(line))
+		if len(line) == 1 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, offset, err
+		}
+		records = append(records, r)
+	}
+}
@@ -0,0 +1,155 @@
+// Package batch runs many API operations with a bounded worker pool, a shared
+// rate limit and retries of transient errors.
+package batch
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// maxBackoff caps the delay between retries
+const maxBackoff = 30 * time.Second
+
+// Options configures Run
+type Options struct {
+	Parallelism int           // Concurrent workers; values below 1 mean 1
+	Rate        float64       // Attempts started per second across all workers; 0 is unlimited
+	Retries     int           // Retries of an item after transient errors
+	Backoff     time.Duration // Delay before the first retry, doubled for each further one
+	StopOnError bool          // Skip the items not started yet once one failed
+
+	// Done, if set, is called as each attempted item finishes, from its worker
+	Done func(Result)
+}
+
+// Result is the outcome of one item
+type Result struct {
+	Index    int
+	Err      error
+	Attempts int
+	Skipped  bool // Not attempted because an earlier item failed (StopOnError)
+	Duration time.Duration
+}
+
+// Run calls do for the items 0..n-1 and returns their results in item order.
+// do is called concurrently and must be safe for that.
+func Run(n int, opts Options, do func(i int) error) []Result {
+	workers := opts.Parallelism
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if opts.Rate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(opts.Rate), 1)
+	}
+
+	results := make([]Result, n)
+	items := make(chan int)
+	var failed sync.Once
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				results[i] = runItem(i, opts, limiter, do)
+				if opts.Done != nil {
+					opts.Done(results[i])
+				}
+				if results[i].Err != nil && opts.StopOnError {
+					failed.Do(func() { close(stop) })
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		if stopped(stop) {
+			skipFrom(results, i)
+			break
+		}
+		select {
+		case items <- i:
+		case <-stop:
+			skipFrom(results, i)
+		}
+	}
+	close(items)
+	wg.Wait()
+	return results
+}
+
+// stopped reports whether stop is closed
+func stopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// skipFrom marks the items from i on as skipped
+func skipFrom(results []Result, i int) {
+	for ; i < len(results); i++ {
+		results[i] = Result{Index: i, Skipped: true}
+	}
+}
+
+// runItem calls do for item i until it succeeds, fails permanently or runs out
+// of retries
+func runItem(i int, opts Options, limiter *rate.Limiter, do func(i int) error) Result {
+	start := time.Now()
+	result := Result{Index: i}
+	for {
+		limiter.Wait(context.Background())
+		result.Attempts++
+		result.Err = do(i)
+		if result.Err == nil || !IsTransient(result.Err) || result.Attempts > opts.Retries {
+			break
+		}
+		time.Sleep(retryDelay(result.Err, opts.Backoff, result.Attempts))
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// retryDelay returns the delay before retry number attempt: exponential with
+// jitter, or longer if the server asked for it (e.g., Retry-After on a 429)
+func retryDelay(err error, backoff time.Duration, attempt int) time.Duration {
+	delay := backoff << (attempt - 1)
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	// Up to 20% jitter so workers throttled together don't retry together
+	delay += time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+		if suggested := time.Duration(seconds) * time.Second; suggested > delay {
+			delay = suggested
+		}
+	}
+	return delay
+}
+
+// IsTransient reports whether err is worth retrying: throttling, server
+// timeouts, an unavailable server, or a network timeout
+func IsTransient(err error) bool {
+	if apierrors.IsTooManyRequests(err) || apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) || apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
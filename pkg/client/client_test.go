@@ -0,0 +1,285 @@
+package client_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client/clienttest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestResolveResourceType(t *testing.T) {
+	resources := append(clienttest.DefaultResources(),
+		&metav1.APIResourceList{
+			GroupVersion: "batch/v1beta1",
+			APIResources: []metav1.APIResource{clienttest.Resource("cronjobs", "CronJob", true, "cj")},
+		},
+		&metav1.APIResourceList{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{clienttest.Resource("widgets", "Widget", true, "wd")},
+		},
+	)
+	c := clienttest.NewClient(clienttest.Options{Resources: resources})
+
+	tests := []struct {
+		resourceType string
+		want         schema.GroupVersionResource
+		wantErr      string
+	}{
+		{resourceType: "pods", want: schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+		{resourceType: "pod", want: schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+		{resourceType: "Pod", want: schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+		{resourceType: "po", want: schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+		{resourceType: "deploy", want: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+		{resourceType: "deployments.apps", want: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+		{resourceType: "wd", want: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}},
+		{resourceType: "widgets.example.com", want: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}},
+		{resourceType: "pods.v1", want: schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+		{resourceType: "cronjobs.v1beta1.batch", want: schema.GroupVersionResource{Group: "batch", Version: "v1beta1", Resource: "cronjobs"}},
+		{resourceType: "cronjobs.v2.batch", wantErr: "version v2 of cronjobs.batch is not served"},
+		{resourceType: "depoyment", wantErr: "did you mean deployments.apps?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			got, err := c.ResolveResourceType(tt.resourceType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveResourceType(%q) error = %v, want %q", tt.resourceType, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveResourceType(%q) error = %v", tt.resourceType, err)
+			}
+			if got.Group != tt.want.Group || got.Resource != tt.want.Resource {
+				t.Errorf("ResolveResourceType(%q) = %v, want %v", tt.resourceType, got, tt.want)
+			}
+			// The fake serves groups in no particular order, so the preferred version isn't fixed
+			if tt.want.Version != "" && strings.Contains(tt.resourceType, "."+tt.want.Version) && got.Version != tt.want.Version {
+				t.Errorf("ResolveResourceType(%q) version = %s, want %s", tt.resourceType, got.Version, tt.want.Version)
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	c, fakes := clienttest.NewClientWithFakes(clienttest.Options{})
+	if _, err := c.ResolveResourceType("widgets"); err == nil {
+		t.Fatal("ResolveResourceType(widgets) succeeded before the CRD was installed")
+	}
+
+	// Discovery is cached until refreshed
+	fakes.Discovery.Resources = append(fakes.Discovery.Resources, &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{clienttest.Resource("widgets", "Widget", true, "wd")},
+	})
+	if _, err := c.ResolveResourceType("widgets"); err == nil {
+		t.Fatal("ResolveResourceType(widgets) succeeded without a refresh")
+	}
+	c.Refresh()
+	want := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	for _, name := range []string{"widgets", "wd"} {
+		got, err := c.ResolveResourceType(name)
+		if err != nil {
+			t.Fatalf("ResolveResourceType(%s) after Refresh: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("ResolveResourceType(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestRequestedVersion(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		version      string
+		rest         string
+	}{
+		{"deployments", "", "deployments"},
+		{"deployments.apps", "", "deployments.apps"},
+		{"ingresses.networking.k8s.io", "", "ingresses.networking.k8s.io"},
+		{"deployments.v1.apps", "v1", "deployments.apps"},
+		{"cronjobs.v1beta1.batch", "v1beta1", "cronjobs.batch"},
+		{"widgets.v1alpha2.example.com", "v1alpha2", "widgets.example.com"},
+		{"pods.v1", "v1", "pods"},
+	}
+	for _, tt := range tests {
+		version, rest := client.RequestedVersion(tt.resourceType)
+		if version != tt.version || rest != tt.rest {
+			t.Errorf("RequestedVersion(%q) = %q, %q, want %q, %q", tt.resourceType, version, rest, tt.version, tt.rest)
+		}
+	}
+}
+
+func TestIsNamespaced(t *testing.T) {
+	c := clienttest.NewClient(clienttest.Options{})
+	if !c.IsNamespaced(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}) {
+		t.Error("configmaps should be namespaced")
+	}
+	if c.IsNamespaced(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}) {
+		t.Error("namespaces should be cluster-scoped")
+	}
+}
+
+func TestDiscoverAuthorizableResources(t *testing.T) {
+	resources := append(clienttest.DefaultResources(), &metav1.APIResourceList{
+		GroupVersion: "metrics.k8s.io/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "pods", Kind: "PodMetrics", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	})
+	resources[0].APIResources = append(resources[0].APIResources,
+		metav1.APIResource{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"get"}})
+	c := clienttest.NewClient(clienttest.Options{Resources: resources})
+
+	all, err := c.DiscoverAuthorizableResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, r := range all {
+		found[r.Name+"."+r.Group] = true
+	}
+	// Resources that can't be created and subresources are named by rules too
+	for _, want := range []string{"pods.", "pods/log.", "pods.metrics.k8s.io", "deployments.apps"} {
+		if !found[want] {
+			t.Errorf("%s is missing from %v", want, all)
+		}
+	}
+}
+
+func TestGetResourceSchema(t *testing.T) {
+	c := clienttest.NewClient(clienttest.Options{})
+
+	deployments, err := c.GetResourceSchema(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deployments.GVK.Kind != "Deployment" {
+		t.Errorf("kind = %q, want Deployment", deployments.GVK.Kind)
+	}
+	replicas, ok := deployments.FindField("spec.replicas")
+	if !ok || replicas.Type != "integer" || replicas.Format != "int32" {
+		t.Errorf("spec.replicas = %+v, want an int32 integer", replicas)
+	}
+	image, ok := deployments.FindField("spec.template.spec.containers[0].image")
+	if !ok || image.Type != "string" {
+		t.Errorf("container image = %+v, want a string", image)
+	}
+	selector, ok := deployments.FindField("spec.selector")
+	if !ok || !selector.Required {
+		t.Errorf("spec.selector = %+v, want it required", selector)
+	}
+
+	secrets, err := c.GetResourceSchema(schema.GroupVersionResource{Version: "v1", Resource: "secrets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keys of maps resolve to the schema of their values
+	password, ok := secrets.FindField("data.password")
+	if !ok || password.Format != "byte" {
+		t.Errorf("data.password = %+v, want a byte string", password)
+	}
+}
+
+func TestSearchFields(t *testing.T) {
+	c := clienttest.NewClient(clienttest.Options{})
+
+	deployments, err := c.GetResourceSchema(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := deployments.SearchFields("Image Pull Secret")
+	if len(found) == 0 || found[0].Path != "spec.template.spec.imagePullSecrets" {
+		t.Fatalf("SearchFields(Image Pull Secret) = %v, want spec.template.spec.imagePullSecrets first", found)
+	}
+	for _, f := range deployments.SearchFields("replicas") {
+		if f.Path == "spec.replicas" {
+			return
+		}
+	}
+	t.Errorf("SearchFields(replicas) is missing spec.replicas")
+}
+
+func TestCreateResource(t *testing.T) {
+	existing := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "existing", "namespace": "default"},
+	}}
+	c, fakes := clienttest.NewClientWithFakes(clienttest.Options{Objects: []*unstructured.Unstructured{existing}})
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	names, err := c.ListNames(configMaps, "default")
+	if err != nil || len(names) != 1 || names[0] != "existing" {
+		t.Fatalf("ListNames() = %v, %v, want [existing]", names, err)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "settings", "namespace": "default"},
+		"data":       map[string]interface{}{"mode": "fast"},
+	}}
+	if _, err := c.CreateResource(configMaps, "default", obj); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetResource(configMaps, "default", "settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode, _, _ := unstructured.NestedString(got.Object, "data", "mode"); mode != "fast" {
+		t.Errorf("data.mode = %q, want fast", mode)
+	}
+
+	var creates int
+	for _, action := range fakes.Dynamic.Actions() {
+		if action.GetVerb() == "create" {
+			creates++
+		}
+	}
+	if creates != 1 {
+		t.Errorf("%d creates, want 1", creates)
+	}
+
+	if _, err := c.CreateResource(configMaps, "default", obj); err == nil {
+		t.Error("creating the same object twice should fail")
+	}
+}
+
+func TestContextNamespace(t *testing.T) {
+	kubeconfig := t.TempDir() + "/config"
+	err := writeFile(kubeconfig, `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster: {server: https://127.0.0.1:6443}
+users:
+- name: test
+  user: {token: abc}
+contexts:
+- name: with-namespace
+  context: {cluster: test, user: test, namespace: team-a}
+- name: without-namespace
+  context: {cluster: test, user: test}
+current-context: with-namespace
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		context string
+		want    string
+	}{
+		{"", "team-a"},
+		{"with-namespace", "team-a"},
+		{"without-namespace", "default"},
+	}
+	for _, tt := range tests {
+		if got := client.ContextNamespace(kubeconfig, tt.context); got != tt.want {
+			t.Errorf("ContextNamespace(%q) = %q, want %q", tt.context, got, tt.want)
+		}
+	}
+}
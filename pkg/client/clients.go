@@ -0,0 +1,41 @@
+package client
+
+import (
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/openapi"
+)
+
+// Clients are the API clients a K8sClient works through. Programs embedding
+// this package can bring their own, and tests can use fakes (see package
+// clienttest) to exercise resolution, schemas and creation without a cluster.
+type Clients struct {
+	Discovery discovery.DiscoveryInterface // Resource types; cached by the K8sClient unless already cached
+	Dynamic   dynamic.Interface            // Objects
+	OpenAPI   openapi.Client               // OpenAPI v3 schemas (Discovery.OpenAPIV3() when nil)
+}
+
+// NewK8sClientFromClients creates a client working through existing API clients
+func NewK8sClientFromClients(clients Clients) *K8sClient {
+	cachedDiscovery, ok := clients.Discovery.(discovery.CachedDiscoveryInterface)
+	if !ok {
+		cachedDiscovery = memory.NewMemCacheClient(clients.Discovery)
+	}
+	return &K8sClient{
+		dynamicClient:   clients.Dynamic,
+		discoveryClient: cachedDiscovery,
+		restMapper:      newRESTMapper(cachedDiscovery),
+		openAPIClient:   clients.OpenAPI,
+		warnings:        &warningCollector{},
+		objectCache:     make(map[string]cachedObject),
+	}
+}
+
+// openAPI returns the client serving OpenAPI v3 schemas
+func (c *K8sClient) openAPI() openapi.Client {
+	if c.openAPIClient != nil {
+		return c.openAPIClient
+	}
+	return c.discoveryClient.OpenAPIV3()
+}
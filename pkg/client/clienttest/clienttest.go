@@ -0,0 +1,133 @@
+// Package clienttest provides a client.K8sClient backed by in-memory fakes of
+// the discovery, dynamic and OpenAPI clients, for testing code built on
+// package client without a cluster.
+package clienttest
+
+import (
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/openapitest"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// Options configure the fake cluster
+type Options struct {
+	// Resources are the resource types discovery serves (DefaultResources() when nil)
+	Resources []*metav1.APIResourceList
+
+	// OpenAPI serves the OpenAPI v3 schemas. When nil, the documents of the core,
+	// apps, batch, discovery.k8s.io and networking.k8s.io (v1alpha1) groups
+	// bundled with client-go are served.
+	OpenAPI openapi.Client
+
+	// Objects already exist in the cluster
+	Objects []*unstructured.Unstructured
+}
+
+// NewClient creates a client for a fake cluster
+func NewClient(opts Options) *client.K8sClient {
+	c, _ := NewClientWithFakes(opts)
+	return c
+}
+
+// Fakes are the fake clients behind a client from NewClientWithFakes, to
+// inspect the requests made (e.g., Dynamic.Actions()) or inject errors with
+// reactors
+type Fakes struct {
+	Discovery *fakediscovery.FakeDiscovery
+	Dynamic   *fakedynamic.FakeDynamicClient
+}
+
+// NewClientWithFakes creates a client for a fake cluster and returns its fakes
+func NewClientWithFakes(opts Options) (*client.K8sClient, Fakes) {
+	resources := opts.Resources
+	if resources == nil {
+		resources = DefaultResources()
+	}
+	openAPI := opts.OpenAPI
+	if openAPI == nil {
+		openAPI = openapitest.NewEmbeddedFileClient()
+	}
+
+	// Lists need the list kind of each resource
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, list := range resources {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if !strings.Contains(r.Name, "/") {
+				listKinds[gv.WithResource(r.Name)] = r.Kind + "List"
+			}
+		}
+	}
+	objects := make([]runtime.Object, len(opts.Objects))
+	for i, obj := range opts.Objects {
+		objects[i] = obj
+	}
+
+	fakes := Fakes{
+		Discovery: &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: resources}},
+		Dynamic:   fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...),
+	}
+	c := client.NewK8sClientFromClients(client.Clients{
+		Discovery: fakes.Discovery,
+		Dynamic:   fakes.Dynamic,
+		OpenAPI:   openAPI,
+	})
+	return c, fakes
+}
+
+// Resource describes a resource type of an APIResourceList, with the verbs
+// of a type objects can be created of
+func Resource(name, kind string, namespaced bool, shortNames ...string) metav1.APIResource {
+	return metav1.APIResource{
+		Name:       name,
+		Kind:       kind,
+		Namespaced: namespaced,
+		ShortNames: shortNames,
+		Verbs:      metav1.Verbs{"create", "delete", "get", "list", "watch"},
+	}
+}
+
+// DefaultResources returns common built-in resource types, matching the
+// bundled OpenAPI documents: pods, config maps, secrets, services, service
+// accounts and namespaces in v1, deployments in apps/v1, and jobs and cron
+// jobs in batch/v1
+func DefaultResources() []*metav1.APIResourceList {
+	return []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				Resource("pods", "Pod", true, "po"),
+				Resource("configmaps", "ConfigMap", true, "cm"),
+				Resource("secrets", "Secret", true),
+				Resource("services", "Service", true, "svc"),
+				Resource("serviceaccounts", "ServiceAccount", true, "sa"),
+				Resource("namespaces", "Namespace", false, "ns"),
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				Resource("deployments", "Deployment", true, "deploy"),
+			},
+		},
+		{
+			GroupVersion: "batch/v1",
+			APIResources: []metav1.APIResource{
+				Resource("jobs", "Job", true),
+				Resource("cronjobs", "CronJob", true, "cj"),
+			},
+		},
+	}
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	// Register the auth provider plugins (e.g., OIDC) that kubeconfigs may reference
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ConnectionOptions configures how the client connects to the cluster
+type ConnectionOptions struct {
+	Kubeconfig string // Path to the kubeconfig file ("" for in-cluster or ~/.kube/config)
+	Context    string // Kubeconfig context ("" for the current context)
+
+	// Overrides of the kubeconfig cluster and credentials, like kubectl's flags
+	Server                string
+	Token                 string
+	CertificateAuthority  string
+	InsecureSkipTLSVerify bool
+
+	// Client-side rate limits; a negative QPS disables rate limiting
+	QPS   float32
+	Burst int
+
+	// Impersonation, like kubectl --as, --as-group and --as-uid
+	AsUser   string
+	AsGroups []string
+	AsUID    string
+
+	// FastDiscovery fetches the OpenAPI document of the target group version
+	// directly, without listing the paths of every group first
+	FastDiscovery bool
+}
+
+// buildConfig creates a Kubernetes rest.Config from kubeconfig and the connection options
+func buildConfig(opts ConnectionOptions) (*rest.Config, error) {
+	config, err := loadRestConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyImpersonation(config, opts); err != nil {
+		return nil, err
+	}
+	if opts.QPS != 0 {
+		config.QPS = opts.QPS
+	}
+	if opts.Burst != 0 {
+		config.Burst = opts.Burst
+	}
+	if opts.FastDiscovery {
+		// Schema documents are large JSON; make sure they come gzipped
+		config.DisableCompression = false
+	}
+	return config, nil
+}
+
+// unixSocketPath returns the socket path of a unix:// server address
+func unixSocketPath(server string) (string, bool) {
+	if !strings.HasPrefix(server, "unix://") {
+		return "", false
+	}
+	return strings.TrimPrefix(server, "unix://"), true
+}
+
+// loadRestConfig loads the rest.Config with clientcmd's full loading rules, the
+// same as kubectl: $KUBECONFIG, exec credential plugins, auth providers, and the
+// in-cluster config when no kubeconfig is found
+func loadRestConfig(opts ConnectionOptions) (*rest.Config, error) {
+	// A unix socket (e.g., from kubectl proxy --unix-socket) is reached over plain HTTP
+	server := opts.Server
+	socketPath, isSocket := unixSocketPath(server)
+	if isSocket {
+		server = "http://localhost"
+	}
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: opts.Context,
+		ClusterInfo: clientcmdapi.Cluster{
+			Server:                server,
+			CertificateAuthority:  opts.CertificateAuthority,
+			InsecureSkipTLSVerify: opts.InsecureSkipTLSVerify,
+		},
+		AuthInfo: clientcmdapi.AuthInfo{
+			Token: opts.Token,
+		},
+	}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		kubeconfigLoadingRules(opts.Kubeconfig), overrides,
+	).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if isSocket {
+		config.Dial = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socketPath)
+		}
+	}
+	return config, nil
+}
+
+// applyImpersonation sets the impersonated user, groups and UID on config
+func applyImpersonation(config *rest.Config, opts ConnectionOptions) error {
+	if opts.AsUser == "" {
+		if len(opts.AsGroups) > 0 || opts.AsUID != "" {
+			return fmt.Errorf("--as-group and --as-uid require --as")
+		}
+		return nil
+	}
+
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: opts.AsUser,
+		Groups:   opts.AsGroups,
+		UID:      opts.AsUID,
+	}
+	return nil
+}
+
+// ContextName returns the kubeconfig context the client was created for, or ""
+// for the current context
+func (c *K8sClient) ContextName() string {
+	return c.contextName
+}
+
+// Server returns the address of the API server, or "" in offline mode
+func (c *K8sClient) Server() string {
+	if c.restConfig == nil {
+		return ""
+	}
+	return c.restConfig.Host
+}
+
+// CertificateAuthority returns the PEM certificates trusted for the API server,
+// and whether the client skips verifying it. Both are empty when the system's
+// roots are used.
+func (c *K8sClient) CertificateAuthority() ([]byte, bool, error) {
+	if c.restConfig == nil {
+		return nil, false, nil
+	}
+	tls := c.restConfig.TLSClientConfig
+	if len(tls.CAData) > 0 || tls.CAFile == "" {
+		return tls.CAData, tls.Insecure, nil
+	}
+	data, err := os.ReadFile(tls.CAFile)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read the certificate authority: %w", err)
+	}
+	return data, tls.Insecure, nil
+}
+
+// Impersonating returns the impersonated user name, or "" when not impersonating
+func (c *K8sClient) Impersonating() string {
+	if c.restConfig == nil {
+		return ""
+	}
+	return c.restConfig.Impersonate.UserName
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubeconfigContexts returns the context names defined in the kubeconfig, sorted
+func KubeconfigContexts(kubeconfigPath string) ([]string, error) {
+	rawConfig, err := kubeconfigLoadingRules(kubeconfigPath).Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	var contexts []string
+	for contextName := range rawConfig.Contexts {
+		contexts = append(contexts, contextName)
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
+
+// kubeconfigLoadingRules loads kubeconfigPath, or $KUBECONFIG and ~/.kube/config
+func kubeconfigLoadingRules(kubeconfigPath string) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfigPath
+	return rules
+}
+
+// ContextNamespace returns the namespace of a kubeconfig context ("" for the
+// current context), or "default" when it doesn't set one
+func ContextNamespace(kubeconfigPath, contextName string) string {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	ns, _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		kubeconfigLoadingRules(kubeconfigPath), overrides,
+	).Namespace()
+	if err != nil || ns == "" {
+		return "default"
+	}
+	return ns
+}
+
+// CurrentContext returns the name of the kubeconfig's current context
+func CurrentContext(kubeconfigPath string) (string, error) {
+	rawConfig, err := kubeconfigLoadingRules(kubeconfigPath).Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return rawConfig.CurrentContext, nil
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// discoveryFailures holds the API group versions whose discovery failed, e.g.
+// aggregated APIs with a stale APIService or a down backend like metrics-server
+type discoveryFailures struct {
+	mu     sync.Mutex
+	groups map[schema.GroupVersion]error
+	warned bool
+}
+
+// checkDiscoveryError returns err unless it only reports failed groups, which
+// are recorded and named in a single warning so discovery can go on without them
+func (c *K8sClient) checkDiscoveryError(err error) error {
+	var failed *discovery.ErrGroupDiscoveryFailed
+	if err == nil || !errors.As(err, &failed) {
+		return err
+	}
+
+	f := &c.discoveryFailures
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.groups == nil {
+		f.groups = make(map[schema.GroupVersion]error)
+	}
+	for gv, groupErr := range failed.Groups {
+		f.groups[gv] = groupErr
+	}
+	if !f.warned {
+		f.warned = true
+		fmt.Fprintf(os.Stderr, "Warning: discovery failed for API groups %s; their resource types are unavailable\n",
+			strings.Join(describeFailures(f.groups), ", "))
+	}
+	return nil
+}
+
+// FailedGroups returns the API group versions whose discovery failed, with the error
+func (c *K8sClient) FailedGroups() []string {
+	f := &c.discoveryFailures
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return describeFailures(f.groups)
+}
+
+// groupFailed reports whether discovery failed for a version of group
+func (c *K8sClient) groupFailed(group string) bool {
+	f := &c.discoveryFailures
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for gv := range f.groups {
+		if strings.EqualFold(gv.Group, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeFailures formats failed group versions as "group/version (error)", sorted
+func describeFailures(groups map[schema.GroupVersion]error) []string {
+	described := make([]string, 0, len(groups))
+	for gv, err := range groups {
+		described = append(described, fmt.Sprintf("%s (%v)", gv, err))
+	}
+	sort.Strings(described)
+	return described
+}
+
+// notFoundError explains why no resource type matches name (in group). When
+// discovery failed for the group, the type may well exist there, so that is
+// said instead of suggesting look-alikes, which only come from the groups that
+// were discovered.
+func (c *K8sClient) notFoundError(resourceType, name, group string, resources []ResourceInfo) error {
+	if group != "" && c.groupFailed(group) {
+		return fmt.Errorf("resource type %q not found: discovery of API group %s failed", resourceType, group)
+	}
+
+	var available []ResourceInfo
+	for _, r := range resources {
+		if !c.groupFailed(r.Group) {
+			available = append(available, r)
+		}
+	}
+	if suggestions := suggestResourceTypes(name, available); len(suggestions) > 0 {
+		return fmt.Errorf("unknown resource type %q; did you mean %s?", resourceType, strings.Join(suggestions, " or "))
+	}
+	if failed := c.FailedGroups(); group == "" && len(failed) > 0 {
+		return fmt.Errorf("resource type %q not found; it may be served by an API group whose discovery failed: %s",
+			resourceType, strings.Join(failed, ", "))
+	}
+	return fmt.Errorf("resource type %q not found", resourceType)
+}
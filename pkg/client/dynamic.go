@@ -3,24 +3,67 @@ package client
 import (
 	"context"
 	"fmt"
-	"path/filepath"
+	"os"
+	"slices"
 	"strings"
+	"sync"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/openapi"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
 // K8sClient wraps the Kubernetes dynamic client and discovery client
 type K8sClient struct {
-	dynamicClient   dynamic.Interface
-	discoveryClient discovery.DiscoveryInterface
-	restConfig      *rest.Config
+	dynamicClient dynamic.Interface
+
+	// discoveryClient caches discovery for the lifetime of the client. It uses
+	// aggregated discovery (all groups and resources in one request for /api and
+	// one for /apis) when the server supports it, and per-group requests otherwise.
+	discoveryClient discovery.CachedDiscoveryInterface
+
+	// restMapper resolves resource names, kinds and short names like kubectl does
+	restMapper meta.RESTMapper
+
+	// openAPIClient serves OpenAPI v3 schemas when set, instead of discoveryClient
+	// (see NewK8sClientFromClients)
+	openAPIClient openapi.Client
+
+	restConfig  *rest.Config
+	contextName string // Kubeconfig context ("" for the current context)
+
+	// warnings collects API server warnings received during this run
+	warnings *warningCollector
+
+	// objectCache holds objects fetched during this run, keyed by objectCacheKey
+	objectCache map[string]cachedObject
+	cacheMu     sync.Mutex
+
+	// offline serves resource types and schemas from local files (nil when connected)
+	offline *offlineCatalog
+
+	// crds holds types from local CRD files, overlaid on discovery (see AddCRDs)
+	crds *offlineCatalog
+
+	// discoveryFailures holds the API groups discovery skipped
+	discoveryFailures discoveryFailures
+
+	// fastDiscovery fetches schemas by group version path (see ConnectionOptions)
+	fastDiscovery bool
+}
+
+// cachedObject is a GetResource result; NotFound errors are cached as well
+type cachedObject struct {
+	obj *unstructured.Unstructured
+	err error
 }
 
 // ResourceInfo contains information about an API resource
@@ -31,15 +74,40 @@ type ResourceInfo struct {
 	Kind       string
 	Namespaced bool
 	Verbs      []string
+	ShortNames []string
+}
+
+// SubresourceInfo contains information about a create-capable subresource (e.g., serviceaccounts/token)
+type SubresourceInfo struct {
+	Parent      schema.GroupVersionResource // The resource that owns the subresource
+	Subresource string                      // Subresource name (e.g., "token")
+	Group       string                      // API group of the subresource kind
+	Version     string                      // API version of the subresource kind
+	Kind        string                      // Kind of the request body (e.g., "TokenRequest")
+	Namespaced  bool
+}
+
+// Name returns the subresource in resource/subresource form
+func (s SubresourceInfo) Name() string {
+	return s.Parent.Resource + "/" + s.Subresource
 }
 
 // NewK8sClient creates a new Kubernetes client
 func NewK8sClient(kubeconfigPath string) (*K8sClient, error) {
-	config, err := buildConfig(kubeconfigPath)
+	return NewK8sClientWithOptions(ConnectionOptions{Kubeconfig: kubeconfigPath})
+}
+
+// NewK8sClientWithOptions creates a Kubernetes client with the given connection settings
+func NewK8sClientWithOptions(opts ConnectionOptions) (*K8sClient, error) {
+	config, err := buildConfig(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build config: %w", err)
 	}
 
+	// Collect API server warnings (deprecations, admission warnings) while still printing them
+	warnings := &warningCollector{}
+	config.WarningHandler = warnings
+
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
@@ -50,68 +118,79 @@ func NewK8sClient(kubeconfigPath string) (*K8sClient, error) {
 		return nil, fmt.Errorf("failed to create discovery client: %w", err)
 	}
 
+	cachedDiscovery := memory.NewMemCacheClient(discoveryClient)
+
 	return &K8sClient{
 		dynamicClient:   dynamicClient,
-		discoveryClient: discoveryClient,
+		discoveryClient: cachedDiscovery,
+		restMapper:      newRESTMapper(cachedDiscovery),
 		restConfig:      config,
+		contextName:     opts.Context,
+		warnings:        warnings,
+		objectCache:     make(map[string]cachedObject),
+		fastDiscovery:   opts.FastDiscovery,
 	}, nil
 }
 
-// buildConfig creates a Kubernetes rest.Config from kubeconfig
-func buildConfig(kubeconfigPath string) (*rest.Config, error) {
-	if kubeconfigPath == "" {
-		// Try in-cluster config first
-		config, err := rest.InClusterConfig()
-		if err == nil {
-			return config, nil
-		}
+// DiscoverResources returns all available API resources in the cluster
+func (c *K8sClient) DiscoverResources() ([]ResourceInfo, error) {
+	if c.offline != nil {
+		return c.offline.resources, nil
+	}
+
+	_, resourceLists, err := c.discoveryClient.ServerGroupsAndResources()
+	// Groups that fail discovery are skipped with a warning
+	if err := c.checkDiscoveryError(err); err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+
+	var resources []ResourceInfo
+	seen := make(map[string]bool)
 
-		// Fall back to default kubeconfig location
-		if home := homedir.HomeDir(); home != "" {
-			kubeconfigPath = filepath.Join(home, ".kube", "config")
+	for _, resourceList := range resourceLists {
+		for _, r := range resourcesFromList(resourceList) {
+			// Create a unique key to avoid duplicates
+			key := fmt.Sprintf("%s.%s", r.Name, r.Group)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			resources = append(resources, r)
 		}
 	}
 
-	return clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if c.crds != nil {
+		resources = overlayResources(c.crds.resources, resources)
+	}
+	return resources, nil
 }
 
-// DiscoverResources returns all available API resources in the cluster
-func (c *K8sClient) DiscoverResources() ([]ResourceInfo, error) {
+// DiscoverAuthorizableResources returns all API resources in the cluster as
+// RBAC rules name them, including those that don't support create and
+// subresources (e.g., pods/log)
+func (c *K8sClient) DiscoverAuthorizableResources() ([]ResourceInfo, error) {
+	if c.offline != nil {
+		return c.offline.resources, nil
+	}
+
 	_, resourceLists, err := c.discoveryClient.ServerGroupsAndResources()
-	if err != nil {
-		// Some resources might fail discovery but we can still proceed
-		if !discovery.IsGroupDiscoveryFailedError(err) {
-			return nil, fmt.Errorf("failed to discover resources: %w", err)
-		}
+	if err := c.checkDiscoveryError(err); err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
 	}
 
 	var resources []ResourceInfo
 	seen := make(map[string]bool)
-
 	for _, resourceList := range resourceLists {
 		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
 		if err != nil {
 			continue
 		}
-
 		for _, r := range resourceList.APIResources {
-			// Skip subresources (e.g., pods/status)
-			if strings.Contains(r.Name, "/") {
-				continue
-			}
-
-			// Skip resources that don't support create
-			if !containsVerb(r.Verbs, "create") {
-				continue
-			}
-
-			// Create a unique key to avoid duplicates
 			key := fmt.Sprintf("%s.%s", r.Name, gv.Group)
 			if seen[key] {
 				continue
 			}
 			seen[key] = true
-
 			resources = append(resources, ResourceInfo{
 				Name:       r.Name,
 				Group:      gv.Group,
@@ -119,15 +198,106 @@ func (c *K8sClient) DiscoverResources() ([]ResourceInfo, error) {
 				Kind:       r.Kind,
 				Namespaced: r.Namespaced,
 				Verbs:      r.Verbs,
+				ShortNames: r.ShortNames,
 			})
 		}
 	}
 
+	if c.crds != nil {
+		resources = overlayResources(c.crds.resources, resources)
+	}
 	return resources, nil
 }
 
-// ResolveResourceType resolves a resource type string to a GroupVersionResource
+// DiscoverSubresources returns all subresources in the cluster that support create
+func (c *K8sClient) DiscoverSubresources() ([]SubresourceInfo, error) {
+	if c.offline != nil {
+		return nil, nil
+	}
+
+	_, resourceLists, err := c.discoveryClient.ServerGroupsAndResources()
+	if err := c.checkDiscoveryError(err); err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+
+	var subresources []SubresourceInfo
+	seen := make(map[string]bool)
+
+	for _, resourceList := range resourceLists {
+		for _, sub := range subresourcesFromList(resourceList) {
+			key := fmt.Sprintf("%s.%s", sub.Name(), sub.Parent.Group)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			subresources = append(subresources, sub)
+		}
+	}
+
+	return subresources, nil
+}
+
+// ResolveSubresource resolves a "resource/subresource" string (e.g., "serviceaccounts/token")
+func (c *K8sClient) ResolveSubresource(resourceType string) (*SubresourceInfo, error) {
+	parts := strings.SplitN(resourceType, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid subresource %q (expected resource/subresource)", resourceType)
+	}
+
+	parent, err := c.ResolveResourceType(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	subresources, err := c.DiscoverSubresources()
+	if err != nil {
+		return nil, err
+	}
+
+	var available []string
+	for _, s := range subresources {
+		if s.Parent.Resource != parent.Resource || s.Parent.Group != parent.Group {
+			continue
+		}
+		if strings.EqualFold(s.Subresource, parts[1]) {
+			sub := s
+			return &sub, nil
+		}
+		available = append(available, s.Subresource)
+	}
+
+	if len(available) == 0 {
+		return nil, fmt.Errorf("resource %q has no subresources that support create", parent.Resource)
+	}
+	return nil, fmt.Errorf("subresource %q not found for %s, available: %s",
+		parts[1], parent.Resource, strings.Join(available, ", "))
+}
+
+// ResolveResourceType resolves a resource type string to a GroupVersionResource.
+// A version given as resource.version.group must be served.
 func (c *K8sClient) ResolveResourceType(resourceType string) (schema.GroupVersionResource, error) {
+	version, rest := RequestedVersion(resourceType)
+	if version == "" {
+		return c.resolveResourceType(resourceType)
+	}
+	gvr, err := c.resolveResourceType(rest)
+	if err != nil || gvr.Version == version {
+		return gvr, err
+	}
+	versions, err := c.ResourceVersions(gvr)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	if !slices.Contains(versions.Served, version) {
+		return schema.GroupVersionResource{}, fmt.Errorf("version %s of %s is not served, available: %s",
+			version, gvr.GroupResource(), strings.Join(versions.Served, ", "))
+	}
+	gvr.Version = version
+	return gvr, nil
+}
+
+// resolveResourceType resolves a resource type without a version, to its preferred version
+func (c *K8sClient) resolveResourceType(resourceType string) (schema.GroupVersionResource, error) {
 	resources, err := c.DiscoverResources()
 	if err != nil {
 		return schema.GroupVersionResource{}, err
@@ -141,15 +311,26 @@ func (c *K8sClient) ResolveResourceType(resourceType string) (schema.GroupVersio
 		group = parts[1]
 	}
 
-	// Find matching resource
+	// Prefer the server's RESTMapper, which follows its group priority order
+	if c.restMapper != nil {
+		gvr, ok, err := c.mapResourceType(name, group, resources)
+		if err != nil {
+			return schema.GroupVersionResource{}, err
+		}
+		if ok {
+			return gvr, nil
+		}
+	}
+
+	// Otherwise (offline, local CRDs) match discovered names and kinds
 	var matches []ResourceInfo
 	for _, r := range resources {
 		resourceName := strings.ToLower(r.Name)
 		resourceKind := strings.ToLower(r.Kind)
 
-		// Match by name (plural) or kind (singular)
+		// Match by name (plural), kind (singular) or short name
 		if resourceName == name || resourceKind == name ||
-			resourceName == name+"s" || resourceKind+"s" == name {
+			resourceName == name+"s" || resourceKind+"s" == name || containsShortName(r.ShortNames, name) {
 			if group == "" || strings.EqualFold(r.Group, group) {
 				matches = append(matches, r)
 			}
@@ -157,7 +338,7 @@ func (c *K8sClient) ResolveResourceType(resourceType string) (schema.GroupVersio
 	}
 
 	if len(matches) == 0 {
-		return schema.GroupVersionResource{}, fmt.Errorf("resource type %q not found", resourceType)
+		return schema.GroupVersionResource{}, c.notFoundError(resourceType, name, group, resources)
 	}
 
 	if len(matches) > 1 && group == "" {
@@ -194,42 +375,212 @@ func (c *K8sClient) ResolveResourceType(resourceType string) (schema.GroupVersio
 
 // GetResourceSchema returns the OpenAPI schema for a resource
 func (c *K8sClient) GetResourceSchema(gvr schema.GroupVersionResource) (*ResourceSchema, error) {
-	return GetSchema(c.discoveryClient, gvr)
+	if c.offline != nil {
+		return c.offline.schemaFor(gvr)
+	}
+	if c.crds != nil && c.crds.has(gvr) {
+		return c.crds.schemaFor(gvr)
+	}
+	if c.fastDiscovery {
+		return c.getSchemaDirect(gvr, c.kindFor(gvr))
+	}
+	return getSchemaForKind(c.openAPI(), gvr, c.kindFor(gvr))
+}
+
+// GetSubresourceSchema returns the OpenAPI schema for a subresource request body
+func (c *K8sClient) GetSubresourceSchema(sub *SubresourceInfo) (*ResourceSchema, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
+	gvk := schema.GroupVersionKind{Group: sub.Group, Version: sub.Version, Kind: sub.Kind}
+	if c.fastDiscovery {
+		return c.getSchemaDirect(sub.Parent, gvk)
+	}
+	return getSchemaForKind(c.openAPI(), sub.Parent, gvk)
 }
 
 // CreateResource creates a resource in the cluster
 func (c *K8sClient) CreateResource(gvr schema.GroupVersionResource, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
+	ctx := context.Background()
+	created, err := c.resourceInterface(gvr, namespace).Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Later lookups in this run should see the new object rather than a cached NotFound
+	c.storeCachedObject(gvr, namespace, created.GetName(), created.DeepCopy(), nil)
+	return created, nil
+}
+
+// DryRunCreateResource submits obj with server-side dry-run, returning the object
+// as it would be persisted after defaulting and admission
+func (c *K8sClient) DryRunCreateResource(gvr schema.GroupVersionResource, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
+	ctx := context.Background()
+	return c.resourceInterface(gvr, namespace).Create(ctx, obj, metav1.CreateOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+}
+
+// Warnings returns the API server warnings received so far
+func (c *K8sClient) Warnings() []string {
+	if c.warnings == nil {
+		return nil
+	}
+	return c.warnings.list()
+}
+
+// CreateSubresource posts obj to a subresource of the named parent object
+func (c *K8sClient) CreateSubresource(sub *SubresourceInfo, namespace, parentName string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
 	ctx := context.Background()
 
-	// Determine if resource is namespaced
-	namespaced := c.isNamespaced(gvr)
+	// The dynamic client takes the parent name from the object
+	obj.SetName(parentName)
 
 	var resourceInterface dynamic.ResourceInterface
-	if namespaced {
-		resourceInterface = c.dynamicClient.Resource(gvr).Namespace(namespace)
+	if sub.Namespaced {
+		resourceInterface = c.dynamicClient.Resource(sub.Parent).Namespace(namespace)
 	} else {
-		resourceInterface = c.dynamicClient.Resource(gvr)
+		resourceInterface = c.dynamicClient.Resource(sub.Parent)
 	}
 
-	return resourceInterface.Create(ctx, obj, metav1.CreateOptions{})
+	return resourceInterface.Create(ctx, obj, metav1.CreateOptions{}, sub.Subresource)
 }
 
-// GetResource fetches an existing resource and returns it as unstructured
+// DeleteResource deletes an object, in the background like kubectl delete. A
+// non-empty uid makes sure only that exact object is deleted, not a later one
+// with the same name.
+func (c *K8sClient) DeleteResource(gvr schema.GroupVersionResource, namespace, name string, uid types.UID) error {
+	if c.offline != nil {
+		return ErrOffline
+	}
+	ctx := context.Background()
+
+	propagation := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{PropagationPolicy: &propagation}
+	if uid != "" {
+		opts.Preconditions = &metav1.Preconditions{UID: &uid}
+	}
+	if err := c.resourceInterface(gvr, namespace).Delete(ctx, name, opts); err != nil {
+		return err
+	}
+
+	// Later lookups in this run should not see the deleted object
+	c.cacheMu.Lock()
+	delete(c.objectCache, objectCacheKey(gvr, namespace, name))
+	c.cacheMu.Unlock()
+	return nil
+}
+
+// GetResource fetches an existing resource and returns it as unstructured.
+// Results are cached for the lifetime of the client, so repeated lookups of the
+// same object (suggestions, reference checks, templates) only hit the API once.
 func (c *K8sClient) GetResource(gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
+	if cached, ok := c.loadCachedObject(gvr, namespace, name); ok {
+		if cached.err != nil {
+			return nil, cached.err
+		}
+		return cached.obj.DeepCopy(), nil
+	}
+
 	ctx := context.Background()
+	obj, err := c.resourceInterface(gvr, namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		// Only NotFound is stable enough to cache; transient errors should be retried
+		if apierrors.IsNotFound(err) {
+			c.storeCachedObject(gvr, namespace, name, nil, err)
+		}
+		return nil, err
+	}
 
-	// Determine if resource is namespaced
-	namespaced := c.isNamespaced(gvr)
+	c.storeCachedObject(gvr, namespace, name, obj.DeepCopy(), nil)
+	return obj, nil
+}
 
-	var resourceInterface dynamic.ResourceInterface
-	if namespaced {
-		resourceInterface = c.dynamicClient.Resource(gvr).Namespace(namespace)
-	} else {
-		resourceInterface = c.dynamicClient.Resource(gvr)
+// objectCacheKey builds the cache key for an object
+func objectCacheKey(gvr schema.GroupVersionResource, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", gvr.String(), namespace, name)
+}
+
+// loadCachedObject returns a previously fetched object, if any
+func (c *K8sClient) loadCachedObject(gvr schema.GroupVersionResource, namespace, name string) (cachedObject, bool) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	cached, ok := c.objectCache[objectCacheKey(gvr, namespace, name)]
+	return cached, ok
+}
+
+// storeCachedObject records a fetched object (or NotFound error) for later lookups
+func (c *K8sClient) storeCachedObject(gvr schema.GroupVersionResource, namespace, name string, obj *unstructured.Unstructured, err error) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	if c.objectCache == nil {
+		c.objectCache = make(map[string]cachedObject)
+	}
+	c.objectCache[objectCacheKey(gvr, namespace, name)] = cachedObject{obj: obj, err: err}
+}
+
+// resourceInterface returns a dynamic client scoped to the namespace when the resource is namespaced
+func (c *K8sClient) resourceInterface(gvr schema.GroupVersionResource, namespace string) dynamic.ResourceInterface {
+	if c.isNamespaced(gvr) {
+		return c.dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+	return c.dynamicClient.Resource(gvr)
+}
+
+// ListNames returns the names of the objects of a resource type in namespace
+// (all namespaces are ignored for cluster-scoped types)
+func (c *K8sClient) ListNames(gvr schema.GroupVersionResource, namespace string) ([]string, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
+	ctx := context.Background()
+	list, err := c.resourceInterface(gvr, namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	return names, nil
+}
+
+// ListObjects returns the objects of a resource type in namespace (all
+// namespaces are ignored for cluster-scoped types)
+func (c *K8sClient) ListObjects(gvr schema.GroupVersionResource, namespace string) ([]unstructured.Unstructured, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
+	list, err := c.resourceInterface(gvr, namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
 	}
+	return list.Items, nil
+}
 
-	// Get the resource
-	return resourceInterface.Get(ctx, name, metav1.GetOptions{})
+// ListSelected returns the objects of a resource type in namespace, or in all
+// namespaces when it is empty, whose labels match selector
+func (c *K8sClient) ListSelected(gvr schema.GroupVersionResource, namespace, selector string) ([]unstructured.Unstructured, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
+	list, err := c.resourceInterface(gvr, namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
 }
 
 // GetResourceSpec fetches an existing resource and returns its spec as a flat map
@@ -250,6 +601,11 @@ func (c *K8sClient) GetResourceSpec(gvr schema.GroupVersionResource, namespace,
 	return result, nil
 }
 
+// IsNamespaced reports whether a resource type is namespaced (defaults to true if unknown)
+func (c *K8sClient) IsNamespaced(gvr schema.GroupVersionResource) bool {
+	return c.isNamespaced(gvr)
+}
+
 // isNamespaced checks if a resource type is namespaced
 func (c *K8sClient) isNamespaced(gvr schema.GroupVersionResource) bool {
 	resources, err := c.DiscoverResources()
@@ -265,10 +621,21 @@ func (c *K8sClient) isNamespaced(gvr schema.GroupVersionResource) bool {
 	return true
 }
 
+// FlattenObject flattens obj into dot-notation paths, like GetResourceSpec does
+// with the spec, for all of its top-level fields
+func FlattenObject(obj map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	flattenMap(obj, "", result)
+	return result
+}
+
 // flattenMap flattens a nested map into dot-notation paths
 func flattenMap(m map[string]interface{}, prefix string, result map[string]interface{}) {
 	for k, v := range m {
-		path := prefix + "." + k
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
 		switch val := v.(type) {
 		case map[string]interface{}:
 			// Recurse into nested maps
@@ -290,6 +657,30 @@ func flattenMap(m map[string]interface{}, prefix string, result map[string]inter
 	}
 }
 
+// warningCollector is a rest.WarningHandler that prints and records warnings
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+// HandleWarningHeader implements rest.WarningHandler
+func (w *warningCollector) HandleWarningHeader(code int, agent string, text string) {
+	if code != 299 || text == "" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", text)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.warnings = append(w.warnings, text)
+}
+
+// list returns a copy of the recorded warnings
+func (w *warningCollector) list() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.warnings...)
+}
+
 // containsVerb checks if a verb is in the list
 func containsVerb(verbs []string, verb string) bool {
 	for _, v := range verbs {
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// eventsGVR is the core v1 Events resource
+var eventsGVR = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+
+// EventInfo is a simplified Kubernetes Event
+type EventInfo struct {
+	Type    string // Normal or Warning
+	Reason  string
+	Message string
+	Source  string // Reporting component
+	Count   int64
+	Time    time.Time
+}
+
+// WatchEvents streams Events whose involvedObject is obj for the given duration,
+// calling handle for each new or updated event. Existing events are delivered first.
+func (c *K8sClient) WatchEvents(obj *unstructured.Unstructured, duration time.Duration, handle func(EventInfo)) error {
+	if c.offline != nil {
+		return ErrOffline
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	// Events for cluster-scoped objects are recorded in the default namespace
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	selector := fields.Set{
+		"involvedObject.name": obj.GetName(),
+		"involvedObject.kind": obj.GetKind(),
+	}
+	if uid := string(obj.GetUID()); uid != "" {
+		selector["involvedObject.uid"] = uid
+	}
+
+	watcher, err := c.dynamicClient.Resource(eventsGVR).Namespace(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: selector.AsSelector().String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch events: %w", err)
+	}
+	defer watcher.Stop()
+
+	// Track counts so repeated deliveries of the same event are reported once
+	seen := make(map[string]int64)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			if ev.Type != watch.Added && ev.Type != watch.Modified {
+				continue
+			}
+			u, ok := ev.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+
+			info := eventInfoFrom(u)
+			key := string(u.GetUID())
+			if count, ok := seen[key]; ok && count >= info.Count {
+				continue
+			}
+			seen[key] = info.Count
+			handle(info)
+		}
+	}
+}
+
+// eventInfoFrom extracts the interesting fields of an Event
+func eventInfoFrom(u *unstructured.Unstructured) EventInfo {
+	info := EventInfo{Count: 1}
+	info.Type, _, _ = unstructured.NestedString(u.Object, "type")
+	info.Reason, _, _ = unstructured.NestedString(u.Object, "reason")
+	info.Message, _, _ = unstructured.NestedString(u.Object, "message")
+	info.Source, _, _ = unstructured.NestedString(u.Object, "source", "component")
+	if info.Source == "" {
+		info.Source, _, _ = unstructured.NestedString(u.Object, "reportingComponent")
+	}
+	if count, ok, _ := unstructured.NestedInt64(u.Object, "count"); ok && count > 0 {
+		info.Count = count
+	}
+
+	for _, field := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+		if ts, ok, _ := unstructured.NestedString(u.Object, field); ok && ts != "" {
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				info.Time = t
+				break
+			}
+		}
+	}
+	if info.Time.IsZero() {
+		info.Time = u.GetCreationTimestamp().Time
+	}
+	return info
+}
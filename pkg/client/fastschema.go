@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// openAPIPath returns the OpenAPI v3 document path of the group version serving gvr
+func openAPIPath(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return "/openapi/v3/api/" + gvr.Version
+	}
+	return fmt.Sprintf("/openapi/v3/apis/%s/%s", gvr.Group, gvr.Version)
+}
+
+// getSchemaDirect fetches the schema for gvk from the OpenAPI document of gvr's
+// group version with a single request, instead of listing the paths of every
+// group first. Servers that don't serve the path get the regular lookup.
+func (c *K8sClient) getSchemaDirect(gvr schema.GroupVersionResource, gvk schema.GroupVersionKind) (*ResourceSchema, error) {
+	path := openAPIPath(gvr)
+	schemaBytes, err := c.discoveryClient.RESTClient().Get().
+		AbsPath(path).
+		SetHeader("Accept", "application/json").
+		Do(context.Background()).
+		Raw()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Note: Failed to get schema from %s, looking it up in all paths: %v\n", path, err)
+		return getSchemaForKind(c.openAPI(), gvr, gvk)
+	}
+
+	resourceSchema, err := parseOpenAPISchema(schemaBytes, gvk, gvr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Note: Failed to parse schema from %s: %v\n", path, err)
+		fmt.Fprintf(os.Stderr, "Using basic schema (name, namespace, labels, annotations)\n")
+		return createBasicSchema(gvk), nil
+	}
+	fmt.Fprintf(os.Stderr, "Found schema with %d fields from %s\n", len(resourceSchema.Fields), path)
+	return resourceSchema, nil
+}
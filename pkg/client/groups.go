@@ -0,0 +1,153 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupInfo contains an API group and its preferred version
+type GroupInfo struct {
+	Name    string // Group name ("" for the core API)
+	Version string // Preferred version
+}
+
+// GroupVersion returns the group/version string used by discovery
+func (g GroupInfo) GroupVersion() string {
+	return schema.GroupVersion{Group: g.Name, Version: g.Version}.String()
+}
+
+// ServerGroups returns the API groups served by the cluster. Without aggregated
+// discovery this takes a single request and doesn't fetch the resources of each group.
+func (c *K8sClient) ServerGroups() ([]GroupInfo, error) {
+	if c.offline != nil {
+		return c.offline.groups(), nil
+	}
+
+	groupList, err := c.discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+
+	var groups []GroupInfo
+	for _, g := range groupList.Groups {
+		version := g.PreferredVersion.Version
+		if version == "" && len(g.Versions) > 0 {
+			version = g.Versions[0].Version
+		}
+		groups = append(groups, GroupInfo{Name: g.Name, Version: version})
+	}
+
+	// Groups that only exist in local CRD files
+	if c.crds != nil {
+		for _, local := range c.crds.groups() {
+			served := false
+			for _, g := range groups {
+				if g.Name == local.Name {
+					served = true
+					break
+				}
+			}
+			if !served {
+				groups = append(groups, local)
+			}
+		}
+	}
+	return groups, nil
+}
+
+// DiscoverGroupResources fetches the create-capable resources and subresources of a single group
+func (c *K8sClient) DiscoverGroupResources(group GroupInfo) ([]ResourceInfo, []SubresourceInfo, error) {
+	if c.offline != nil {
+		return c.offline.groupResources(group), nil, nil
+	}
+
+	var local []ResourceInfo
+	if c.crds != nil {
+		local = c.crds.groupResources(group)
+	}
+
+	resourceList, err := c.discoveryClient.ServerResourcesForGroupVersion(group.GroupVersion())
+	if err != nil {
+		// The group may only exist in local CRD files
+		if len(local) > 0 {
+			return local, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to discover resources for %s: %w", group.GroupVersion(), err)
+	}
+	return overlayResources(local, resourcesFromList(resourceList)), subresourcesFromList(resourceList), nil
+}
+
+// resourcesFromList extracts create-capable top-level resources from a discovery list
+func resourcesFromList(resourceList *metav1.APIResourceList) []ResourceInfo {
+	gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+	if err != nil {
+		return nil
+	}
+
+	var resources []ResourceInfo
+	for _, r := range resourceList.APIResources {
+		// Skip subresources (e.g., pods/status)
+		if strings.Contains(r.Name, "/") {
+			continue
+		}
+
+		// Skip resources that don't support create
+		if !containsVerb(r.Verbs, "create") {
+			continue
+		}
+
+		resources = append(resources, ResourceInfo{
+			Name:       r.Name,
+			Group:      gv.Group,
+			Version:    gv.Version,
+			Kind:       r.Kind,
+			Namespaced: r.Namespaced,
+			Verbs:      r.Verbs,
+			ShortNames: r.ShortNames,
+		})
+	}
+	return resources
+}
+
+// subresourcesFromList extracts create-capable subresources from a discovery list
+func subresourcesFromList(resourceList *metav1.APIResourceList) []SubresourceInfo {
+	gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+	if err != nil {
+		return nil
+	}
+
+	var subresources []SubresourceInfo
+	for _, r := range resourceList.APIResources {
+		parts := strings.SplitN(r.Name, "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if !containsVerb(r.Verbs, "create") {
+			continue
+		}
+
+		// Subresources may use a kind from a different group (e.g., TokenRequest)
+		group, version := gv.Group, gv.Version
+		if r.Group != "" || r.Version != "" {
+			group, version = r.Group, r.Version
+		}
+
+		subresources = append(subresources, SubresourceInfo{
+			Parent: schema.GroupVersionResource{
+				Group:    gv.Group,
+				Version:  gv.Version,
+				Resource: parts[0],
+			},
+			Subresource: parts[1],
+			Group:       group,
+			Version:     version,
+			Kind:        r.Kind,
+			Namespaced:  r.Namespaced,
+		})
+	}
+	return subresources
+}
@@ -0,0 +1,8 @@
+package client_test
+
+import "os"
+
+// writeFile writes a test fixture
+func writeFile(path, content string) error {
+	return os.WriteFile(path, []byte(content), 0o600)
+}
@@ -0,0 +1,419 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ErrOffline is returned for operations that need a cluster connection in offline mode
+var ErrOffline = errors.New("not available in offline mode")
+
+// offlineCatalog holds resource types and schemas loaded from local files
+type offlineCatalog struct {
+	resources []ResourceInfo
+	schemas   *componentSchemas                          // Component schemas by name
+	kinds     map[schema.GroupVersionKind]string         // Schema name for each kind
+	seen      map[schema.GroupVersionResource]bool       // Resources already in the catalog
+	versions  map[schema.GroupResource]*ResourceVersions // Served versions of each resource type
+}
+
+// NewOfflineClient creates a client that serves resource types and schemas from
+// local files instead of a cluster. path is an OpenAPI document (v2 or v3, JSON
+// or YAML), a CRD manifest, or a directory containing any mix of those.
+// Operations that need a cluster return ErrOffline.
+// An empty path starts with no types, to be filled with AddCRDs.
+func NewOfflineClient(path string) (*K8sClient, error) {
+	catalog := newOfflineCatalog()
+
+	if path != "" {
+		if err := catalog.loadPath(path, true); err != nil {
+			return nil, err
+		}
+		if len(catalog.resources) == 0 {
+			return nil, fmt.Errorf("no resource types found in %s", path)
+		}
+		fmt.Fprintf(os.Stderr, "Loaded %d resource types from %s (offline)\n", len(catalog.resources), path)
+	}
+
+	return &K8sClient{
+		offline:     catalog,
+		objectCache: make(map[string]cachedObject),
+	}, nil
+}
+
+// AddCRDs loads CustomResourceDefinitions from a file or directory. Their types
+// resolve even when not installed in the cluster, and their schemas take
+// precedence over the ones served by the cluster.
+func (c *K8sClient) AddCRDs(path string) error {
+	catalog := c.offline
+	if catalog == nil {
+		if c.crds == nil {
+			c.crds = newOfflineCatalog()
+		}
+		catalog = c.crds
+	}
+
+	before := len(catalog.resources)
+	if err := catalog.loadPath(path, false); err != nil {
+		return err
+	}
+	added := len(catalog.resources) - before
+	if added == 0 {
+		return fmt.Errorf("no CustomResourceDefinitions found in %s", path)
+	}
+	fmt.Fprintf(os.Stderr, "Loaded %d resource types from CRDs in %s\n", added, path)
+	return nil
+}
+
+// Offline reports whether the client works from local schema files
+func (c *K8sClient) Offline() bool {
+	return c.offline != nil
+}
+
+// newOfflineCatalog creates an empty catalog
+func newOfflineCatalog() *offlineCatalog {
+	return &offlineCatalog{
+		schemas:  newComponentSchemas(),
+		kinds:    make(map[schema.GroupVersionKind]string),
+		seen:     make(map[schema.GroupVersionResource]bool),
+		versions: make(map[schema.GroupResource]*ResourceVersions),
+	}
+}
+
+// loadPath loads a schema file, or every schema file in a directory; OpenAPI
+// documents are skipped unless withOpenAPI is set
+func (oc *offlineCatalog) loadPath(path string, withOpenAPI bool) error {
+	files, err := schemaFiles(path)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := oc.loadFile(file, withOpenAPI); err != nil {
+			return fmt.Errorf("failed to load schema file %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// schemaFiles returns path itself, or the JSON and YAML files in it when it is a directory
+func schemaFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".json", ".yaml", ".yml":
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// loadFile adds every OpenAPI document and CRD found in a (possibly multi-document) file
+func (oc *offlineCatalog) loadFile(path string, withOpenAPI bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		switch {
+		case doc == nil:
+			continue
+		case doc["kind"] == "CustomResourceDefinition":
+			oc.addCRD(doc)
+		case doc["kind"] == "List":
+			items, _ := doc["items"].([]interface{})
+			for _, item := range items {
+				if crd, ok := item.(map[string]interface{}); ok && crd["kind"] == "CustomResourceDefinition" {
+					oc.addCRD(crd)
+				}
+			}
+		case withOpenAPI:
+			oc.addOpenAPI(doc)
+		}
+	}
+}
+
+// addOpenAPI adds the component schemas (v3) or definitions (v2) of an OpenAPI document,
+// and the resources created by its POST operations
+func (oc *offlineCatalog) addOpenAPI(doc map[string]interface{}) {
+	schemas, _ := doc["definitions"].(map[string]interface{})
+	if components, ok := doc["components"].(map[string]interface{}); ok {
+		schemas, _ = components["schemas"].(map[string]interface{})
+	}
+
+	for schemaName, def := range schemas {
+		oc.schemas.add(schemaName, def)
+		for _, gvk := range gvksOf(def) {
+			oc.kinds[gvk] = schemaName
+		}
+	}
+
+	// Paths tell us the plural name and scope of each creatable kind
+	paths, _ := doc["paths"].(map[string]interface{})
+	var pathKeys []string
+	for p := range paths {
+		pathKeys = append(pathKeys, p)
+	}
+	sort.Strings(pathKeys)
+
+	for _, p := range pathKeys {
+		item, _ := paths[p].(map[string]interface{})
+		post, ok := item["post"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		gvk, ok := gvkFrom(post["x-kubernetes-group-version-kind"])
+		if !ok {
+			continue
+		}
+
+		segments := strings.Split(strings.Trim(p, "/"), "/")
+		resource := segments[len(segments)-1]
+		if strings.HasPrefix(resource, "{") {
+			// A path ending in a name parameter
+			continue
+		}
+		if len(segments) > 1 && strings.HasPrefix(segments[len(segments)-2], "{") &&
+			segments[len(segments)-2] != "{namespace}" {
+			// A subresource such as pods/{name}/eviction
+			continue
+		}
+		oc.addResource(ResourceInfo{
+			Name:       resource,
+			Group:      gvk.Group,
+			Version:    gvk.Version,
+			Kind:       gvk.Kind,
+			Namespaced: strings.Contains(p, "/namespaces/{namespace}/"),
+			Verbs:      []string{"create"},
+		})
+		oc.addVersion(schema.GroupResource{Group: gvk.Group, Resource: resource}, gvk.Version, false)
+	}
+}
+
+// addCRD adds the served versions of a CustomResourceDefinition
+func (oc *offlineCatalog) addCRD(crd map[string]interface{}) {
+	spec, _ := crd["spec"].(map[string]interface{})
+	names, _ := spec["names"].(map[string]interface{})
+	group, _ := spec["group"].(string)
+	plural, _ := names["plural"].(string)
+	kind, _ := names["kind"].(string)
+	if group == "" || plural == "" || kind == "" {
+		return
+	}
+	namespaced := spec["scope"] != "Cluster"
+
+	var shortNames []string
+	list, _ := names["shortNames"].([]interface{})
+	for _, n := range list {
+		if shortName, ok := n.(string); ok {
+			shortNames = append(shortNames, shortName)
+		}
+	}
+
+	// Prefer the storage version, like discovery's preferred version
+	versions, _ := spec["versions"].([]interface{})
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, _ := versions[i].(map[string]interface{})
+		return vi["storage"] == true
+	})
+
+	for _, v := range versions {
+		version, _ := v.(map[string]interface{})
+		versionName, _ := version["name"].(string)
+		if versionName == "" || version["served"] == false {
+			continue
+		}
+
+		gvk := schema.GroupVersionKind{Group: group, Version: versionName, Kind: kind}
+		if s, ok := version["schema"].(map[string]interface{}); ok {
+			if def, ok := s["openAPIV3Schema"].(map[string]interface{}); ok {
+				schemaName := fmt.Sprintf("%s.%s.%s", group, versionName, kind)
+				oc.schemas.add(schemaName, def)
+				oc.kinds[gvk] = schemaName
+			}
+		}
+
+		oc.addResource(ResourceInfo{
+			Name:       plural,
+			Group:      group,
+			Version:    versionName,
+			Kind:       kind,
+			Namespaced: namespaced,
+			Verbs:      []string{"create"},
+			ShortNames: shortNames,
+		})
+		oc.addVersion(schema.GroupResource{Group: group, Resource: plural}, versionName, version["storage"] == true)
+	}
+}
+
+// addVersion records a served version of a resource type, the first one being preferred
+func (oc *offlineCatalog) addVersion(gr schema.GroupResource, version string, storage bool) {
+	v := oc.versions[gr]
+	if v == nil {
+		v = &ResourceVersions{Preferred: version}
+		oc.versions[gr] = v
+	}
+	if !slices.Contains(v.Served, version) {
+		v.Served = append(v.Served, version)
+	}
+	if storage {
+		v.Storage = version
+	}
+}
+
+// addResource records a resource type, keeping the first version seen
+func (oc *offlineCatalog) addResource(r ResourceInfo) {
+	key := schema.GroupVersionResource{Group: r.Group, Resource: r.Name}
+	if oc.seen[key] {
+		return
+	}
+	oc.seen[key] = true
+	oc.resources = append(oc.resources, r)
+}
+
+// has reports whether the catalog defines gvr's resource
+func (oc *offlineCatalog) has(gvr schema.GroupVersionResource) bool {
+	return oc.seen[schema.GroupVersionResource{Group: gvr.Group, Resource: gvr.Resource}]
+}
+
+// overlayResources merges local resources over discovered ones; the local
+// version of a resource replaces the discovered one
+func overlayResources(local, discovered []ResourceInfo) []ResourceInfo {
+	merged := append([]ResourceInfo(nil), local...)
+	for _, r := range discovered {
+		replaced := false
+		for _, l := range local {
+			if l.Name == r.Name && l.Group == r.Group {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// groups returns the API groups of the catalog with the version of their first resource
+func (oc *offlineCatalog) groups() []GroupInfo {
+	var groups []GroupInfo
+	seen := make(map[string]bool)
+	for _, r := range oc.resources {
+		if seen[r.Group] {
+			continue
+		}
+		seen[r.Group] = true
+		groups = append(groups, GroupInfo{Name: r.Group, Version: r.Version})
+	}
+	return groups
+}
+
+// groupResources returns the resources of one group
+func (oc *offlineCatalog) groupResources(group GroupInfo) []ResourceInfo {
+	var resources []ResourceInfo
+	for _, r := range oc.resources {
+		if r.Group == group.Name {
+			resources = append(resources, r)
+		}
+	}
+	return resources
+}
+
+// schemaFor builds the ResourceSchema of gvr from the loaded schemas
+func (oc *offlineCatalog) schemaFor(gvr schema.GroupVersionResource) (*ResourceSchema, error) {
+	gvk := gvrToGVK(gvr)
+	for _, r := range oc.resources {
+		if r.Name == gvr.Resource && r.Group == gvr.Group {
+			gvk.Kind = r.Kind
+			break
+		}
+	}
+
+	schemaName, ok := oc.kinds[gvk]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Note: No schema found for %s in schema files, using basic schema\n", gvk)
+		return createBasicSchema(gvk), nil
+	}
+
+	def, ok := oc.schemas.get(schemaName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Note: Invalid schema %s in schema files, using basic schema\n", schemaName)
+		return createBasicSchema(gvk), nil
+	}
+	fields := extractFields(def, "", oc.schemas)
+	fmt.Fprintf(os.Stderr, "Found schema with %d fields from %s\n", len(fields), schemaName)
+
+	return &ResourceSchema{
+		GVK:         gvk,
+		Description: def.Description,
+		Fields:      fields,
+	}, nil
+}
+
+// gvksOf returns the kinds a schema declares via x-kubernetes-group-version-kind
+func gvksOf(def interface{}) []schema.GroupVersionKind {
+	d, ok := def.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	list, _ := d["x-kubernetes-group-version-kind"].([]interface{})
+	var gvks []schema.GroupVersionKind
+	for _, entry := range list {
+		if gvk, ok := gvkFrom(entry); ok {
+			gvks = append(gvks, gvk)
+		}
+	}
+	return gvks
+}
+
+// gvkFrom parses a {group, version, kind} extension value
+func gvkFrom(v interface{}) (schema.GroupVersionKind, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return schema.GroupVersionKind{}, false
+	}
+	group, _ := m["group"].(string)
+	version, _ := m["version"].(string)
+	kind, _ := m["kind"].(string)
+	if version == "" || kind == "" {
+		return schema.GroupVersionKind{}, false
+	}
+	return schema.GroupVersionKind{Group: group, Version: version, Kind: kind}, true
+}
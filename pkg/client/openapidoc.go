@@ -0,0 +1,247 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// componentSchemas holds the component schemas of an OpenAPI v3 document
+// undecoded. A schema is only decoded when it is looked up, so parsing the
+// document of a large group costs the schemas of one resource, not all of them.
+type componentSchemas struct {
+	raw     map[string][]byte
+	decoded map[string]*spec.Schema
+}
+
+func newComponentSchemas() *componentSchemas {
+	return &componentSchemas{
+		raw:     make(map[string][]byte),
+		decoded: make(map[string]*spec.Schema),
+	}
+}
+
+// readComponentSchemas scans an OpenAPI v3 document for components.schemas,
+// skipping everything else (paths, info, ...) without decoding it. The raw
+// schemas are slices of data, not copies.
+func readComponentSchemas(data []byte) (*componentSchemas, error) {
+	sc := &jsonScanner{data: data}
+	var schemas *componentSchemas
+
+	err := sc.readObject(func(key string) error {
+		if key != "components" {
+			_, err := sc.skipValue()
+			return err
+		}
+		return sc.readObject(func(key string) error {
+			if key != "schemas" {
+				_, err := sc.skipValue()
+				return err
+			}
+			schemas = newComponentSchemas()
+			return sc.readObject(func(name string) error {
+				def, err := sc.skipValue()
+				schemas.raw[name] = def
+				return err
+			})
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if schemas == nil {
+		return nil, fmt.Errorf("no schemas in components")
+	}
+	return schemas, nil
+}
+
+// jsonScanner walks the structure of a JSON document. It only finds where
+// values start and end; the values that are needed are decoded with
+// encoding/json, which also validates them.
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+func (sc *jsonScanner) skipSpace() {
+	for sc.pos < len(sc.data) {
+		switch sc.data[sc.pos] {
+		case ' ', '\t', '\n', '\r':
+			sc.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume skips whitespace and the byte c
+func (sc *jsonScanner) consume(c byte) error {
+	sc.skipSpace()
+	if sc.pos >= len(sc.data) {
+		return io.ErrUnexpectedEOF
+	}
+	if sc.data[sc.pos] != c {
+		return fmt.Errorf("expected %q at offset %d, got %q", c, sc.pos, sc.data[sc.pos])
+	}
+	sc.pos++
+	return nil
+}
+
+// peek skips whitespace and returns the next byte, or 0 at the end
+func (sc *jsonScanner) peek() byte {
+	sc.skipSpace()
+	if sc.pos >= len(sc.data) {
+		return 0
+	}
+	return sc.data[sc.pos]
+}
+
+// readObject reads an object, calling member for each key with the scanner
+// positioned at its value. member must consume the value.
+func (sc *jsonScanner) readObject(member func(key string) error) error {
+	if err := sc.consume('{'); err != nil {
+		return err
+	}
+	if sc.peek() == '}' {
+		sc.pos++
+		return nil
+	}
+	for {
+		key, err := sc.readString()
+		if err != nil {
+			return err
+		}
+		if err := sc.consume(':'); err != nil {
+			return err
+		}
+		if err := member(key); err != nil {
+			return err
+		}
+		if sc.peek() == '}' {
+			sc.pos++
+			return nil
+		}
+		if err := sc.consume(','); err != nil {
+			return err
+		}
+	}
+}
+
+// readString reads a string, decoding escapes only when it has any
+func (sc *jsonScanner) readString() (string, error) {
+	sc.skipSpace()
+	start := sc.pos
+	if err := sc.skipString(); err != nil {
+		return "", err
+	}
+	raw := sc.data[start:sc.pos]
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return string(raw[1 : len(raw)-1]), nil
+	}
+	var s string
+	err := json.Unmarshal(raw, &s)
+	return s, err
+}
+
+// skipString moves past the string at the current position
+func (sc *jsonScanner) skipString() error {
+	if err := sc.consume('"'); err != nil {
+		return err
+	}
+	for sc.pos < len(sc.data) {
+		switch sc.data[sc.pos] {
+		case '\\':
+			sc.pos += 2
+		case '"':
+			sc.pos++
+			return nil
+		default:
+			sc.pos++
+		}
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// skipValue moves past the value at the current position and returns its bytes
+func (sc *jsonScanner) skipValue() ([]byte, error) {
+	first := sc.peek()
+	start := sc.pos
+	switch first {
+	case 0:
+		return nil, io.ErrUnexpectedEOF
+	case '"':
+		if err := sc.skipString(); err != nil {
+			return nil, err
+		}
+	case '{', '[':
+		depth := 0
+		for {
+			if sc.pos >= len(sc.data) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			c := sc.data[sc.pos]
+			if c == '"' {
+				if err := sc.skipString(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			sc.pos++
+			switch c {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+			if depth == 0 {
+				break
+			}
+		}
+	default:
+		// A number, true, false or null runs to the next delimiter
+		for sc.pos < len(sc.data) && strings.IndexByte(",}] \t\n\r", sc.data[sc.pos]) < 0 {
+			sc.pos++
+		}
+	}
+	return sc.data[start:sc.pos], nil
+}
+
+// names returns the names of all component schemas
+func (s *componentSchemas) names() []string {
+	names := make([]string, 0, len(s.raw))
+	for name := range s.raw {
+		names = append(names, name)
+	}
+	return names
+}
+
+// add adds a component schema given in decoded form, as read from YAML
+func (s *componentSchemas) add(name string, def interface{}) {
+	raw, err := json.Marshal(def)
+	if err != nil {
+		return
+	}
+	s.raw[name] = raw
+	delete(s.decoded, name)
+}
+
+// get decodes the component schema name, once
+func (s *componentSchemas) get(name string) (*spec.Schema, bool) {
+	if def, ok := s.decoded[name]; ok {
+		return def, def != nil
+	}
+	raw, ok := s.raw[name]
+	if !ok {
+		return nil, false
+	}
+	def := &spec.Schema{}
+	if err := json.Unmarshal(raw, def); err != nil {
+		def = nil
+	}
+	s.decoded[name] = def
+	return def, def != nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+)
+
+// newRESTMapper creates a RESTMapper over cached discovery that also expands
+// short names (e.g., "deploy"), the same as kubectl's
+func newRESTMapper(cachedDiscovery discovery.CachedDiscoveryInterface) meta.RESTMapper {
+	return restmapper.NewShortcutExpander(
+		restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+		cachedDiscovery,
+		func(warning string) {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		},
+	)
+}
+
+// Refresh drops the cached discovery and the mappings built from it, so types
+// installed since, like new CRDs or API versions, are found. Long-running
+// users of the client, such as serve, call it when a lookup misses.
+func (c *K8sClient) Refresh() {
+	if c.offline != nil || c.discoveryClient == nil {
+		return
+	}
+	c.discoveryClient.Invalidate()
+	if mapper, ok := c.restMapper.(meta.ResettableRESTMapper); ok {
+		mapper.Reset()
+	}
+	c.discoveryFailures.mu.Lock()
+	c.discoveryFailures.groups = nil
+	c.discoveryFailures.mu.Unlock()
+}
+
+// mapResourceType resolves name (and an optional group) with the RESTMapper.
+// When several groups serve the name, the server's group priority decides, so
+// e.g. a CRD named like a built-in doesn't shadow it. ok is false when the
+// mapper doesn't know a create-capable resource by that name, so the caller can
+// fall back to its own matching.
+func (c *K8sClient) mapResourceType(name, group string, resources []ResourceInfo) (gvr schema.GroupVersionResource, ok bool, err error) {
+	mapped, err := c.restMapper.ResourceFor(schema.GroupVersionResource{Group: group, Resource: name})
+	if err != nil {
+		var ambiguous *meta.AmbiguousResourceError
+		if errors.As(err, &ambiguous) {
+			return schema.GroupVersionResource{}, false, ambiguousResourceError(name, ambiguous.MatchingResources)
+		}
+		return schema.GroupVersionResource{}, false, nil
+	}
+
+	// Use the version of the create-capable resource (which may come from a local CRD)
+	for _, r := range resources {
+		if r.Name == mapped.Resource && r.Group == mapped.Group {
+			return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Name}, true, nil
+		}
+	}
+	return schema.GroupVersionResource{}, false, nil
+}
+
+// ResourceForKind returns the resource serving a kind, e.g. for the paramKind
+// of an admission policy or the objects of a manifest. Offline, the kind is
+// looked up in the local schemas and CRDs.
+func (c *K8sClient) ResourceForKind(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	if c.restMapper == nil {
+		resources, err := c.DiscoverResources()
+		if err != nil {
+			return schema.GroupVersionResource{}, err
+		}
+		for _, r := range resources {
+			if r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind {
+				return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Name}, nil
+			}
+		}
+		return schema.GroupVersionResource{}, fmt.Errorf("no resource type found for %s", gvk)
+	}
+
+	mapping, err := c.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
+
+// kindFor returns the kind of gvr as discovery serves it, or guessed from the
+// resource name (e.g., Queue for queues) when discovery doesn't know it. Names
+// of several words, like persistentvolumeclaims, can't be guessed.
+func (c *K8sClient) kindFor(gvr schema.GroupVersionResource) schema.GroupVersionKind {
+	if c.restMapper != nil {
+		if gvk, err := c.restMapper.KindFor(gvr); err == nil {
+			return gvk
+		}
+	}
+	return gvrToGVK(gvr)
+}
+
+// ambiguousResourceError lists the groups a name could refer to
+func ambiguousResourceError(name string, matches []schema.GroupVersionResource) error {
+	var options []string
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		option := m.Resource
+		if m.Group != "" {
+			option = fmt.Sprintf("%s.%s", m.Resource, m.Group)
+		}
+		if seen[option] {
+			continue
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+	return fmt.Errorf("ambiguous resource type %q, specify group: %s", name, strings.Join(options, ", "))
+}
@@ -1,14 +1,16 @@
 package client
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/openapi"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
 // ResourceSchema represents the schema for a Kubernetes resource
@@ -20,34 +22,164 @@ type ResourceSchema struct {
 
 // FieldSchema represents a field in a resource schema
 type FieldSchema struct {
-	Path        string        // JSON path (e.g., "spec.replicas")
-	Name        string        // Field name (e.g., "replicas")
-	Type        string        // Field type (string, integer, boolean, array, object)
-	Description string        // Field description
-	Required    bool          // Whether the field is required
-	Default     interface{}   // Default value if any
-	Items       *FieldSchema  // For arrays, the schema of items
-	Properties  []FieldSchema // For objects, nested properties
+	Path        string        `json:"path,omitempty"`        // JSON path (e.g., "spec.replicas")
+	Name        string        `json:"name,omitempty"`        // Field name (e.g., "replicas")
+	Type        string        `json:"type,omitempty"`        // Field type (string, integer, boolean, array, object)
+	Description string        `json:"description,omitempty"` // Field description
+	Required    bool          `json:"required,omitempty"`    // Whether the field is required
+	Default     interface{}   `json:"default,omitempty"`     // Default value if any
+	Enum        []interface{} `json:"enum,omitempty"`        // Allowed values, if restricted
+	Items       *FieldSchema  `json:"items,omitempty"`       // For arrays, the schema of items
+	Values      *FieldSchema  `json:"values,omitempty"`      // For maps (additionalProperties), the schema of values
+	Properties  []FieldSchema `json:"properties,omitempty"`  // For objects, nested properties
+	Ref         string        `json:"ref,omitempty"`         // Referenced component schema, if any (e.g., "io.k8s.api.core.v1.PodTemplateSpec")
+	Format      string        `json:"format,omitempty"`      // Format of the value, if any (e.g., "int32", "date-time", "byte")
+	Nullable    bool          `json:"nullable,omitempty"`    // Whether null is an allowed value
+	Variants    []FieldSchema `json:"variants,omitempty"`    // Alternatives of a oneOf/anyOf field, one of which is filled in
+
+	// PreserveUnknownFields is set for fields that accept fields not in the
+	// schema (x-kubernetes-preserve-unknown-fields)
+	PreserveUnknownFields bool `json:"preserveUnknownFields,omitempty"`
+
+	Constraints
 }
 
+// Constraints are the limits a schema puts on the value of a field. Unset
+// limits are nil.
+type Constraints struct {
+	Pattern   string   `json:"pattern,omitempty"`   // Regular expression strings must match
+	MinLength *int64   `json:"minLength,omitempty"` // Minimum length of strings, in characters
+	MaxLength *int64   `json:"maxLength,omitempty"` // Maximum length of strings, in characters
+	Minimum   *float64 `json:"minimum,omitempty"`   // Minimum of numbers
+	Maximum   *float64 `json:"maximum,omitempty"`   // Maximum of numbers
+	MinItems  *int64   `json:"minItems,omitempty"`  // Minimum number of items of arrays
+}
+
+// SchemaDocument is the machine-readable schema of a resource type. Its fields
+// are a stable interface for tools building their own creation forms.
+type SchemaDocument struct {
+	APIVersion  string        `json:"apiVersion"`
+	Kind        string        `json:"kind"`
+	Resource    string        `json:"resource"`
+	Namespaced  bool          `json:"namespaced"`
+	Description string        `json:"description,omitempty"`
+	Fields      []FieldSchema `json:"fields"`
+}
+
+// NewSchemaDocument describes the schema of resource type gvr
+func NewSchemaDocument(gvr schema.GroupVersionResource, resourceSchema *ResourceSchema, namespaced bool) SchemaDocument {
+	return SchemaDocument{
+		APIVersion:  resourceSchema.GVK.GroupVersion().String(),
+		Kind:        resourceSchema.GVK.Kind,
+		Resource:    gvr.Resource,
+		Namespaced:  namespaced,
+		Description: resourceSchema.Description,
+		Fields:      resourceSchema.Fields,
+	}
+}
+
+// FindField returns the field at a dotted path such as
+// "spec.template.spec.tolerations". Array indices ("containers[0]") are
+// ignored, path segments below an array refer to the fields of its items, and
+// keys of a map (e.g., "data.password") to the schema of its values.
+func (s *ResourceSchema) FindField(path string) (*FieldSchema, bool) {
+	fields := s.Fields
+	var found *FieldSchema
+	for _, part := range strings.Split(path, ".") {
+		if i := strings.Index(part, "["); i >= 0 {
+			part = part[:i]
+		}
+		if part == "" {
+			return nil, false
+		}
+		parent := found
+		found = nil
+		for i := range fields {
+			if fields[i].Name == part {
+				found = &fields[i]
+				break
+			}
+		}
+		if found == nil && parent != nil {
+			found = parent.Values
+		}
+		if found == nil {
+			return nil, false
+		}
+		fields = found.Properties
+		if found.Items != nil {
+			fields = found.Items.Properties
+		}
+	}
+	return found, found != nil
+}
+
+// SearchFields returns the fields, at any depth, whose name or description
+// matches keyword, ignoring case: fields whose name contains the keyword without
+// its spaces (so "image pull secret" finds imagePullSecrets) come first, then
+// those whose name and description contain each of its words
+func (s *ResourceSchema) SearchFields(keyword string) []FieldSchema {
+	words := strings.Fields(strings.ToLower(keyword))
+	if len(words) == 0 {
+		return nil
+	}
+	joined := strings.Join(words, "")
+
+	var byName, byDescription []FieldSchema
+	var walk func(fields []FieldSchema)
+	walk = func(fields []FieldSchema) {
+		for _, f := range fields {
+			name := strings.ToLower(f.Name)
+			text := name + " " + strings.ToLower(f.Description)
+			switch {
+			case strings.Contains(name, joined):
+				byName = append(byName, f)
+			case containsAll(text, words):
+				byDescription = append(byDescription, f)
+			}
+			if f.Items != nil {
+				walk(f.Items.Properties)
+			} else {
+				walk(f.Properties)
+			}
+		}
+	}
+	walk(s.Fields)
+	return append(byName, byDescription...)
+}
+
+// containsAll reports whether text contains each of words
+func containsAll(text string, words []string) bool {
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// PodTemplateSpecRef is the component schema name of a pod template
+const PodTemplateSpecRef = "io.k8s.api.core.v1.PodTemplateSpec"
+
 // GetSchema retrieves the OpenAPI schema for a resource
 func GetSchema(discoveryClient discovery.DiscoveryInterface, gvr schema.GroupVersionResource) (*ResourceSchema, error) {
-	// Get the OpenAPI v3 client
-	openAPIClient := discoveryClient.OpenAPIV3()
+	return getSchemaForKind(discoveryClient.OpenAPIV3(), gvr, gvrToGVK(gvr))
+}
+
+// getSchemaForKind retrieves the schema for gvk from the OpenAPI document serving gvr
+func getSchemaForKind(openAPIClient openapi.Client, gvr schema.GroupVersionResource, gvk schema.GroupVersionKind) (*ResourceSchema, error) {
 	if openAPIClient == nil {
 		fmt.Fprintf(os.Stderr, "Note: OpenAPI v3 not available, using basic schema\n")
-		return createBasicSchema(gvrToGVK(gvr)), nil
+		return createBasicSchema(gvk), nil
 	}
 
 	// Get the paths
 	paths, err := openAPIClient.Paths()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Note: Failed to get OpenAPI paths: %v\n", err)
-		return createBasicSchema(gvrToGVK(gvr)), nil
+		return createBasicSchema(gvk), nil
 	}
 
-	gvk := gvrToGVK(gvr)
-
 	// Build target path patterns to search for
 	targetPatterns := buildTargetPaths(gvr)
 
@@ -127,24 +259,14 @@ func gvrToGVK(gvr schema.GroupVersionResource) schema.GroupVersionKind {
 	}
 }
 
-// parseOpenAPISchema parses the OpenAPI schema bytes into a ResourceSchema
+// parseOpenAPISchema parses the OpenAPI schema bytes into a ResourceSchema. Only
+// the resource's component schema and the schemas it references are decoded.
 func parseOpenAPISchema(schemaBytes []byte, gvk schema.GroupVersionKind, gvr schema.GroupVersionResource) (*ResourceSchema, error) {
-	var openAPISpec map[string]interface{}
-	if err := json.Unmarshal(schemaBytes, &openAPISpec); err != nil {
+	schemas, err := readComponentSchemas(schemaBytes)
+	if err != nil {
 		return nil, err
 	}
 
-	// Get components/schemas
-	components, ok := openAPISpec["components"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("no components in schema")
-	}
-
-	schemas, ok := components["schemas"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("no schemas in components")
-	}
-
 	// Find the schema for our resource using multiple strategies
 	resourceDef := findResourceSchema(schemas, gvk, gvr)
 	if resourceDef == nil {
@@ -155,7 +277,7 @@ func parseOpenAPISchema(schemaBytes []byte, gvk schema.GroupVersionKind, gvr sch
 	fields := extractFields(resourceDef, "", schemas)
 
 	// Get description
-	description, _ := resourceDef["description"].(string)
+	description := resourceDef.Description
 
 	return &ResourceSchema{
 		GVK:         gvk,
@@ -164,14 +286,17 @@ func parseOpenAPISchema(schemaBytes []byte, gvk schema.GroupVersionKind, gvr sch
 	}, nil
 }
 
-// findResourceSchema searches for the resource schema using multiple strategies
-func findResourceSchema(schemas map[string]interface{}, gvk schema.GroupVersionKind, gvr schema.GroupVersionResource) map[string]interface{} {
+// findResourceSchema searches for the resource schema using multiple strategies.
+// Candidates are matched by name, so only they are decoded.
+func findResourceSchema(schemas *componentSchemas, gvk schema.GroupVersionKind, gvr schema.GroupVersionResource) *spec.Schema {
+	names := schemas.names()
+
 	// Strategy 1: Match by Kind suffix (works for most CRDs and built-in resources)
-	for name, def := range schemas {
+	for _, name := range names {
 		if strings.HasSuffix(name, "."+gvk.Kind) {
-			if d, ok := def.(map[string]interface{}); ok {
+			if d, ok := schemas.get(name); ok {
 				// Verify it has properties (is a real resource schema)
-				if _, hasProps := d["properties"]; hasProps {
+				if d.Properties != nil {
 					return d
 				}
 			}
@@ -181,7 +306,7 @@ func findResourceSchema(schemas map[string]interface{}, gvk schema.GroupVersionK
 	// Strategy 2: Match by group components in schema name
 	// CRDs often use reversed domain notation: com.example.v1.MyResource
 	groupParts := strings.Split(gvr.Group, ".")
-	for name, def := range schemas {
+	for _, name := range names {
 		nameLower := strings.ToLower(name)
 		// Check if schema name contains group parts and kind
 		matchesGroup := true
@@ -192,8 +317,8 @@ func findResourceSchema(schemas map[string]interface{}, gvk schema.GroupVersionK
 			}
 		}
 		if matchesGroup && strings.Contains(nameLower, strings.ToLower(gvk.Kind)) {
-			if d, ok := def.(map[string]interface{}); ok {
-				if _, hasProps := d["properties"]; hasProps {
+			if d, ok := schemas.get(name); ok {
+				if d.Properties != nil {
 					return d
 				}
 			}
@@ -202,9 +327,9 @@ func findResourceSchema(schemas map[string]interface{}, gvk schema.GroupVersionK
 
 	// Strategy 3: Built-in Kubernetes resources
 	builtInRef := gvkToSchemaRef(gvk)
-	for name, def := range schemas {
+	for _, name := range names {
 		if strings.Contains(name, builtInRef) {
-			if d, ok := def.(map[string]interface{}); ok {
+			if d, ok := schemas.get(name); ok {
 				return d
 			}
 		}
@@ -213,28 +338,33 @@ func findResourceSchema(schemas map[string]interface{}, gvk schema.GroupVersionK
 	return nil
 }
 
-// extractFields recursively extracts field schemas from an OpenAPI definition
-func extractFields(def map[string]interface{}, prefix string, allSchemas map[string]interface{}) []FieldSchema {
+// extractFields recursively extracts field schemas from an OpenAPI definition,
+// resolving references against schemas
+func extractFields(def *spec.Schema, prefix string, schemas *componentSchemas) []FieldSchema {
+	return extractFieldsVisiting(def, prefix, schemas, make(map[string]bool))
+}
+
+// extractFieldsVisiting extracts fields while tracking the component schemas on the
+// current path, so self-referencing schemas (e.g., JSONSchemaProps) terminate
+func extractFieldsVisiting(def *spec.Schema, prefix string, schemas *componentSchemas, visiting map[string]bool) []FieldSchema {
 	var fields []FieldSchema
 
-	properties, ok := def["properties"].(map[string]interface{})
-	if !ok {
+	def, done := mergeAllOf(def, schemas, visiting)
+	defer done()
+
+	if def.Properties == nil {
 		return fields
 	}
 
 	// Get required fields
 	requiredFields := make(map[string]bool)
-	if required, ok := def["required"].([]interface{}); ok {
-		for _, r := range required {
-			if s, ok := r.(string); ok {
-				requiredFields[s] = true
-			}
-		}
+	for _, r := range def.Required {
+		requiredFields[r] = true
 	}
 
 	// Sort property names for consistent ordering, but put required fields first
 	var requiredNames, optionalNames []string
-	for name := range properties {
+	for name := range def.Properties {
 		if requiredFields[name] {
 			requiredNames = append(requiredNames, name)
 		} else {
@@ -246,11 +376,7 @@ func extractFields(def map[string]interface{}, prefix string, allSchemas map[str
 	propNames := append(requiredNames, optionalNames...)
 
 	for _, name := range propNames {
-		prop := properties[name]
-		propDef, ok := prop.(map[string]interface{})
-		if !ok {
-			continue
-		}
+		propDef := def.Properties[name]
 
 		// Skip apiVersion, kind, and status as they're handled specially
 		if prefix == "" && (name == "apiVersion" || name == "kind" || name == "status") {
@@ -262,71 +388,315 @@ func extractFields(def map[string]interface{}, prefix string, allSchemas map[str
 			path = prefix + "." + name
 		}
 
-		field := FieldSchema{
-			Path:     path,
-			Name:     name,
-			Required: requiredFields[name],
-		}
+		field := fieldFor(&propDef, path, schemas, visiting)
+		field.Name = name
+		field.Required = requiredFields[name]
+		fields = append(fields, field)
+	}
 
-		// Get type
-		if t, ok := propDef["type"].(string); ok {
-			field.Type = t
-		}
+	return fields
+}
 
-		// Get description
-		if d, ok := propDef["description"].(string); ok {
-			field.Description = d
-		}
+// fieldFor builds the field described by def at path, with its nested fields,
+// array items and variants
+func fieldFor(def *spec.Schema, path string, schemas *componentSchemas, visiting map[string]bool) FieldSchema {
+	def, done := mergeAllOf(def, schemas, visiting)
+	defer done()
+
+	field := FieldSchema{
+		Path:        path,
+		Description: def.Description,
+		Default:     def.Default,
+		Enum:        def.Enum,
+		Format:      def.Format,
+	}
+	field.Type, field.Nullable = schemaType(def)
+	field.PreserveUnknownFields, _ = def.Extensions.GetBool("x-kubernetes-preserve-unknown-fields")
+	field.Constraints = Constraints{
+		Pattern:   def.Pattern,
+		MinLength: def.MinLength,
+		MaxLength: def.MaxLength,
+		Minimum:   def.Minimum,
+		Maximum:   def.Maximum,
+		MinItems:  def.MinItems,
+	}
 
-		// Get default
-		if d, ok := propDef["default"]; ok {
-			field.Default = d
+	// Handle $ref
+	if refName := schemaRef(def); refName != "" {
+		field.Ref = refName
+		if refDef, ok := schemas.get(refName); ok {
+			field.Type = "object"
+			if !visiting[refName] {
+				visiting[refName] = true
+				resolved := fieldFor(refDef, path, schemas, visiting)
+				delete(visiting, refName)
+
+				// References to scalars (e.g., Time) and unions (e.g., IntOrString)
+				// keep their type; untyped ones are objects
+				if resolved.Type != "" || len(resolved.Variants) > 0 {
+					field.Type = resolved.Type
+				}
+				field.Properties = resolved.Properties
+				field.Items = resolved.Items
+				field.Values = resolved.Values
+				field.Variants = resolved.Variants
+				field.Nullable = field.Nullable || resolved.Nullable
+				if field.Format == "" {
+					field.Format = resolved.Format
+				}
+				if field.Enum == nil {
+					field.Enum = resolved.Enum
+				}
+				if field.Constraints == (Constraints{}) {
+					field.Constraints = resolved.Constraints
+				}
+			}
 		}
+		return field
+	}
 
-		// Handle $ref
-		if ref, ok := propDef["$ref"].(string); ok {
-			refName := strings.TrimPrefix(ref, "#/components/schemas/")
-			if refDef, ok := allSchemas[refName].(map[string]interface{}); ok {
-				field.Type = "object"
-				field.Properties = extractFields(refDef, path, allSchemas)
+	switch field.Type {
+	case "object":
+		// Handle nested objects with additionalProperties (maps)
+		if def.AdditionalProperties != nil && def.AdditionalProperties.Schema != nil {
+			// This is a map type - mark it specially
+			if addType, _ := schemaType(def.AdditionalProperties.Schema); addType != "" {
+				field.Description = fmt.Sprintf("Map of string to %s. %s", addType, field.Description)
 			}
+			valueField := fieldFor(def.AdditionalProperties.Schema, path+".*", schemas, visiting)
+			valueField.Path = ""
+			valueField.Description = ""
+			valueField.Default = nil
+			field.Values = &valueField
+		} else {
+			// Regular nested object
+			field.Properties = extractFieldsVisiting(def, path, schemas, visiting)
 		}
+	case "array":
+		if items := itemsSchema(def); items != nil {
+			itemField := fieldFor(items, path+"[*]", schemas, visiting)
+			itemField.Path = ""
+			itemField.Description = ""
+			itemField.Default = nil
+			field.Items = &itemField
+		}
+	}
 
-		// Handle nested objects with additionalProperties (maps)
-		if field.Type == "object" {
-			if addProps, ok := propDef["additionalProperties"].(map[string]interface{}); ok {
-				// This is a map type - mark it specially
-				if addType, ok := addProps["type"].(string); ok {
-					field.Description = fmt.Sprintf("Map of string to %s. %s", addType, field.Description)
+	field.Variants = variantsFor(def, &field, schemas, visiting)
+	return field
+}
+
+// variantsFor returns the alternatives of a oneOf or anyOf in def. Members that
+// only list required properties (the usual way CRDs declare a union of fields)
+// become object variants made of those properties of field.
+func variantsFor(def *spec.Schema, field *FieldSchema, schemas *componentSchemas, visiting map[string]bool) []FieldSchema {
+	members := def.OneOf
+	if len(members) == 0 {
+		members = def.AnyOf
+	}
+
+	var variants []FieldSchema
+	for i := range members {
+		member := &members[i]
+		var variant FieldSchema
+		if isRequiredOnly(member) && len(field.Properties) > 0 {
+			variant = FieldSchema{Path: field.Path, Type: "object"}
+			for _, p := range field.Properties {
+				if slices.Contains(member.Required, p.Name) {
+					p.Required = true
+					variant.Properties = append(variant.Properties, p)
 				}
-			} else if len(field.Properties) == 0 {
-				// Regular nested object
-				field.Properties = extractFields(propDef, path, allSchemas)
 			}
+			variant.Name = strings.Join(member.Required, ", ")
+		} else {
+			variant = fieldFor(member, field.Path, schemas, visiting)
+			variant.Name = variantName(member, variant)
 		}
+		if variant.Name == "" {
+			variant.Name = fmt.Sprintf("variant %d", i+1)
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+// isRequiredOnly reports whether def only constrains which properties are set
+func isRequiredOnly(def *spec.Schema) bool {
+	return len(def.Required) > 0 && len(def.Type) == 0 && def.Properties == nil &&
+		def.Ref.String() == "" && len(def.AllOf) == 0 && len(def.OneOf) == 0 && len(def.AnyOf) == 0
+}
+
+// variantName names a union member after its title, referenced schema or type
+func variantName(def *spec.Schema, variant FieldSchema) string {
+	switch {
+	case def.Title != "":
+		return def.Title
+	case variant.Ref != "":
+		return variant.Ref[strings.LastIndex(variant.Ref, ".")+1:]
+	}
+	return variant.Type
+}
 
-		// Handle arrays
-		if field.Type == "array" {
-			if items, ok := propDef["items"].(map[string]interface{}); ok {
-				itemField := FieldSchema{}
-				if t, ok := items["type"].(string); ok {
-					itemField.Type = t
+// mergeAllOf folds the members of def's allOf into a copy of def: properties and
+// required fields are combined, and other keywords are taken from def, then from
+// the members in order. A single referenced member is left to schemaRef. The
+// component schemas merged in are marked as visited until done is called.
+func mergeAllOf(def *spec.Schema, schemas *componentSchemas, visiting map[string]bool) (*spec.Schema, func()) {
+	if len(def.AllOf) == 0 || schemaRef(def) != "" {
+		return def, func() {}
+	}
+
+	merged := *def
+	merged.AllOf = nil
+	merged.Properties = make(map[string]spec.Schema, len(def.Properties))
+	for name, p := range def.Properties {
+		merged.Properties[name] = p
+	}
+	merged.Required = append([]string(nil), def.Required...)
+	merged.Extensions = spec.Extensions{}
+	for key, value := range def.Extensions {
+		merged.Extensions[key] = value
+	}
+
+	var pulled []string
+	var merge func(members []spec.Schema)
+	merge = func(members []spec.Schema) {
+		for i := range members {
+			member := &members[i]
+			if ref := member.Ref.String(); ref != "" {
+				refName := trimRefPrefix(ref)
+				refDef, ok := schemas.get(refName)
+				if !ok || visiting[refName] {
+					continue
 				}
-				if ref, ok := items["$ref"].(string); ok {
-					refName := strings.TrimPrefix(ref, "#/components/schemas/")
-					if refDef, ok := allSchemas[refName].(map[string]interface{}); ok {
-						itemField.Type = "object"
-						itemField.Properties = extractFields(refDef, path+"[*]", allSchemas)
-					}
+				visiting[refName] = true
+				pulled = append(pulled, refName)
+				member = refDef
+			}
+
+			for name, p := range member.Properties {
+				if _, ok := merged.Properties[name]; !ok {
+					merged.Properties[name] = p
 				}
-				field.Items = &itemField
 			}
+			merged.Required = append(merged.Required, member.Required...)
+			for key, value := range member.Extensions {
+				if _, ok := merged.Extensions[key]; !ok {
+					merged.Extensions[key] = value
+				}
+			}
+			if len(merged.Type) == 0 {
+				merged.Type = member.Type
+			}
+			merged.Nullable = merged.Nullable || member.Nullable
+			if merged.Format == "" {
+				merged.Format = member.Format
+			}
+			if merged.Description == "" {
+				merged.Description = member.Description
+			}
+			if merged.Default == nil {
+				merged.Default = member.Default
+			}
+			if merged.Enum == nil {
+				merged.Enum = member.Enum
+			}
+			if merged.Pattern == "" {
+				merged.Pattern = member.Pattern
+			}
+			if merged.MinLength == nil {
+				merged.MinLength = member.MinLength
+			}
+			if merged.MaxLength == nil {
+				merged.MaxLength = member.MaxLength
+			}
+			if merged.Minimum == nil {
+				merged.Minimum = member.Minimum
+			}
+			if merged.Maximum == nil {
+				merged.Maximum = member.Maximum
+			}
+			if merged.MinItems == nil {
+				merged.MinItems = member.MinItems
+			}
+			if merged.Items == nil {
+				merged.Items = member.Items
+			}
+			if merged.AdditionalProperties == nil {
+				merged.AdditionalProperties = member.AdditionalProperties
+			}
+			if len(merged.OneOf) == 0 {
+				merged.OneOf = member.OneOf
+			}
+			if len(merged.AnyOf) == 0 {
+				merged.AnyOf = member.AnyOf
+			}
+			merge(member.AllOf)
 		}
+	}
+	merge(def.AllOf)
 
-		fields = append(fields, field)
+	// Merged members with properties make an object even without a type
+	if len(merged.Type) == 0 && len(merged.Properties) > 0 {
+		merged.Type = spec.StringOrArray{"object"}
 	}
 
-	return fields
+	return &merged, func() {
+		for _, refName := range pulled {
+			delete(visiting, refName)
+		}
+	}
+}
+
+// schemaType returns the type of def and whether it is nullable. A type list
+// such as ["string", "null"] (JSON Schema's way of saying nullable) yields its
+// first non-null type.
+func schemaType(def *spec.Schema) (string, bool) {
+	nullable := def.Nullable
+	typ := ""
+	for _, t := range def.Type {
+		if t == "null" {
+			nullable = true
+		} else if typ == "" {
+			typ = t
+		}
+	}
+	return typ, nullable
+}
+
+// itemsSchema returns the schema of the items of an array, or nil if it has none
+func itemsSchema(def *spec.Schema) *spec.Schema {
+	if def.Items == nil {
+		return nil
+	}
+	if def.Items.Schema != nil {
+		return def.Items.Schema
+	}
+	if len(def.Items.Schemas) > 0 {
+		return &def.Items.Schemas[0]
+	}
+	return nil
+}
+
+// schemaRef returns the component schema referenced by def, either directly via
+// $ref or wrapped in a single-element allOf (as Kubernetes OpenAPI v3 does for
+// properties that carry their own description or default)
+func schemaRef(def *spec.Schema) string {
+	if ref := def.Ref.String(); ref != "" {
+		return trimRefPrefix(ref)
+	}
+	if len(def.AllOf) == 1 {
+		if ref := def.AllOf[0].Ref.String(); ref != "" {
+			return trimRefPrefix(ref)
+		}
+	}
+	return ""
+}
+
+// trimRefPrefix strips the OpenAPI v3 (components) or v2 (definitions) prefix from a $ref
+func trimRefPrefix(ref string) string {
+	ref = strings.TrimPrefix(ref, "#/components/schemas/")
+	return strings.TrimPrefix(ref, "#/definitions/")
 }
 
 // createBasicSchema creates a basic schema with common Kubernetes resource fields
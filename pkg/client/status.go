@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// crdGVR is the CustomResourceDefinition resource
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// PrinterColumn is an additional printer column declared by a CRD
+type PrinterColumn struct {
+	Name     string
+	JSONPath string
+	Type     string
+	Priority int64
+}
+
+// GetPrinterColumns returns the additionalPrinterColumns a CRD declares for gvr's
+// version. Built-in resources (and CRDs without columns) return nil.
+func (c *K8sClient) GetPrinterColumns(gvr schema.GroupVersionResource) ([]PrinterColumn, error) {
+	if gvr.Group == "" || !strings.Contains(gvr.Group, ".") {
+		return nil, nil
+	}
+
+	crd, err := c.GetResource(crdGVR, "", gvr.Resource+"."+gvr.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok || version["name"] != gvr.Version {
+			continue
+		}
+		columns, _, _ := unstructured.NestedSlice(version, "additionalPrinterColumns")
+		var result []PrinterColumn
+		for _, col := range columns {
+			colMap, ok := col.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			pc := PrinterColumn{}
+			pc.Name, _, _ = unstructured.NestedString(colMap, "name")
+			pc.JSONPath, _, _ = unstructured.NestedString(colMap, "jsonPath")
+			pc.Type, _, _ = unstructured.NestedString(colMap, "type")
+			pc.Priority, _, _ = unstructured.NestedInt64(colMap, "priority")
+			if pc.Name != "" && pc.JSONPath != "" {
+				result = append(result, pc)
+			}
+		}
+		return result, nil
+	}
+	return nil, nil
+}
+
+// WaitForStatus polls an object (bypassing the cache) until it has a non-empty
+// .status or the timeout expires, returning the latest version of the object
+func (c *K8sClient) WaitForStatus(gvr schema.GroupVersionResource, namespace, name string, timeout time.Duration) (*unstructured.Unstructured, error) {
+	return c.WaitFor(gvr, namespace, name, timeout, func(obj *unstructured.Unstructured) bool {
+		status, ok := obj.Object["status"].(map[string]interface{})
+		return ok && len(status) > 0
+	})
+}
+
+// WaitFor polls an object (bypassing the cache) until ready returns true for it
+// or the timeout expires, returning the latest version of the object
+func (c *K8sClient) WaitFor(gvr schema.GroupVersionResource, namespace, name string, timeout time.Duration, ready func(*unstructured.Unstructured) bool) (*unstructured.Unstructured, error) {
+	if c.offline != nil {
+		return nil, ErrOffline
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var latest *unstructured.Unstructured
+	for {
+		obj, err := c.resourceInterface(gvr, namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			latest = obj
+			c.storeCachedObject(gvr, namespace, name, obj.DeepCopy(), nil)
+			if ready(obj) {
+				return obj, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if latest == nil {
+				return nil, fmt.Errorf("timed out waiting for %s/%s", gvr.Resource, name)
+			}
+			return latest, nil
+		case <-ticker.C:
+		}
+	}
+}
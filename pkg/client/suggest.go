@@ -0,0 +1,92 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the number of did-you-mean suggestions for an unknown resource type
+const maxSuggestions = 3
+
+// suggestResourceTypes returns the resources whose name, kind or short names are
+// closest to an unknown resource type, formatted as resource.group
+func suggestResourceTypes(name string, resources []ResourceInfo) []string {
+	name = strings.ToLower(name)
+	maxDistance := len(name)/3 + 1
+
+	type candidate struct {
+		display  string
+		distance int
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+
+	for _, r := range resources {
+		display := r.Name
+		if r.Group != "" {
+			display = fmt.Sprintf("%s.%s", r.Name, r.Group)
+		}
+		if seen[display] {
+			continue
+		}
+
+		best := -1
+		names := append([]string{strings.ToLower(r.Name), strings.ToLower(r.Kind)}, r.ShortNames...)
+		for _, n := range names {
+			if d := editDistance(name, n); best < 0 || d < best {
+				best = d
+			}
+		}
+		if best > maxDistance {
+			continue
+		}
+		seen[display] = true
+		candidates = append(candidates, candidate{display: display, distance: best})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].display < candidates[j].display
+	})
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].display)
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// containsShortName reports whether name is one of shortNames
+func containsShortName(shortNames []string, name string) bool {
+	for _, s := range shortNames {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceVersions are the versions a resource type is served in
+type ResourceVersions struct {
+	Served    []string // Served versions, in the server's order of preference
+	Preferred string   // Version used when none is given
+	Storage   string   // Version objects are stored in, when known (CRDs)
+}
+
+// kubeVersion matches API versions such as v1, v2beta1 and v1alpha3
+var kubeVersion = regexp.MustCompile(`^v\d+((alpha|beta)\d+)?$`)
+
+// RequestedVersion returns the version in a resource type given in kubectl's
+// fully qualified form, resource.version.group (e.g., "cronjobs.v1beta1.batch"
+// or "pods.v1" for the core group), and the resource type without it. The
+// version is empty when the resource type has none.
+func RequestedVersion(resourceType string) (version, rest string) {
+	parts := strings.SplitN(resourceType, ".", 3)
+	if len(parts) < 2 || !kubeVersion.MatchString(parts[1]) {
+		return "", resourceType
+	}
+	if len(parts) == 2 {
+		return parts[1], parts[0]
+	}
+	return parts[1], parts[0] + "." + parts[2]
+}
+
+// ResourceVersions returns the versions gvr's resource type is served in
+func (c *K8sClient) ResourceVersions(gvr schema.GroupVersionResource) (*ResourceVersions, error) {
+	gr := gvr.GroupResource()
+	if c.offline != nil {
+		return c.offline.resourceVersions(gr)
+	}
+	if c.crds != nil && c.crds.has(gvr) {
+		return c.crds.resourceVersions(gr)
+	}
+
+	groups, err := c.discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+	versions := &ResourceVersions{}
+	for _, g := range groups.Groups {
+		if g.Name != gvr.Group {
+			continue
+		}
+		for _, v := range g.Versions {
+			resourceList, err := c.discoveryClient.ServerResourcesForGroupVersion(v.GroupVersion)
+			if err != nil {
+				continue
+			}
+			for _, r := range resourcesFromList(resourceList) {
+				if r.Name == gvr.Resource {
+					versions.Served = append(versions.Served, v.Version)
+					break
+				}
+			}
+		}
+		versions.Preferred = g.PreferredVersion.Version
+	}
+	if len(versions.Served) == 0 {
+		return nil, fmt.Errorf("no served versions found for %s", gr)
+	}
+	// The preferred version of the group may not serve this resource
+	if !slices.Contains(versions.Served, versions.Preferred) {
+		versions.Preferred = versions.Served[0]
+	}
+	versions.Storage = c.crdStorageVersion(gr)
+	return versions, nil
+}
+
+// crdStorageVersion returns the storage version of a custom resource from its
+// CRD, or "" for built-in types and when the CRD can't be read
+func (c *K8sClient) crdStorageVersion(gr schema.GroupResource) string {
+	if gr.Group == "" || !strings.Contains(gr.Group, ".") {
+		return ""
+	}
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	crd, err := c.GetResource(crds, "", gr.String())
+	if err != nil {
+		return ""
+	}
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, _ := v.(map[string]interface{})
+		if version["storage"] == true {
+			name, _ := version["name"].(string)
+			return name
+		}
+	}
+	return ""
+}
+
+// resourceVersions returns the versions of a resource type in the catalog
+func (oc *offlineCatalog) resourceVersions(gr schema.GroupResource) (*ResourceVersions, error) {
+	versions, ok := oc.versions[gr]
+	if !ok {
+		return nil, fmt.Errorf("no served versions found for %s", gr)
+	}
+	return versions, nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+var (
+	answersFile       string
+	recordAnswersFile string
+	answers           *prompt.AnswerPrompter // Set with --answers
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&answersFile, "answers", "",
+		"answer the interactive questions from a YAML file of field paths (or question names) and answers; in a terminal the questions it doesn't answer are asked, otherwise they take their defaults (e.g., --answers=answers.yaml)")
+	rootCmd.Flags().StringVar(&recordAnswersFile, "record-answers", "",
+		"save the answers to the interactive questions to a YAML file for --answers to replay (e.g., --record-answers=answers.yaml)")
+}
+
+// loadAnswers makes the --answers file answer the questions, and starts
+// recording them for --record-answers
+func loadAnswers() error {
+	if recordAnswersFile != "" {
+		prompt.RecordAnswers()
+	}
+	if answersFile == "" {
+		return nil
+	}
+	var err error
+	answers, err = prompt.LoadAnswers(answersFile)
+	if err != nil {
+		return err
+	}
+	// In a terminal, the user answers what the file doesn't
+	if prompt.IsTerminal() {
+		answers.AskMissing(prompt.ActivePrompter())
+	}
+	prompt.SetPrompter(answers)
+	return nil
+}
+
+// saveRecordedAnswers writes the answers of the run to the --record-answers
+// file, also when it failed, so that replaying them picks up where it ended
+func saveRecordedAnswers() {
+	if recordAnswersFile == "" {
+		return
+	}
+	saved, err := prompt.WriteRecordedAnswers(recordAnswersFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %v\n"), err)
+		return
+	}
+	if saved {
+		fmt.Fprintf(os.Stderr, i18n.T("Wrote the answers to %s\n"), recordAnswersFile)
+	}
+}
+
+// warnUnusedAnswers lists the answers no question was asked for, which are
+// typically misspelled keys or fields also given with --set
+func warnUnusedAnswers() {
+	if answers == nil {
+		return
+	}
+	if unused := answers.Unused(); len(unused) > 0 {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %s has answers to questions that weren't asked: %s\n"),
+			answersFile, strings.Join(unused, ", "))
+	}
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var applyFiles []string
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Create the resources of previously generated manifests",
+	Long: `Create the resources of previously generated manifests, so generating a manifest
+and submitting it can be separate steps (e.g., reviewed in between).
+
+Files may hold several YAML documents separated by "---", JSON objects or a v1 List,
+as printed by --dry-run and -o. Each object goes through the same checks, confirmations,
+history and audit log as creating it directly. Objects without a namespace are created
+in the namespace of -n or the kubeconfig context.
+
+Examples:
+  # Generate a manifest, then create it
+  kubectl create-resource deployment web --image=nginx -o yaml > web.yaml
+  kubectl create-resource apply -f web.yaml
+
+  # Pipe the generated manifest straight in
+  kubectl create-resource configmap settings --set data.mode=fast -o yaml | kubectl create-resource apply -f -
+
+  # Check the objects with a server-side dry-run
+  kubectl create-resource apply -f web.yaml --dry-run=server`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func init() {
+	applyCmd.Flags().StringArrayVarP(&applyFiles, "filename", "f", nil,
+		"manifest file to create the resources of, or - for stdin (repeatable)")
+	applyCmd.Flags().VarPF(dryRunValue{}, "dry-run", "",
+		"only print the manifests without creating them: client (the default for a bare --dry-run) or server").NoOptDefVal = "client"
+	applyCmd.Flags().StringVarP(&output, "output", "o", "",
+		"output format (yaml or json) - implies dry-run")
+	applyCmd.Flags().BoolVar(&continueOnError, "continue-on-error", false,
+		"keep creating the remaining objects after one fails")
+
+	rootCmd.AddCommand(applyCmd)
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	if len(applyFiles) == 0 {
+		return i18n.Errorf("-f is required, pass a manifest file or - for stdin")
+	}
+	if output != "" {
+		dryRun = true
+	}
+	namespaceExplicit = cmd.Flags().Changed("namespace")
+	collectedValues = nil
+	if err := validateOfflineFlags(cmd); err != nil {
+		return err
+	}
+
+	objs, err := readManifestFiles(applyFiles)
+	if err != nil {
+		return err
+	}
+	if len(objs) == 0 {
+		return i18n.Errorf("no objects found in %v", applyFiles)
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+	defaultNamespace := namespace
+	if !namespaceExplicit && !k8sClient.Offline() {
+		defaultNamespace = client.ContextNamespace(kubeconfig, k8sClient.ContextName())
+	}
+
+	failed := 0
+	for i, obj := range objs {
+		if err := applyObject(k8sClient, obj, defaultNamespace, i > 0); err != nil {
+			failed++
+			if !continueOnError {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v\n"), err)
+		}
+	}
+	if failed > 0 {
+		return i18n.Errorf("failed to create %d of %d objects", failed, len(objs))
+	}
+	return nil
+}
+
+// applyObject creates obj, or prints it with --dry-run, in its namespace or
+// defaultNamespace. separate is whether a dry-run output precedes it.
+func applyObject(k8sClient *client.K8sClient, obj *unstructured.Unstructured, defaultNamespace string, separate bool) error {
+	gvk := obj.GroupVersionKind()
+	ref := fmt.Sprintf("%s %s", gvk.Kind, obj.GetName())
+	gvr, err := k8sClient.ResourceForKind(gvk)
+	if err != nil {
+		return i18n.Errorf("failed to resolve the resource type of %s: %w", ref, err)
+	}
+
+	// The package-level namespace is what the checks and the create use
+	namespace = ""
+	if k8sClient.IsNamespaced(gvr) {
+		namespace = obj.GetNamespace()
+		switch {
+		case namespace == "":
+			namespace = defaultNamespace
+		case namespaceExplicit && namespace != defaultNamespace:
+			return i18n.Errorf("the namespace of %s (%s) does not match --namespace=%s", ref, namespace, defaultNamespace)
+		}
+	}
+	obj.SetNamespace(namespace)
+
+	if err := applyRequiredMetadata(gvr, obj, true); err != nil {
+		return err
+	}
+
+	if dryRun {
+		if separate && output != "json" {
+			fmt.Println("---")
+		}
+		return printDryRun(k8sClient, gvr, obj)
+	}
+
+	created, err := submitResource(k8sClient, gvr, obj)
+	if err != nil {
+		return i18n.Errorf("failed to create %s: %w", ref, err)
+	}
+	fmt.Printf(i18n.T("%s/%s created\n"), gvr.Resource, created.GetName())
+	return nil
+}
+
+// readManifestFiles reads the objects of the manifest files, - being stdin, and
+// checks they can be created
+func readManifestFiles(paths []string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	for _, path := range paths {
+		var r io.Reader = os.Stdin
+		if path != "-" {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, i18n.Errorf("failed to read manifest: %w", err)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		read, err := generator.ReadManifests(r)
+		if err != nil {
+			return nil, i18n.Errorf("invalid manifest %s: %w", path, err)
+		}
+		for _, obj := range read {
+			switch {
+			case obj.GetAPIVersion() == "" || obj.GetKind() == "":
+				return nil, i18n.Errorf("invalid manifest %s: apiVersion and kind are required", path)
+			case obj.GetName() == "":
+				return nil, i18n.Errorf("invalid manifest %s: metadata.name is required for %s", path, obj.GetKind())
+			}
+		}
+		objs = append(objs, read...)
+	}
+	return objs, nil
+}
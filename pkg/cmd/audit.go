@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	noAudit     bool
+	auditLines  int
+	auditFollow bool
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit log of creates, dry runs and deletes",
+	Long: `Inspect the audit log of creates, dry runs and deletes.
+
+Every operation is appended to audit.jsonl next to the config file with the local
+user, time, context, resource type, name and result. Pass --no-audit to skip it.`,
+}
+
+var auditTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Print the most recent audit records",
+	Long: `Print the most recent audit records, optionally following new ones.
+
+Examples:
+  # Show the last 20 operations
+  kubectl create-resource audit tail
+
+  # Keep printing operations as they happen
+  kubectl create-resource audit tail -f`,
+	Args: cobra.NoArgs,
+	RunE: runAuditTail,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noAudit, "no-audit", false,
+		"don't record operations in the local audit log")
+
+	auditTailCmd.Flags().IntVar(&auditLines, "lines", 20,
+		"number of records to print")
+	auditTailCmd.Flags().BoolVarP(&auditFollow, "follow", "f", false,
+		"keep printing records as they are appended")
+
+	auditCmd.AddCommand(auditTailCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+// recordAudit appends an operation on the object name of gvr to the audit log.
+// k8sClient is nil for operations that didn't need a cluster.
+func recordAudit(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, namespace, name, operation string, opErr error) {
+	if noAudit {
+		return
+	}
+	record := audit.Record{
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Namespace: namespace,
+		Name:      name,
+		Operation: operation,
+		Result:    audit.ResultSuccess,
+	}
+	if k8sClient != nil && !k8sClient.Offline() {
+		record.Context = clientContext(k8sClient)
+		record.Server = k8sClient.Server()
+		record.Impersonated = k8sClient.Impersonating()
+	}
+	if opErr != nil {
+		record.Result = audit.ResultError
+		record.Error = opErr.Error()
+	}
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	if err := audit.Append(audit.DefaultPath(), record); err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %v\n"), err)
+	}
+}
+
+func runAuditTail(cmd *cobra.Command, args []string) error {
+	if auditLines < 0 {
+		return i18n.Errorf("--lines must not be negative")
+	}
+	path := audit.DefaultPath()
+	records, offset, err := audit.Tail(path, auditLines)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 && !auditFollow {
+		fmt.Println(i18n.T("No operations in the audit log"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tUSER\tCLUSTER\tOPERATION\tRESOURCE\tNAMESPACE\tNAME\tRESULT")
+	for _, r := range records {
+		writeAuditRecord(w, r)
+	}
+	w.Flush()
+	if !auditFollow {
+		return nil
+	}
+
+	return audit.Follow(path, offset, time.Second, func(r audit.Record) {
+		writeAuditRecord(w, r)
+		w.Flush()
+	})
+}
+
+// writeAuditRecord writes a row of audit tail
+func writeAuditRecord(w io.Writer, r audit.Record) {
+	user := r.User
+	if r.Impersonated != "" {
+		user += " as " + r.Impersonated
+	}
+	cluster := r.Context
+	if cluster == "" {
+		cluster = r.Server
+	}
+	if cluster == "" {
+		cluster = "-"
+	}
+	namespace := r.Namespace
+	if namespace == "" {
+		namespace = "-"
+	}
+	result := r.Result
+	if r.Error != "" {
+		result += ": " + r.Error
+	}
+	gvr := schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		r.Time.Local().Format("2006-01-02 15:04:05"), user, cluster, r.Operation, formatGVR(gvr), namespace, r.Name, result)
+}
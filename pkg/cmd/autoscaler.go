@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Fields of HorizontalPodAutoscalers set by the horizontalpodautoscaler preset
+const (
+	scaleTargetPath = "spec.scaleTargetRef"
+	minReplicasPath = "spec.minReplicas"
+	maxReplicasPath = "spec.maxReplicas"
+	metricsPath     = "spec.metrics"
+)
+
+// autoscalers is the resource type of the horizontalpodautoscaler preset, for
+// the autoscaling/v2 API and its metrics
+var autoscalers = schema.GroupResource{Group: "autoscaling", Resource: "horizontalpodautoscalers"}
+
+// scaleTargetKind is a kind of workload HorizontalPodAutoscalers scale
+type scaleTargetKind struct {
+	kind  string
+	gvr   schema.GroupVersionResource
+	names []string // Names of the kind in --scale-target, the first in the list to pick from
+}
+
+var scaleTargetKinds = []scaleTargetKind{
+	{
+		kind:  "Deployment",
+		gvr:   schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		names: []string{"deployment", "deployments", "deploy"},
+	},
+	{
+		kind:  "StatefulSet",
+		gvr:   schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		names: []string{"statefulset", "statefulsets", "sts"},
+	},
+}
+
+// metricResources are the resources autoscalers scale on with the preset,
+// along with an average value per pod shown as an example
+var metricResources = []struct{ name, example string }{
+	{name: "cpu", example: "500m"},
+	{name: "memory", example: "512Mi"},
+}
+
+var (
+	scaleTarget  string
+	minReplicas  int
+	maxReplicas  int
+	cpuTarget    string
+	memoryTarget string
+)
+
+func init() {
+	typePresets[autoscalers] = preset{
+		flags:    []string{"scale-target", "min", "max", "cpu", "memory"},
+		versions: []string{"v2"},
+		fields:   []string{scaleTargetPath, minReplicasPath, maxReplicasPath, metricsPath},
+		prepare:  prepareAutoscaler,
+	}
+
+	rootCmd.Flags().StringVar(&scaleTarget, "scale-target", "",
+		"with horizontalpodautoscaler, the Deployment or StatefulSet to scale (e.g., --scale-target=deployment/web; picked from a list in a terminal)")
+	rootCmd.Flags().IntVar(&minReplicas, "min", 0,
+		"with horizontalpodautoscaler, the minimum number of replicas")
+	rootCmd.Flags().IntVar(&maxReplicas, "max", 0,
+		"with horizontalpodautoscaler, the maximum number of replicas")
+	rootCmd.Flags().StringVar(&cpuTarget, "cpu", "",
+		"with horizontalpodautoscaler, the CPU target, as a utilization of the requests (e.g., --cpu=80%) or an average value per pod (e.g., --cpu=500m)")
+	rootCmd.Flags().StringVar(&memoryTarget, "memory", "",
+		"with horizontalpodautoscaler, the memory target, as a utilization of the requests (e.g., --memory=75%) or an average value per pod (e.g., --memory=512Mi)")
+}
+
+// prepareAutoscaler sets the workload to scale, the replica bounds and the
+// metrics of the autoscaler from their flags, or asks for those not set yet.
+// The workload, picked from the Deployments and StatefulSets of the
+// namespace, and the maximum replicas are needed otherwise.
+func prepareAutoscaler(k8sClient *client.K8sClient, values *prompt.CollectedValues) error {
+	if values == nil {
+		return rejectPresetFlagsWithFrom(autoscalers)
+	}
+	ask := !example && prompt.CanPrompt()
+
+	var target map[string]interface{}
+	switch {
+	case scaleTarget != "":
+		ref, err := parseScaleTarget(scaleTarget)
+		if err != nil {
+			return i18n.Errorf("invalid --scale-target %q: %w", scaleTarget, err)
+		}
+		target = ref
+		values.Set(scaleTargetPath, ref, prompt.SourceFlag)
+	case values.Has(scaleTargetPath):
+	case ask:
+		ref, err := askScaleTarget(k8sClient)
+		if err != nil {
+			return err
+		}
+		target = ref
+		values.Set(scaleTargetPath, ref, prompt.SourcePrompt)
+	default:
+		return i18n.Errorf("a HorizontalPodAutoscaler needs the workload to scale, give --scale-target (e.g., deployment/web)")
+	}
+
+	least := 1
+	switch {
+	case presetFlagsSet["min"]:
+		if minReplicas < 1 {
+			return i18n.Errorf("invalid --min %d, must be at least 1", minReplicas)
+		}
+		least = minReplicas
+		values.Set(minReplicasPath, int64(minReplicas), prompt.SourceFlag)
+	case values.Has(minReplicasPath):
+	case ask:
+		n, err := askReplicas(minReplicasPath, i18n.T("Minimum replicas"), "1", 1)
+		if err != nil {
+			return err
+		}
+		least = n
+		values.Set(minReplicasPath, int64(n), prompt.SourcePrompt)
+	}
+
+	switch {
+	case presetFlagsSet["max"]:
+		if maxReplicas < least {
+			return i18n.Errorf("invalid --max %d, must be at least 1 and --min", maxReplicas)
+		}
+		values.Set(maxReplicasPath, int64(maxReplicas), prompt.SourceFlag)
+	case values.Has(maxReplicasPath):
+	case ask:
+		label := i18n.T("Maximum replicas")
+		if replicas, ok := targetReplicas(k8sClient, target); ok {
+			label = i18n.T("Maximum replicas (%s/%s has %d now)", strings.ToLower(fmt.Sprint(target["kind"])), target["name"], replicas)
+		}
+		n, err := askReplicas(maxReplicasPath, label, "", least)
+		if err != nil {
+			return err
+		}
+		values.Set(maxReplicasPath, int64(n), prompt.SourcePrompt)
+	default:
+		return i18n.Errorf("a HorizontalPodAutoscaler needs its maximum replicas, give --max")
+	}
+
+	var metrics []interface{}
+	switch {
+	case cpuTarget != "" || memoryTarget != "":
+		for _, m := range []struct{ resource, target string }{{"cpu", cpuTarget}, {"memory", memoryTarget}} {
+			if m.target == "" {
+				continue
+			}
+			metric, err := resourceMetric(m.resource, m.target)
+			if err != nil {
+				return i18n.Errorf("invalid --%s %q: %w", m.resource, m.target, err)
+			}
+			metrics = append(metrics, metric)
+		}
+		values.Set(metricsPath, metrics, prompt.SourceFlag)
+	case values.Has(metricsPath):
+	case ask:
+		var err error
+		if metrics, err = askMetrics(); err != nil {
+			return err
+		}
+		if len(metrics) > 0 {
+			values.Set(metricsPath, metrics, prompt.SourcePrompt)
+		}
+	}
+	warnMissingRequests(k8sClient, target, metrics)
+	return nil
+}
+
+// askScaleTarget asks for the workload to scale, picked from the Deployments
+// and StatefulSets of the namespace
+func askScaleTarget(k8sClient *client.K8sClient) (map[string]interface{}, error) {
+	var workloads []string
+	for _, t := range scaleTargetKinds {
+		names, _ := k8sClient.ListNames(t.gvr, namespace)
+		for _, name := range names {
+			workloads = append(workloads, t.names[0]+"/"+name)
+		}
+	}
+	workload, err := prompt.PickOrEnter(scaleTargetPath, i18n.T("Workload to scale (e.g., deployment/web)"), workloads, "", func(s string) error {
+		_, err := parseScaleTarget(s)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseScaleTarget(workload)
+}
+
+// parseScaleTarget parses a workload as kind/name (e.g., deployment/web) into
+// the scaleTargetRef of an autoscaler
+func parseScaleTarget(s string) (map[string]interface{}, error) {
+	kindName, name, ok := strings.Cut(s, "/")
+	if !ok || name == "" {
+		return nil, i18n.Errorf("must be kind/name, e.g., deployment/web")
+	}
+	for _, t := range scaleTargetKinds {
+		for _, n := range t.names {
+			if strings.EqualFold(kindName, n) || strings.EqualFold(kindName, t.kind) {
+				return map[string]interface{}{
+					"apiVersion": t.gvr.GroupVersion().String(),
+					"kind":       t.kind,
+					"name":       name,
+				}, nil
+			}
+		}
+	}
+	return nil, i18n.Errorf("%q is not a Deployment or StatefulSet", kindName)
+}
+
+// targetKind returns the kind of workload target refers to, if it's one the
+// preset knows
+func targetKind(target map[string]interface{}) (scaleTargetKind, bool) {
+	for _, t := range scaleTargetKinds {
+		if target != nil && target["kind"] == t.kind {
+			return t, true
+		}
+	}
+	return scaleTargetKind{}, false
+}
+
+// targetReplicas returns the replicas the workload of target has now, if it
+// can be read
+func targetReplicas(k8sClient *client.K8sClient, target map[string]interface{}) (int64, bool) {
+	t, ok := targetKind(target)
+	if !ok {
+		return 0, false
+	}
+	obj, err := k8sClient.GetResource(t.gvr, namespace, fmt.Sprint(target["name"]))
+	if err != nil {
+		return 0, false
+	}
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	return replicas, found
+}
+
+// askReplicas asks for a number of replicas of at least least
+func askReplicas(key, label, defaultVal string, least int) (int, error) {
+	text, err := prompt.AskText(key, label, defaultVal, true, func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < least {
+			return i18n.Errorf("must be a number of at least %d", least)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(text)
+}
+
+// askMetrics asks which resources to scale on and the target of each
+func askMetrics() ([]interface{}, error) {
+	names := make([]string, len(metricResources))
+	for i, r := range metricResources {
+		names[i] = r.name
+	}
+	picked, err := prompt.ChooseMany(metricsPath, i18n.T("Scale on"), names, []bool{true})
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []interface{}
+	for _, i := range picked {
+		r := metricResources[i]
+		label := i18n.T("Target %s, as a utilization of the requests (e.g., 80%%) or an average value per pod (e.g., %s)", r.name, r.example)
+		target, err := prompt.AskText(r.name, label, "80%", true, func(s string) error {
+			_, err := resourceMetric(r.name, s)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		metric, _ := resourceMetric(r.name, target)
+		metrics = append(metrics, metric)
+	}
+	return metrics, nil
+}
+
+// resourceMetric returns the metric of an autoscaler for a resource and its
+// target, a utilization percentage (e.g., 80%) or an average value per pod
+func resourceMetric(name, target string) (map[string]interface{}, error) {
+	metricTarget := map[string]interface{}{}
+	if percent, ok := strings.CutSuffix(target, "%"); ok {
+		n, err := strconv.Atoi(percent)
+		if err != nil || n < 1 {
+			return nil, i18n.Errorf("%q is not a utilization percentage (e.g., 80%%)", target)
+		}
+		metricTarget["type"] = "Utilization"
+		metricTarget["averageUtilization"] = int64(n)
+	} else {
+		if _, err := resource.ParseQuantity(target); err != nil {
+			return nil, i18n.Errorf("%q is neither a utilization percentage (e.g., 80%%) nor a quantity (e.g., 500m)", target)
+		}
+		metricTarget["type"] = "AverageValue"
+		metricTarget["averageValue"] = target
+	}
+	return map[string]interface{}{
+		"type":     "Resource",
+		"resource": map[string]interface{}{"name": name, "target": metricTarget},
+	}, nil
+}
+
+// warnMissingRequests warns when the containers of the workload to scale have
+// no request of a resource a utilization metric is relative to, which leaves
+// the autoscaler unable to compute it
+func warnMissingRequests(k8sClient *client.K8sClient, target map[string]interface{}, metrics []interface{}) {
+	t, ok := targetKind(target)
+	if !ok || k8sClient.Offline() {
+		return
+	}
+	var utilization []string
+	for _, m := range metrics {
+		targetType, _, _ := unstructured.NestedString(m.(map[string]interface{}), "resource", "target", "type")
+		if targetType == "Utilization" {
+			name, _, _ := unstructured.NestedString(m.(map[string]interface{}), "resource", "name")
+			utilization = append(utilization, name)
+		}
+	}
+	if len(utilization) == 0 {
+		return
+	}
+
+	obj, err := k8sClient.GetResource(t.gvr, namespace, fmt.Sprint(target["name"]))
+	if err != nil {
+		return
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	for _, name := range utilization {
+		for _, c := range containers {
+			container, _ := c.(map[string]interface{})
+			if _, found, _ := unstructured.NestedFieldNoCopy(container, "resources", "requests", name); !found {
+				fmt.Fprintf(os.Stderr, i18n.T("Warning: container %s of %s/%s has no %s request, which the %s utilization is relative to\n"),
+					container["name"], strings.ToLower(t.kind), target["name"], name, name)
+			}
+		}
+	}
+}
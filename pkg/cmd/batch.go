@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/batch"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/progress"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// retryBackoff is the delay before the first retry of a transient error
+const retryBackoff = 500 * time.Millisecond
+
+var (
+	count       int
+	parallelism int
+	createRate  float64
+	retries     int
+
+	// recordMu serializes the artifact, history and audit log writes of concurrent creates
+	recordMu sync.Mutex
+)
+
+func init() {
+	rootCmd.Flags().IntVar(&count, "count", 1,
+		"create this many copies of the resource, named <name>-1 to <name>-N")
+	rootCmd.Flags().IntVar(&parallelism, "parallelism", 1,
+		"with --count, --contexts or --all-contexts, create this many resources at a time")
+	rootCmd.Flags().Float64Var(&createRate, "rate", 0,
+		"with --count, --contexts or --all-contexts, start at most this many creates per second (0 for no limit)")
+	rootCmd.Flags().IntVar(&retries, "retries", 3,
+		"retry a create this many times after throttling (429) or timeouts")
+}
+
+// validateBatchFlags checks --count, --parallelism, --rate and --retries
+func validateBatchFlags() error {
+	switch {
+	case count < 1:
+		return i18n.Errorf("--count must be at least 1")
+	case parallelism < 1:
+		return i18n.Errorf("--parallelism must be at least 1")
+	case createRate < 0:
+		return i18n.Errorf("--rate must not be negative")
+	case retries < 0:
+		return i18n.Errorf("--retries must not be negative")
+	}
+	if count == 1 {
+		return nil
+	}
+	switch {
+	case dryRun:
+		return i18n.Errorf("--count cannot be used with --dry-run")
+	case len(targetContexts) > 0:
+		return i18n.Errorf("--count cannot be combined with --contexts or --all-contexts")
+	case artifactsDir != "":
+		return i18n.Errorf("--count cannot be used with --artifacts-dir")
+	case showEvents || showStatus:
+		return i18n.Errorf("--count cannot be used with --show-events or --status")
+	}
+	return nil
+}
+
+// batchOptions returns the worker pool settings from the command line
+func batchOptions(stopOnError bool) batch.Options {
+	return batch.Options{
+		Parallelism: parallelism,
+		Rate:        createRate,
+		Retries:     retries,
+		Backoff:     retryBackoff,
+		StopOnError: stopOnError,
+	}
+}
+
+// createCopies creates --count copies of manifest with the worker pool, after
+// checking the first one, and prints a result table
+func createCopies(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, manifest *unstructured.Unstructured) error {
+	if err := checkBeforeCreate(k8sClient, gvr, manifest); err != nil {
+		return i18n.Errorf("failed to create resource: %w", err)
+	}
+
+	copies := make([]*unstructured.Unstructured, count)
+	for i := range copies {
+		copies[i] = manifest.DeepCopy()
+		copies[i].SetName(fmt.Sprintf("%s-%d", manifest.GetName(), i+1))
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Creating %d %s with %d workers\n"), count, gvr.Resource, parallelism)
+	start := time.Now()
+	bar := progress.StartBar(i18n.T("Creating %s", gvr.Resource), count)
+	opts := batchOptions(!continueOnError)
+	opts.Done = func(batch.Result) { bar.Increment() }
+	results := batch.Run(count, opts, func(i int) error {
+		_, err := createChecked(k8sClient, gvr, copies[i])
+		return err
+	})
+	bar.Stop()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tRESULT\tATTEMPTS\tDURATION")
+	created, failed := 0, 0
+	for _, r := range results {
+		name := copies[r.Index].GetName()
+		switch {
+		case r.Skipped:
+			fmt.Fprintf(w, "%s\tskipped\t-\t-\n", name)
+		case r.Err != nil:
+			failed++
+			fmt.Fprintf(w, "%s\tfailed: %v\t%d\t%s\n", name, r.Err, r.Attempts, r.Duration.Round(time.Millisecond))
+		default:
+			created++
+			fmt.Fprintf(w, "%s\tcreated\t%d\t%s\n", name, r.Attempts, r.Duration.Round(time.Millisecond))
+		}
+	}
+	w.Flush()
+	fmt.Printf(i18n.T("\nCreated %d of %d %s in %s\n"), created, count, gvr.Resource, time.Since(start).Round(time.Millisecond))
+
+	if failed > 0 {
+		return i18n.Errorf("failed to create %d of %d %s", failed, count, gvr.Resource)
+	}
+	return nil
+}
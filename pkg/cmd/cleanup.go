@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/history"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	cleanupSelector      string
+	cleanupYes           bool
+	cleanupAllNamespaces bool
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup --selector <label-selector>",
+	Short: "Delete the resources matching a label selector, e.g. those created in a session",
+	Long: `Delete the resources of every type that can be listed and deleted whose labels match
+the selector, after listing them and asking for confirmation. This is meant for demo and
+test environments, to remove what was created for them in one go: every resource created
+in an invocation is labeled create-resource.kubectl.io/session with the ID it prints.
+
+Namespaced resources are looked up in the namespace (the context's unless -n is given),
+or in all namespaces with --all-namespaces; cluster-scoped ones always. Resources are
+deleted only if they are still the same objects (matched by UID), namespaced ones first.
+
+Examples:
+  # Delete everything an invocation created, in all namespaces
+  kubectl create-resource cleanup -A -l create-resource.kubectl.io/session=3f2a9c1e-6b0d-4e8a-9f57-1c2d3e4f5a6b
+
+  # Delete the resources labeled for a demo, after confirmation
+  kubectl create-resource cleanup --selector app=demo
+
+  # Delete the demo resources in all namespaces without asking
+  kubectl create-resource cleanup -l app=demo -A --yes`,
+	Args: cobra.NoArgs,
+	RunE: runCleanup,
+}
+
+func init() {
+	cleanupCmd.Flags().StringVarP(&cleanupSelector, "selector", "l", "",
+		"label selector of the resources to delete (required)")
+	cleanupCmd.Flags().BoolVarP(&cleanupYes, "yes", "y", false,
+		"delete without asking for confirmation")
+	cleanupCmd.Flags().BoolVarP(&cleanupAllNamespaces, "all-namespaces", "A", false,
+		"look for namespaced resources in all namespaces")
+
+	rootCmd.AddCommand(cleanupCmd)
+}
+
+// cleanupTarget is a resource matching the selector of cleanup
+type cleanupTarget struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+	uid       string
+}
+
+func (t cleanupTarget) String() string {
+	s := fmt.Sprintf("%s/%s", formatGVR(t.gvr), t.name)
+	if t.namespace != "" {
+		s += i18n.T(" in namespace %s", t.namespace)
+	}
+	return s
+}
+
+func runCleanup(cmd *cobra.Command, args []string) error {
+	if offline {
+		return i18n.Errorf("cleanup deletes from the cluster and cannot be used with --offline")
+	}
+	selector, err := labels.Parse(cleanupSelector)
+	if err != nil {
+		return i18n.Errorf("invalid --selector: %w", err)
+	}
+	if selector.Empty() {
+		return i18n.Errorf("--selector is required, cleanup does not delete everything")
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+	if cleanupAllNamespaces {
+		namespace = ""
+	} else if !cmd.Flags().Changed("namespace") {
+		namespace = client.ContextNamespace(kubeconfig, k8sClient.ContextName())
+	}
+
+	targets, err := findCleanupTargets(k8sClient, selector.String())
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Fprintf(os.Stderr, i18n.T("No resources match %s\n"), selector)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Resources matching %s:\n"), selector)
+	for _, t := range targets {
+		fmt.Fprintf(os.Stderr, "  %s\n", t)
+	}
+	if !cleanupYes {
+		if !prompt.CanPrompt() {
+			return i18n.Errorf("refusing to delete without confirmation, use --yes")
+		}
+		if !prompt.Confirm("delete", i18n.T("Delete %d resources", len(targets))) {
+			return i18n.Errorf("aborted")
+		}
+	}
+
+	// History entries of the deleted resources are marked deleted, like undo does
+	path := history.DefaultPath()
+	entries, _ := history.Load(path)
+	entryIDs := make(map[string]int)
+	for _, e := range entries {
+		if e.UID != "" && e.DeletedAt == nil {
+			entryIDs[e.UID] = e.ID
+		}
+	}
+
+	failed := 0
+	for _, t := range targets {
+		err := k8sClient.DeleteResource(t.gvr, t.namespace, t.name, types.UID(t.uid))
+		recordAudit(k8sClient, t.gvr, t.namespace, t.name, audit.OperationDelete, err)
+		switch {
+		case apierrors.IsNotFound(err):
+			fmt.Fprintf(os.Stderr, i18n.T("%s no longer exists\n"), t)
+		case apierrors.IsConflict(err):
+			fmt.Fprintf(os.Stderr, i18n.T("%s was replaced by another object with the same name, not deleting it\n"), t)
+			continue
+		case err != nil:
+			fmt.Fprintf(os.Stderr, i18n.T("failed to delete %s: %v\n"), t, err)
+			failed++
+			continue
+		default:
+			fmt.Printf(i18n.T("%s/%s deleted\n"), t.gvr.Resource, t.name)
+		}
+		if id, ok := entryIDs[t.uid]; ok {
+			if err := history.MarkDeleted(path, id); err != nil {
+				fmt.Fprintf(os.Stderr, i18n.T("Warning: %v\n"), err)
+			}
+		}
+	}
+	if failed > 0 {
+		return i18n.Errorf("failed to delete %d of %d resources", failed, len(targets))
+	}
+	return nil
+}
+
+// findCleanupTargets lists the resources matching selector, of the types that
+// can be listed and deleted, namespaced ones first so namespaces matching it
+// are deleted after what they contain
+func findCleanupTargets(k8sClient *client.K8sClient, selector string) ([]cleanupTarget, error) {
+	resources, err := k8sClient.DiscoverResources()
+	if err != nil {
+		return nil, i18n.Errorf("failed to discover resources: %w", err)
+	}
+
+	var namespaced, clusterScoped []cleanupTarget
+	for _, r := range resources {
+		if !slices.Contains(r.Verbs, "list") || !slices.Contains(r.Verbs, "delete") {
+			continue
+		}
+		gvr := schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Name}
+		ns := ""
+		if r.Namespaced {
+			ns = namespace
+		}
+		objs, err := k8sClient.ListSelected(gvr, ns, selector)
+		if apierrors.IsForbidden(err) || apierrors.IsMethodNotSupported(err) {
+			// What can't be listed can't be cleaned up either
+			continue
+		}
+		if err != nil {
+			return nil, i18n.Errorf("failed to list %s: %w", formatGVR(gvr), err)
+		}
+		for _, obj := range objs {
+			t := cleanupTarget{gvr: gvr, namespace: obj.GetNamespace(), name: obj.GetName(), uid: string(obj.GetUID())}
+			if r.Namespaced {
+				namespaced = append(namespaced, t)
+			} else {
+				clusterScoped = append(clusterScoped, t)
+			}
+		}
+	}
+	return append(namespaced, clusterScoped...), nil
+}
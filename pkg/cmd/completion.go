@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// registerCompletions adds the dynamic completions of resource types, object
+// names, namespaces, contexts and --set field paths. It runs from Execute, once
+// the flags of all commands are defined.
+func registerCompletions() {
+	rootCmd.ValidArgsFunction = completeResourceTypeArg
+	schemaCmd.ValidArgsFunction = completeResourceTypeArg
+	exportCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return completeObjectNames(args[0])
+		}
+		return completeResourceTypeArg(cmd, args, toComplete)
+	}
+	inspectCmd.ValidArgsFunction = exportCmd.ValidArgsFunction
+	diffInstancesCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 || len(args) == 2 {
+			return completeObjectNames(args[0])
+		}
+		return completeResourceTypeArg(cmd, args, toComplete)
+	}
+
+	rootCmd.RegisterFlagCompletionFunc("set", completeFieldPaths)
+	historyRerunCmd.RegisterFlagCompletionFunc("set", completeFieldPaths)
+	rootCmd.RegisterFlagCompletionFunc("explain", completeExplainPaths)
+	rootCmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		// Manifest files, by their relative or absolute path
+		if strings.HasPrefix(toComplete, ".") || strings.HasPrefix(toComplete, "/") {
+			return []string{"yaml", "yml", "json"}, cobra.ShellCompDirectiveFilterFileExt
+		}
+		// The pod template of another kind, as type/name
+		if sourceType, _, ok := strings.Cut(toComplete, "/"); ok {
+			names, directive := completeObjectNames(sourceType)
+			for i, n := range names {
+				names[i] = sourceType + "/" + n
+			}
+			return names, directive
+		}
+		return completeObjectNames(args[0])
+	})
+	rootCmd.RegisterFlagCompletionFunc("namespace", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeNames(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"})
+	})
+	for _, flag := range []string{"context", "contexts"} {
+		rootCmd.RegisterFlagCompletionFunc(flag, completeContexts)
+	}
+	rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"yaml", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("name-suffix", cobra.FixedCompletions([]string{"random", "timestamp", "gitsha"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// completionClient creates a client for completions; their stderr is discarded by the shell scripts
+func completionClient() (*client.K8sClient, bool) {
+	k8sClient, err := newClient()
+	if err != nil {
+		return nil, false
+	}
+	return k8sClient, true
+}
+
+// completeResourceTypeArg completes the resource type as the first argument
+func completeResourceTypeArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	k8sClient, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	resources, err := k8sClient.DiscoverResources()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, r := range resources {
+		if r.Group == "" {
+			completions = append(completions, r.Name+"\t"+r.Kind)
+		} else {
+			completions = append(completions, r.Name+"."+r.Group+"\t"+r.Kind)
+			completions = append(completions, r.Name+"\t"+r.Kind+" ("+r.Group+")")
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeObjectNames completes the names of existing objects of resourceType
+func completeObjectNames(resourceType string) ([]string, cobra.ShellCompDirective) {
+	k8sClient, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	gvr, err := k8sClient.ResolveResourceType(resourceType)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, err := k8sClient.ListNames(gvr, namespace)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeNames completes the names of the objects of gvr
+func completeNames(gvr schema.GroupVersionResource) ([]string, cobra.ShellCompDirective) {
+	k8sClient, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, err := k8sClient.ListNames(gvr, "")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContexts completes kubeconfig context names
+func completeContexts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	contexts, err := client.KubeconfigContexts(kubeconfig)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return contexts, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFieldPaths completes --set with the field paths of the resource type
+// argument, followed by "="
+func completeFieldPaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.Contains(toComplete, "=") {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	k8sClient, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	gvr, err := k8sClient.ResolveResourceType(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	resourceSchema, err := k8sClient.GetResourceSchema(gvr)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	var walk func(fields []client.FieldSchema)
+	walk = func(fields []client.FieldSchema) {
+		for _, f := range fields {
+			if f.Type != "object" && f.Type != "array" && strings.HasPrefix(f.Path, toComplete) {
+				completion := f.Path + "="
+				if f.Type != "" {
+					completion += "\t" + f.Type
+				}
+				completions = append(completions, completion)
+			}
+			// Only descend into what is being typed, schemas like pod templates are large
+			if strings.HasPrefix(f.Path, toComplete) || strings.HasPrefix(toComplete, f.Path+".") {
+				walk(f.Properties)
+			}
+		}
+	}
+	walk(resourceSchema.Fields)
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeExplainPaths completes --explain with the field paths of the resource
+// type argument, objects and arrays included
+func completeExplainPaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	k8sClient, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	gvr, err := k8sClient.ResolveResourceType(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	resourceSchema, err := k8sClient.GetResourceSchema(gvr)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	var walk func(fields []client.FieldSchema, prefix string)
+	walk = func(fields []client.FieldSchema, prefix string) {
+		for _, f := range fields {
+			path := prefix + f.Name
+			if strings.HasPrefix(path, toComplete) {
+				completion := path
+				if f.Type != "" {
+					completion += "\t" + f.Type
+				}
+				completions = append(completions, completion)
+			}
+			children := f.Properties
+			if f.Items != nil {
+				children = f.Items.Properties
+			}
+			// Only descend into what is being typed
+			if strings.HasPrefix(toComplete, path+".") {
+				walk(children, path+".")
+			}
+		}
+	}
+	walk(resourceSchema.Fields, "")
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
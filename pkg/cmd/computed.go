@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/config"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// computedPlaceholder matches the {{path}} placeholders of computed values
+var computedPlaceholder = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// applyComputedFields sets the fields the config computes for gvr from the
+// collected values, in order, so later fields can use earlier ones. Fields
+// that are already set are kept unless overridden, and fields whose sources
+// aren't set are left out.
+func applyComputedFields(gvr schema.GroupVersionResource, values *prompt.CollectedValues) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	for _, f := range cfg.ComputedFor(gvr) {
+		if values.Has(f.Path) && !f.Override {
+			continue
+		}
+		if f.From != "" {
+			copyComputedField(values, f)
+			continue
+		}
+		if val, ok := computedValue(values, f.Value); ok {
+			values.Set(f.Path, val, prompt.SourceDefault)
+		}
+	}
+	return nil
+}
+
+// copyComputedField copies the value at f.From, or the fields within it, to f.Path
+func copyComputedField(values *prompt.CollectedValues, f config.ComputedField) {
+	copied := map[string]interface{}{}
+	for path, val := range values.Values {
+		if path == f.From {
+			copied[f.Path] = val
+		} else if rest, ok := strings.CutPrefix(path, f.From); ok && (rest[0] == '.' || rest[0] == '[') {
+			copied[f.Path+rest] = val
+		}
+	}
+	if len(copied) == 0 {
+		return
+	}
+	// An overridden object is replaced rather than merged with
+	for path := range values.Values {
+		if path == f.Path || strings.HasPrefix(path, f.Path+".") || strings.HasPrefix(path, f.Path+"[") {
+			delete(values.Values, path)
+			delete(values.Sources, path)
+		}
+	}
+	for path, val := range copied {
+		values.Set(path, val, prompt.SourceDefault)
+	}
+}
+
+// computedValue fills the {{path}} placeholders of text with the collected
+// values. ok is false when one of them isn't set.
+func computedValue(values *prompt.CollectedValues, text string) (string, bool) {
+	ok := true
+	filled := computedPlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		path := computedPlaceholder.FindStringSubmatch(placeholder)[1]
+		val, set := values.Values[path]
+		if !set {
+			ok = false
+			return ""
+		}
+		return fmt.Sprint(val)
+	})
+	return filled, ok
+}
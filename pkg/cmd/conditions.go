@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// promptConditions parses the conditions the config puts on prompting for the
+// fields of gvr
+func promptConditions(gvr schema.GroupVersionResource) ([]prompt.Condition, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	var conditions []prompt.Condition
+	for _, c := range cfg.ConditionsFor(gvr) {
+		when, err := prompt.ParseExpression(c.When)
+		if err != nil {
+			return nil, i18n.Errorf("condition of %s: %w", c.Path, err)
+		}
+		conditions = append(conditions, prompt.Condition{Path: c.Path, When: when})
+	}
+	return conditions, nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// What to do when an object of the type already has the name (--on-name-conflict)
+const (
+	nameConflictPrompt = "prompt"
+	nameConflictSuffix = "suffix"
+	nameConflictFail   = "fail"
+)
+
+// maxNameSuffix is the highest -N suffix tried for a free name
+const maxNameSuffix = 100
+
+var onNameConflict string
+
+// numberedName matches names ending in a -N suffix, e.g. web-2
+var numberedName = regexp.MustCompile(`^(.*)-(\d+)$`)
+
+func init() {
+	rootCmd.Flags().StringVar(&onNameConflict, "on-name-conflict", nameConflictPrompt,
+		"when an object already has the name: prompt for another (fails without a terminal), suffix it with -2, -3, ..., or fail")
+}
+
+// validateNameConflictFlag checks the value of --on-name-conflict
+func validateNameConflictFlag() error {
+	switch onNameConflict {
+	case nameConflictPrompt, nameConflictSuffix, nameConflictFail:
+		return nil
+	}
+	return i18n.Errorf("invalid --on-name-conflict %q, must be prompt, suffix or fail", onNameConflict)
+}
+
+// resolveNameConflict returns the name to create the object of gvr under when
+// an object already has name, per --on-name-conflict. Names aren't checked
+// offline, nor for --count copies and other contexts, which are checked when
+// created.
+func resolveNameConflict(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, name string) (string, error) {
+	if name == "" || k8sClient.Offline() || count > 1 || len(targetContexts) > 0 {
+		return name, nil
+	}
+	taken, err := nameTaken(k8sClient, gvr, name)
+	if err != nil || !taken {
+		// Without get permission, the create reports the conflict
+		return name, nil
+	}
+
+	conflict := i18n.Errorf("%s %q already exists%s", gvr.Resource, name, inNamespace())
+	switch onNameConflict {
+	case nameConflictSuffix:
+		free, err := nextFreeName(k8sClient, gvr, name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(os.Stderr, i18n.T("%v, using name %s\n"), conflict, free)
+		return free, nil
+	case nameConflictPrompt:
+		if prompt.CanPrompt() && !prompt.Scripted() {
+			break
+		}
+		fallthrough
+	default:
+		return "", i18n.Errorf("%w (use --on-name-conflict=suffix to add -2, -3, ...)", conflict)
+	}
+
+	fmt.Println(i18n.T("%v, enter another name", conflict))
+	suggestion, err := nextFreeName(k8sClient, gvr, name)
+	if err != nil {
+		suggestion = ""
+	}
+	return prompt.PromptForName(prompt.NameRuleFor(gvr.Group, gvr.Resource), suggestion, func(input string) error {
+		if taken, err := nameTaken(k8sClient, gvr, input); err == nil && taken {
+			return i18n.Errorf("%s already exists", input)
+		}
+		return nil
+	})
+}
+
+// nameTaken reports whether an object of gvr has name in the namespace
+func nameTaken(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, name string) (bool, error) {
+	_, err := k8sClient.GetResource(gvr, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// nextFreeName returns the first of name-2, name-3, ... that no object of gvr
+// has, counting on from the number of names like web-2
+func nextFreeName(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, name string) (string, error) {
+	base, next := name, 2
+	if m := numberedName.FindStringSubmatch(name); m != nil {
+		n, err := strconv.Atoi(m[2])
+		if err == nil && n < maxNameSuffix {
+			base, next = m[1], n+1
+		}
+	}
+	rule := prompt.NameRuleFor(gvr.Group, gvr.Resource)
+	for n := next; n <= maxNameSuffix; n++ {
+		candidate := fmt.Sprintf("%s-%d", base, n)
+		if err := prompt.ValidateName(candidate, rule); err != nil {
+			return "", i18n.Errorf("name with suffix is invalid: %w", err)
+		}
+		taken, err := nameTaken(k8sClient, gvr, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", i18n.Errorf("%s-%d to %s-%d are all taken", base, next, base, maxNameSuffix)
+}
+
+// inNamespace returns " in namespace <namespace>" for namespaced resources
+func inNamespace() string {
+	if namespace == "" {
+		return ""
+	}
+	return i18n.T(" in namespace %s", namespace)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+// resolveContext checks --context and --pick-context and, with --pick-context,
+// lets the user choose the context to create in
+func resolveContext() error {
+	if kubeContext != "" && pickContext {
+		return i18n.Errorf("--context and --pick-context cannot be used together")
+	}
+	if (kubeContext != "" || pickContext) && len(targetContexts) > 0 {
+		return i18n.Errorf("--context and --pick-context cannot be combined with --contexts or --all-contexts")
+	}
+	if !pickContext {
+		return nil
+	}
+	if offline {
+		return i18n.Errorf("--pick-context needs a cluster and cannot be used with --offline")
+	}
+	if !prompt.CanPrompt() {
+		return i18n.Errorf("--pick-context needs a terminal, use --context instead")
+	}
+
+	contexts, err := client.KubeconfigContexts(kubeconfig)
+	if err != nil {
+		return err
+	}
+	if len(contexts) == 0 {
+		return i18n.Errorf("no contexts in kubeconfig")
+	}
+	current, _ := client.CurrentContext(kubeconfig)
+	kubeContext, err = prompt.PickContext(contexts, current)
+	if err != nil {
+		return i18n.Errorf("no context picked")
+	}
+	return nil
+}
+
+// showContext prints the context and cluster resources are about to be created
+// in at the start of an interactive run, so the wrong cluster is noticed before
+// answering any prompts
+func showContext(k8sClient *client.K8sClient) {
+	if k8sClient.Offline() || len(targetContexts) > 0 || !prompt.IsTerminal() {
+		return
+	}
+	contextName := clientContext(k8sClient)
+	if contextName == "" {
+		contextName = "(none)"
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Context: %s (cluster %s)\n"), contextName, k8sClient.Server())
+}
+
+// clientContext returns the kubeconfig context k8sClient was created for,
+// resolving the current context
+func clientContext(k8sClient *client.K8sClient) string {
+	if contextName := k8sClient.ContextName(); contextName != "" {
+		return contextName
+	}
+	contextName, _ := client.CurrentContext(kubeconfig)
+	return contextName
+}
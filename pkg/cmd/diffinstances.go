@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/spf13/cobra"
+)
+
+var diffAllFields bool
+
+// diffValueWidth is how much of a value diff-instances shows
+const diffValueWidth = 50
+
+var diffInstancesCmd = &cobra.Command{
+	Use:   "diff-instances <resource-type> <name-a> <name-b>",
+	Short: "Compare the fields of two existing resources of a type",
+	Long: `Compare two existing resources of a type field by field, listing each field path
+whose value differs with the value in each resource, to see why one custom resource
+works and another doesn't, or what to parameterize in a template.
+
+Only the spec is compared by default, or, for types without one (e.g., ConfigMaps),
+all fields but the metadata and status. --all compares the labels, annotations and
+status too.
+
+Examples:
+  # What differs between two queues
+  kubectl create-resource diff-instances queue team-a team-b
+
+  # Including the metadata and status
+  kubectl create-resource diff-instances deployment web web-canary --all`,
+	Args: cobra.ExactArgs(3),
+	RunE: runDiffInstances,
+}
+
+func init() {
+	diffInstancesCmd.Flags().BoolVar(&diffAllFields, "all", false,
+		"compare all fields, including the metadata and status, instead of the spec")
+
+	rootCmd.AddCommand(diffInstancesCmd)
+}
+
+func runDiffInstances(cmd *cobra.Command, args []string) error {
+	if offline {
+		return i18n.Errorf("diff-instances reads from the cluster and cannot be used with --offline")
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	gvr, err := k8sClient.ResolveResourceType(args[0])
+	if err != nil {
+		return i18n.Errorf("failed to resolve resource type %q: %w", args[0], err)
+	}
+	if !k8sClient.IsNamespaced(gvr) {
+		namespace = ""
+	}
+
+	a, err := k8sClient.GetResource(gvr, namespace, args[1])
+	if err != nil {
+		return i18n.Errorf("failed to get %s %q: %w", gvr.Resource, args[1], err)
+	}
+	b, err := k8sClient.GetResource(gvr, namespace, args[2])
+	if err != nil {
+		return i18n.Errorf("failed to get %s %q: %w", gvr.Resource, args[2], err)
+	}
+
+	_, specA := a.Object["spec"]
+	_, specB := b.Object["spec"]
+	compared := func(path string) bool {
+		switch {
+		case path == "metadata.name":
+			return false
+		case diffAllFields:
+			return true
+		case specA || specB:
+			return strings.HasPrefix(path, "spec.")
+		}
+		return !strings.HasPrefix(path, "metadata.")
+	}
+	fieldsA, fieldsB := inspectFields(a, diffAllFields), inspectFields(b, diffAllFields)
+	for _, fields := range []map[string]string{fieldsA, fieldsB} {
+		for path := range fields {
+			if !compared(path) {
+				delete(fields, path)
+			}
+		}
+	}
+
+	differences := diffFields(fieldsA, fieldsB)
+	if len(differences) == 0 {
+		fmt.Printf(i18n.T("No differences between %s and %s\n"), args[1], args[2])
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "PATH\t%s\t%s\n", strings.ToUpper(args[1]), strings.ToUpper(args[2]))
+	for _, path := range differences {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", path, diffValue(fieldsA, path), diffValue(fieldsB, path))
+	}
+	return w.Flush()
+}
+
+// diffFields returns the sorted paths whose values differ between a and b,
+// including those set in only one of them
+func diffFields(a, b map[string]string) []string {
+	var paths []string
+	for path, val := range a {
+		if other, ok := b[path]; !ok || other != val {
+			paths = append(paths, path)
+		}
+	}
+	for path := range b {
+		if _, ok := a[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// diffValue returns the value of path in fields, shortened to one column, or
+// a dash when it isn't set
+func diffValue(fields map[string]string, path string) string {
+	val, ok := fields[path]
+	if !ok {
+		return "-"
+	}
+	if len(val) > diffValueWidth {
+		val = val[:diffValueWidth-3] + "..."
+	}
+	return val
+}
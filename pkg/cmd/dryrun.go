@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/progress"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// serverDryRun is set by --dry-run=server (dryRun is set too)
+	serverDryRun  bool
+	showMutations bool
+)
+
+// mutationSkipPaths are set by the server on every object, so not worth reporting
+var mutationSkipPaths = []string{
+	"metadata.uid",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.managedFields",
+}
+
+// dryRunValue implements --dry-run[=client|server|none], like kubectl. A bare
+// --dry-run (or --dry-run=true) means client.
+type dryRunValue struct{}
+
+func (dryRunValue) String() string {
+	switch {
+	case serverDryRun:
+		return "server"
+	case dryRun:
+		return "client"
+	}
+	return "none"
+}
+
+func (dryRunValue) Set(s string) error {
+	switch s {
+	case "client", "true":
+		dryRun, serverDryRun = true, false
+	case "server":
+		dryRun, serverDryRun = true, true
+	case "none", "false":
+		dryRun, serverDryRun = false, false
+	default:
+		return i18n.Errorf("must be client, server or none")
+	}
+	return nil
+}
+
+func (dryRunValue) Type() string {
+	return "string"
+}
+
+// printDryRun prints manifest for --dry-run, after the pre-flight checks. With --dry-run=server the manifest
+// is first submitted with a server-side dry-run and the server's result is
+// printed instead; --show-mutations also lists what defaulting and mutating
+// webhooks changed. The objects of a preset's bundle follow it as generated.
+func printDryRun(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, manifest *unstructured.Unstructured, bundle ...*unstructured.Unstructured) error {
+	if !serverDryRun {
+		err := runPreflightChecks(k8sClient, gvr, manifest, false)
+		recordAudit(k8sClient, gvr, namespace, manifest.GetName(), audit.OperationDryRun, err)
+		if err != nil {
+			return err
+		}
+		return generator.PrintManifests(append([]*unstructured.Unstructured{manifest}, bundle...), output)
+	}
+
+	indicator := progress.Start(i18n.T("Waiting for the server dry-run"))
+	result, err := k8sClient.DryRunCreateResource(gvr, namespace, manifest.DeepCopy())
+	indicator.Stop()
+	recordAudit(k8sClient, gvr, namespace, manifest.GetName(), audit.OperationServerDryRun, err)
+	if err != nil {
+		return i18n.Errorf("server dry-run failed: %w", err)
+	}
+
+	if showMutations {
+		changes := generator.DiffObjects(manifest.Object, result.Object, mutationSkipPaths...)
+		if len(changes) == 0 {
+			fmt.Fprintln(os.Stderr, i18n.T("The server made no changes to the manifest"))
+		} else {
+			fmt.Fprintf(os.Stderr, i18n.T("The server changed %d fields (defaulting and mutating webhooks):\n"), len(changes))
+			for _, c := range changes {
+				fmt.Fprintf(os.Stderr, "  %s\n", generator.FormatChange(c))
+			}
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+
+	format := output
+	if format == "" {
+		format = "yaml"
+	}
+	return generator.PrintManifests(append([]*unstructured.Unstructured{result}, bundle...), format)
+}
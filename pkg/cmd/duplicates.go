@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/preflight"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var checkDuplicates bool
+
+func init() {
+	rootCmd.Flags().BoolVar(&checkDuplicates, "check-duplicates", false,
+		"before creating, look for resources of the type in the namespace with an identical spec and ask before creating another")
+}
+
+// checkDuplicateSpec looks for existing objects with the spec of obj. The spec
+// is compared as written and as the server would default it (with a server-side
+// dry-run), since existing objects carry their defaults. When enforce is set,
+// creating a duplicate needs a confirmation; otherwise (dry-run) it is a warning.
+func checkDuplicateSpec(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, enforce bool) error {
+	if !checkDuplicates || k8sClient.Offline() {
+		return nil
+	}
+
+	hashes := []string{preflight.SpecHash(obj)}
+	if defaulted, err := k8sClient.DryRunCreateResource(gvr, namespace, obj.DeepCopy()); err == nil {
+		hashes = append(hashes, preflight.SpecHash(defaulted))
+	}
+	duplicates, err := preflight.FindDuplicates(k8sClient, gvr, namespace, hashes...)
+	if err != nil {
+		if !apierrors.IsForbidden(err) {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: could not check for duplicates: %v\n"), err)
+		}
+		return nil
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	existing := strings.Join(duplicates, ", ")
+	fmt.Fprintf(os.Stderr, i18n.T("Warning: %s %s is identical to existing %s\n"), obj.GetKind(), obj.GetName(), existing)
+	if !enforce {
+		return nil
+	}
+	if !prompt.CanPrompt() {
+		return i18n.Errorf("%s %s is identical to existing %s; not created (drop --check-duplicates to create it anyway)", obj.GetKind(), obj.GetName(), existing)
+	}
+	if !prompt.Confirm("create-anyway", i18n.T("Create anyway")) {
+		return i18n.Errorf("aborted, %s %s would duplicate %s", obj.GetKind(), obj.GetName(), existing)
+	}
+	return nil
+}
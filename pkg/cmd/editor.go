@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// editorCommand overrides the editor with --editor
+var editorCommand string
+
+// maxDescribedFields caps the field descriptions in the editor header
+const maxDescribedFields = 40
+
+const editHeader = `# Please edit the object below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the creation. If an error occurs while saving this
+# file will be reopened with the relevant failures.
+#
+`
+
+// editManifest opens obj in the editor with instructions and field descriptions,
+// like kubectl edit. When the saved file doesn't parse or isn't valid, the editor
+// is reopened with the error so the edits aren't lost.
+func editManifest(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	yamlBytes, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return nil, i18n.Errorf("failed to marshal template: %w", err)
+	}
+
+	var described []string
+	if resourceSchema, err := k8sClient.GetResourceSchema(gvr); err == nil {
+		described = describeFields(resourceSchema.Fields, obj.Object)
+	}
+
+	tmpFile, err := os.CreateTemp("", "kubectl-create-resource-*.yaml")
+	if err != nil {
+		return nil, i18n.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+	keepFile := false
+	defer func() {
+		if !keepFile {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	editor, err := getEditor()
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	var lastContent []byte
+	for {
+		var buf bytes.Buffer
+		buf.WriteString(editHeader)
+		if lastErr != nil {
+			buf.WriteString("# The manifest could not be used:\n")
+			for _, line := range strings.Split(strings.TrimSpace(lastErr.Error()), "\n") {
+				buf.WriteString("#   " + line + "\n")
+			}
+			buf.WriteString("#\n")
+		}
+		if len(described) > 0 {
+			fmt.Fprintf(&buf, "# Fields of %s:\n", obj.GetKind())
+			for _, line := range described {
+				buf.WriteString("#   " + line + "\n")
+			}
+			buf.WriteString("#\n")
+		}
+		buf.Write(yamlBytes)
+
+		if err := os.WriteFile(tmpPath, buf.Bytes(), 0o600); err != nil {
+			return nil, i18n.Errorf("failed to write temp file: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, i18n.T("Opening %s in %s...\n"), tmpPath, editor[0])
+		cmd := exec.Command(editor[0], append(editor[1:], tmpPath)...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, i18n.Errorf("editor exited with error: %w", err)
+		}
+
+		editedBytes, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return nil, i18n.Errorf("failed to read edited file: %w", err)
+		}
+		content := stripComments(editedBytes)
+		if len(bytes.TrimSpace(content)) == 0 {
+			return nil, i18n.Errorf("edit cancelled, no resource created")
+		}
+
+		// Saving the same invalid content again gives up, keeping the edits
+		if lastErr != nil && bytes.Equal(content, lastContent) {
+			keepFile = true
+			return nil, i18n.Errorf("%w\nyour edits were kept in %s", lastErr, tmpPath)
+		}
+		lastContent = content
+		yamlBytes = content
+
+		edited, err := parseEditedManifest(k8sClient, gvr, content)
+		if err != nil {
+			lastErr = err
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v\nReopening the editor...\n"), err)
+			continue
+		}
+		return edited, nil
+	}
+}
+
+// getEditor returns the editor command and its arguments, from --editor,
+// $KUBE_EDITOR, $EDITOR or $VISUAL like kubectl, or the first editor of the
+// platform found (vim, vi or nano; notepad on Windows).
+// The variables are split into words like a shell would, so "code --wait" works.
+func getEditor() ([]string, error) {
+	source, value := "--editor", editorCommand
+	if value == "" {
+		for _, env := range []string{"KUBE_EDITOR", "EDITOR", "VISUAL"} {
+			if v := os.Getenv(env); strings.TrimSpace(v) != "" {
+				source, value = "$"+env, v
+				break
+			}
+		}
+	}
+	if value != "" {
+		words, err := splitShellWords(value)
+		if err != nil {
+			return nil, i18n.Errorf("invalid editor in %s: %w", source, err)
+		}
+		if len(words) == 0 {
+			return nil, i18n.Errorf("invalid editor in %s: empty command", source)
+		}
+		return words, nil
+	}
+
+	// Try common editors of the platform
+	for _, editor := range fallbackEditors {
+		if _, err := exec.LookPath(editor); err == nil {
+			return []string{editor}, nil
+		}
+	}
+	return nil, i18n.Errorf("no editor found, set $KUBE_EDITOR or $EDITOR or use --editor")
+}
+
+// splitShellWords splits s into words like a POSIX shell, honoring single and
+// double quotes and (except on Windows) backslash escapes, without expanding anything
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && editorEscapes:
+			// Inside double quotes a backslash only escapes a few characters,
+			// but editor paths rarely care about the difference
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if escaped {
+		return nil, i18n.Errorf("trailing backslash")
+	}
+	if quote != 0 {
+		return nil, i18n.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// parseEditedManifest parses and validates the saved editor content. Unless
+// offline, the manifest is checked with a server-side dry-run; only rejections
+// of the manifest itself count, not connection or permission errors.
+func parseEditedManifest(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, content []byte) (*unstructured.Unstructured, error) {
+	var edited unstructured.Unstructured
+	if err := yaml.Unmarshal(content, &edited.Object); err != nil {
+		return nil, i18n.Errorf("invalid YAML: %w", err)
+	}
+	if edited.Object == nil {
+		return nil, i18n.Errorf("the manifest is not an object")
+	}
+	if edited.GetAPIVersion() == "" || edited.GetKind() == "" {
+		return nil, i18n.Errorf("apiVersion and kind are required")
+	}
+	if edited.GetName() == "" && edited.GetGenerateName() == "" {
+		return nil, i18n.Errorf("metadata.name is required")
+	}
+	if edited.GetName() != "" {
+		if err := prompt.ValidateName(edited.GetName(), prompt.NameRuleFor(gvr.Group, gvr.Resource)); err != nil {
+			return nil, err
+		}
+	}
+
+	if !offline {
+		_, err := k8sClient.DryRunCreateResource(gvr, namespace, edited.DeepCopy())
+		if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+			return nil, err
+		}
+	}
+	return &edited, nil
+}
+
+// editFragment opens content, the value of the free-form field at path, in the
+// editor and returns it as saved, without comments
+func editFragment(path string, content []byte) ([]byte, error) {
+	header := fmt.Sprintf("# Enter the value of %s as YAML or JSON. Lines beginning with a '#'\n", path) +
+		"# will be ignored, and an empty file leaves the field unset.\n#\n"
+	edited, err := editTempFile("kubectl-create-resource-*.yaml", append([]byte(header), content...))
+	if err != nil {
+		return nil, err
+	}
+	return stripComments(edited), nil
+}
+
+// editText opens content, the text value of the field at path, in the editor
+// and returns it as saved. There's no header: '#' lines of scripts are content.
+func editText(path string, content []byte) ([]byte, error) {
+	return editTempFile("kubectl-create-resource-*.txt", content)
+}
+
+// editTempFile writes content to a temp file named after pattern, opens it in
+// the editor and returns the file as saved
+func editTempFile(pattern string, content []byte) ([]byte, error) {
+	editor, err := getEditor()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, i18n.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	tmpFile.Write(content)
+	if err := tmpFile.Close(); err != nil {
+		return nil, i18n.Errorf("failed to write temp file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Opening %s in %s...\n"), tmpPath, editor[0])
+	cmd := exec.Command(editor[0], append(editor[1:], tmpPath)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, i18n.Errorf("editor exited with error: %w", err)
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return nil, i18n.Errorf("failed to read edited file: %w", err)
+	}
+	return edited, nil
+}
+
+// stripComments removes the lines beginning with '#', like kubectl edit
+func stripComments(data []byte) []byte {
+	var out bytes.Buffer
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("#")) {
+			continue
+		}
+		out.Write(line)
+	}
+	return out.Bytes()
+}
+
+// describeFields returns "path (type): description" lines for the schema fields
+// set in obj, skipping metadata
+func describeFields(fields []client.FieldSchema, obj map[string]interface{}) []string {
+	var lines []string
+	var walk func(fields []client.FieldSchema, values map[string]interface{})
+	walk = func(fields []client.FieldSchema, values map[string]interface{}) {
+		for _, f := range fields {
+			if len(lines) >= maxDescribedFields {
+				return
+			}
+			value, ok := values[f.Name]
+			if !ok || f.Path == "metadata" {
+				continue
+			}
+			line := f.Path
+			if f.Type != "" {
+				line += " (" + f.Type + ")"
+			}
+			if summary := summarizeDescription(f.Description); summary != "" {
+				line += ": " + summary
+			}
+			lines = append(lines, line)
+
+			if nested, ok := value.(map[string]interface{}); ok {
+				walk(f.Properties, nested)
+			}
+		}
+	}
+	walk(fields, obj)
+	return lines
+}
+
+// summarizeDescription returns the first sentence of a field description
+func summarizeDescription(description string) string {
+	summary := strings.TrimSpace(description)
+	if i := strings.Index(summary, "\n"); i >= 0 {
+		summary = summary[:i]
+	}
+	if i := strings.Index(summary, ". "); i >= 0 {
+		summary = summary[:i+1]
+	}
+	if len(summary) > 100 {
+		summary = summary[:97] + "..."
+	}
+	return summary
+}
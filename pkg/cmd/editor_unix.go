@@ -0,0 +1,9 @@
+//go:build !windows
+
+package cmd
+
+// fallbackEditors are tried in order when no editor is configured
+var fallbackEditors = []string{"vim", "vi", "nano"}
+
+// editorEscapes enables backslash escapes when splitting $EDITOR, like a POSIX shell
+const editorEscapes = true
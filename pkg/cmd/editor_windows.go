@@ -0,0 +1,10 @@
+//go:build windows
+
+package cmd
+
+// fallbackEditors are tried in order when no editor is configured
+var fallbackEditors = []string{"notepad"}
+
+// editorEscapes is false because backslashes separate paths on Windows, so
+// C:\Tools\edit.exe stays intact when splitting $EDITOR
+const editorEscapes = false
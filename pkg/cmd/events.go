@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// streamEvents prints Events about a newly created object for --events-duration
+func streamEvents(k8sClient *client.K8sClient, created *unstructured.Unstructured) error {
+	fmt.Fprintf(os.Stderr, i18n.T("Watching events for %s/%s for %s (Ctrl+C to stop)...\n"),
+		created.GetKind(), created.GetName(), eventsDuration)
+
+	count := 0
+	err := k8sClient.WatchEvents(created, eventsDuration, func(ev client.EventInfo) {
+		count++
+		timestamp := ev.Time.Local().Format("15:04:05")
+		source := ""
+		if ev.Source != "" {
+			source = fmt.Sprintf(" (%s)", ev.Source)
+		}
+		repeat := ""
+		if ev.Count > 1 {
+			repeat = fmt.Sprintf(" x%d", ev.Count)
+		}
+		fmt.Printf("%s  %-7s  %s%s%s: %s\n", timestamp, ev.Type, ev.Reason, source, repeat, ev.Message)
+	})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		fmt.Fprint(os.Stderr, i18n.T("No events received\n"))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+// exampleName is the name of --example resources when none is given
+const exampleName = "example"
+
+// exampleFieldValues collects values for --example without prompting: example
+// values for the required fields, an nginx container for resources with a pod
+// template (unless --image is given), then presets and --set on top
+func exampleFieldValues(resourceSchema *client.ResourceSchema, presets map[string]interface{}) (*prompt.CollectedValues, error) {
+	flagValues, err := prompt.ParseSetValues(setValues)
+	if err != nil {
+		return nil, err
+	}
+
+	exName := name
+	if exName == "" {
+		if v, ok := flagValues["metadata.name"]; ok {
+			exName = fmt.Sprintf("%v", v)
+		} else {
+			exName = exampleName
+		}
+	}
+
+	rule := prompt.NameSubdomain
+	if resourceSchema != nil {
+		rule = prompt.NameRuleFor(resourceSchema.GVK.Group, resourceSchema.GVK.Kind)
+	}
+	if err := prompt.ValidateName(exName, rule); err != nil {
+		return nil, err
+	}
+
+	values := prompt.ExampleValues(resourceSchema, exName)
+	if shortcuts.IsEmpty() {
+		container := prompt.WorkloadShortcuts{Image: "nginx"}
+		if containerValues, err := container.Values(resourceSchema, exName); err == nil {
+			for k, v := range containerValues {
+				values[k] = v
+			}
+		}
+	}
+	for k, v := range presets {
+		values[k] = v
+	}
+	for k, v := range flagValues {
+		values[k] = v
+	}
+	values["metadata.name"] = exName
+
+	return &prompt.CollectedValues{
+		Name:    exName,
+		Values:  values,
+		Answers: map[string]interface{}{},
+	}, nil
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/progress"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+var (
+	// explainPath is the field path given to --explain
+	explainPath  string
+	showRequired bool
+	// searchKeyword is the keyword given to --search
+	searchKeyword string
+)
+
+// loadSchema resolves resourceType and fetches its schema
+func loadSchema(resourceType string) (*client.ResourceSchema, error) {
+	k8sClient, err := newClient()
+	if err != nil {
+		return nil, i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	indicator := progress.Start(i18n.T("Discovering resource types"))
+	gvr, err := k8sClient.ResolveResourceType(resourceType)
+	indicator.Stop()
+	if err != nil {
+		return nil, i18n.Errorf("failed to resolve resource type %q: %w", resourceType, err)
+	}
+	indicator = progress.Start(i18n.T("Fetching the OpenAPI schema"))
+	resourceSchema, err := k8sClient.GetResourceSchema(gvr)
+	indicator.Stop()
+	if err != nil {
+		return nil, i18n.Errorf("failed to get schema for %s: %w", formatGVR(gvr), err)
+	}
+	return resourceSchema, nil
+}
+
+// explainField prints the schema subtree at path of resourceType, like kubectl
+// explain --recursive with descriptions: type, whether the field is required,
+// its default and allowed values, and the same for each nested field
+func explainField(resourceType, path string) error {
+	resourceSchema, err := loadSchema(resourceType)
+	if err != nil {
+		return err
+	}
+
+	field, ok := resourceSchema.FindField(path)
+	if !ok {
+		return i18n.Errorf("field %q does not exist in %s", path, resourceSchema.GVK.Kind)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "KIND:     %s\n", resourceSchema.GVK.Kind)
+	fmt.Fprintf(&b, "VERSION:  %s\n\n", resourceSchema.GVK.GroupVersion().String())
+	fmt.Fprintf(&b, "FIELD:    %s <%s>%s\n", field.Path, explainType(*field), requiredMarker(*field))
+	writeFieldDetails(&b, *field, "    ")
+
+	children := field.Properties
+	if field.Items != nil {
+		children = field.Items.Properties
+	}
+	if len(children) > 0 {
+		b.WriteString("\nFIELDS:\n")
+		writeFieldTree(&b, children, "  ")
+	}
+
+	fmt.Print(b.String())
+	return nil
+}
+
+// searchFields lists the fields of resourceType, at any depth, whose name or
+// description matches keyword, so a setting can be found in a large CRD
+// without reading all of it
+func searchFields(resourceType, keyword string) error {
+	resourceSchema, err := loadSchema(resourceType)
+	if err != nil {
+		return err
+	}
+
+	found := resourceSchema.SearchFields(keyword)
+	if len(found) == 0 {
+		return i18n.Errorf("no fields of %s match %q", resourceSchema.GVK.Kind, keyword)
+	}
+	prompt.PrintSearchResults(found)
+	return nil
+}
+
+// printRequiredFields lists the required field paths of resourceType with their
+// types: the required top-level fields (and spec, when it has required fields),
+// and the required fields inside them and inside the items of required lists,
+// recursively. Optional objects are not descended into, since their required
+// fields only apply when they are set.
+func printRequiredFields(resourceType string) error {
+	resourceSchema, err := loadSchema(resourceType)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tTYPE")
+	count := 0
+	var walk func(fields []client.FieldSchema, prefix string)
+	walk = func(fields []client.FieldSchema, prefix string) {
+		for _, f := range fields {
+			// The name is given as an argument or --name. CRDs rarely mark spec
+			// as required, but a spec with required fields has to be set anyway.
+			if prefix == "" && f.Name == "metadata" {
+				continue
+			}
+			if !f.Required && !(prefix == "" && f.Name == "spec" && hasRequiredField(f.Properties)) {
+				continue
+			}
+			path := prefix + f.Name
+			fmt.Fprintf(w, "%s\t%s\n", path, explainType(f))
+			count++
+			if f.Items != nil {
+				walk(f.Items.Properties, path+"[*].")
+			} else {
+				walk(f.Properties, path+".")
+			}
+		}
+	}
+	walk(resourceSchema.Fields, "")
+
+	if count == 0 {
+		fmt.Printf(i18n.T("%s has no required fields besides its name\n"), resourceSchema.GVK.Kind)
+		return nil
+	}
+	return w.Flush()
+}
+
+// hasRequiredField reports whether any of fields is required
+func hasRequiredField(fields []client.FieldSchema) bool {
+	for _, f := range fields {
+		if f.Required {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFieldTree writes fields and their nested fields, indented by depth
+func writeFieldTree(b *strings.Builder, fields []client.FieldSchema, indent string) {
+	for _, f := range fields {
+		fmt.Fprintf(b, "%s%s <%s>%s\n", indent, f.Name, explainType(f), requiredMarker(f))
+		writeFieldDetails(b, f, indent+"    ")
+
+		children := f.Properties
+		if f.Items != nil {
+			children = f.Items.Properties
+		}
+		writeFieldTree(b, children, indent+"  ")
+	}
+}
+
+// writeFieldDetails writes the description, default and allowed values of f
+func writeFieldDetails(b *strings.Builder, f client.FieldSchema, indent string) {
+	for _, line := range strings.Split(strings.TrimSpace(f.Description), "\n") {
+		if line != "" {
+			b.WriteString(indent + line + "\n")
+		}
+	}
+	if f.Default != nil {
+		fmt.Fprintf(b, "%sDefault: %v\n", indent, f.Default)
+	}
+	enum := f.Enum
+	if f.Items != nil && len(enum) == 0 {
+		enum = f.Items.Enum
+	}
+	if len(enum) > 0 {
+		values := make([]string, len(enum))
+		for i, v := range enum {
+			values[i] = fmt.Sprintf("%v", v)
+		}
+		fmt.Fprintf(b, "%sAllowed: %s\n", indent, strings.Join(values, ", "))
+	}
+	if f.PreserveUnknownFields {
+		fmt.Fprintf(b, "%sAccepts fields not in the schema\n", indent)
+	}
+	// Variants named after their type (e.g., IntOrString) are already in the type
+	named := false
+	names := make([]string, len(f.Variants))
+	for i, v := range f.Variants {
+		names[i] = v.Name
+		named = named || v.Name != v.Type
+	}
+	if named {
+		fmt.Fprintf(b, "%sOne of: %s\n", indent, strings.Join(names, " | "))
+	}
+}
+
+// explainType formats the type of f like kubectl explain (e.g., "[]Object")
+func explainType(f client.FieldSchema) string {
+	switch f.Type {
+	case "array":
+		if f.Items != nil && f.Items.Type != "" {
+			return "[]" + explainType(*f.Items)
+		}
+		return "[]"
+	case "object":
+		if len(f.Properties) == 0 && strings.HasPrefix(f.Description, "Map of string to ") {
+			return "map[string]" + strings.TrimSuffix(strings.Fields(f.Description)[4], ".")
+		}
+		return "Object"
+	case "":
+		if len(f.Variants) > 0 {
+			types := make([]string, len(f.Variants))
+			for i, v := range f.Variants {
+				types[i] = explainType(v)
+			}
+			return strings.Join(types, " | ")
+		}
+		return "unknown"
+	}
+	return f.Type
+}
+
+// requiredMarker returns " -required-" for required fields, like kubectl explain
+func requiredMarker(f client.FieldSchema) string {
+	if f.Required {
+		return " -required-"
+	}
+	return ""
+}
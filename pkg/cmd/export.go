@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+var exportFile string
+
+var exportCmd = &cobra.Command{
+	Use:   "export <resource-type> <name>",
+	Short: "Write an existing resource as a manifest ready to create again",
+	Long: `Write an existing resource as a manifest ready to create again, without its status,
+server-generated metadata and namespace. This is the read-only counterpart of --from.
+
+With --strip-defaults, fields the server filled in by itself are removed too (see
+--strip-defaults of --from).
+
+Examples:
+  # Print a deployment as YAML
+  kubectl create-resource export deployment web
+
+  # Save a queue without its defaulted fields, under a new name
+  kubectl create-resource export queue team-a --strip-defaults --name=team-b -o team-b.yaml`,
+	Args: cobra.ExactArgs(2),
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFile, "output", "o", "",
+		"write the manifest to a file instead of stdout (JSON for .json files, YAML otherwise)")
+	exportCmd.Flags().BoolVar(&stripDefaults, "strip-defaults", false,
+		"remove fields defaulted by the server (uses a server-side dry-run)")
+	exportCmd.Flags().StringVar(&name, "name", "",
+		"name for the exported manifest (default: the name of the resource)")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if offline {
+		return i18n.Errorf("export reads from the cluster and cannot be used with --offline")
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	gvr, err := k8sClient.ResolveResourceType(args[0])
+	if err != nil {
+		return i18n.Errorf("failed to resolve resource type %q: %w", args[0], err)
+	}
+	if !k8sClient.IsNamespaced(gvr) {
+		namespace = ""
+	}
+
+	obj, err := k8sClient.GetResource(gvr, namespace, args[1])
+	if err != nil {
+		return i18n.Errorf("failed to get %s %q: %w", gvr.Resource, args[1], err)
+	}
+
+	newName := name
+	if newName == "" {
+		newName = obj.GetName()
+	}
+	exported := cleanTemplateForCreation(obj, newName, "")
+
+	if stripDefaults {
+		exported = minifyManifest(k8sClient, gvr, obj, exported)
+	}
+
+	return writeExport(exported, exportFile)
+}
+
+// writeExport prints obj as YAML, or writes it to path in the format of its extension
+func writeExport(obj *unstructured.Unstructured, path string) error {
+	if path == "" {
+		return generator.PrintManifest(obj, "yaml")
+	}
+
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(obj.Object, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(obj.Object)
+	}
+	if err != nil {
+		return i18n.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	// Exported secrets carry their data, so the file is private to the user
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return i18n.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Wrote %s %s to %s\n"), obj.GetKind(), obj.GetName(), path)
+	return nil
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
+	"github.com/gshaibi/kubectl-create-resource/pkg/batch"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// contextResult is the outcome of creating the resource in one kubeconfig context
+type contextResult struct {
+	context string
+	name    string
+	err     error
+	skipped bool
+}
+
+// resolveTargetContexts returns the contexts selected with --contexts or
+// --all-contexts/--context-selector, or nil to use the current context only
+func resolveTargetContexts() ([]string, error) {
+	if len(contextNames) > 0 && allContexts {
+		return nil, i18n.Errorf("--contexts and --all-contexts cannot be used together")
+	}
+	if contextSelector != "" && !allContexts {
+		return nil, i18n.Errorf("--context-selector requires --all-contexts")
+	}
+	if len(contextNames) == 0 && !allContexts {
+		return nil, nil
+	}
+	if offline {
+		return nil, i18n.Errorf("--contexts and --all-contexts need a cluster and cannot be used with --offline")
+	}
+
+	available, err := client.KubeconfigContexts(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allContexts {
+		for _, c := range contextNames {
+			if !slices.Contains(available, c) {
+				return nil, i18n.Errorf("context %q not found in kubeconfig", c)
+			}
+		}
+		return contextNames, nil
+	}
+
+	var selected []string
+	for _, c := range available {
+		if contextSelector != "" {
+			matched, err := path.Match(contextSelector, c)
+			if err != nil {
+				return nil, i18n.Errorf("invalid --context-selector %q: %w", contextSelector, err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		selected = append(selected, c)
+	}
+	if len(selected) == 0 {
+		return nil, i18n.Errorf("no kubeconfig contexts match %q", contextSelector)
+	}
+	return selected, nil
+}
+
+// createInTargets creates manifest with k8sClient, or in every target context
+// when --contexts or --all-contexts is set
+func createInTargets(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, manifest *unstructured.Unstructured) error {
+	if len(targetContexts) == 0 {
+		if count > 1 {
+			return createCopies(k8sClient, gvr, manifest)
+		}
+		created, err := submitResource(k8sClient, gvr, manifest)
+		if err != nil {
+			return i18n.Errorf("failed to create resource: %w", err)
+		}
+		fmt.Printf(i18n.T("%s/%s created\n"), gvr.Resource, created.GetName())
+		return afterCreate(k8sClient, gvr, created)
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Creating %s/%s in %d contexts\n"), gvr.Resource, manifest.GetName(), len(targetContexts))
+
+	// Connect and run the checks (which may prompt) one context at a time, then
+	// create in the contexts that passed with the worker pool. k8sClient was built
+	// for the first context, which the manifest was generated against.
+	results := make([]contextResult, len(targetContexts))
+	clients := make([]*client.K8sClient, len(targetContexts))
+	var ready []int
+	failed := 0
+	for i, contextName := range targetContexts {
+		results[i] = contextResult{context: contextName}
+		if failed > 0 && !continueOnError {
+			results[i].skipped = true
+			continue
+		}
+
+		clients[i] = k8sClient
+		if i > 0 {
+			var err error
+			clients[i], err = client.NewK8sClientWithOptions(connectionOptions(contextName))
+			if err != nil {
+				results[i].err = err
+				failed++
+				continue
+			}
+		}
+		if err := checkBeforeCreate(clients[i], gvr, manifest); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("[%s] failed to create resource: %v\n"), contextName, err)
+			recordAudit(clients[i], gvr, namespace, manifest.GetName(), audit.OperationCreate, err)
+			results[i].err = err
+			failed++
+			continue
+		}
+		ready = append(ready, i)
+	}
+
+	created := make([]*unstructured.Unstructured, len(targetContexts))
+	if failed == 0 || continueOnError {
+		outcomes := batch.Run(len(ready), batchOptions(!continueOnError), func(n int) error {
+			i := ready[n]
+			obj, err := createChecked(clients[i], gvr, manifest)
+			if err != nil {
+				if !batch.IsTransient(err) {
+					fmt.Fprintf(os.Stderr, i18n.T("[%s] failed to create resource: %v\n"), targetContexts[i], err)
+				}
+				return err
+			}
+			fmt.Printf(i18n.T("[%s] %s/%s created\n"), targetContexts[i], gvr.Resource, obj.GetName())
+			created[i] = obj
+			return nil
+		})
+		for _, outcome := range outcomes {
+			i := ready[outcome.Index]
+			results[i].err = outcome.Err
+			results[i].skipped = outcome.Skipped
+			if outcome.Err != nil {
+				failed++
+			}
+		}
+	} else {
+		for _, i := range ready {
+			results[i].skipped = true
+		}
+	}
+
+	// The post-create steps may stream output, so they run one context at a time
+	for i, obj := range created {
+		if obj == nil {
+			continue
+		}
+		results[i].name = obj.GetName()
+		if err := afterCreate(clients[i], gvr, obj); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("[%s] Warning: %v\n"), targetContexts[i], err)
+		}
+	}
+
+	printContextResults(gvr, results)
+	if failed > 0 {
+		return i18n.Errorf("failed to create %s in %d of %d contexts", gvr.Resource, failed, len(targetContexts))
+	}
+	return nil
+}
+
+// printContextResults prints a per-context summary of a fan-out create
+func printContextResults(gvr schema.GroupVersionResource, results []contextResult) {
+	fmt.Printf(i18n.T("\nResults for %s:\n"), gvr.Resource)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	for _, r := range results {
+		switch {
+		case r.skipped:
+			fmt.Fprintf(w, "  %s\tskipped\n", r.context)
+		case r.err != nil:
+			fmt.Fprintf(w, "  %s\tfailed: %v\n", r.context, r.err)
+		default:
+			fmt.Fprintf(w, "  %s\tcreated %s\n", r.context, r.name)
+		}
+	}
+}
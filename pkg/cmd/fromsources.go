@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestExtensions are the extensions of --from values read from files
+// instead of the cluster
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// isManifestFile reports whether the --from source is a manifest file, such
+// as ./base.yaml, rather than the name of a resource in the cluster
+func isManifestFile(source string) bool {
+	ext := strings.ToLower(filepath.Ext(source))
+	for _, e := range manifestExtensions {
+		if ext == e {
+			info, err := os.Stat(source)
+			return err == nil && info.Mode().IsRegular()
+		}
+	}
+	return false
+}
+
+// loadTemplate builds the template of a --from run from its sources, deep-merged
+// in order: the first is the base and each of the others an overlay, whose
+// objects are merged into the base's and whose other values, lists included,
+// replace the base's. The version, name and namespace are the base's.
+func loadTemplate(k8sClient *client.K8sClient, gvr schema.GroupVersionResource) (*unstructured.Unstructured, error) {
+	var merged *unstructured.Unstructured
+	for i, source := range fromSources {
+		obj, err := loadFromSource(k8sClient, gvr, source, i == 0)
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = obj
+			continue
+		}
+		fmt.Fprintf(os.Stderr, i18n.T("Merging %s into the template\n"), source)
+		delete(obj.Object, "apiVersion")
+		delete(obj.Object, "kind")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "name")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "namespace")
+		mergeTemplate(merged.Object, obj.Object)
+	}
+	if merged.GetName() == "" {
+		return nil, i18n.Errorf("the template %s has no name, give one with --name or as an argument", fromSources[0])
+	}
+	return merged, nil
+}
+
+// loadFromSource loads one --from source, cleaned up for creation: a manifest
+// file, the pod template of a resource of another kind (type/name) or a
+// resource of gvr. Overlay files may hold only the fields they change; the base
+// has to be a whole manifest.
+func loadFromSource(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, source string, base bool) (*unstructured.Unstructured, error) {
+	fromResource = source
+	if isManifestFile(source) {
+		return loadTemplateFile(k8sClient, gvr, source, base)
+	}
+
+	// --from=type/name of another kind (e.g., job --from=cronjob/nightly) only
+	// lends its pod template
+	obj, otherKind, err := templateFromOtherKind(k8sClient, gvr)
+	if err != nil || otherKind {
+		return obj, err
+	}
+
+	templateObj, err := k8sClient.GetResource(gvr, namespace, fromResource)
+	if err != nil {
+		return nil, i18n.Errorf("failed to get template resource %q: %w", fromResource, err)
+	}
+	cleaned := cleanTemplateForCreation(templateObj, name, namespace)
+	if stripDefaults {
+		cleaned = minifyManifest(k8sClient, gvr, templateObj, cleaned)
+	}
+	return cleaned, nil
+}
+
+// loadTemplateFile reads the manifest file path as a template of gvr
+func loadTemplateFile(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, path string, base bool) (*unstructured.Unstructured, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("failed to read template %s: %w", path, err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+		return nil, i18n.Errorf("failed to parse template %s: %w", path, err)
+	}
+	if obj.Object == nil {
+		obj.Object = map[string]interface{}{}
+	}
+
+	if obj.GetKind() == "" {
+		if base {
+			return nil, i18n.Errorf("the template %s has no apiVersion and kind, only the files after the first may hold just the fields they change", path)
+		}
+	} else if fileGVR, err := k8sClient.ResourceForKind(obj.GroupVersionKind()); err != nil || fileGVR.GroupResource() != gvr.GroupResource() {
+		return nil, i18n.Errorf("the template %s is a %s, not one of %s", path, obj.GetKind(), gvr.Resource)
+	}
+
+	newName := name
+	if newName == "" {
+		newName = obj.GetName()
+	}
+	return cleanTemplateForCreation(obj, newName, namespace), nil
+}
+
+// mergeTemplate deep-merges the fields of overlay into dst: objects are merged
+// key by key, and any other value, including lists, replaces the one in dst
+func mergeTemplate(dst, overlay map[string]interface{}) {
+	for k, v := range overlay {
+		overlayMap, ok := v.(map[string]interface{})
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		dstMap, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dst[k] = overlayMap
+			continue
+		}
+		mergeTemplate(dstMap, overlayMap)
+	}
+}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// cronJobInstantiateAnnotation marks Jobs created by hand from a CronJob, as
+// kubectl create job --from=cronjob/name does
+const cronJobInstantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+
+// controllerPodLabels are set on pods by their controllers, and would make a
+// copy of the template match the pods of the original
+var controllerPodLabels = []string{
+	"pod-template-hash",
+	"controller-revision-hash",
+	"controller-uid",
+	"job-name",
+	"batch.kubernetes.io/controller-uid",
+	"batch.kubernetes.io/job-name",
+	"batch.kubernetes.io/job-completion-index",
+	"statefulset.kubernetes.io/pod-name",
+	"apps.kubernetes.io/pod-index",
+}
+
+// serviceAccountVolumePrefix names the volume the API server injects into pods
+// for the service account token
+const serviceAccountVolumePrefix = "kube-api-access-"
+
+// splitFromSource splits --from=type/name into the type and name of a resource of
+// another kind, e.g. cronjob/nightly. ok is false for the name of a resource of
+// the type being created.
+func splitFromSource(from string) (resourceType, sourceName string, ok bool) {
+	resourceType, sourceName, ok = strings.Cut(from, "/")
+	if !ok || resourceType == "" || sourceName == "" {
+		return "", from, false
+	}
+	return resourceType, sourceName, true
+}
+
+// templateFromOtherKind builds a resource of gvr around the pod template of the
+// resource --from=type/name names, like kubectl create job --from=cronjob/name.
+// Pod templates are found through the schemas of both types, so this works for
+// Pods, built-in workloads and custom resources that embed a PodTemplateSpec.
+// ok is false when --from names a resource of gvr itself.
+func templateFromOtherKind(k8sClient *client.K8sClient, gvr schema.GroupVersionResource) (*unstructured.Unstructured, bool, error) {
+	sourceType, sourceName, ok := splitFromSource(fromResource)
+	if !ok {
+		return nil, false, nil
+	}
+	sourceGVR, err := k8sClient.ResolveResourceType(sourceType)
+	if err != nil {
+		return nil, false, i18n.Errorf("failed to resolve resource type %q: %w", sourceType, err)
+	}
+	if sourceGVR.GroupResource() == gvr.GroupResource() {
+		fromResource = sourceName
+		return nil, false, nil
+	}
+	if stripDefaults {
+		return nil, false, i18n.Errorf("--strip-defaults cannot be used with --from of another kind")
+	}
+
+	source, err := k8sClient.GetResource(sourceGVR, namespace, sourceName)
+	if err != nil {
+		return nil, false, i18n.Errorf("failed to get template resource %q: %w", fromResource, err)
+	}
+	sourceSchema, err := k8sClient.GetResourceSchema(sourceGVR)
+	if err != nil {
+		// The known workloads are found by kind
+		sourceSchema = &client.ResourceSchema{GVK: source.GroupVersionKind()}
+	}
+	targetSchema, err := k8sClient.GetResourceSchema(gvr)
+	if err != nil {
+		return nil, false, i18n.Errorf("failed to get the schema of %s to place the pod template: %w", gvr.Resource, err)
+	}
+
+	sourceSpec, sourceTemplate, ok := prompt.PodSpecOf(sourceSchema)
+	if !ok {
+		return nil, false, i18n.Errorf("%s %s has no pod template to create %s from", source.GetKind(), sourceName, gvr.Resource)
+	}
+	targetSpec, targetTemplate, ok := prompt.PodSpecOf(targetSchema)
+	if !ok {
+		return nil, false, i18n.Errorf("%s has no pod template to fill from %s", targetSchema.GVK.Kind, fromResource)
+	}
+
+	template, err := podTemplateOf(source, sourceSpec, sourceTemplate)
+	if err != nil {
+		return nil, false, err
+	}
+	cleanPodTemplate(template)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetGroupVersionKind(targetSchema.GVK)
+	obj.SetName(sourceName)
+	if name != "" {
+		obj.SetName(name)
+	}
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+
+	if targetTemplate == nil {
+		// A Pod takes the template's metadata and spec directly
+		setTemplateMetadata(obj, template)
+		spec, _, _ := unstructured.NestedMap(template, "spec")
+		if err := unstructured.SetNestedMap(obj.Object, spec, splitPath(targetSpec)...); err != nil {
+			return nil, false, i18n.Errorf("failed to set %s: %w", targetSpec, err)
+		}
+		fmt.Fprintf(os.Stderr, i18n.T("Using the pod template of %s\n"), fromResource)
+		return obj, true, nil
+	}
+
+	if sourceTemplate != nil {
+		copyTemplateSiblings(obj, targetSchema, source, sourceTemplate.Path, targetTemplate.Path)
+	}
+	fixRestartPolicy(template, targetTemplate.RestartOnFailure)
+	if err := unstructured.SetNestedMap(obj.Object, template, splitPath(targetTemplate.Path)...); err != nil {
+		return nil, false, i18n.Errorf("failed to set %s: %w", targetTemplate.Path, err)
+	}
+	if targetTemplate.SelectorPath != "" {
+		labels, _, _ := unstructured.NestedStringMap(template, "metadata", "labels")
+		if len(labels) == 0 {
+			return nil, false, i18n.Errorf("the pod template of %s has no labels for the selector of %s", fromResource, targetSchema.GVK.Kind)
+		}
+		selector := make(map[string]interface{}, len(labels))
+		for k, v := range labels {
+			selector[k] = v
+		}
+		unstructured.SetNestedMap(obj.Object, selector, append(splitPath(targetTemplate.SelectorPath), "matchLabels")...)
+	}
+	if strings.EqualFold(source.GetKind(), "CronJob") && strings.EqualFold(targetSchema.GVK.Kind, "Job") {
+		instantiatedFrom(obj, source)
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Using the pod template of %s\n"), fromResource)
+	return obj, true, nil
+}
+
+// podTemplateOf returns a copy of the pod template of source: the one at
+// template, or the metadata and spec of a Pod
+func podTemplateOf(source *unstructured.Unstructured, podSpec string, template *prompt.PodTemplate) (map[string]interface{}, error) {
+	if template != nil {
+		t, found, err := unstructured.NestedMap(source.Object, splitPath(template.Path)...)
+		if err != nil || !found {
+			return nil, i18n.Errorf("%s %s has no %s", source.GetKind(), source.GetName(), template.Path)
+		}
+		return t, nil
+	}
+
+	spec, found, err := unstructured.NestedMap(source.Object, splitPath(podSpec)...)
+	if err != nil || !found {
+		return nil, i18n.Errorf("%s %s has no %s", source.GetKind(), source.GetName(), podSpec)
+	}
+	metadata := map[string]interface{}{}
+	if labels := source.GetLabels(); len(labels) > 0 {
+		metadata["labels"] = stringMap(labels)
+	}
+	if annotations := source.GetAnnotations(); len(annotations) > 0 {
+		metadata["annotations"] = stringMap(annotations)
+	}
+	return map[string]interface{}{"metadata": metadata, "spec": spec}, nil
+}
+
+// cleanPodTemplate removes what controllers and the API server add to pods, so
+// the template describes new pods rather than the ones it was read from
+func cleanPodTemplate(template map[string]interface{}) {
+	unstructured.RemoveNestedField(template, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(template, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	for _, label := range controllerPodLabels {
+		unstructured.RemoveNestedField(template, "metadata", "labels", label)
+	}
+	for _, field := range []string{"labels", "annotations"} {
+		if m, _, _ := unstructured.NestedMap(template, "metadata", field); len(m) == 0 {
+			unstructured.RemoveNestedField(template, "metadata", field)
+		}
+	}
+
+	spec, ok := template["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	delete(spec, "nodeName")
+	delete(spec, "ephemeralContainers")
+
+	// Drop the injected service account token volume and its mounts
+	volumes, _ := spec["volumes"].([]interface{})
+	kept := volumes[:0]
+	for _, v := range volumes {
+		volume, _ := v.(map[string]interface{})
+		if volumeName, _ := volume["name"].(string); strings.HasPrefix(volumeName, serviceAccountVolumePrefix) && volume["projected"] != nil {
+			continue
+		}
+		kept = append(kept, v)
+	}
+	if len(kept) == 0 {
+		delete(spec, "volumes")
+	} else if len(kept) < len(volumes) {
+		spec["volumes"] = kept
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := spec[field].([]interface{})
+		for _, c := range containers {
+			container, _ := c.(map[string]interface{})
+			mounts, _ := container["volumeMounts"].([]interface{})
+			var keptMounts []interface{}
+			for _, m := range mounts {
+				mount, _ := m.(map[string]interface{})
+				if mountName, _ := mount["name"].(string); strings.HasPrefix(mountName, serviceAccountVolumePrefix) {
+					continue
+				}
+				keptMounts = append(keptMounts, m)
+			}
+			if len(keptMounts) == 0 {
+				delete(container, "volumeMounts")
+			} else {
+				container["volumeMounts"] = keptMounts
+			}
+		}
+	}
+}
+
+// fixRestartPolicy makes the restartPolicy of template valid for the target:
+// pods that run to completion can't restart Always, and those of other
+// workloads can only restart Always, which is the default
+func fixRestartPolicy(template map[string]interface{}, runToCompletion bool) {
+	policy, _, _ := unstructured.NestedString(template, "spec", "restartPolicy")
+	switch {
+	case runToCompletion && (policy == "" || policy == "Always"):
+		unstructured.SetNestedField(template, "OnFailure", "spec", "restartPolicy")
+	case !runToCompletion && policy != "" && policy != "Always":
+		unstructured.RemoveNestedField(template, "spec", "restartPolicy")
+	}
+}
+
+// copyTemplateSiblings copies the fields next to the source's pod template that
+// the target also has next to its own, e.g. the backoffLimit of the jobTemplate
+// of a CronJob to a Job. Selectors are wired to the template labels instead.
+func copyTemplateSiblings(obj *unstructured.Unstructured, targetSchema *client.ResourceSchema, source *unstructured.Unstructured, sourcePath, targetPath string) {
+	sourceParent := parentPath(sourcePath)
+	targetParent := parentPath(targetPath)
+	if sourceParent == "" || targetParent == "" {
+		return
+	}
+	siblings, _, _ := unstructured.NestedMap(source.Object, splitPath(sourceParent)...)
+	for field, value := range siblings {
+		if field == lastPathPart(sourcePath) || field == "selector" || field == "manualSelector" {
+			continue
+		}
+		if _, ok := targetSchema.FindField(targetParent + "." + field); !ok {
+			continue
+		}
+		unstructured.SetNestedField(obj.Object, value, append(splitPath(targetParent), field)...)
+	}
+
+	// The labels and annotations of an enclosing template (jobTemplate.metadata)
+	// go on the new resource itself
+	if strings.HasSuffix(sourceParent, ".spec") && targetParent == "spec" {
+		enclosing, _, _ := unstructured.NestedMap(source.Object, splitPath(strings.TrimSuffix(sourceParent, ".spec"))...)
+		setTemplateMetadata(obj, enclosing)
+	}
+}
+
+// setTemplateMetadata adds the labels and annotations of a template to obj
+func setTemplateMetadata(obj *unstructured.Unstructured, template map[string]interface{}) {
+	if labels, _, _ := unstructured.NestedStringMap(template, "metadata", "labels"); len(labels) > 0 {
+		obj.SetLabels(labels)
+	}
+	if annotations, _, _ := unstructured.NestedStringMap(template, "metadata", "annotations"); len(annotations) > 0 {
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// instantiatedFrom marks a Job as created by hand from cronJob and makes the
+// CronJob its owner, like kubectl create job --from=cronjob/name
+func instantiatedFrom(obj, cronJob *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[cronJobInstantiateAnnotation] = "manual"
+	obj.SetAnnotations(annotations)
+
+	controller := true
+	obj.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: cronJob.GetAPIVersion(),
+		Kind:       cronJob.GetKind(),
+		Name:       cronJob.GetName(),
+		UID:        cronJob.GetUID(),
+		Controller: &controller,
+	}})
+}
+
+// parentPath returns the path of the object holding the field at path
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// lastPathPart returns the name of the field at path
+func lastPathPart(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// stringMap converts labels or annotations to unstructured content
+func stringMap(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
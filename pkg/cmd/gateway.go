@@ -0,0 +1,708 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// gatewayGroup is the API group of the Gateway API
+const gatewayGroup = "gateway.networking.k8s.io"
+
+// Resource types of the Gateway API presets
+var (
+	gateways   = schema.GroupResource{Group: gatewayGroup, Resource: "gateways"}
+	httpRoutes = schema.GroupResource{Group: gatewayGroup, Resource: "httproutes"}
+	grpcRoutes = schema.GroupResource{Group: gatewayGroup, Resource: "grpcroutes"}
+)
+
+var gatewaysGVR = schema.GroupVersionResource{Group: gatewayGroup, Version: "v1", Resource: "gateways"}
+
+// gatewayClasses are the GatewayClasses of Gateways
+var gatewayClasses = classType{
+	gvr:             schema.GroupVersionResource{Group: gatewayGroup, Version: "v1", Resource: "gatewayclasses"},
+	controllerField: "spec.controllerName",
+}
+
+// listenerProtocols are the protocols of Gateway listeners, with the port
+// offered for each
+var listenerProtocols = []struct{ protocol, port string }{
+	{protocol: "HTTP", port: "80"},
+	{protocol: "HTTPS", port: "443"},
+	{protocol: "TLS", port: "443"},
+	{protocol: "TCP"},
+	{protocol: "UDP"},
+}
+
+// httpPathTypes are the types of HTTPRoute path matches, the default first
+var httpPathTypes = []string{"PathPrefix", "Exact", "RegularExpression"}
+
+// routeType is a kind of route of the Gateway API, attached to Gateways and
+// matching requests by rule
+type routeType struct {
+	gr   schema.GroupResource
+	kind string
+
+	// matchExample shows a rule of --route-rule
+	matchExample string
+
+	// parseMatch parses the match of a rule of --route-rule, nil for a rule
+	// matching all requests
+	parseMatch func(s string) (map[string]interface{}, error)
+
+	// askMatch asks for the match of rule i, reporting done when no more
+	// rules are wanted
+	askMatch func(key string, i int) (match map[string]interface{}, done bool, err error)
+}
+
+var routeTypes = map[schema.GroupResource]routeType{
+	httpRoutes: {
+		gr:           httpRoutes,
+		kind:         "HTTPRoute",
+		matchExample: "--route-rule='/api*=api:8080'",
+		parseMatch:   parseHTTPMatch,
+		askMatch:     askHTTPMatch,
+	},
+	grpcRoutes: {
+		gr:           grpcRoutes,
+		kind:         "GRPCRoute",
+		matchExample: "--route-rule='shop.v1.Cart/Checkout=cart:9090'",
+		parseMatch:   parseGRPCMatch,
+		askMatch:     askGRPCMatch,
+	},
+}
+
+var (
+	gatewayClass       string
+	gatewayListeners   []string
+	routeParents       []string
+	routeHostnames     []string
+	routeRules         []string
+	allowAllNamespaces bool
+)
+
+func init() {
+	typePresets[gateways] = preset{
+		flags:   []string{"gateway-class", "listener", "allow-routes-from-all-namespaces"},
+		fields:  []string{"spec.gatewayClassName", "spec.listeners"},
+		prepare: prepareGateway,
+	}
+	for gr, t := range routeTypes {
+		typePresets[gr] = preset{
+			flags:  []string{"parent", "hostname", "route-rule"},
+			fields: []string{"spec.parentRefs", "spec.hostnames", "spec.rules"},
+			prepare: func(k8sClient *client.K8sClient, values *prompt.CollectedValues) error {
+				return prepareRoute(k8sClient, values, t)
+			},
+		}
+	}
+
+	rootCmd.Flags().StringVar(&gatewayClass, "gateway-class", "",
+		"with gateway, the GatewayClass of the Gateway (picked from a list in a terminal)")
+	rootCmd.Flags().StringArrayVar(&gatewayListeners, "listener", []string{},
+		"with gateway, a listener PROTOCOL:port[,name=name][,hostname=host][,tls=secret] (e.g., --listener='HTTPS:443,hostname=shop.example.com,tls=shop-tls')")
+	rootCmd.Flags().BoolVar(&allowAllNamespaces, "allow-routes-from-all-namespaces", false,
+		"with gateway, let routes of all namespaces attach to the listeners rather than those of the Gateway's namespace")
+	rootCmd.Flags().StringArrayVar(&routeParents, "parent", []string{},
+		"with httproute or grpcroute, a Gateway to attach to as [namespace/]gateway[:listener] (picked from a list in a terminal)")
+	rootCmd.Flags().StringArrayVar(&routeHostnames, "hostname", []string{},
+		"with httproute or grpcroute, a hostname the route matches (e.g., --hostname=shop.example.com)")
+	rootCmd.Flags().StringArrayVar(&routeRules, "route-rule", []string{},
+		"with httproute or grpcroute, a rule match=service:port[@weight],... where the match is a path of httproutes, ending in * for a prefix, or service[/method] of grpcroutes (e.g., --route-rule='/api*=api:8080@90,api-canary:8080@10')")
+}
+
+// prepareGateway sets the class and listeners of the Gateway from their flags,
+// or asks for them in a terminal when they aren't set yet
+func prepareGateway(k8sClient *client.K8sClient, values *prompt.CollectedValues) error {
+	if values == nil {
+		return rejectPresetFlagsWithFrom(gateways)
+	}
+	ask := !example && prompt.CanPrompt()
+
+	switch {
+	case gatewayClass != "":
+		values.Set("spec.gatewayClassName", gatewayClass, prompt.SourceFlag)
+	case values.Has("spec.gatewayClassName"):
+	case ask:
+		classes, err := listClasses(k8sClient, gatewayClasses)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: failed to list gateway classes: %v\n"), err)
+		}
+		name := ""
+		if len(classes) > 0 {
+			name, err = prompt.PickClass("spec.gatewayClassName", i18n.T("Gateway class"), classes)
+		} else {
+			name, err = prompt.AskText("spec.gatewayClassName", i18n.T("Gateway class"), "", true, validateGatewayName)
+		}
+		if err != nil {
+			return err
+		}
+		values.Set("spec.gatewayClassName", name, prompt.SourcePrompt)
+	default:
+		return i18n.Errorf("a Gateway needs a class, give --gateway-class")
+	}
+
+	var listeners []interface{}
+	source := prompt.SourceFlag
+	switch {
+	case len(gatewayListeners) > 0:
+		for _, l := range gatewayListeners {
+			listener, err := parseListener(l, listeners)
+			if err != nil {
+				return i18n.Errorf("invalid --listener %q: %w", l, err)
+			}
+			listeners = append(listeners, listener)
+		}
+	case values.Has("spec.listeners"):
+		return nil
+	case ask:
+		var err error
+		if listeners, err = askListeners(k8sClient); err != nil {
+			return err
+		}
+		source = prompt.SourcePrompt
+	default:
+		return i18n.Errorf("a Gateway needs listeners, give --listener (e.g., --listener=HTTP:80)")
+	}
+
+	if allowAllNamespaces || (source == prompt.SourcePrompt && prompt.Confirm("spec.listeners.allowedRoutes", i18n.T("Let routes of all namespaces attach to the listeners"))) {
+		for _, l := range listeners {
+			unstructured.SetNestedField(l.(map[string]interface{}), "All", "allowedRoutes", "namespaces", "from")
+		}
+	}
+	values.Set("spec.listeners", listeners, source)
+	return nil
+}
+
+// askListeners asks for the listeners of the Gateway one by one: the
+// protocol, port, hostname and, for HTTPS and TLS, the certificate Secret
+func askListeners(k8sClient *client.K8sClient) ([]interface{}, error) {
+	protocols := make([]string, len(listenerProtocols))
+	for i, p := range listenerProtocols {
+		protocols[i] = p.protocol
+	}
+
+	var listeners []interface{}
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("spec.listeners[%d]", i)
+		items, label := protocols, i18n.T("Listener protocol")
+		if i > 0 {
+			items, label = append([]string{i18n.T("Done")}, protocols...), i18n.T("Protocol of another listener")
+		}
+		choice, err := prompt.Choose(key+".protocol", label, items, 0)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			if choice == 0 {
+				break
+			}
+			choice--
+		}
+		p := listenerProtocols[choice]
+
+		port, err := prompt.AskText(key+".port", i18n.T("Port"), p.port, true, validateGatewayPort)
+		if err != nil {
+			return nil, err
+		}
+		listener := map[string]interface{}{"protocol": p.protocol}
+		listener["port"], _ = strconv.ParseInt(port, 10, 32)
+		if p.protocol != "TCP" && p.protocol != "UDP" {
+			host, err := prompt.AskText(key+".hostname", i18n.T("Hostname (e.g., shop.example.com, empty for any host)"), "", false, validateIngressHost)
+			if err != nil {
+				return nil, err
+			}
+			if host != "" {
+				listener["hostname"] = host
+			}
+		}
+		listenerName, err := prompt.AskText(key+".name", i18n.T("Listener name"), defaultListenerName(p.protocol, port, listeners), true, validateGatewayName)
+		if err != nil {
+			return nil, err
+		}
+		listener["name"] = listenerName
+
+		switch p.protocol {
+		case "HTTPS":
+			secret, err := prompt.PickOrEnter(key+".tls.certificateRefs[0].name", i18n.T("Secret of the certificate"), tlsSecrets(k8sClient), "", nil)
+			if err != nil {
+				return nil, err
+			}
+			setListenerTLS(listener, secret)
+		case "TLS":
+			setListenerTLS(listener, "")
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// parseListener parses a listener of --listener,
+// PROTOCOL:port[,name=name][,hostname=host][,tls=secret]. HTTPS listeners
+// terminate TLS with the certificate of the Secret, TLS listeners pass it
+// through without one.
+func parseListener(s string, others []interface{}) (map[string]interface{}, error) {
+	parts := strings.Split(s, ",")
+	protocol, port, ok := strings.Cut(parts[0], ":")
+	protocol = strings.ToUpper(protocol)
+	if !ok || !slices.ContainsFunc(listenerProtocols, func(p struct{ protocol, port string }) bool { return p.protocol == protocol }) {
+		return nil, i18n.Errorf("must be PROTOCOL:port with a protocol of HTTP, HTTPS, TLS, TCP or UDP")
+	}
+	if err := validateGatewayPort(port); err != nil {
+		return nil, err
+	}
+	listener := map[string]interface{}{"protocol": protocol}
+	listener["port"], _ = strconv.ParseInt(port, 10, 32)
+
+	secret, hasTLS := "", false
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "name":
+			if err := validateGatewayName(value); err != nil {
+				return nil, err
+			}
+			listener["name"] = value
+		case "hostname":
+			if err := validateIngressHost(value); err != nil {
+				return nil, err
+			}
+			listener["hostname"] = value
+		case "tls":
+			secret, hasTLS = value, true
+		default:
+			return nil, i18n.Errorf("unknown option %q", option)
+		}
+	}
+	if _, ok := listener["name"]; !ok {
+		listener["name"] = defaultListenerName(protocol, port, others)
+	}
+
+	switch {
+	case protocol == "HTTPS" && secret == "":
+		return nil, i18n.Errorf("an HTTPS listener needs the Secret of its certificate, add tls=secret")
+	case protocol == "HTTPS" || protocol == "TLS":
+		setListenerTLS(listener, secret)
+	case hasTLS:
+		return nil, i18n.Errorf("tls only applies to HTTPS and TLS listeners")
+	}
+	return listener, nil
+}
+
+// setListenerTLS terminates TLS at the listener with the certificate of
+// secret, or passes it through to the backends without one
+func setListenerTLS(listener map[string]interface{}, secret string) {
+	if secret == "" {
+		listener["tls"] = map[string]interface{}{"mode": "Passthrough"}
+		return
+	}
+	listener["tls"] = map[string]interface{}{
+		"mode":            "Terminate",
+		"certificateRefs": []interface{}{map[string]interface{}{"kind": "Secret", "name": secret}},
+	}
+}
+
+// defaultListenerName names a listener after its protocol (e.g., https), or
+// its protocol and port when another listener has that name
+func defaultListenerName(protocol, port string, others []interface{}) string {
+	listenerName := strings.ToLower(protocol)
+	for _, l := range others {
+		if l.(map[string]interface{})["name"] == listenerName {
+			return listenerName + "-" + port
+		}
+	}
+	return listenerName
+}
+
+// prepareRoute sets the parent Gateways, hostnames and rules of a route of
+// type t from their flags, or asks for them in a terminal when they aren't set
+// yet. Without a terminal, --parent and --route-rule are needed.
+func prepareRoute(k8sClient *client.K8sClient, values *prompt.CollectedValues, t routeType) error {
+	if values == nil {
+		return rejectPresetFlagsWithFrom(t.gr)
+	}
+	ask := !example && prompt.CanPrompt()
+
+	switch {
+	case len(routeParents) > 0:
+		var parents []interface{}
+		for _, p := range routeParents {
+			ref, err := parseParentRef(p)
+			if err != nil {
+				return i18n.Errorf("invalid --parent %q: %w", p, err)
+			}
+			parents = append(parents, ref)
+		}
+		values.Set("spec.parentRefs", parents, prompt.SourceFlag)
+	case values.Has("spec.parentRefs"):
+	case ask:
+		parents, err := askParentRefs(k8sClient)
+		if err != nil {
+			return err
+		}
+		values.Set("spec.parentRefs", parents, prompt.SourcePrompt)
+	default:
+		return i18n.Errorf("a %s needs the Gateway it attaches to, give --parent", t.kind)
+	}
+
+	switch {
+	case len(routeHostnames) > 0:
+		var hostnames []interface{}
+		for _, host := range routeHostnames {
+			if err := validateIngressHost(host); err != nil {
+				return i18n.Errorf("invalid --hostname: %w", err)
+			}
+			hostnames = append(hostnames, host)
+		}
+		values.Set("spec.hostnames", hostnames, prompt.SourceFlag)
+	case values.Has("spec.hostnames"):
+	case ask:
+		hostnames, err := askRouteHostnames()
+		if err != nil {
+			return err
+		}
+		if len(hostnames) > 0 {
+			values.Set("spec.hostnames", hostnames, prompt.SourcePrompt)
+		}
+	}
+
+	switch {
+	case len(routeRules) > 0:
+		var rules []interface{}
+		for _, r := range routeRules {
+			rule, err := parseRouteRule(r, t)
+			if err != nil {
+				return i18n.Errorf("invalid --route-rule %q: %w", r, err)
+			}
+			rules = append(rules, rule)
+		}
+		values.Set("spec.rules", rules, prompt.SourceFlag)
+	case values.Has("spec.rules"):
+	case ask:
+		rules, err := askRouteRules(k8sClient, t)
+		if err != nil {
+			return err
+		}
+		values.Set("spec.rules", rules, prompt.SourcePrompt)
+	default:
+		return i18n.Errorf("a %s needs rules, give --route-rule (e.g., %s)", t.kind, t.matchExample)
+	}
+	return nil
+}
+
+// askParentRefs asks for the Gateways the route attaches to, picked from the
+// Gateways of the cluster, and the listener of each (all by default)
+func askParentRefs(k8sClient *client.K8sClient) ([]interface{}, error) {
+	var names []string
+	listeners := map[string][]string{}
+	if !k8sClient.Offline() {
+		objs, _ := k8sClient.ListObjects(gatewaysGVR, "")
+		for _, obj := range objs {
+			gateway := obj.GetName()
+			if obj.GetNamespace() != namespace {
+				gateway = obj.GetNamespace() + "/" + gateway
+			}
+			names = append(names, gateway)
+			specListeners, _, _ := unstructured.NestedSlice(obj.Object, "spec", "listeners")
+			for _, l := range specListeners {
+				if listenerName, ok := l.(map[string]interface{})["name"].(string); ok {
+					listeners[gateway] = append(listeners[gateway], listenerName)
+				}
+			}
+		}
+		slices.Sort(names)
+	}
+
+	var parents []interface{}
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("spec.parentRefs[%d]", i)
+		gateway, err := prompt.PickOrEnter(key+".name", i18n.T("Gateway to attach to ([namespace/]name)"), names, "", func(s string) error {
+			_, err := parseParentRef(s)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		ref, _ := parseParentRef(gateway)
+
+		if sections := listeners[gateway]; len(sections) > 0 {
+			items := append([]string{i18n.T("All listeners")}, sections...)
+			choice, err := prompt.Choose(key+".sectionName", i18n.T("Listener of %s", gateway), items, 0)
+			if err != nil {
+				return nil, err
+			}
+			if choice > 0 {
+				ref["sectionName"] = sections[choice-1]
+			}
+		}
+		parents = append(parents, ref)
+
+		if !prompt.Confirm(fmt.Sprintf("spec.parentRefs[%d]", i+1), i18n.T("Attach to another Gateway")) {
+			return parents, nil
+		}
+	}
+}
+
+// parseParentRef parses a parent Gateway as [namespace/]gateway[:listener]
+func parseParentRef(s string) (map[string]interface{}, error) {
+	rest, section, hasSection := strings.Cut(s, ":")
+	ns, gateway, hasNamespace := strings.Cut(rest, "/")
+	if !hasNamespace {
+		ns, gateway = "", rest
+	}
+	if err := validateGatewayName(gateway); err != nil {
+		return nil, err
+	}
+	ref := map[string]interface{}{"name": gateway}
+	if hasNamespace && ns != namespace {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return nil, i18n.Errorf("invalid namespace %q: %s", ns, strings.Join(errs, "; "))
+		}
+		ref["namespace"] = ns
+	}
+	if hasSection {
+		if err := validateGatewayName(section); err != nil {
+			return nil, err
+		}
+		ref["sectionName"] = section
+	}
+	return ref, nil
+}
+
+// askRouteHostnames asks for the hostnames the route matches, until one is
+// left empty
+func askRouteHostnames() ([]interface{}, error) {
+	var hostnames []interface{}
+	for i := 0; ; i++ {
+		label := i18n.T("Hostname (e.g., shop.example.com, empty for those of the listeners)")
+		if i > 0 {
+			label = i18n.T("Another hostname (empty when done)")
+		}
+		host, err := prompt.AskText(fmt.Sprintf("spec.hostnames[%d]", i), label, "", false, validateIngressHost)
+		if err != nil {
+			return nil, err
+		}
+		if host == "" {
+			return hostnames, nil
+		}
+		hostnames = append(hostnames, host)
+	}
+}
+
+// askRouteRules asks for the rules of a route of type t one by one: the match
+// and the backends the matching requests go to
+func askRouteRules(k8sClient *client.K8sClient, t routeType) ([]interface{}, error) {
+	services, _ := k8sClient.ListNames(servicesGVR, namespace)
+
+	var rules []interface{}
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("spec.rules[%d]", i)
+		match, done, err := t.askMatch(key+".matches[0]", i)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return rules, nil
+		}
+		rule := map[string]interface{}{}
+		if match != nil {
+			rule["matches"] = []interface{}{match}
+		}
+
+		var backends []interface{}
+		for j := 0; ; j++ {
+			backendKey := fmt.Sprintf("%s.backendRefs[%d]", key, j)
+			service, err := prompt.PickOrEnter(backendKey+".name", i18n.T("Backend service"), services, "", validateServiceName)
+			if err != nil {
+				return nil, err
+			}
+			ports := servicePorts(k8sClient, service)
+			defaultPort := ""
+			if len(ports) > 0 {
+				defaultPort = ports[0]
+			}
+			port, err := prompt.PickOrEnter(backendKey+".port", i18n.T("Port of service %s", service), ports, defaultPort, validateGatewayPort)
+			if err != nil {
+				return nil, err
+			}
+			backend := map[string]interface{}{"name": service}
+			backend["port"], _ = strconv.ParseInt(port, 10, 32)
+			backends = append(backends, backend)
+
+			if !prompt.Confirm(fmt.Sprintf("%s.backendRefs[%d]", key, j+1), i18n.T("Split the traffic with another backend")) {
+				break
+			}
+		}
+		if len(backends) > 1 {
+			for j, b := range backends {
+				backend := b.(map[string]interface{})
+				weight, err := prompt.AskText(fmt.Sprintf("%s.backendRefs[%d].weight", key, j), i18n.T("Weight of %s", backend["name"]), "1", true, validateWeight)
+				if err != nil {
+					return nil, err
+				}
+				backend["weight"], _ = strconv.ParseInt(weight, 10, 32)
+			}
+		}
+		rule["backendRefs"] = backends
+		rules = append(rules, rule)
+	}
+}
+
+// askHTTPMatch asks for the path an HTTPRoute rule matches and its type. An
+// empty path ends the rules after the first one.
+func askHTTPMatch(key string, i int) (map[string]interface{}, bool, error) {
+	label, defaultPath := i18n.T("Path"), "/"
+	if i > 0 {
+		label, defaultPath = i18n.T("Path of another rule (empty when done)"), ""
+	}
+	path, err := prompt.AskText(key+".path.value", label, defaultPath, i == 0, validateIngressPath)
+	if err != nil || path == "" {
+		return nil, true, err
+	}
+	t, err := prompt.Choose(key+".path.type", i18n.T("Path type"), httpPathTypes, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	return httpPathMatch(httpPathTypes[t], path), false, nil
+}
+
+// askGRPCMatch asks for the gRPC service and method a GRPCRoute rule
+// matches. An empty service matches all requests in the first rule and ends
+// the rules after it.
+func askGRPCMatch(key string, i int) (map[string]interface{}, bool, error) {
+	label := i18n.T("gRPC service (e.g., shop.v1.Cart, empty for all)")
+	if i > 0 {
+		label = i18n.T("gRPC service of another rule (empty when done)")
+	}
+	service, err := prompt.AskText(key+".method.service", label, "", false, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if service == "" {
+		return nil, i > 0, nil
+	}
+	method, err := prompt.AskText(key+".method.method", i18n.T("Method of %s (empty for all)", service), "", false, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	return grpcMethodMatch(service, method), false, nil
+}
+
+// parseRouteRule parses a rule of --route-rule, match=service:port[@weight],...
+// with a match parsed for routes of type t
+func parseRouteRule(s string, t routeType) (map[string]interface{}, error) {
+	matchText, backendsText, ok := strings.Cut(s, "=")
+	if !ok || backendsText == "" {
+		return nil, i18n.Errorf("must be match=service:port[@weight],..., e.g., %s", t.matchExample)
+	}
+	rule := map[string]interface{}{}
+	if matchText != "" {
+		match, err := t.parseMatch(matchText)
+		if err != nil {
+			return nil, err
+		}
+		rule["matches"] = []interface{}{match}
+	}
+
+	var backends []interface{}
+	for _, b := range strings.Split(backendsText, ",") {
+		backend := map[string]interface{}{}
+		if serviceText, weight, ok := strings.Cut(b, "@"); ok {
+			if err := validateWeight(weight); err != nil {
+				return nil, err
+			}
+			backend["weight"], _ = strconv.ParseInt(weight, 10, 32)
+			b = serviceText
+		}
+		service, port, err := parseServicePort(b)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateGatewayPort(port); err != nil {
+			return nil, err
+		}
+		backend["name"] = service
+		backend["port"], _ = strconv.ParseInt(port, 10, 32)
+		backends = append(backends, backend)
+	}
+	rule["backendRefs"] = backends
+	return rule, nil
+}
+
+// parseHTTPMatch parses the path of an HTTPRoute rule, a prefix when it ends
+// in * (like --rule of ingresses) and exact otherwise
+func parseHTTPMatch(s string) (map[string]interface{}, error) {
+	pathType := "Exact"
+	if prefix, ok := strings.CutSuffix(s, "*"); ok {
+		s, pathType = prefix, "PathPrefix"
+	}
+	if err := validateIngressPath(s); err != nil {
+		return nil, err
+	}
+	return httpPathMatch(pathType, s), nil
+}
+
+// parseGRPCMatch parses the service[/method] of a GRPCRoute rule
+func parseGRPCMatch(s string) (map[string]interface{}, error) {
+	service, method, _ := strings.Cut(s, "/")
+	if service == "" {
+		return nil, i18n.Errorf("%q is not service[/method]", s)
+	}
+	return grpcMethodMatch(service, method), nil
+}
+
+// httpPathMatch is the match of an HTTPRoute rule for a path
+func httpPathMatch(pathType, path string) map[string]interface{} {
+	return map[string]interface{}{
+		"path": map[string]interface{}{"type": pathType, "value": path},
+	}
+}
+
+// grpcMethodMatch is the match of a GRPCRoute rule for a service and, unless
+// empty, a method of it
+func grpcMethodMatch(service, method string) map[string]interface{} {
+	m := map[string]interface{}{"type": "Exact", "service": service}
+	if method != "" {
+		m["method"] = method
+	}
+	return map[string]interface{}{"method": m}
+}
+
+// validateGatewayName checks the name of a Gateway, GatewayClass or listener
+func validateGatewayName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return i18n.Errorf("invalid name %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateGatewayPort checks a port of a listener or backend, which the
+// Gateway API only takes as a number
+func validateGatewayPort(port string) error {
+	number, err := strconv.Atoi(port)
+	if err != nil {
+		return i18n.Errorf("port %q must be a number", port)
+	}
+	if errs := validation.IsValidPortNum(number); len(errs) > 0 {
+		return i18n.Errorf("invalid port %q: %s", port, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateWeight checks the weight of a backend, a share of the traffic
+// relative to the other backends of the rule
+func validateWeight(weight string) error {
+	if n, err := strconv.Atoi(weight); err != nil || n < 0 || n > 1000000 {
+		return i18n.Errorf("weight %q must be a number from 0 to 1000000", weight)
+	}
+	return nil
+}
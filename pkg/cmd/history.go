@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/history"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	noHistory         bool
+	noHistoryDefaults bool
+
+	// collectedValues are the field values of the resource being created, recorded
+	// in history (nil for templates)
+	collectedValues map[string]interface{}
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List resources created with kubectl-create-resource",
+	Long: `List resources created with kubectl-create-resource, most recent last.
+
+Examples:
+  # Show the history
+  kubectl create-resource history
+
+  # Create entry 12 again under a new name
+  kubectl create-resource history rerun 12 --name=web-2 --set=spec.replicas=2`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+var historyRerunCmd = &cobra.Command{
+	Use:   "rerun <id>",
+	Short: "Create a resource from the history again, optionally with overrides",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistoryRerun,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false,
+		"don't record created resources in the local history")
+	rootCmd.Flags().BoolVar(&noHistoryDefaults, "no-history-defaults", false,
+		"don't default prompts to the values used the last time the type was created")
+
+	// Overrides for the re-created resource
+	historyRerunCmd.Flags().StringVar(&name, "name", "",
+		"name of the new resource (default: the recorded name)")
+	historyRerunCmd.Flags().StringArrayVar(&setValues, "set", []string{},
+		"override field values (e.g., --set=spec.replicas=3)")
+	historyRerunCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"only print the resource manifest without creating it")
+	historyRerunCmd.Flags().StringVarP(&output, "output", "o", "",
+		"output format (yaml or json) - implies dry-run")
+
+	historyCmd.AddCommand(historyRerunCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+// recordHistory adds a created resource to the local history
+func recordHistory(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, submitted, created *unstructured.Unstructured) {
+	if noHistory {
+		return
+	}
+	entry, err := history.Append(history.DefaultPath(), history.Entry{
+		Context:   k8sClient.ContextName(),
+		Server:    k8sClient.Server(),
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Namespace: created.GetNamespace(),
+		Name:      created.GetName(),
+		UID:       string(created.GetUID()),
+		Values:    collectedValues,
+		Manifest:  submitted.Object,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %v\n"), err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Recorded as history entry %d\n"), entry.ID)
+}
+
+// lastValues returns the values of the previous creation of gvr from the history,
+// used as prompt defaults
+func lastValues(gvr schema.GroupVersionResource) map[string]interface{} {
+	if noHistoryDefaults || !prompt.IsTerminal() || prompt.Scripted() {
+		return nil
+	}
+	values, err := history.LastValues(history.DefaultPath(), gvr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %v\n"), err)
+		return nil
+	}
+	return values
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	entries, err := history.Load(history.DefaultPath())
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println(i18n.T("No resources in history"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "ID\tCREATED\tCLUSTER\tRESOURCE\tNAMESPACE\tNAME")
+	for _, e := range entries {
+		cluster := e.Context
+		if cluster == "" {
+			cluster = e.Server
+		}
+		namespace := e.Namespace
+		if namespace == "" {
+			namespace = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			e.ID, e.Time.Local().Format("2006-01-02 15:04"), cluster, formatGVR(e.GVR()), namespace, e.Name)
+	}
+	return nil
+}
+
+func runHistoryRerun(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return i18n.Errorf("invalid history entry %q", args[0])
+	}
+	targetContexts, err = resolveTargetContexts()
+	if err != nil {
+		return err
+	}
+	entry, err := history.Get(history.DefaultPath(), id)
+	if err != nil {
+		return err
+	}
+	if history.Redacted(entry.GVR()) {
+		return i18n.Errorf("history entry %d is a Secret, whose data isn't kept in the history; create it again instead", id)
+	}
+	if entry.Manifest == nil {
+		return i18n.Errorf("history entry %d has no recorded manifest", id)
+	}
+
+	if output != "" {
+		dryRun = true
+	}
+
+	// The recorded namespace applies unless -n is given
+	if !cmd.Flags().Changed("namespace") {
+		namespace = entry.Namespace
+	}
+
+	overrides, err := prompt.ParseSetValues(setValues)
+	if err != nil {
+		return i18n.Errorf("failed to parse --set values: %w", err)
+	}
+	if name != "" {
+		overrides["metadata.name"] = name
+	}
+
+	obj := entry.Object()
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+	applySetValues(obj, overrides)
+	if err := applyRequiredMetadata(entry.GVR(), obj, true); err != nil {
+		return err
+	}
+
+	// Record the values of the new resource, including the overrides
+	if entry.Values != nil {
+		collectedValues = make(map[string]interface{})
+		for k, v := range entry.Values {
+			collectedValues[k] = v
+		}
+		for k, v := range overrides {
+			collectedValues[k] = v
+		}
+	}
+
+	if dryRun {
+		recordAudit(nil, entry.GVR(), obj.GetNamespace(), obj.GetName(), audit.OperationDryRun, nil)
+		return generator.PrintManifest(obj, output)
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+	if entry.Server != "" && k8sClient.Server() != entry.Server {
+		fmt.Fprintf(os.Stderr, i18n.T("Note: entry %d was created in %s, creating in %s\n"), id, entry.Server, k8sClient.Server())
+	}
+
+	return createInTargets(k8sClient, entry.GVR(), obj)
+}
+
+// formatGVR formats a resource type as resource.group
+func formatGVR(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Resource
+	}
+	return gvr.Resource + "." + gvr.Group
+}
@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ingresses is the resource type of the ingress preset
+var ingresses = schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}
+
+// ingressClasses are the IngressClasses of Ingresses
+var ingressClasses = classType{
+	gvr:                schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingressclasses"},
+	controllerField:    "spec.controller",
+	defaultAnnotations: []string{"ingressclass.kubernetes.io/is-default-class"},
+}
+
+// pathTypes are the path types of Ingress paths, the default first
+var pathTypes = []string{"Prefix", "Exact", "ImplementationSpecific"}
+
+var servicesGVR = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+
+var (
+	ingressClass          string
+	ingressRules          []string
+	ingressDefaultBackend string
+)
+
+func init() {
+	typePresets[ingresses] = preset{
+		flags:   []string{"ingress-class", "rule", "default-backend"},
+		fields:  []string{"spec.ingressClassName", "spec.rules", "spec.tls", "spec.defaultBackend"},
+		prepare: prepareIngress,
+	}
+
+	rootCmd.Flags().StringVar(&ingressClass, "ingress-class", "",
+		"with ingress, the IngressClass of the Ingress (picked from a list in a terminal)")
+	rootCmd.Flags().StringArrayVar(&ingressRules, "rule", []string{},
+		"with ingress, a rule host/path=service:port[,tls[=secret]] like kubectl create ingress; a path ending in * is a prefix (e.g., --rule='shop.example.com/api*=api:80,tls=shop-tls')")
+	rootCmd.Flags().StringVar(&ingressDefaultBackend, "default-backend", "",
+		"with ingress, the service:port of requests no rule matches")
+}
+
+// ingressPath is a path of an Ingress rule and the Service port it routes to
+type ingressPath struct {
+	host     string
+	path     string
+	pathType string
+	service  string
+	port     string // Number or name of the port
+}
+
+// ingressTLS is a TLS entry of an Ingress: its hosts and the Secret of their
+// certificate, or the controller's default certificate without one
+type ingressTLS struct {
+	hosts  []string
+	secret string
+}
+
+// prepareIngress sets the class, rules, TLS and default backend of the
+// Ingress from their flags, or asks for them in a terminal when its rules
+// aren't set yet
+func prepareIngress(k8sClient *client.K8sClient, values *prompt.CollectedValues) error {
+	if values == nil {
+		return rejectPresetFlagsWithFrom(ingresses)
+	}
+	ask := !example && prompt.CanPrompt()
+
+	switch {
+	case ingressClass != "":
+		values.Set("spec.ingressClassName", ingressClass, prompt.SourceFlag)
+	case !values.Has("spec.ingressClassName") && ask:
+		classes, err := listClasses(k8sClient, ingressClasses)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: failed to list ingress classes: %v\n"), err)
+		}
+		if len(classes) > 0 {
+			name, err := prompt.PickClass("spec.ingressClassName", i18n.T("Ingress class"), classes)
+			if err != nil {
+				return err
+			}
+			values.Set("spec.ingressClassName", name, prompt.SourcePrompt)
+		}
+	}
+
+	if ingressDefaultBackend != "" {
+		service, port, err := parseServicePort(ingressDefaultBackend)
+		if err != nil {
+			return i18n.Errorf("invalid --default-backend %q: %w", ingressDefaultBackend, err)
+		}
+		setServiceBackend(values, "spec.defaultBackend", service, port, prompt.SourceFlag)
+	}
+
+	switch {
+	case len(ingressRules) > 0:
+		paths, tls, err := parseIngressRules(ingressRules)
+		if err != nil {
+			return err
+		}
+		setIngressRules(values, paths, tls, prompt.SourceFlag)
+	case values.Has("spec.rules") || values.Has("spec.defaultBackend"):
+	case ask:
+		paths, tls, err := askIngressRules(k8sClient)
+		if err != nil {
+			return err
+		}
+		setIngressRules(values, paths, tls, prompt.SourcePrompt)
+	default:
+		return i18n.Errorf("an Ingress needs rules or a default backend, give --rule or --default-backend")
+	}
+	return nil
+}
+
+// askIngressRules asks for the hosts of the Ingress, the paths of each host
+// and their backends, picked from the Services of the namespace, then whether
+// to serve the hosts over TLS with a Secret of the namespace
+func askIngressRules(k8sClient *client.K8sClient) ([]ingressPath, []ingressTLS, error) {
+	services, _ := k8sClient.ListNames(servicesGVR, namespace)
+
+	var paths []ingressPath
+	var hosts []string
+	for i := 0; ; i++ {
+		label := i18n.T("Host (e.g., shop.example.com, empty for any host)")
+		if i > 0 {
+			label = i18n.T("Host of another rule (empty when done)")
+		}
+		host, err := prompt.AskText(fmt.Sprintf("spec.rules[%d].host", i), label, "", false, validateIngressHost)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i > 0 && host == "" {
+			break
+		}
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+
+		for j := 0; ; j++ {
+			key := fmt.Sprintf("spec.rules[%d].http.paths[%d]", i, j)
+			label, defaultPath := i18n.T("Path"), "/"
+			if j > 0 {
+				label, defaultPath = i18n.T("Another path (empty when done)"), ""
+			}
+			path, err := prompt.AskText(key+".path", label, defaultPath, j == 0, validateIngressPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			if path == "" {
+				break
+			}
+			t, err := prompt.Choose(key+".pathType", i18n.T("Path type"), pathTypes, 0)
+			if err != nil {
+				return nil, nil, err
+			}
+			service, err := prompt.PickOrEnter(key+".backend.service.name", i18n.T("Backend service"), services, "", validateServiceName)
+			if err != nil {
+				return nil, nil, err
+			}
+			ports := servicePorts(k8sClient, service)
+			defaultPort := ""
+			if len(ports) > 0 {
+				defaultPort = ports[0]
+			}
+			port, err := prompt.PickOrEnter(key+".backend.service.port", i18n.T("Port of service %s", service), ports, defaultPort, validateServicePort)
+			if err != nil {
+				return nil, nil, err
+			}
+			paths = append(paths, ingressPath{host: host, path: path, pathType: pathTypes[t], service: service, port: port})
+		}
+	}
+
+	if len(hosts) == 0 || !prompt.Confirm("tls", i18n.T("Serve %s over TLS", strings.Join(hosts, ", "))) {
+		return paths, nil, nil
+	}
+	secret, err := prompt.PickOrEnter("spec.tls[0].secretName", i18n.T("Secret of the certificate"), tlsSecrets(k8sClient), "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return paths, []ingressTLS{{hosts: hosts, secret: secret}}, nil
+}
+
+// servicePorts returns the port numbers of a Service of the namespace, or
+// nil when it can't be read
+func servicePorts(k8sClient *client.K8sClient, service string) []string {
+	if k8sClient.Offline() {
+		return nil
+	}
+	obj, err := k8sClient.GetResource(servicesGVR, namespace, service)
+	if err != nil {
+		return nil
+	}
+	specPorts, _, _ := unstructured.NestedSlice(obj.Object, "spec", "ports")
+	var ports []string
+	for _, p := range specPorts {
+		if port, ok := p.(map[string]interface{})["port"]; ok {
+			ports = append(ports, fmt.Sprint(port))
+		}
+	}
+	return ports
+}
+
+// tlsSecrets returns the names of the TLS Secrets of the namespace
+func tlsSecrets(k8sClient *client.K8sClient) []string {
+	if k8sClient.Offline() {
+		return nil
+	}
+	objs, err := k8sClient.ListObjects(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, namespace)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, obj := range objs {
+		if t, _, _ := unstructured.NestedString(obj.Object, "type"); t == "kubernetes.io/tls" {
+			names = append(names, obj.GetName())
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// parseIngressRules parses the values of --rule, in the format of kubectl
+// create ingress: host/path=service:port[,tls[=secret]]. A path ending in *
+// is a Prefix path, other paths are Exact.
+func parseIngressRules(rules []string) ([]ingressPath, []ingressTLS, error) {
+	var paths []ingressPath
+	var tls []ingressTLS
+	for _, rule := range rules {
+		parts := strings.Split(rule, ",")
+		route, backend, ok := strings.Cut(parts[0], "=")
+		slash := strings.Index(route, "/")
+		if !ok || slash < 0 {
+			return nil, nil, i18n.Errorf("invalid --rule %q, must be host/path=service:port[,tls[=secret]]", rule)
+		}
+		p := ingressPath{host: route[:slash], path: route[slash:], pathType: "Exact"}
+		if strings.HasSuffix(p.path, "*") {
+			p.path, p.pathType = strings.TrimSuffix(p.path, "*"), "Prefix"
+		}
+		if p.host != "" {
+			if err := validateIngressHost(p.host); err != nil {
+				return nil, nil, i18n.Errorf("invalid --rule %q: %w", rule, err)
+			}
+		}
+		var err error
+		if p.service, p.port, err = parseServicePort(backend); err != nil {
+			return nil, nil, i18n.Errorf("invalid --rule %q: %w", rule, err)
+		}
+		paths = append(paths, p)
+
+		for _, option := range parts[1:] {
+			name, secret, _ := strings.Cut(option, "=")
+			if name != "tls" {
+				return nil, nil, i18n.Errorf("invalid --rule %q, unknown option %q", rule, option)
+			}
+			if p.host == "" {
+				return nil, nil, i18n.Errorf("invalid --rule %q, tls needs a host", rule)
+			}
+			i := slices.IndexFunc(tls, func(t ingressTLS) bool { return t.secret == secret })
+			if i < 0 {
+				tls = append(tls, ingressTLS{secret: secret})
+				i = len(tls) - 1
+			}
+			if !slices.Contains(tls[i].hosts, p.host) {
+				tls[i].hosts = append(tls[i].hosts, p.host)
+			}
+		}
+	}
+	return paths, tls, nil
+}
+
+// parseServicePort parses service:port, the port as a number or a name
+func parseServicePort(s string) (service, port string, err error) {
+	service, port, ok := strings.Cut(s, ":")
+	if !ok || service == "" || port == "" {
+		return "", "", i18n.Errorf("%q is not service:port", s)
+	}
+	if err := validateServiceName(service); err != nil {
+		return "", "", err
+	}
+	if err := validateServicePort(port); err != nil {
+		return "", "", err
+	}
+	return service, port, nil
+}
+
+// setIngressRules sets the rules of the Ingress to paths, grouped by host in
+// the order they come, and its TLS entries to tls
+func setIngressRules(values *prompt.CollectedValues, paths []ingressPath, tls []ingressTLS, source prompt.Source) {
+	var hosts []string
+	pathCounts := map[string]int{}
+	for _, p := range paths {
+		i := slices.Index(hosts, p.host)
+		if i < 0 {
+			hosts = append(hosts, p.host)
+			i = len(hosts) - 1
+			if p.host != "" {
+				values.Set(fmt.Sprintf("spec.rules[%d].host", i), p.host, source)
+			}
+		}
+		key := fmt.Sprintf("spec.rules[%d].http.paths[%d]", i, pathCounts[p.host])
+		pathCounts[p.host]++
+		values.Set(key+".path", p.path, source)
+		values.Set(key+".pathType", p.pathType, source)
+		setServiceBackend(values, key+".backend", p.service, p.port, source)
+	}
+
+	for i, t := range tls {
+		hosts := make([]interface{}, len(t.hosts))
+		for j, host := range t.hosts {
+			hosts[j] = host
+		}
+		values.Set(fmt.Sprintf("spec.tls[%d].hosts", i), hosts, source)
+		if t.secret != "" {
+			values.Set(fmt.Sprintf("spec.tls[%d].secretName", i), t.secret, source)
+		}
+	}
+}
+
+// setServiceBackend sets the backend at path to the port of service, by
+// number or by name
+func setServiceBackend(values *prompt.CollectedValues, path, service, port string, source prompt.Source) {
+	values.Set(path+".service.name", service, source)
+	if number, err := strconv.ParseInt(port, 10, 32); err == nil {
+		values.Set(path+".service.port.number", number, source)
+	} else {
+		values.Set(path+".service.port.name", port, source)
+	}
+}
+
+// validateIngressHost checks a host of an Ingress rule, which may start with a
+// wildcard label (e.g., *.example.com)
+func validateIngressHost(host string) error {
+	check := validation.IsDNS1123Subdomain
+	if strings.HasPrefix(host, "*.") {
+		check = validation.IsWildcardDNS1123Subdomain
+	}
+	if errs := check(host); len(errs) > 0 {
+		return i18n.Errorf("invalid host %q: %s", host, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateIngressPath checks that an Ingress path is absolute
+func validateIngressPath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return i18n.Errorf("path %q must start with /", path)
+	}
+	return nil
+}
+
+// validateServiceName checks the name of a backend Service
+func validateServiceName(name string) error {
+	if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+		return i18n.Errorf("invalid service name %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateServicePort checks a port of a backend Service, by number or name
+func validateServicePort(port string) error {
+	errs := validation.IsValidPortName(port)
+	if number, err := strconv.Atoi(port); err == nil {
+		errs = validation.IsValidPortNum(number)
+	}
+	if len(errs) > 0 {
+		return i18n.Errorf("invalid port %q: %s", port, strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var inspectStatus bool
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <resource-type> <name>",
+	Short: "Print the field paths and values of an existing resource",
+	Long: `Print an existing resource flattened to one field path and value per line, in the
+form --set takes, so exact paths can be copied into the flags of the next create.
+Status, server-generated metadata and the last applied configuration are left out.
+
+Examples:
+  # Field paths of a deployment's containers
+  kubectl create-resource inspect deployment web | grep containers
+
+  # A queue, with its status
+  kubectl create-resource inspect queue team-a --status`,
+	Args: cobra.ExactArgs(2),
+	RunE: runInspect,
+}
+
+func init() {
+	inspectCmd.Flags().BoolVar(&inspectStatus, "status", false,
+		"include the status of the resource")
+
+	rootCmd.AddCommand(inspectCmd)
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	if offline {
+		return i18n.Errorf("inspect reads from the cluster and cannot be used with --offline")
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	gvr, err := k8sClient.ResolveResourceType(args[0])
+	if err != nil {
+		return i18n.Errorf("failed to resolve resource type %q: %w", args[0], err)
+	}
+	if !k8sClient.IsNamespaced(gvr) {
+		namespace = ""
+	}
+
+	obj, err := k8sClient.GetResource(gvr, namespace, args[1])
+	if err != nil {
+		return i18n.Errorf("failed to get %s %q: %w", gvr.Resource, args[1], err)
+	}
+
+	for _, line := range inspectLines(obj, inspectStatus) {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// inspectLines flattens obj to path=value lines sorted by path, without the
+// fields a create can't set, and its status unless withStatus
+func inspectLines(obj *unstructured.Unstructured, withStatus bool) []string {
+	fields := inspectFields(obj, withStatus)
+	paths := slices.Sorted(maps.Keys(fields))
+	lines := make([]string, len(paths))
+	for i, path := range paths {
+		lines[i] = path + "=" + fields[path]
+	}
+	return lines
+}
+
+// inspectFields flattens obj to the values of its field paths, formatted by
+// inspectValue, without the fields a create can't set, and its status unless
+// withStatus
+func inspectFields(obj *unstructured.Unstructured, withStatus bool) map[string]string {
+	cleaned := cleanTemplateForCreation(obj, obj.GetName(), "")
+	delete(cleaned.Object, "apiVersion")
+	delete(cleaned.Object, "kind")
+	unstructured.RemoveNestedField(cleaned.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	if withStatus {
+		if status, ok := obj.Object["status"]; ok {
+			cleaned.Object["status"] = status
+		}
+	}
+
+	fields := map[string]string{}
+	for path, val := range client.FlattenObject(cleaned.Object) {
+		// Lists are also flattened item by item
+		if list, ok := val.([]interface{}); ok && len(list) > 0 {
+			continue
+		}
+		fields[path] = inspectValue(val)
+	}
+	return fields
+}
+
+// inspectValue formats val as --set takes it: strings as they are, unless they
+// span lines, and empty lists as JSON
+func inspectValue(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		if strings.ContainsAny(v, "\n\r") {
+			return strconv.Quote(v)
+		}
+		return v
+	case []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(v)
+	}
+}
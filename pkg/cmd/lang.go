@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var lang string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "",
+		"language of prompts, messages and help (e.g. es; defaults to LC_ALL, LC_MESSAGES or LANG)")
+
+	// Help is shown before cobra runs the initializers, so it selects the
+	// language itself
+	defaultHelp := rootCmd.HelpFunc()
+	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		applyLanguage()
+		translateHelp(cmd)
+		defaultHelp(cmd, args)
+	})
+}
+
+// applyLanguage selects the --lang language, or the locale's. Only an explicit
+// --lang without translations is worth a warning, locales fall back to English.
+func applyLanguage() {
+	if lang == "" {
+		i18n.SetLanguage(i18n.Detect())
+		return
+	}
+	if err := i18n.SetLanguage(lang); err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %v\n"), err)
+	}
+}
+
+// translateHelp translates the descriptions and flag usages of cmd and its
+// subcommands, as listed in its help
+func translateHelp(cmd *cobra.Command) {
+	cmd.Short = i18n.T(cmd.Short)
+	cmd.Long = i18n.T(cmd.Long)
+	translate := func(f *pflag.Flag) { f.Usage = i18n.T(f.Usage) }
+	cmd.LocalFlags().VisitAll(translate)
+	cmd.InheritedFlags().VisitAll(translate)
+	for _, sub := range cmd.Commands() {
+		sub.Short = i18n.T(sub.Short)
+	}
+}
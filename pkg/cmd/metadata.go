@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/config"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// applyRequiredMetadata sets the labels and annotations the config requires on
+// obj. Values already set (e.g., with --set metadata.labels.team=x) are checked
+// against the allowed values; missing ones are prompted for, or take their
+// default when interactive is false or stdin is not a terminal.
+func applyRequiredMetadata(gvr schema.GroupVersionResource, obj *unstructured.Unstructured, interactive bool) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	requiredLabels, requiredAnnotations := cfg.RequiredMetadataFor(gvr)
+	interactive = interactive && prompt.CanPrompt()
+
+	labels, err := requireMetadata("label", obj.GetLabels(), requiredLabels, interactive)
+	if err != nil {
+		return err
+	}
+	if len(labels) > 0 {
+		obj.SetLabels(labels)
+	}
+	annotations, err := requireMetadata("annotation", obj.GetAnnotations(), requiredAnnotations, interactive)
+	if err != nil {
+		return err
+	}
+	if len(annotations) > 0 {
+		obj.SetAnnotations(annotations)
+	}
+	return nil
+}
+
+// requireMetadata returns current with the required keys filled in
+func requireMetadata(kind string, current map[string]string, required []config.RequiredMetadata, interactive bool) (map[string]string, error) {
+	if len(required) == 0 {
+		return current, nil
+	}
+	if current == nil {
+		current = make(map[string]string)
+	}
+
+	validate := func(string) error { return nil }
+	if kind == "label" {
+		validate = func(v string) error {
+			if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+				return fmt.Errorf("%s", strings.Join(errs, "; "))
+			}
+			return nil
+		}
+	}
+
+	for _, r := range required {
+		if value, ok := current[r.Key]; ok && value != "" {
+			if len(r.Allowed) > 0 && !slices.Contains(r.Allowed, value) {
+				return nil, i18n.Errorf("%s %s=%s is not allowed by the config (use %s)", kind, r.Key, value, strings.Join(r.Allowed, ", "))
+			}
+			continue
+		}
+
+		if !interactive {
+			if r.Default == "" && strings.Contains(r.Key, ".") {
+				return nil, i18n.Errorf("%s %s is required by the config and has no default, run interactively to set it", kind, r.Key)
+			}
+			if r.Default == "" {
+				return nil, i18n.Errorf("%s %s is required by the config, set it with --set metadata.%ss.%s=<value>", kind, r.Key, kind, r.Key)
+			}
+			current[r.Key] = r.Default
+			fmt.Fprintf(os.Stderr, i18n.T("Using %s %s=%s required by the config\n"), kind, r.Key, r.Default)
+			continue
+		}
+
+		value, err := prompt.PromptMetadataValue(kind, r.Key, r.Description, r.Default, r.Allowed, validate)
+		if err != nil {
+			return nil, i18n.Errorf("%s %s is required by the config: %w", kind, r.Key, err)
+		}
+		current[r.Key] = value
+	}
+	return current, nil
+}
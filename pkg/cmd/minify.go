@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// stripDefaults enables minifyManifest for --from and export
+var stripDefaults bool
+
+// minifyManifest removes the fields of cleaned that equal what the server would
+// fill in by itself, so templates only carry the fields that were chosen.
+// original is the object as read from the server.
+//
+// Fields no field manager set, and fields holding their schema default, are left
+// out of a minimal copy, which is created with server-side dry-run. A field is
+// then removed only if the dry-run result has the same value, so creating the
+// minified manifest yields the same object. On failure cleaned is returned as is.
+func minifyManifest(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, original, cleaned *unstructured.Unstructured) *unstructured.Unstructured {
+	minimal := cleaned
+	if managed := original.GetManagedFields(); len(managed) > 0 {
+		minimal = generator.ManagedOnly(minimal, managed)
+	}
+	if resourceSchema, err := k8sClient.GetResourceSchema(gvr); err == nil {
+		minimal = generator.WithoutSchemaDefaults(minimal, resourceSchema.Fields)
+	}
+	before := generator.CountFields(cleaned)
+	if generator.CountFields(minimal) == before {
+		fmt.Fprintf(os.Stderr, i18n.T("No defaulted fields found in %s\n"), original.GetName())
+		return cleaned
+	}
+
+	// Dry-run the minimal copy under a generated name, so the original doesn't
+	// make it fail with AlreadyExists
+	probe := minimal.DeepCopy()
+	probe.SetName("")
+	probe.SetGenerateName(original.GetName() + "-")
+	probe.SetNamespace(namespace)
+	defaulted, err := k8sClient.DryRunCreateResource(gvr, namespace, probe)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: could not dry-run a minimal %s, keeping defaulted fields: %v\n"), gvr.Resource, err)
+		return cleaned
+	}
+
+	minified := generator.StripDefaults(cleaned, minimal, defaulted)
+	fmt.Fprintf(os.Stderr, i18n.T("Removed %d of %d fields defaulted by the server\n"), before-generator.CountFields(minified), before)
+	return minified
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// resolveNamespace picks the namespace of a namespaced resource when -n wasn't
+// given: the kubeconfig context's namespace, or one chosen from the cluster's
+// namespaces in a terminal, preselecting the context's
+func resolveNamespace(k8sClient *client.K8sClient) error {
+	if namespaceExplicit || k8sClient.Offline() {
+		return nil
+	}
+	namespace = client.ContextNamespace(kubeconfig, k8sClient.ContextName())
+	if example || len(targetContexts) > 1 || !prompt.CanPrompt() {
+		return nil
+	}
+
+	// Fall back to entering the namespace when it can't be listed
+	namespaces, _ := k8sClient.ListNames(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, "")
+	sort.Strings(namespaces)
+	picked, err := prompt.PickNamespace(namespaces, namespace)
+	if err != nil {
+		return err
+	}
+	namespace = picked
+	return nil
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// podSecurityLevels are the Pod Security Standards levels of --pod-security
+var podSecurityLevels = []string{"privileged", "baseline", "restricted"}
+
+// podSecurityLabels are the namespace labels set to the --pod-security level
+var podSecurityLabels = []string{
+	"pod-security.kubernetes.io/enforce",
+	"pod-security.kubernetes.io/warn",
+	"pod-security.kubernetes.io/audit",
+}
+
+// networkPolicies are the values of --network-policy, besides none
+var networkPolicies = []string{
+	generator.NetworkPolicyDenyIngress,
+	generator.NetworkPolicySameNamespace,
+	generator.NetworkPolicyDenyAll,
+}
+
+// Resources asked about by the namespace preset, in order
+var (
+	quotaResources = []string{"requests.cpu", "requests.memory", "limits.cpu", "limits.memory", "pods"}
+	limitResources = []string{"cpu", "memory"}
+)
+
+var (
+	podSecurity        string
+	quotaFlags         []string
+	defaultRequestFlag []string
+	defaultLimitFlag   []string
+	networkPolicy      string
+
+	namespaceDefaults generator.NamespaceDefaults
+)
+
+func init() {
+	typePresets[schema.GroupResource{Resource: "namespaces"}] = preset{
+		flags:   []string{"pod-security", "quota", "default-request", "default-limit", "network-policy"},
+		prepare: prepareNamespace,
+		bundle:  bundleNamespace,
+	}
+
+	rootCmd.Flags().StringVar(&podSecurity, "pod-security", "",
+		"with namespace, label it to enforce a Pod Security Standards level: privileged, baseline, restricted or none")
+	rootCmd.Flags().StringArrayVar(&quotaFlags, "quota", []string{},
+		"with namespace, also create a ResourceQuota with this hard limit (e.g., --quota=requests.cpu=4)")
+	rootCmd.Flags().StringArrayVar(&defaultRequestFlag, "default-request", []string{},
+		"with namespace, also create a LimitRange with this container default request (e.g., --default-request=cpu=100m)")
+	rootCmd.Flags().StringArrayVar(&defaultLimitFlag, "default-limit", []string{},
+		"with namespace, also create a LimitRange with this container default limit (e.g., --default-limit=memory=512Mi)")
+	rootCmd.Flags().StringVar(&networkPolicy, "network-policy", "",
+		"with namespace, also create a default NetworkPolicy: deny-ingress, same-namespace, deny-all or none")
+}
+
+// prepareNamespace checks the flags of the namespace preset, or asks about
+// what to provision along with the namespace when none is given
+func prepareNamespace(_ *client.K8sClient, _ *prompt.CollectedValues) error {
+	var err error
+	if namespaceDefaults.Quota, err = parseQuantities("quota", quotaFlags); err != nil {
+		return err
+	}
+	if namespaceDefaults.DefaultRequests, err = parseQuantities("default-request", defaultRequestFlag); err != nil {
+		return err
+	}
+	if namespaceDefaults.DefaultLimits, err = parseQuantities("default-limit", defaultLimitFlag); err != nil {
+		return err
+	}
+
+	flagsGiven := false
+	for _, flag := range typePresets[schema.GroupResource{Resource: "namespaces"}].flags {
+		flagsGiven = flagsGiven || presetFlagsSet[flag]
+	}
+	if !flagsGiven && !example && prompt.CanPrompt() {
+		if err := askNamespaceDefaults(); err != nil {
+			return err
+		}
+	}
+
+	switch podSecurity {
+	case "", "none":
+		podSecurity = ""
+	case podSecurityLevels[0], podSecurityLevels[1], podSecurityLevels[2]:
+	default:
+		return i18n.Errorf("invalid --pod-security %q, must be privileged, baseline, restricted or none", podSecurity)
+	}
+	switch networkPolicy {
+	case "", "none":
+		networkPolicy = ""
+	case generator.NetworkPolicyDenyIngress, generator.NetworkPolicySameNamespace, generator.NetworkPolicyDenyAll:
+	default:
+		return i18n.Errorf("invalid --network-policy %q, must be deny-ingress, same-namespace, deny-all or none", networkPolicy)
+	}
+	namespaceDefaults.NetworkPolicy = networkPolicy
+	return nil
+}
+
+// askNamespaceDefaults asks for the Pod Security level, ResourceQuota,
+// LimitRange and NetworkPolicy of the namespace
+func askNamespaceDefaults() error {
+	levels := []string{i18n.T("No Pod Security labels")}
+	for _, level := range podSecurityLevels {
+		levels = append(levels, i18n.T("Enforce the %s Pod Security Standard", level))
+	}
+	i, err := prompt.Choose("pod-security", i18n.T("Pod Security Standard of the namespace"), levels, 0)
+	if err != nil {
+		return err
+	}
+	if i > 0 {
+		podSecurity = podSecurityLevels[i-1]
+	}
+
+	if prompt.Confirm("quota", i18n.T("Add a ResourceQuota")) {
+		if namespaceDefaults.Quota, err = askQuantities("quota", quotaResources); err != nil {
+			return err
+		}
+	}
+	if prompt.Confirm("limit-range", i18n.T("Add a LimitRange with container defaults")) {
+		if namespaceDefaults.DefaultRequests, err = askQuantities("default-request", limitResources); err != nil {
+			return err
+		}
+		if namespaceDefaults.DefaultLimits, err = askQuantities("default-limit", limitResources); err != nil {
+			return err
+		}
+	}
+
+	policies := []string{
+		i18n.T("No NetworkPolicy"),
+		i18n.T("Deny all ingress to the namespace's pods"),
+		i18n.T("Allow ingress only from the namespace's pods"),
+		i18n.T("Deny all ingress and egress"),
+	}
+	i, err = prompt.Choose("network-policy", i18n.T("Default NetworkPolicy of the namespace"), policies, 0)
+	if err != nil {
+		return err
+	}
+	if i > 0 {
+		networkPolicy = networkPolicies[i-1]
+	}
+	return nil
+}
+
+// askQuantities asks for a quantity of each of resources, skipping those
+// left empty
+func askQuantities(kind string, resources []string) (map[string]string, error) {
+	quantities := map[string]string{}
+	for _, res := range resources {
+		label := i18n.T("%s %s (empty to skip)", kind, res)
+		quantity, err := prompt.AskQuantity(kind+"."+res, label, "", false)
+		if err != nil {
+			return nil, err
+		}
+		if quantity != "" {
+			quantities[res] = quantity
+		}
+	}
+	return quantities, nil
+}
+
+// parseQuantities parses the resource=quantity values of --flag
+func parseQuantities(flag string, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	quantities := make(map[string]string, len(values))
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, i18n.Errorf("invalid --%s %q, must be resource=quantity", flag, v)
+		}
+		if err := prompt.ValidateQuantity(parts[1]); err != nil {
+			return nil, i18n.Errorf("invalid --%s %q: %w", flag, v, err)
+		}
+		quantities[parts[0]] = parts[1]
+	}
+	return quantities, nil
+}
+
+// bundleNamespace labels the namespace with its Pod Security level and returns
+// the ResourceQuota, LimitRange and NetworkPolicy to create in it
+func bundleNamespace(manifest *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	if podSecurity != "" {
+		labels := manifest.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for _, label := range podSecurityLabels {
+			labels[label] = podSecurity
+		}
+		manifest.SetLabels(labels)
+	}
+
+	objs := generator.GenerateNamespaceDefaults(manifest.GetName(), namespaceDefaults)
+	if len(objs) > 0 && (count > 1 || len(targetContexts) > 0) {
+		return nil, i18n.Errorf("--quota, --default-request, --default-limit and --network-policy cannot be combined with --count, --contexts or --all-contexts")
+	}
+	return objs, nil
+}
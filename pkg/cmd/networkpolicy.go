@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// networkPolicyType is the resource type of the networkpolicy preset
+var networkPolicyType = schema.GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"}
+
+// policyDirection is the traffic a NetworkPolicy restricts in one direction
+type policyDirection struct {
+	policyType string // Ingress or Egress
+	field      string // Field of the rules in the spec
+	peers      string // Field of the peers in a rule
+}
+
+var policyDirections = []policyDirection{
+	{policyType: "Ingress", field: "ingress", peers: "from"},
+	{policyType: "Egress", field: "egress", peers: "to"},
+}
+
+// Peers an ingress or egress rule allows, as asked by the networkpolicy preset
+var peerKinds = []string{
+	"Pods of this namespace",
+	"Pods of other namespaces",
+	"IP addresses (CIDR)",
+	"Anywhere",
+}
+
+func init() {
+	typePresets[networkPolicyType] = preset{
+		fields:    []string{"spec.podSelector", "spec.policyTypes", "spec.ingress", "spec.egress"},
+		prepare:   prepareNetworkPolicy,
+		summarize: summarizeNetworkPolicy,
+	}
+}
+
+// prepareNetworkPolicy walks through the pods the policy applies to and its
+// ingress and egress rules, unless the spec is given otherwise (e.g., with
+// --set). The pod selector defaults to all pods of the namespace.
+func prepareNetworkPolicy(_ *client.K8sClient, values *prompt.CollectedValues) error {
+	if values == nil {
+		return nil
+	}
+	given := false
+	for _, field := range typePresets[networkPolicyType].fields {
+		given = given || values.Has(field)
+	}
+	if !given && !example && prompt.CanPrompt() {
+		return askNetworkPolicy(values)
+	}
+	if !values.Has("spec.podSelector") {
+		values.Set("spec.podSelector", map[string]interface{}{}, prompt.SourceDefault)
+	}
+	return nil
+}
+
+// askNetworkPolicy asks for the pod selector, the directions of traffic the
+// policy restricts and the rules of each
+func askNetworkPolicy(values *prompt.CollectedValues) error {
+	podSelector, err := askSelector("spec.podSelector", i18n.T("Labels of the pods the policy applies to (key=value,..., empty for all pods)"))
+	if err != nil {
+		return err
+	}
+	values.Set("spec.podSelector", podSelector, prompt.SourcePrompt)
+
+	types := make([]string, len(policyDirections))
+	for i, d := range policyDirections {
+		types[i] = d.policyType
+	}
+	picked, err := prompt.ChooseMany("spec.policyTypes", i18n.T("Traffic the policy restricts"), types, []bool{true})
+	if err != nil {
+		return err
+	}
+	if len(picked) == 0 {
+		return i18n.Errorf("a NetworkPolicy restricts Ingress, Egress or both")
+	}
+
+	var policyTypes []interface{}
+	for _, i := range picked {
+		d := policyDirections[i]
+		policyTypes = append(policyTypes, d.policyType)
+		rules, err := askPolicyRules(d)
+		if err != nil {
+			return err
+		}
+		if len(rules) > 0 {
+			values.Set("spec."+d.field, rules, prompt.SourcePrompt)
+		}
+	}
+	values.Set("spec.policyTypes", policyTypes, prompt.SourcePrompt)
+	return nil
+}
+
+// askPolicyRules asks for the rules of the traffic allowed in direction d,
+// one peer and its ports at a time, until no more rules are picked
+func askPolicyRules(d policyDirection) ([]interface{}, error) {
+	label := i18n.T("Allow incoming traffic from")
+	if d.policyType == "Egress" {
+		label = i18n.T("Allow outgoing traffic to")
+	}
+
+	var rules []interface{}
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("spec.%s[%d]", d.field, i)
+		items := []string{i18n.T("No more rules")}
+		if i == 0 {
+			items[0] = i18n.T("Nothing, block all of it")
+		}
+		for _, kind := range peerKinds {
+			items = append(items, i18n.T(kind))
+		}
+		kind, err := prompt.Choose(key, label, items, 0)
+		if err != nil {
+			return nil, err
+		}
+		if kind == 0 {
+			return rules, nil
+		}
+
+		peerKey := fmt.Sprintf("%s.%s[0]", key, d.peers)
+		peer := map[string]interface{}{}
+		switch peerKinds[kind-1] {
+		case "Pods of this namespace":
+			if peer["podSelector"], err = askSelector(peerKey+".podSelector", i18n.T("Labels of the pods (key=value,..., empty for all pods)")); err != nil {
+				return nil, err
+			}
+		case "Pods of other namespaces":
+			if peer["namespaceSelector"], err = askSelector(peerKey+".namespaceSelector", i18n.T("Labels of the namespaces (key=value,..., empty for all namespaces)")); err != nil {
+				return nil, err
+			}
+			pods, err := askSelector(peerKey+".podSelector", i18n.T("Labels of the pods (key=value,..., empty for all pods)"))
+			if err != nil {
+				return nil, err
+			}
+			if len(pods) > 0 {
+				peer["podSelector"] = pods
+			}
+		case "IP addresses (CIDR)":
+			if peer["ipBlock"], err = askIPBlock(peerKey + ".ipBlock"); err != nil {
+				return nil, err
+			}
+		}
+
+		rule := map[string]interface{}{}
+		if len(peer) > 0 {
+			rule[d.peers] = []interface{}{peer}
+		}
+		text, err := prompt.AskText(key+".ports", i18n.T("Ports (e.g., 80, 53/UDP, 8000-8080, http; empty for all ports)"), "", false, func(s string) error {
+			_, err := parsePolicyPorts(s)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ports, _ := parsePolicyPorts(text); len(ports) > 0 {
+			rule["ports"] = ports
+		}
+		rules = append(rules, rule)
+	}
+}
+
+// askSelector asks for the labels of a label selector. No labels select
+// everything.
+func askSelector(key, label string) (map[string]interface{}, error) {
+	text, err := prompt.AskText(key, label, "", false, func(s string) error {
+		_, err := labels.ConvertSelectorToLabelsMap(s)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	set, _ := labels.ConvertSelectorToLabelsMap(text)
+	if len(set) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	matchLabels := make(map[string]interface{}, len(set))
+	for k, v := range set {
+		matchLabels[k] = v
+	}
+	return map[string]interface{}{"matchLabels": matchLabels}, nil
+}
+
+// askIPBlock asks for a CIDR and the CIDRs within it to exclude
+func askIPBlock(key string) (map[string]interface{}, error) {
+	cidr, err := prompt.AskText(key+".cidr", i18n.T("CIDR (e.g., 10.0.0.0/8)"), "", true, validateCIDR)
+	if err != nil {
+		return nil, err
+	}
+	text, err := prompt.AskText(key+".except", i18n.T("CIDRs to exclude (comma-separated, empty for none)"), "", false, func(s string) error {
+		for _, except := range strings.Split(s, ",") {
+			if err := validateCIDR(strings.TrimSpace(except)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	block := map[string]interface{}{"cidr": cidr}
+	var excepts []interface{}
+	for _, except := range strings.Split(text, ",") {
+		if except = strings.TrimSpace(except); except != "" {
+			excepts = append(excepts, except)
+		}
+	}
+	if len(excepts) > 0 {
+		block["except"] = excepts
+	}
+	return block, nil
+}
+
+// validateCIDR checks that s is a CIDR
+func validateCIDR(s string) error {
+	if _, _, err := net.ParseCIDR(s); err != nil {
+		return i18n.Errorf("%q is not a CIDR (e.g., 10.0.0.0/8)", s)
+	}
+	return nil
+}
+
+// parsePolicyPorts parses comma-separated ports of a NetworkPolicy rule, each
+// a number, a range or a name with an optional protocol (e.g., 53/UDP)
+func parsePolicyPorts(s string) ([]interface{}, error) {
+	var ports []interface{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		port, protocol, ok := strings.Cut(part, "/")
+		protocol = strings.ToUpper(protocol)
+		switch {
+		case !ok:
+			protocol = string(corev1.ProtocolTCP)
+		case protocol != string(corev1.ProtocolTCP) && protocol != string(corev1.ProtocolUDP) && protocol != string(corev1.ProtocolSCTP):
+			return nil, i18n.Errorf("invalid protocol in %q, must be TCP, UDP or SCTP", part)
+		}
+
+		entry := map[string]interface{}{"protocol": protocol}
+		start, end, isRange := strings.Cut(port, "-")
+		first, err := strconv.Atoi(start)
+		switch {
+		case isRange:
+			last, err2 := strconv.Atoi(end)
+			if err != nil || err2 != nil || len(validation.IsValidPortNum(first)) > 0 || len(validation.IsValidPortNum(last)) > 0 || last < first {
+				return nil, i18n.Errorf("invalid port range in %q", part)
+			}
+			entry["port"], entry["endPort"] = int64(first), int64(last)
+		case err == nil:
+			if len(validation.IsValidPortNum(first)) > 0 {
+				return nil, i18n.Errorf("invalid port in %q", part)
+			}
+			entry["port"] = int64(first)
+		default:
+			if errs := validation.IsValidPortName(port); len(errs) > 0 {
+				return nil, i18n.Errorf("invalid port in %q: %s", part, strings.Join(errs, "; "))
+			}
+			entry["port"] = port
+		}
+		ports = append(ports, entry)
+	}
+	return ports, nil
+}
+
+// policyRule is an ingress or egress rule of a NetworkPolicy
+type policyRule struct {
+	peers []networkingv1.NetworkPolicyPeer
+	ports []networkingv1.NetworkPolicyPort
+}
+
+// summarizeNetworkPolicy describes the traffic the policy allows and blocks
+func summarizeNetworkPolicy(manifest *unstructured.Unstructured) []string {
+	var policy networkingv1.NetworkPolicy
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(manifest.Object, &policy); err != nil {
+		return nil
+	}
+	spec := policy.Spec
+
+	// Without policy types, a policy restricts ingress, and egress when it has
+	// egress rules
+	restricts := map[networkingv1.PolicyType]bool{networkingv1.PolicyTypeIngress: len(spec.PolicyTypes) == 0}
+	restricts[networkingv1.PolicyTypeEgress] = len(spec.PolicyTypes) == 0 && len(spec.Egress) > 0
+	for _, t := range spec.PolicyTypes {
+		restricts[t] = true
+	}
+
+	var ingress, egress []policyRule
+	for _, r := range spec.Ingress {
+		ingress = append(ingress, policyRule{peers: r.From, ports: r.Ports})
+	}
+	for _, r := range spec.Egress {
+		egress = append(egress, policyRule{peers: r.To, ports: r.Ports})
+	}
+
+	lines := []string{i18n.T("NetworkPolicy %s applies to %s in namespace %s:", policy.Name, describePods(spec.PodSelector), policy.Namespace)}
+	lines = append(lines, describeRules(i18n.T("Incoming traffic"), i18n.T("from %s on %s"), restricts[networkingv1.PolicyTypeIngress], ingress)...)
+	lines = append(lines, describeRules(i18n.T("Outgoing traffic"), i18n.T("to %s on %s"), restricts[networkingv1.PolicyTypeEgress], egress)...)
+	if restricts[networkingv1.PolicyTypeEgress] && !allowsDNS(egress) {
+		lines = append(lines, i18n.T("  Note: DNS lookups (port 53) are blocked too"))
+	}
+	return lines
+}
+
+// describeRules describes the traffic of one direction, each rule with
+// format (its peers and ports)
+func describeRules(traffic, format string, restricted bool, rules []policyRule) []string {
+	switch {
+	case !restricted:
+		return []string{i18n.T("  %s: not restricted by this policy", traffic)}
+	case len(rules) == 0:
+		return []string{i18n.T("  %s: all blocked", traffic)}
+	}
+	lines := []string{i18n.T("  %s: blocked, except", traffic)}
+	for _, r := range rules {
+		peers := []string{i18n.T("anywhere")}
+		if len(r.peers) > 0 {
+			peers = peers[:0]
+			for _, p := range r.peers {
+				peers = append(peers, describePeer(p))
+			}
+		}
+		lines = append(lines, "    - "+fmt.Sprintf(format, strings.Join(peers, i18n.T(" or ")), describePorts(r.ports)))
+	}
+	return lines
+}
+
+// describePeer describes the pods or addresses of a peer of a rule
+func describePeer(p networkingv1.NetworkPolicyPeer) string {
+	switch {
+	case p.IPBlock != nil:
+		if len(p.IPBlock.Except) > 0 {
+			return i18n.T("addresses in %s except %s", p.IPBlock.CIDR, strings.Join(p.IPBlock.Except, ", "))
+		}
+		return i18n.T("addresses in %s", p.IPBlock.CIDR)
+	case p.NamespaceSelector == nil:
+		return i18n.T("%s in this namespace", describePods(*p.PodSelector))
+	}
+
+	namespaces := i18n.T("all namespaces")
+	if !emptySelector(*p.NamespaceSelector) {
+		namespaces = i18n.T("namespaces with %s", metav1.FormatLabelSelector(p.NamespaceSelector))
+	}
+	if p.PodSelector == nil {
+		return i18n.T("all pods in %s", namespaces)
+	}
+	return i18n.T("%s in %s", describePods(*p.PodSelector), namespaces)
+}
+
+// describePods describes the pods a selector selects
+func describePods(selector metav1.LabelSelector) string {
+	if emptySelector(selector) {
+		return i18n.T("all pods")
+	}
+	return i18n.T("pods with %s", metav1.FormatLabelSelector(&selector))
+}
+
+// emptySelector reports whether selector selects everything
+func emptySelector(selector metav1.LabelSelector) bool {
+	return len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0
+}
+
+// describePorts lists the ports of a rule
+func describePorts(ports []networkingv1.NetworkPolicyPort) string {
+	if len(ports) == 0 {
+		return i18n.T("any port")
+	}
+	described := make([]string, len(ports))
+	for i, p := range ports {
+		protocol := corev1.ProtocolTCP
+		if p.Protocol != nil {
+			protocol = *p.Protocol
+		}
+		switch {
+		case p.Port == nil:
+			described[i] = i18n.T("any %s port", protocol)
+		case p.EndPort != nil:
+			described[i] = fmt.Sprintf("%s-%d/%s", p.Port.String(), *p.EndPort, protocol)
+		default:
+			described[i] = fmt.Sprintf("%s/%s", p.Port.String(), protocol)
+		}
+	}
+	return strings.Join(described, ", ")
+}
+
+// allowsDNS reports whether egress rules allow traffic to port 53
+func allowsDNS(rules []policyRule) bool {
+	for _, r := range rules {
+		if len(r.ports) == 0 {
+			return true
+		}
+		for _, p := range r.ports {
+			if p.Port == nil || p.Port.IntValue() == 53 || (p.EndPort != nil && p.Port.IntValue() <= 53 && 53 <= int(*p.EndPort)) {
+				return true
+			}
+		}
+	}
+	return false
+}
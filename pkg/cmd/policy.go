@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/policy"
+	"github.com/gshaibi/kubectl-create-resource/pkg/preflight"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// skipPolicyCheck disables the local ValidatingAdmissionPolicy check
+	skipPolicyCheck bool
+	gatekeeperCheck bool
+	skipQuotaCheck  bool
+	lint            bool
+	lintDisable     []string
+)
+
+// runPreflightChecks lints obj with --lint and checks it against the admission
+// policies, Gatekeeper constraints and quotas of the cluster, and with
+// --check-duplicates its existing objects, before it is created (enforce) or
+// printed for --dry-run
+func runPreflightChecks(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, enforce bool) error {
+	if lint {
+		for _, f := range preflight.Lint(obj, lintDisable) {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: %s\n"), f)
+		}
+	}
+	if err := checkAdmissionPolicies(k8sClient, gvr, obj, enforce); err != nil {
+		return err
+	}
+	if err := checkGatekeeper(k8sClient, gvr, obj, enforce); err != nil {
+		return err
+	}
+	checkQuota(k8sClient, gvr, obj)
+	return checkDuplicateSpec(k8sClient, gvr, obj, enforce)
+}
+
+// checkAdmissionPolicies evaluates the ValidatingAdmissionPolicies bound to the
+// create of obj locally. When enforce is set, denials are returned as an error
+// so the object is not sent; otherwise (dry-run) they are printed as warnings.
+// Clusters without the API, and users who may not list policies, skip the check.
+func checkAdmissionPolicies(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, enforce bool) error {
+	if skipPolicyCheck || k8sClient.Offline() {
+		return nil
+	}
+
+	report, err := policy.CheckValidatingPolicies(k8sClient, gvr, obj, namespace)
+	if err != nil {
+		if !apierrors.IsForbidden(err) && !apierrors.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: could not check admission policies: %v\n"), err)
+		}
+		return nil
+	}
+
+	for _, skipped := range report.Skipped {
+		fmt.Fprintf(os.Stderr, i18n.T("Note: admission policy %s\n"), skipped)
+	}
+	for _, f := range report.Warnings {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %s\n"), formatFinding(f))
+	}
+	if len(report.Denials) == 0 {
+		return nil
+	}
+
+	if !enforce {
+		for _, f := range report.Denials {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: would be denied: %s\n"), formatFinding(f))
+		}
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %q would be denied by admission policies (checked locally, nothing was sent):", obj.GetKind(), obj.GetName())
+	for _, f := range report.Denials {
+		fmt.Fprintf(&b, "\n  - %s", formatFinding(f))
+	}
+	b.WriteString("\nuse --skip-policy-check to send it anyway")
+	return fmt.Errorf("%s", b.String())
+}
+
+// formatFinding describes a failed policy validation
+func formatFinding(f policy.Finding) string {
+	return fmt.Sprintf("ValidatingAdmissionPolicy %q (binding %q): %s [%s]", f.Policy, f.Binding, f.Message, f.Reason)
+}
+
+// checkGatekeeper runs the Gatekeeper pre-check of --gatekeeper-check: it lists
+// the constraints that apply to obj and their violations from a server-side
+// dry-run. When enforce is set, denying violations are returned as an error.
+func checkGatekeeper(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, enforce bool) error {
+	if !gatekeeperCheck {
+		return nil
+	}
+
+	report, err := policy.CheckGatekeeper(k8sClient, gvr, obj, namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return i18n.Errorf("--gatekeeper-check: Gatekeeper is not installed in the cluster")
+		}
+		return i18n.Errorf("--gatekeeper-check: %w", err)
+	}
+
+	if len(report.Constraints) == 0 {
+		fmt.Fprintf(os.Stderr, i18n.T("No Gatekeeper constraints apply to %s %q\n"), obj.GetKind(), obj.GetName())
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Gatekeeper constraints applying to %s %q:\n"), obj.GetKind(), obj.GetName())
+	for _, c := range report.Constraints {
+		fmt.Fprintf(os.Stderr, "  %s/%s (%s)\n", c.Kind, c.Name, c.EnforcementAction)
+	}
+
+	denied := 0
+	if len(report.Violations) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("No violations"))
+	} else {
+		fmt.Fprintln(os.Stderr, i18n.T("Violations:"))
+		for _, v := range report.Violations {
+			action := "warn"
+			if v.Denied {
+				action = "deny"
+				denied++
+			}
+			fmt.Fprintf(os.Stderr, "  [%s] %s (%s)\n", v.Constraint, v.Message, action)
+		}
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if enforce && denied > 0 {
+		return i18n.Errorf("%s %q violates %d Gatekeeper constraints, nothing was created", obj.GetKind(), obj.GetName(), denied)
+	}
+	return nil
+}
+
+// checkQuota warns when obj would exceed the remaining ResourceQuota of the
+// namespace or the LimitRanges there. Quotas only warn: workloads are admitted
+// even when their pods will not be.
+func checkQuota(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) {
+	if skipQuotaCheck || k8sClient.Offline() {
+		return
+	}
+	findings, err := preflight.CheckQuota(k8sClient, gvr, obj, namespace)
+	if err != nil {
+		if !apierrors.IsForbidden(err) {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: could not check quotas: %v\n"), err)
+		}
+		return
+	}
+	for _, f := range findings {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %s\n"), f)
+	}
+}
+
+// validateLintRules rejects unknown rules in --lint-disable
+func validateLintRules() error {
+	for _, rule := range lintDisable {
+		if _, ok := preflight.LintRules[rule]; !ok {
+			names := make([]string, 0, len(preflight.LintRules))
+			for name := range preflight.LintRules {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return i18n.Errorf("unknown lint rule %q in --lint-disable (use %s)", rule, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
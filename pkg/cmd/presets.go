@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// preset is a guided flow for a resource type, around the generic one: it asks
+// about what usually goes along with creating the type and does it afterwards
+type preset struct {
+	// flags are the flags of the preset, which other types reject
+	flags []string
+
+	// versions are the API versions of the type the preset applies to, all
+	// when empty
+	versions []string
+
+	// fields are the fields prepare asks for, which the prompts for the
+	// fields of the schema skip
+	fields []string
+
+	// prepare runs once the values are collected, before the manifest is
+	// generated; values are nil for resources created from a template (--from)
+	prepare func(k8sClient *client.K8sClient, values *prompt.CollectedValues) error
+
+	// bundle returns the objects to create along with the generated manifest,
+	// which it may complete (e.g., with labels)
+	bundle func(manifest *unstructured.Unstructured) ([]*unstructured.Unstructured, error)
+
+	// summarize describes the generated manifest in words, before it is
+	// created or printed
+	summarize func(manifest *unstructured.Unstructured) []string
+
+	// created runs after the resource and its bundle were created
+	created func(k8sClient *client.K8sClient, obj *unstructured.Unstructured) error
+}
+
+// typePresets are the guided flows by resource type, registered by the files
+// of the presets
+var typePresets = map[schema.GroupResource]preset{}
+
+var noPreset bool
+
+func init() {
+	rootCmd.Flags().BoolVar(&noPreset, "no-preset", false,
+		"skip the guided flow of resource types that have one (e.g., the token of serviceaccounts)")
+}
+
+// presetFor returns the guided flow for gvr, unless --no-preset is set
+func presetFor(gvr schema.GroupVersionResource) (preset, bool) {
+	if noPreset {
+		return preset{}, false
+	}
+	p, ok := typePresets[gvr.GroupResource()]
+	if ok && len(p.versions) > 0 && !slices.Contains(p.versions, gvr.Version) {
+		return preset{}, false
+	}
+	return p, ok
+}
+
+// presetFlagsSet holds the flags of presets given on the command line
+var presetFlagsSet = map[string]bool{}
+
+// recordPresetFlags records which flags of presets cmd was given
+func recordPresetFlags(cmd *cobra.Command) {
+	for _, p := range typePresets {
+		for _, flag := range p.flags {
+			presetFlagsSet[flag] = cmd.Flags().Changed(flag)
+		}
+	}
+}
+
+// validatePresetFlags rejects the flags of the presets of other types than gvr.
+// Presets of related types may share flags (e.g., --parent of routes).
+func validatePresetFlags(gvr schema.GroupVersionResource) error {
+	current, applies := presetFor(gvr)
+	for gr, p := range typePresets {
+		if gr == gvr.GroupResource() && applies {
+			continue
+		}
+		for _, flag := range p.flags {
+			if !presetFlagsSet[flag] || (applies && slices.Contains(current.flags, flag)) {
+				continue
+			}
+			switch {
+			case gr == gvr.GroupResource() && noPreset:
+				return i18n.Errorf("--%s cannot be used with --no-preset", flag)
+			case len(p.versions) > 0:
+				return i18n.Errorf("--%s only applies to %s %s", flag, gr.String(), strings.Join(p.versions, ", "))
+			}
+			return i18n.Errorf("--%s only applies to %s", flag, gr.String())
+		}
+	}
+	return nil
+}
+
+// rejectPresetFlagsWithFrom fails when flags of the preset of gr that set
+// fields are given with --from, which takes the fields from the template
+func rejectPresetFlagsWithFrom(gr schema.GroupResource) error {
+	for _, flag := range typePresets[gr].flags {
+		if presetFlagsSet[flag] {
+			return i18n.Errorf("--%s doesn't apply with --from, edit the template instead", flag)
+		}
+	}
+	return nil
+}
+
+// guidedFields returns the fields the preset of gvr asks for, if any
+func guidedFields(gvr schema.GroupVersionResource) []string {
+	p, _ := presetFor(gvr)
+	return p.fields
+}
+
+// prepareWithPreset runs the prepare step of the preset of gvr, if any
+func prepareWithPreset(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, values *prompt.CollectedValues) error {
+	p, ok := presetFor(gvr)
+	if !ok || p.prepare == nil {
+		return nil
+	}
+	return p.prepare(k8sClient, values)
+}
+
+// bundleWithPreset returns the objects the preset of gvr creates along with
+// manifest, if any
+func bundleWithPreset(gvr schema.GroupVersionResource, manifest *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	p, ok := presetFor(gvr)
+	if !ok || p.bundle == nil {
+		return nil, nil
+	}
+	return p.bundle(manifest)
+}
+
+// printPresetSummary prints the description of manifest by the preset of gvr,
+// if any
+func printPresetSummary(gvr schema.GroupVersionResource, manifest *unstructured.Unstructured) {
+	p, ok := presetFor(gvr)
+	if !ok || p.summarize == nil {
+		return
+	}
+	for _, line := range p.summarize(manifest) {
+		fmt.Fprintln(os.Stderr, line)
+	}
+}
+
+// createBundle creates the objects of a preset's bundle after the resource,
+// each in its own namespace. Objects that exist are left as they are.
+func createBundle(k8sClient *client.K8sClient, objs []*unstructured.Unstructured) error {
+	saved := namespace
+	defer func() { namespace = saved }()
+
+	for _, obj := range objs {
+		gvr, err := k8sClient.ResourceForKind(obj.GroupVersionKind())
+		if err != nil {
+			return i18n.Errorf("failed to resolve the resource type of %s: %w", obj.GetKind(), err)
+		}
+		namespace = obj.GetNamespace()
+		created, err := submitResource(k8sClient, gvr, obj)
+		switch {
+		case apierrors.IsAlreadyExists(err):
+			fmt.Fprintf(os.Stderr, i18n.T("%s/%s already exists\n"), gvr.Resource, obj.GetName())
+		case err != nil:
+			return i18n.Errorf("failed to create %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		default:
+			fmt.Printf(i18n.T("%s/%s created\n"), gvr.Resource, created.GetName())
+		}
+	}
+	return nil
+}
+
+// finishWithPreset creates the bundle of the resource created from manifest,
+// then runs the created step of the preset of gvr, if any
+func finishWithPreset(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, manifest *unstructured.Unstructured, bundle []*unstructured.Unstructured) error {
+	p, ok := presetFor(gvr)
+	if !ok || dryRun {
+		return nil
+	}
+	if err := createBundle(k8sClient, bundle); err != nil {
+		return err
+	}
+	if p.created == nil {
+		return nil
+	}
+	return p.created(k8sClient, manifest)
+}
+
+// classType is a resource type of classes, such as StorageClasses, which
+// presets offer to pick from
+type classType struct {
+	gvr                schema.GroupVersionResource
+	controllerField    string   // Field naming the provisioner or controller of a class
+	defaultAnnotations []string // Annotations set to "true" on the default class
+}
+
+// listClasses returns the classes of t in the cluster, sorted by name. There
+// are none to list offline.
+func listClasses(k8sClient *client.K8sClient, t classType) ([]prompt.Class, error) {
+	if k8sClient.Offline() {
+		return nil, nil
+	}
+	objs, err := k8sClient.ListObjects(t.gvr, "")
+	if err != nil {
+		return nil, err
+	}
+	classes := make([]prompt.Class, 0, len(objs))
+	for _, obj := range objs {
+		controller, _, _ := unstructured.NestedString(obj.Object, strings.Split(t.controllerField, ".")...)
+		class := prompt.Class{Name: obj.GetName(), Controller: controller}
+		for _, annotation := range t.defaultAnnotations {
+			class.Default = class.Default || obj.GetAnnotations()[annotation] == "true"
+		}
+		classes = append(classes, class)
+	}
+	slices.SortFunc(classes, func(a, b prompt.Class) int { return strings.Compare(a.Name, b.Name) })
+	return classes, nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+// confirmProtected asks for a typed confirmation before creating in a context
+// or namespace the config marks as protected. The namespace name is typed, or
+// the context name for cluster-scoped resources.
+func confirmProtected(k8sClient *client.K8sClient, namespace string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	contextName := clientContext(k8sClient)
+	if !cfg.IsProtected(contextName, namespace) {
+		return nil
+	}
+
+	what, expected := "namespace", namespace
+	if namespace == "" {
+		what, expected = "context", contextName
+	}
+	if !prompt.CanPrompt() {
+		return i18n.Errorf("%s %s is protected by the config; creating in it needs a terminal to confirm", what, expected)
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Warning: creating in protected context %s"), contextName)
+	if namespace != "" {
+		fmt.Fprintf(os.Stderr, i18n.T(", namespace %s"), namespace)
+	}
+	fmt.Fprintln(os.Stderr)
+	if !prompt.ConfirmTyped("protected", i18n.T("Type the %s name (%s) to proceed", what, expected), expected) {
+		return i18n.Errorf("aborted, the %s name did not match", what)
+	}
+	return nil
+}
+
+// refuseProtected rejects creating in a protected context or namespace where
+// there's no one to confirm (e.g., from serve)
+func refuseProtected(k8sClient *client.K8sClient, namespace string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	contextName := clientContext(k8sClient)
+	if cfg.IsProtected(contextName, namespace) {
+		return i18n.Errorf("context %s, namespace %q is protected by the config and needs an interactive confirmation", contextName, namespace)
+	}
+	return nil
+}
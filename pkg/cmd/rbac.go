@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	withRBAC           string
+	createRBAC         bool
+	rbacServiceAccount string
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&withRBAC, "with-rbac", "",
+		"write a Role (ClusterRole for cluster-scoped types) and a binding granting --rbac-service-account CRUD on the resource type to this file")
+	rootCmd.Flags().BoolVar(&createRBAC, "create-rbac", false,
+		"create the Role and binding of --rbac-service-account after the resource")
+	rootCmd.Flags().StringVar(&rbacServiceAccount, "rbac-service-account", "",
+		"service account the RBAC is for, as <namespace>:<name> or <name> in the namespace of the resource")
+}
+
+// validateRBACFlags checks --with-rbac, --create-rbac and --rbac-service-account
+func validateRBACFlags() error {
+	if withRBAC == "" && !createRBAC {
+		if rbacServiceAccount != "" {
+			return i18n.Errorf("--rbac-service-account requires --with-rbac or --create-rbac")
+		}
+		return nil
+	}
+	if rbacServiceAccount == "" {
+		return i18n.Errorf("--with-rbac and --create-rbac require --rbac-service-account")
+	}
+	if createRBAC && len(targetContexts) > 0 {
+		return i18n.Errorf("--create-rbac cannot be combined with --contexts or --all-contexts")
+	}
+	return nil
+}
+
+// rbacObjects returns the Role and binding granting --rbac-service-account
+// CRUD on gvr, in the namespace of the resource
+func rbacObjects(gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, error) {
+	saNamespace, saName := namespace, rbacServiceAccount
+	if i := strings.Index(rbacServiceAccount, ":"); i >= 0 {
+		saNamespace, saName = rbacServiceAccount[:i], rbacServiceAccount[i+1:]
+	}
+	if saNamespace == "" {
+		return nil, i18n.Errorf("--rbac-service-account needs a namespace (<namespace>:<name>) for cluster-scoped types")
+	}
+	if saName == "" {
+		return nil, i18n.Errorf("--rbac-service-account %q has no name", rbacServiceAccount)
+	}
+	return generator.GenerateRBAC(gvr, namespace, saNamespace, saName), nil
+}
+
+// writeRBAC writes the RBAC for gvr to the file of --with-rbac, if set
+func writeRBAC(gvr schema.GroupVersionResource) error {
+	if withRBAC == "" {
+		return nil
+	}
+	objs, err := rbacObjects(gvr)
+	if err != nil {
+		return err
+	}
+
+	docs := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return i18n.Errorf("failed to marshal manifest: %w", err)
+		}
+		docs = append(docs, string(data))
+	}
+	if err := os.WriteFile(withRBAC, []byte(strings.Join(docs, "---\n")), 0o644); err != nil {
+		return i18n.Errorf("failed to write %s: %w", withRBAC, err)
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Wrote %s %s and %s %s to %s\n"),
+		objs[0].GetKind(), objs[0].GetName(), objs[1].GetKind(), objs[1].GetName(), withRBAC)
+	return nil
+}
+
+// createRBACFor creates the RBAC for gvr with --create-rbac, after the
+// resource. A Role that exists, e.g. from creating another resource of the
+// type, is left as it is.
+func createRBACFor(k8sClient *client.K8sClient, gvr schema.GroupVersionResource) error {
+	if !createRBAC || dryRun {
+		return nil
+	}
+	objs, err := rbacObjects(gvr)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		objGVR := schema.GroupVersionResource{
+			Group:    "rbac.authorization.k8s.io",
+			Version:  "v1",
+			Resource: strings.ToLower(obj.GetKind()) + "s",
+		}
+		created, err := submitResource(k8sClient, objGVR, obj)
+		switch {
+		case apierrors.IsAlreadyExists(err):
+			fmt.Fprintf(os.Stderr, i18n.T("%s/%s already exists\n"), objGVR.Resource, obj.GetName())
+		case err != nil:
+			return i18n.Errorf("failed to create %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		default:
+			fmt.Printf(i18n.T("%s/%s created\n"), objGVR.Resource, created.GetName())
+		}
+	}
+	return nil
+}
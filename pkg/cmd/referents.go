@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// missingReferent is an object of the namespace that a manifest names but
+// that doesn't exist, with the fields naming it
+type missingReferent struct {
+	gvr   schema.GroupVersionResource
+	name  string
+	paths []string
+}
+
+// missingReferents returns the objects of the namespace that manifest
+// references, such as the Secret of a secretRef, and that don't exist. Objects
+// that can't be read (e.g., forbidden) aren't reported.
+func missingReferents(k8sClient *client.K8sClient, manifest *unstructured.Unstructured) []missingReferent {
+	if k8sClient.Offline() || namespace == "" {
+		return nil
+	}
+	var missing []missingReferent
+	checked := map[string]int{}
+	for _, ref := range prompt.FindReferences(manifest.Object) {
+		if !k8sClient.IsNamespaced(ref.Type) {
+			continue
+		}
+		key := ref.Type.String() + "/" + ref.Name
+		if i, ok := checked[key]; ok {
+			if i >= 0 {
+				missing[i].paths = append(missing[i].paths, ref.Path)
+			}
+			continue
+		}
+		checked[key] = -1
+		if _, err := k8sClient.GetResource(ref.Type, namespace, ref.Name); !apierrors.IsNotFound(err) {
+			continue
+		}
+		checked[key] = len(missing)
+		missing = append(missing, missingReferent{gvr: ref.Type, name: ref.Name, paths: []string{ref.Path}})
+	}
+	return missing
+}
+
+// nestedCreateEnabled reports whether missing referents can be created in a
+// nested flow: only in interactive runs that create a single resource, like
+// sessions
+func nestedCreateEnabled() bool {
+	if dryRun || runArtifacts != nil || len(targetContexts) > 0 || count > 1 {
+		return false
+	}
+	if answersFile != "" || recordAnswersFile != "" {
+		return false
+	}
+	return prompt.IsTerminal() && !prompt.Scripted()
+}
+
+// createMissingReferents hints at the objects manifest references that don't
+// exist in the namespace, such as the Secret an operator's resource reads its
+// credentials from, and offers to create each before the resource in an
+// interactive run
+func createMissingReferents(k8sClient *client.K8sClient, manifest *unstructured.Unstructured) {
+	missing := missingReferents(k8sClient, manifest)
+	if len(missing) == 0 {
+		return
+	}
+	offer := nestedCreateEnabled()
+	for _, m := range missing {
+		// --verify-refs already warned about it
+		if !verifyRefs {
+			fmt.Fprintf(os.Stderr, i18n.T("Hint: %s/%s, referenced by %s, doesn't exist in namespace %s\n"),
+				m.gvr.Resource, m.name, strings.Join(m.paths, ", "), namespace)
+		}
+		if !offer || !prompt.Confirm("create-referent", i18n.T("Create %s/%s now", m.gvr.Resource, m.name)) {
+			continue
+		}
+		if err := createReferent(k8sClient, m); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v\n"), err)
+		}
+	}
+}
+
+// createReferent runs the whole create flow for a missing referent, named
+// after the reference, without the flags of the resource that references it
+func createReferent(k8sClient *client.K8sClient, m missingReferent) error {
+	savedNamespace, savedName, savedSet, savedValues := namespace, name, setValues, collectedValues
+	savedFiles, savedSetFiles, savedSetFrom := valuesFiles, setFileValues, setFromValues
+	savedSources, savedShortcuts, savedSuffix := dataSources, shortcuts, nameSuffix
+	savedRBAC, savedCreateRBAC, savedPresetFlags := withRBAC, createRBAC, presetFlagsSet
+	defer func() {
+		namespace, name, setValues, collectedValues = savedNamespace, savedName, savedSet, savedValues
+		valuesFiles, setFileValues, setFromValues = savedFiles, savedSetFiles, savedSetFrom
+		dataSources, shortcuts, nameSuffix = savedSources, savedShortcuts, savedSuffix
+		withRBAC, createRBAC, presetFlagsSet = savedRBAC, savedCreateRBAC, savedPresetFlags
+	}()
+
+	name = m.name
+	setValues, valuesFiles, setFileValues, setFromValues = nil, nil, nil, nil
+	dataSources, shortcuts, nameSuffix = generator.DataSources{}, prompt.WorkloadShortcuts{}, ""
+	withRBAC, createRBAC, presetFlagsSet = "", false, map[string]bool{}
+
+	resourceType := m.gvr.Resource
+	if m.gvr.Group != "" {
+		resourceType += "." + m.gvr.Group
+	}
+	fmt.Fprintln(os.Stderr)
+	return createResourceWithClient(k8sClient, resourceType)
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+var noReview bool
+
+func init() {
+	rootCmd.Flags().BoolVar(&noReview, "no-review", false,
+		"don't list the collected values for changes before generating the manifest")
+}
+
+// reviewValues lets the user check and fix the collected values before the
+// manifest is generated. Only runs that prompted in a terminal are reviewed.
+func reviewValues(resourceSchema *client.ResourceSchema, values *prompt.CollectedValues) error {
+	if noReview || example || len(values.Answers) == 0 || !prompt.IsTerminal() || prompt.Scripted() {
+		return nil
+	}
+	return prompt.ReviewValues(resourceSchema, values)
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// rulesPath is the field of the rules of Roles and ClusterRoles
+const rulesPath = "rules"
+
+// ruleVerbs are the verbs of resources offered by the rule builder, in the
+// order they're listed; verbs discovery serves besides these come after them
+var ruleVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"}
+
+// readVerbs are the verbs selected from the start
+var readVerbs = []string{"get", "list", "watch"}
+
+// nonResourceVerbs are the verbs of non-resource URLs, which are HTTP methods
+var nonResourceVerbs = []string{"get", "head", "post", "put", "patch", "delete"}
+
+func init() {
+	typePresets[schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "roles"}] = preset{
+		fields: []string{rulesPath},
+		prepare: func(k8sClient *client.K8sClient, values *prompt.CollectedValues) error {
+			return prepareRoleRules(k8sClient, values, false)
+		},
+	}
+	typePresets[schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}] = preset{
+		fields: []string{rulesPath},
+		prepare: func(k8sClient *client.K8sClient, values *prompt.CollectedValues) error {
+			return prepareRoleRules(k8sClient, values, true)
+		},
+	}
+}
+
+// prepareRoleRules builds the rules of a Role, or a ClusterRole when cluster
+// is set, one at a time from the API groups, resources and verbs the cluster
+// serves, unless rules are given otherwise (e.g., with --set)
+func prepareRoleRules(k8sClient *client.K8sClient, values *prompt.CollectedValues, cluster bool) error {
+	if values == nil || values.Has(rulesPath) || values.Has("aggregationRule") || example || !prompt.CanPrompt() {
+		return nil
+	}
+
+	resources, err := k8sClient.DiscoverAuthorizableResources()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: failed to discover resources: %v\n"), err)
+	}
+	// The resource types of offline schemas don't list their verbs
+	if k8sClient.Offline() {
+		for i := range resources {
+			resources[i].Verbs = nil
+		}
+	}
+	// A Role only grants access within its namespace
+	if !cluster {
+		resources = slices.DeleteFunc(resources, func(r client.ResourceInfo) bool { return !r.Namespaced })
+	}
+
+	var rules []interface{}
+	for i := 0; ; i++ {
+		rule, err := askRoleRule(fmt.Sprintf("%s[%d]", rulesPath, i), resources, cluster, i == 0)
+		if err != nil {
+			return err
+		}
+		if rule == nil {
+			break
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) > 0 {
+		values.Set(rulesPath, rules, prompt.SourcePrompt)
+	}
+	return nil
+}
+
+// askRoleRule asks for the API group of a rule, then its resources, verbs
+// and the names of the objects it's limited to. It returns nil when no more
+// rules are wanted.
+func askRoleRule(key string, resources []client.ResourceInfo, cluster, first bool) (map[string]interface{}, error) {
+	var groups []string
+	for _, r := range resources {
+		if !slices.Contains(groups, r.Group) {
+			groups = append(groups, r.Group)
+		}
+	}
+	slices.Sort(groups)
+
+	items := []string{i18n.T("No more rules")}
+	if first {
+		items[0] = i18n.T("No rules")
+	}
+	for _, g := range groups {
+		items = append(items, groupLabel(g))
+	}
+	items = append(items, i18n.T("* (all API groups)"), i18n.T("(enter another)"))
+	if cluster {
+		items = append(items, i18n.T("Non-resource URLs (e.g., /healthz)"))
+	}
+
+	label := i18n.T("API group of the rule")
+	if first {
+		label = i18n.T("API group of the first rule")
+	}
+	i, err := prompt.Choose(key+".apiGroups", label, items, 0)
+	if err != nil {
+		return nil, err
+	}
+	var group string
+	switch {
+	case i == 0:
+		return nil, nil
+	case i <= len(groups):
+		group = groups[i-1]
+	case i == len(groups)+1:
+		group = "*"
+	case i == len(groups)+2:
+		if group, err = prompt.AskText(key+".apiGroups", i18n.T("API group (empty for the core group)"), "", false, nil); err != nil {
+			return nil, err
+		}
+	default:
+		return askNonResourceRule(key)
+	}
+
+	var inGroup []client.ResourceInfo
+	for _, r := range resources {
+		if r.Group == group || group == "*" {
+			inGroup = append(inGroup, r)
+		}
+	}
+	names, err := askRuleResources(key+".resources", inGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var verbs []string
+	for _, r := range inGroup {
+		if slices.Contains(names, r.Name) || slices.Contains(names, "*") {
+			verbs = append(verbs, r.Verbs...)
+		}
+	}
+	picked, err := askRuleVerbs(key+".verbs", verbs)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := map[string]interface{}{
+		"apiGroups": []interface{}{group},
+		"resources": toInterfaces(names),
+		"verbs":     toInterfaces(picked),
+	}
+	text, err := prompt.AskText(key+".resourceNames", i18n.T("Names of the objects the rule is limited to (comma-separated, empty for all)"), "", false, nil)
+	if err != nil {
+		return nil, err
+	}
+	if objectNames := splitList(text); len(objectNames) > 0 {
+		rule["resourceNames"] = toInterfaces(objectNames)
+		// The objects of these verbs aren't named in the request
+		var unrestricted []string
+		for _, verb := range []string{"create", "list", "watch", "deletecollection"} {
+			if slices.Contains(picked, verb) {
+				unrestricted = append(unrestricted, verb)
+			}
+		}
+		if len(unrestricted) > 0 {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: object names don't restrict %s, which the rule allows on all objects\n"), strings.Join(unrestricted, ", "))
+		}
+	}
+	return rule, nil
+}
+
+// askRuleResources asks for the resources of a rule among those of its API
+// groups, or to enter them when none were discovered
+func askRuleResources(key string, resources []client.ResourceInfo) ([]string, error) {
+	var names []string
+	for _, r := range resources {
+		if !slices.Contains(names, r.Name) {
+			names = append(names, r.Name)
+		}
+	}
+	slices.Sort(names)
+
+	if len(names) == 0 {
+		text, err := prompt.AskText(key, i18n.T("Resources (comma-separated, e.g., pods, pods/log)"), "", true, nil)
+		if err != nil {
+			return nil, err
+		}
+		return splitList(text), nil
+	}
+
+	items := append(names, "*")
+	picked, err := prompt.ChooseMany(key, i18n.T("Resources"), items, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(picked) == 0 {
+		return nil, i18n.Errorf("a rule needs at least one resource")
+	}
+	chosen := make([]string, len(picked))
+	for i, index := range picked {
+		chosen[i] = items[index]
+	}
+	return chosen, nil
+}
+
+// askRuleVerbs asks for the verbs of a rule among those its resources serve,
+// or the usual ones when not known, with the read-only ones selected
+func askRuleVerbs(key string, served []string) ([]string, error) {
+	var items []string
+	for _, verb := range ruleVerbs {
+		if len(served) == 0 || slices.Contains(served, verb) {
+			items = append(items, verb)
+		}
+	}
+	for _, verb := range served {
+		if !slices.Contains(items, verb) {
+			items = append(items, verb)
+		}
+	}
+	items = append(items, "*")
+	return askVerbs(key, items)
+}
+
+// askVerbs asks to pick verbs among items, with the read-only ones selected
+func askVerbs(key string, items []string) ([]string, error) {
+	selected := make([]bool, len(items))
+	for i, verb := range items {
+		selected[i] = slices.Contains(readVerbs, verb)
+	}
+	picked, err := prompt.ChooseMany(key, i18n.T("Verbs"), items, selected)
+	if err != nil {
+		return nil, err
+	}
+	if len(picked) == 0 {
+		return nil, i18n.Errorf("a rule needs at least one verb")
+	}
+	verbs := make([]string, len(picked))
+	for i, index := range picked {
+		verbs[i] = items[index]
+	}
+	return verbs, nil
+}
+
+// askNonResourceRule asks for the non-resource URLs of a ClusterRole rule
+// and their verbs
+func askNonResourceRule(key string) (map[string]interface{}, error) {
+	text, err := prompt.AskText(key+".nonResourceURLs", i18n.T("Non-resource URLs (comma-separated, e.g., /healthz, /metrics)"), "", true, func(s string) error {
+		for _, url := range splitList(s) {
+			if !strings.HasPrefix(url, "/") && url != "*" {
+				return i18n.Errorf("%q is not a URL path starting with /", url)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	verbs, err := askVerbs(key+".verbs", append(slices.Clone(nonResourceVerbs), "*"))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"nonResourceURLs": toInterfaces(splitList(text)),
+		"verbs":           toInterfaces(verbs),
+	}, nil
+}
+
+// groupLabel names an API group, as core for the core group
+func groupLabel(group string) string {
+	if group == "" {
+		return "core"
+	}
+	return group
+}
+
+// splitList splits comma-separated text, trimming the items and leaving out
+// empty ones
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// toInterfaces converts strings to the items of a list value
+func toInterfaces(items []string) []interface{} {
+	list := make([]interface{}, len(items))
+	for i, item := range items {
+		list[i] = item
+	}
+	return list
+}
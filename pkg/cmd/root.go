@@ -3,12 +3,22 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/gshaibi/kubectl-create-resource/pkg/artifacts"
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
 	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/config"
 	"github.com/gshaibi/kubectl-create-resource/pkg/discovery"
 	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/progress"
 	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"github.com/gshaibi/kubectl-create-resource/pkg/sources"
+	"github.com/gshaibi/kubectl-create-resource/pkg/version"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -23,12 +33,61 @@ var (
 	dryRun       bool
 	output       string
 	setValues    []string
+	noEncode     bool
 	name         string
 	fromResource string
+	fromSources  []string
+	forObject    string
+	configPath   string
+	dataSources  generator.DataSources
+	shortcuts    prompt.WorkloadShortcuts
+	example      bool
+	listGroup    string
+	artifactsDir string
+	nameSuffix   string
+	offline      bool
+	schemaFile   string
+	crdFiles     []string
+
+	quiet           bool
+	noColor         bool
+	simplePrompts   bool
+	kubeContext     string
+	pickContext     bool
+	contextNames    []string
+	allContexts     bool
+	contextSelector string
+	continueOnError bool
+
+	server                string
+	token                 string
+	certificateAuthority  string
+	insecureSkipTLSVerify bool
+	kubeAPIQPS            float32
+	fastDiscovery         bool
+	kubeAPIBurst          int
+
+	asUser   string
+	asGroups []string
+	asUID    string
+
+	// targetContexts are the contexts to create in with --contexts/--all-contexts (nil otherwise)
+	targetContexts []string
+
+	showEvents     bool
+	eventsDuration time.Duration
+	showStatus     bool
+	statusTimeout  time.Duration
+
+	// runArtifacts records the artifacts of this run when --artifacts-dir is set (nil otherwise)
+	runArtifacts *artifacts.Bundle
+
+	// namespaceExplicit records whether -n/--namespace was passed on the command line
+	namespaceExplicit bool
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "kubectl-create-resource [resource-type]",
+	Use:   "kubectl-create-resource [resource-type] [name]",
 	Short: "Create any Kubernetes resource interactively or via flags",
 	Long: `kubectl-create-resource is a kubectl plugin that generalizes resource creation
 to all Kubernetes resource types, including Custom Resource Definitions (CRDs).
@@ -40,18 +99,45 @@ Examples:
   # List all available resource types
   kubectl create-resource --list
 
+  # Pick the resource type interactively, group by group
+  kubectl create-resource
+
   # Create a deployment interactively
   kubectl create-resource deployment
 
   # Create a resource with flags
   kubectl create-resource deployment --name=my-app --set=spec.replicas=3
 
+  # Check a field before setting it
+  kubectl create-resource deployment --explain=spec.template.spec.tolerations
+
+  # List the fields a minimal scripted create must set
+  kubectl create-resource queue --show-required
+
+  # Start from example values for an unfamiliar type
+  kubectl create-resource queue --example --dry-run
+
   # Dry-run to see the generated YAML
   kubectl create-resource deployment --name=my-app --dry-run -o yaml
 
   # Use an existing resource as a template
-  kubectl create-resource queue --from=existing-queue --name=new-queue`,
-	Args: cobra.MaximumNArgs(1),
+  kubectl create-resource queue --from=existing-queue --name=new-queue
+
+  # Run a CronJob now, or a Job with the pods of a Deployment or Pod
+  kubectl create-resource job nightly-now --from=cronjob/nightly
+
+  # Create a workload from an image
+  kubectl create-resource deployment web --image=nginx:1.25 --port=80 --env=MODE=prod
+
+  # kubectl create-style shortcuts for secrets, configmaps and namespaces
+  kubectl create-resource secret app-secrets --from-literal=API_KEY=abc --from-file=./tls.crt
+  kubectl create-resource secret regcred --docker-server=ghcr.io --docker-username=me --docker-password=$TOKEN
+  kubectl create-resource configmap app-config --from-env-file=.env
+  kubectl create-resource namespace team-a
+
+  # Create a subresource (e.g., request a service account token)
+  kubectl create-resource serviceaccounts/token --for=my-sa`,
+	Args: cobra.MaximumNArgs(2),
 	RunE: runCreateResource,
 }
 
@@ -59,23 +145,132 @@ func init() {
 	// Kubeconfig flag
 	if home := homedir.HomeDir(); home != "" {
 		rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "",
-			fmt.Sprintf("path to the kubeconfig file (default: %s/.kube/config)", home))
+			fmt.Sprintf("path to the kubeconfig file (default: %s)", filepath.Join(home, ".kube", "config")))
 	} else {
 		rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "",
 			"path to the kubeconfig file")
 	}
 
+	// Config file
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "",
+		fmt.Sprintf("path to the config file (default: %s)", config.DefaultPath()))
+
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false,
+		"don't show progress indicators for slow operations")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false,
+		"don't use colors in prompts (also set by NO_COLOR or TERM=dumb)")
+	rootCmd.PersistentFlags().BoolVar(&simplePrompts, "simple-prompts", false,
+		"ask with numbered choices and plain text questions instead of cursor-based selects, for screen readers")
+	cobra.OnInitialize(func() {
+		applyLanguage()
+		if noColor || prompt.ColorDisabled() {
+			prompt.DisableColor()
+		}
+		if simplePrompts {
+			prompt.EnableSimple()
+		}
+		prompt.SetFragmentEditor(editFragment)
+		prompt.SetTextEditor(editText)
+		if noEncode {
+			prompt.DisableByteEncoding()
+		}
+		// Dumb terminals can't redraw a line either, nor can screen readers follow it
+		progress.Enable(!quiet && !simplePrompts && os.Getenv("TERM") != "dumb")
+	})
+
 	// Namespace flag
 	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "default",
-		"kubernetes namespace for the resource")
+		"kubernetes namespace for the resource (when omitted, the kubeconfig context's namespace, picked from a list in a terminal)")
 
 	// List available resource types
 	rootCmd.Flags().BoolVar(&listTypes, "list", false,
 		"list all available resource types")
 
+	// Explain one field and exit
+	rootCmd.Flags().StringVar(&explainPath, "explain", "",
+		"print the schema of a field path (e.g., spec.template.spec.tolerations) with its nested fields and exit")
+	rootCmd.Flags().BoolVar(&showRequired, "show-required", false,
+		"list the required field paths with their types and exit")
+	rootCmd.Flags().StringVar(&searchKeyword, "search", "",
+		"list the fields, at any depth, whose name or description matches a keyword (e.g., \"image pull secret\") and exit")
+
+	// Restrict --list to one group
+	rootCmd.Flags().StringVar(&listGroup, "group", "",
+		"with --list, only list resource types in this API group (use \"core\" for the core API)")
+
 	// Dry-run mode
-	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false,
-		"only print the resource manifest without creating it")
+	rootCmd.Flags().VarPF(dryRunValue{}, "dry-run", "",
+		"only print the resource manifest without creating it: client (the default for a bare --dry-run) or server, which prints the object as the server would persist it").NoOptDefVal = "client"
+	rootCmd.Flags().BoolVar(&showMutations, "show-mutations", false,
+		"with --dry-run=server, list the fields the server's defaulting and mutating webhooks changed")
+
+	// Offline mode
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false,
+		"work from local schema files without connecting to a cluster (requires --schema-file or --crd, implies --dry-run)")
+	rootCmd.PersistentFlags().StringVar(&schemaFile, "schema-file", "",
+		"OpenAPI document (v2 or v3) or CRD manifest, or a directory of them, used with --offline")
+
+	// Local CRD definitions
+	rootCmd.PersistentFlags().StringArrayVar(&crdFiles, "crd", []string{},
+		"take the schema of a custom resource from a local CRD file or directory, even if not installed in the cluster")
+
+	// Cluster and credential overrides
+	rootCmd.PersistentFlags().StringVarP(&server, "server", "s", "",
+		"address and port of the Kubernetes API server, overriding the kubeconfig (http:// for kubectl proxy, unix:///path for a socket)")
+	rootCmd.PersistentFlags().StringVar(&token, "token", "",
+		"bearer token for authentication to the API server")
+	rootCmd.PersistentFlags().StringVar(&certificateAuthority, "certificate-authority", "",
+		"path to a cert file for the certificate authority")
+	rootCmd.PersistentFlags().BoolVar(&insecureSkipTLSVerify, "insecure-skip-tls-verify", false,
+		"don't check the server's certificate for validity (makes HTTPS connections insecure)")
+
+	// Client-side rate limits
+	rootCmd.PersistentFlags().Float32Var(&kubeAPIQPS, "kube-api-qps", 50,
+		"queries per second allowed to the API server (negative disables client-side rate limiting)")
+	rootCmd.PersistentFlags().IntVar(&kubeAPIBurst, "kube-api-burst", 300,
+		"burst of requests allowed to the API server")
+	rootCmd.PersistentFlags().BoolVar(&fastDiscovery, "fast-discovery", false,
+		"fetch only the OpenAPI document of the resource's group version, with one request, instead of listing all schema paths (for clusters with many CRDs)")
+
+	// Impersonation
+	rootCmd.PersistentFlags().StringVar(&asUser, "as", "",
+		"username to impersonate for the operation (user or system:serviceaccount:<namespace>:<name>)")
+	rootCmd.PersistentFlags().StringArrayVar(&asGroups, "as-group", []string{},
+		"group to impersonate for the operation, can be repeated")
+	rootCmd.PersistentFlags().StringVar(&asUID, "as-uid", "",
+		"UID to impersonate for the operation")
+
+	// Cluster selection
+	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "",
+		"name of the kubeconfig context to use")
+	rootCmd.Flags().BoolVar(&pickContext, "pick-context", false,
+		"choose the kubeconfig context from a list")
+
+	// Multi-cluster fan-out
+	rootCmd.PersistentFlags().StringSliceVar(&contextNames, "contexts", []string{},
+		"create the resource in each of these kubeconfig contexts (e.g., --contexts=dev,staging)")
+	rootCmd.PersistentFlags().BoolVar(&allContexts, "all-contexts", false,
+		"create the resource in every kubeconfig context, optionally filtered by --context-selector")
+	rootCmd.PersistentFlags().StringVar(&contextSelector, "context-selector", "",
+		"with --all-contexts, only use contexts matching this glob (e.g., --context-selector='prod-*')")
+	rootCmd.Flags().BoolVar(&continueOnError, "continue-on-error", false,
+		"with --count, --contexts or --all-contexts, keep creating the remaining resources after a failure")
+
+	// Artifact bundle
+	rootCmd.Flags().StringVar(&artifactsDir, "artifacts-dir", "",
+		"write the manifest, values, answers, preflight result, server response and warnings of this run into a directory")
+
+	// Post-create event streaming
+	rootCmd.Flags().BoolVar(&showEvents, "show-events", false,
+		"after creating, stream events about the new resource")
+	rootCmd.Flags().DurationVar(&eventsDuration, "events-duration", 30*time.Second,
+		"how long to stream events with --show-events")
+
+	// Post-create status summary
+	rootCmd.Flags().BoolVar(&showStatus, "status", false,
+		"after creating, print a status summary (conditions, phase, ready replicas)")
+	rootCmd.Flags().DurationVar(&statusTimeout, "status-timeout", 10*time.Second,
+		"how long to wait for status with --status")
 
 	// Output format
 	rootCmd.Flags().StringVarP(&output, "output", "o", "",
@@ -84,18 +279,82 @@ func init() {
 	// Set values via flags
 	rootCmd.Flags().StringArrayVar(&setValues, "set", []string{},
 		"set field values (e.g., --set=spec.replicas=3)")
+	rootCmd.Flags().BoolVar(&noEncode, "no-encode", false,
+		"take values of binary fields (format: byte, e.g., Secret data) as already base64-encoded, instead of encoding text and @file values")
 
 	// Name flag for convenience
 	rootCmd.Flags().StringVar(&name, "name", "",
 		"name of the resource to create")
 
+	// Unique name suffix
+	rootCmd.Flags().StringVar(&nameSuffix, "name-suffix", "",
+		"append a suffix to the name: random, timestamp or gitsha")
+
 	// Template from existing resource
-	rootCmd.Flags().StringVar(&fromResource, "from", "",
-		"use an existing resource as a template (e.g., --from=existing-queue)")
+	rootCmd.Flags().StringArrayVar(&fromSources, "from", []string{},
+		"use an existing resource (e.g., --from=existing-queue) or a manifest file (e.g., --from=base.yaml) as a template, or the pod template of another kind (e.g., --from=cronjob/nightly); repeat to deep-merge overlays in order")
+	rootCmd.Flags().StringVar(&editorCommand, "editor", "",
+		"with --from or for free-form and long text fields, the editor command to use, with arguments (e.g., --editor='code --wait')")
+	rootCmd.Flags().BoolVar(&stripDefaults, "strip-defaults", false,
+		"with --from, remove fields defaulted by the server from the template (uses a server-side dry-run)")
+
+	// Parent object for subresource creation
+	rootCmd.Flags().StringVar(&forObject, "for", "",
+		"name of the parent object when creating a subresource (e.g., serviceaccounts/token --for=my-sa)")
+
+	rootCmd.Flags().BoolVar(&skipPolicyCheck, "skip-policy-check", false,
+		"don't check the ValidatingAdmissionPolicies of the cluster locally before creating")
+	rootCmd.Flags().BoolVar(&lint, "lint", false,
+		"warn about missing resource limits and probes, unpinned images and missing recommended labels")
+	rootCmd.Flags().StringSliceVar(&lintDisable, "lint-disable", nil,
+		"with --lint, skip these rules: resource-limits, latest-tag, probes, recommended-labels")
+	rootCmd.Flags().BoolVar(&skipQuotaCheck, "skip-quota-check", false,
+		"don't warn about ResourceQuotas and LimitRanges of the namespace the resource would exceed")
+	rootCmd.Flags().BoolVar(&gatekeeperCheck, "gatekeeper-check", false,
+		"before creating, list the Gatekeeper constraints that apply and their violations (uses a server-side dry-run)")
+	rootCmd.Flags().BoolVar(&example, "example", false,
+		"fill required fields with example values instead of prompting (--set and --image still apply)")
+
+	// Workload shortcuts for the first container
+	rootCmd.Flags().StringVar(&shortcuts.Image, "image", "",
+		"container image for resources with a pod template (e.g., --image=nginx:1.25)")
+	rootCmd.Flags().StringArrayVar(&shortcuts.Ports, "port", []string{},
+		"container port to expose (with --image)")
+	rootCmd.Flags().StringArrayVar(&shortcuts.Env, "env", []string{},
+		"container environment variable NAME=value (with --image)")
+	rootCmd.Flags().StringVar(&shortcuts.Command, "command", "",
+		"container command, space separated (with --image)")
+
+	// kubectl create secret/configmap parity flags
+	rootCmd.Flags().StringArrayVar(&dataSources.Literals, "from-literal", []string{},
+		"secret/configmap data from a literal key=value pair (e.g., --from-literal=LOG_LEVEL=info)")
+	rootCmd.Flags().StringArrayVar(&dataSources.Files, "from-file", []string{},
+		"secret/configmap data from a file or directory ([key=]path)")
+	rootCmd.Flags().StringArrayVar(&dataSources.EnvFiles, "from-env-file", []string{},
+		"secret/configmap data from a file of KEY=VALUE lines")
+	rootCmd.Flags().StringVar(&dataSources.Type, "type", "",
+		"secret type (default: Opaque, or inferred from --docker-*/--cert)")
+	rootCmd.Flags().StringVar(&dataSources.DockerServer, "docker-server", "",
+		"registry server for a docker-registry secret (default: Docker Hub)")
+	rootCmd.Flags().StringVar(&dataSources.DockerUsername, "docker-username", "",
+		"username for a docker-registry secret")
+	rootCmd.Flags().StringVar(&dataSources.DockerPassword, "docker-password", "",
+		"password for a docker-registry secret")
+	rootCmd.Flags().StringVar(&dataSources.DockerEmail, "docker-email", "",
+		"email for a docker-registry secret")
+	rootCmd.Flags().StringVar(&dataSources.Cert, "cert", "",
+		"path to a PEM certificate for a TLS secret")
+	rootCmd.Flags().StringVar(&dataSources.Key, "key", "",
+		"path to a PEM private key for a TLS secret")
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	// Set here rather than in init, after main has recorded the build information
+	rootCmd.Version = version.Get().String()
+	registerCompletions()
+	err := rootCmd.Execute()
+	printSession()
+	return err
 }
 
 func runCreateResource(cmd *cobra.Command, args []string) error {
@@ -104,182 +363,687 @@ func runCreateResource(cmd *cobra.Command, args []string) error {
 		dryRun = true
 	}
 
+	namespaceExplicit = cmd.Flags().Changed("namespace")
+	recordPresetFlags(cmd)
+
+	if err := validateOfflineFlags(cmd); err != nil {
+		return err
+	}
+	if err := loadAnswers(); err != nil {
+		return err
+	}
+	if err := validateNameConflictFlag(); err != nil {
+		return err
+	}
+
+	var err error
+	targetContexts, err = resolveTargetContexts()
+	if err != nil {
+		return err
+	}
+	if err := resolveContext(); err != nil {
+		return err
+	}
+	if err := validateBatchFlags(); err != nil {
+		return err
+	}
+	if err := validateRBACFlags(); err != nil {
+		return err
+	}
+
+	// Fail fast on an unknown suffix mode
+	if nameSuffix != "" && !slices.Contains(generator.NameSuffixModes, nameSuffix) {
+		return i18n.Errorf("unsupported --name-suffix %q (use %s)", nameSuffix, strings.Join(generator.NameSuffixModes, ", "))
+	}
+
+	if showMutations && !serverDryRun {
+		return i18n.Errorf("--show-mutations requires --dry-run=server")
+	}
+
+	if example && len(fromSources) > 0 {
+		return i18n.Errorf("--example cannot be combined with --from")
+	}
+
+	if err := validateLintRules(); err != nil {
+		return err
+	}
+
+	if gatekeeperCheck && offline {
+		return i18n.Errorf("--gatekeeper-check needs a cluster and cannot be used with --offline")
+	}
+
+	if verifyRefs && offline {
+		return i18n.Errorf("--verify-refs needs a cluster and cannot be used with --offline")
+	}
+
+	if stripDefaults && len(fromSources) == 0 {
+		return i18n.Errorf("--strip-defaults requires --from")
+	}
+
+	if err := expandSetValues(); err != nil {
+		return err
+	}
+
 	// Handle --list flag
 	if listTypes {
 		return listResourceTypes()
 	}
 
-	// Require a resource type argument if not listing
-	if len(args) == 0 {
-		return fmt.Errorf("resource type is required. Use --list to see available types")
+	if showRequired {
+		if len(args) == 0 {
+			return i18n.Errorf("--show-required requires a resource type")
+		}
+		return printRequiredFields(args[0])
 	}
 
-	resourceType := args[0]
+	if explainPath != "" {
+		if len(args) == 0 {
+			return i18n.Errorf("--explain requires a resource type")
+		}
+		return explainField(args[0], explainPath)
+	}
+
+	if searchKeyword != "" {
+		if len(args) == 0 {
+			return i18n.Errorf("--search requires a resource type")
+		}
+		return searchFields(args[0], searchKeyword)
+	}
+
+	// Allow the name as a positional argument, like kubectl create <type> <name>
+	if len(args) == 2 {
+		if name != "" && name != args[1] {
+			return i18n.Errorf("name given both as argument (%s) and --name (%s)", args[1], name)
+		}
+		name = args[1]
+	}
+
+	// Without a resource type, the user picks one once the client is up
+	resourceType := ""
+	if len(args) > 0 {
+		resourceType = args[0]
+	}
 	return createResource(resourceType)
 }
 
 func listResourceTypes() error {
-	fmt.Fprintln(os.Stderr, "Discovering available resource types...")
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
 
-	resources, err := discovery.DiscoverResourceTypes(kubeconfig)
+	groups, err := discovery.ListGroups(k8sClient)
 	if err != nil {
-		return fmt.Errorf("failed to discover resource types: %w", err)
+		return i18n.Errorf("failed to discover resource types: %w", err)
 	}
 
-	fmt.Println("\nAvailable resource types:")
+	fmt.Println(i18n.T("Available resource types:"))
 	fmt.Println("-------------------------")
 
-	// Group by API group
-	currentGroup := ""
-	for _, r := range resources {
-		if r.Group != currentGroup {
-			currentGroup = r.Group
-			if currentGroup == "" {
-				fmt.Println("\nCore API (v1):")
+	// Print each group as soon as its resources are fetched
+	found := false
+	for _, group := range groups {
+		if listGroup != "" && !strings.EqualFold(listGroup, group.Name) &&
+			!(group.Name == "" && strings.EqualFold(listGroup, "core")) {
+			continue
+		}
+		found = true
+
+		resources, err := discovery.GroupResourceTypes(k8sClient, group)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: %v\n"), err)
+			continue
+		}
+		if len(resources) == 0 {
+			continue
+		}
+
+		fmt.Printf("\n%s:\n", discovery.GroupDisplayName(group))
+		for _, r := range resources {
+			if r.Namespaced {
+				fmt.Printf("  %s\n", r.Name)
 			} else {
-				fmt.Printf("\n%s:\n", currentGroup)
+				fmt.Printf(i18n.T("  %s (cluster-scoped)\n"), r.Name)
 			}
 		}
-		fmt.Printf("  %s\n", r.Name)
+	}
+
+	if listGroup != "" && !found {
+		return i18n.Errorf("API group %q not found", listGroup)
 	}
 	return nil
 }
 
+// pickResourceType shows API groups and lazily loads a group's resource types when selected
+func pickResourceType(k8sClient *client.K8sClient) (string, error) {
+	indicator := progress.Start(i18n.T("Discovering API groups"))
+	groups, err := discovery.ListGroups(k8sClient)
+	indicator.Stop()
+	if err != nil {
+		return "", err
+	}
+
+	var groupNames []string
+	for _, g := range groups {
+		groupNames = append(groupNames, discovery.GroupDisplayName(g))
+	}
+
+	return prompt.PickResourceType(groupNames, func(i int) ([]string, []string, error) {
+		resources, err := discovery.GroupResourceTypes(k8sClient, groups[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		var names, types []string
+		for _, r := range resources {
+			names = append(names, r.Name)
+			types = append(types, discovery.FormatResourceType(r))
+		}
+		return names, types, nil
+	})
+}
+
 func createResource(resourceType string) error {
 	// Initialize the Kubernetes client
-	k8sClient, err := client.NewK8sClient(kubeconfig)
+	k8sClient, err := newClient()
 	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	runArtifacts, err = artifacts.New(artifactsDir)
+	if err != nil {
+		return err
+	}
+	showContext(k8sClient)
+	defer saveRecordedAnswers()
+
+	if resourceType == "" {
+		resourceType, err = pickResourceType(k8sClient)
+		switch {
+		case err != nil && prompt.Scripted():
+			return err
+		case resourceType == "" && (err == nil || prompt.IsInterrupt(err)):
+			return i18n.Errorf("resource type is required. Use --list to see available types")
+		case err != nil:
+			return i18n.Errorf("failed to pick a resource type: %w", err)
+		}
+	}
+
+	err = createResourceWithClient(k8sClient, resourceType)
+	if err == nil && sessionEnabled() {
+		err = runSession(k8sClient, resourceType)
+	}
+	if err == nil {
+		warnUnusedAnswers()
+	}
+	runArtifacts.Fail(err)
+	runArtifacts.Close(k8sClient.Warnings())
+	return err
+}
+
+// createResourceWithClient resolves, collects, generates and creates a single resource
+func createResourceWithClient(k8sClient *client.K8sClient, resourceType string) error {
+	collectedValues = nil
+
+	// Subresources (e.g., serviceaccounts/token) are posted to an existing parent object
+	if strings.Contains(resourceType, "/") {
+		if len(targetContexts) > 0 {
+			return i18n.Errorf("subresources cannot be created in multiple contexts")
+		}
+		return createSubresource(k8sClient, resourceType)
 	}
 
 	// Resolve the resource type to GVR
+	indicator := progress.Start(i18n.T("Discovering resource types"))
 	gvr, err := k8sClient.ResolveResourceType(resourceType)
+	indicator.Stop()
 	if err != nil {
-		return fmt.Errorf("failed to resolve resource type %q: %w", resourceType, err)
+		return i18n.Errorf("failed to resolve resource type %q: %w", resourceType, err)
 	}
+	gvr, pinnedVersion := checkPinnedVersion(k8sClient, resourceType, gvr)
 
-	fmt.Fprintf(os.Stderr, "Creating %s in namespace %s\n", gvr.Resource, namespace)
+	applyResourceScope(gvr.Resource, k8sClient.IsNamespaced(gvr))
+	if k8sClient.IsNamespaced(gvr) {
+		if err := resolveNamespace(k8sClient); err != nil {
+			return err
+		}
+	}
+	if err := validatePresetFlags(gvr); err != nil {
+		return err
+	}
+	if namespace == "" {
+		fmt.Fprintf(os.Stderr, i18n.T("Creating %s (cluster-scoped)\n"), gvr.Resource)
+	} else {
+		fmt.Fprintf(os.Stderr, i18n.T("Creating %s in namespace %s\n"), gvr.Resource, namespace)
+	}
+
+	// Reject invalid names before any prompting
+	if name != "" {
+		if err := prompt.ValidateName(name, prompt.NameRuleFor(gvr.Group, gvr.Resource)); err != nil {
+			return err
+		}
+	}
 
 	// If --from is specified, use existing resource as template and open in editor
-	if fromResource != "" {
+	if len(fromSources) > 0 {
 		return createFromTemplate(k8sClient, gvr)
 	}
 
 	// Get the schema for the resource
+	indicator = progress.Start(i18n.T("Fetching the OpenAPI schema"))
 	resourceSchema, err := k8sClient.GetResourceSchema(gvr)
+	indicator.Stop()
 	if err != nil {
 		// Continue with basic schema if we can't get the full one
-		fmt.Fprintf(os.Stderr, "Warning: Could not fetch full schema, using basic fields\n")
+		fmt.Fprint(os.Stderr, i18n.T("Warning: Could not fetch full schema, using basic fields\n"))
+		runArtifacts.Warn("could not fetch full schema, using basic fields")
+	}
+	if pinnedVersion != "" && resourceSchema != nil {
+		warnUnmatchedValues(resourceSchema, pinnedVersion)
+	}
+
+	// Resolve configured value sources (lower priority than --set)
+	presets, err := resolveValueSources(gvr)
+	if err != nil {
+		return err
+	}
+
+	// Values files override the value sources
+	fileValues, err := loadValuesFiles()
+	if err != nil {
+		return err
+	}
+	if len(fileValues) > 0 && presets == nil {
+		presets = make(map[string]interface{})
+	}
+	for k, v := range fileValues {
+		presets[k] = v
 	}
 
-	// Collect field values (from flags and/or prompts)
-	values, err := prompt.CollectFieldValues(resourceSchema, name, setValues)
+	// Map --image/--port/--env/--command to the first container
+	shortcutValues, err := shortcuts.Values(resourceSchema, name)
 	if err != nil {
-		return fmt.Errorf("failed to collect field values: %w", err)
+		return err
+	}
+	if len(shortcutValues) > 0 && presets == nil {
+		presets = make(map[string]interface{})
+	}
+	for k, v := range shortcutValues {
+		presets[k] = v
 	}
 
+	// Values from existing secrets and config maps (--set-from)
+	refValues, err := resolveSetFrom(k8sClient)
+	if err != nil {
+		return err
+	}
+	if len(refValues) > 0 && presets == nil {
+		presets = make(map[string]interface{})
+	}
+	for k, v := range refValues {
+		presets[k] = v
+	}
+
+	// File contents (--set-file)
+	setFiles, err := loadSetFiles()
+	if err != nil {
+		return err
+	}
+	if len(setFiles) > 0 && presets == nil {
+		presets = make(map[string]interface{})
+	}
+	for k, v := range setFiles {
+		presets[k] = v
+	}
+
+	// Offer the existing secrets, config maps, etc. for reference fields
+	prompt.SetReferenceLister(func(ref schema.GroupVersionResource) ([]string, error) {
+		return k8sClient.ListNames(ref, namespace)
+	})
+
+	// Leave the fields of the guided flow of the type to it
+	prompt.SetGuidedFields(guidedFields(gvr))
+
+	// Only ask for conditional fields when their conditions hold
+	conditions, err := promptConditions(gvr)
+	if err != nil {
+		return err
+	}
+	prompt.SetConditions(conditions)
+
+	// Check the name as soon as it's known; a suffix is checked once added
+	prompt.SetNameResolver(nil)
+	if nameSuffix == "" {
+		prompt.SetNameResolver(func(name string) (string, error) {
+			return resolveNameConflict(k8sClient, gvr, name)
+		})
+	}
+
+	// Collect field values (from flags and/or prompts, or examples with --example)
+	var values *prompt.CollectedValues
+	if example {
+		values, err = exampleFieldValues(resourceSchema, presets)
+	} else {
+		values, err = prompt.CollectFieldValuesWithLastValues(resourceSchema, name, setValues, presets, lastValues(gvr))
+	}
+	if err != nil {
+		return i18n.Errorf("failed to collect field values: %w", err)
+	}
+	if err := applyComputedFields(gvr, values); err != nil {
+		return err
+	}
+	if err := reviewValues(resourceSchema, values); err != nil {
+		return i18n.Errorf("failed to collect field values: %w", err)
+	}
+	if err := prepareWithPreset(k8sClient, gvr, values); err != nil {
+		return err
+	}
+
+	if nameSuffix != "" {
+		suffixed, err := suffixName(gvr, values.Name)
+		if err != nil {
+			return err
+		}
+		values.Name = suffixed
+		values.Values["metadata.name"] = suffixed
+	}
+	if nameSuffix != "" || example {
+		if values.Name, err = resolveNameConflict(k8sClient, gvr, values.Name); err != nil {
+			return err
+		}
+		values.Values["metadata.name"] = values.Name
+	}
+	runArtifacts.WriteValues(values.Values)
+	collectedValues = values.Values
+	runArtifacts.WriteAnswers(values.Answers)
+
 	// Generate the manifest
 	manifest, err := generator.GenerateManifest(gvr, namespace, values)
 	if err != nil {
-		return fmt.Errorf("failed to generate manifest: %w", err)
+		return i18n.Errorf("failed to generate manifest: %w", err)
+	}
+
+	// Fill secret/configmap data from --from-literal, --from-file, etc.
+	if err := generator.ApplyDataSources(manifest, gvr, dataSources); err != nil {
+		return err
+	}
+	if err := applyRequiredMetadata(gvr, manifest, !example); err != nil {
+		return err
+	}
+	if err := checkSelectorLabels(k8sClient, gvr, manifest); err != nil {
+		return err
+	}
+	bundle, err := bundleWithPreset(gvr, manifest)
+	if err != nil {
+		return err
 	}
+	printPresetSummary(gvr, manifest)
+	runArtifacts.WriteManifest(manifest)
+	if err := writeRBAC(gvr); err != nil {
+		return err
+	}
+
+	// Point out, or create, the Secrets, ConfigMaps, etc. it references that don't exist
+	verifyReferences(k8sClient, manifest)
+	createMissingReferents(k8sClient, manifest)
 
 	// If dry-run, print the manifest and exit
 	if dryRun {
-		return generator.PrintManifest(manifest, output)
+		return printDryRun(k8sClient, gvr, manifest, bundle...)
 	}
 
 	// Create the resource
-	created, err := k8sClient.CreateResource(gvr, namespace, manifest)
-	if err != nil {
-		return fmt.Errorf("failed to create resource: %w", err)
+	if err := createInTargets(k8sClient, gvr, manifest); err != nil {
+		return err
 	}
+	if err := finishWithPreset(k8sClient, gvr, manifest, bundle); err != nil {
+		return err
+	}
+	return createRBACFor(k8sClient, gvr)
+}
 
-	fmt.Printf("%s/%s created\n", gvr.Resource, created.GetName())
+// applyResourceScope clears the namespace for cluster-scoped resources so it is
+// neither used for requests nor written into metadata.namespace
+func applyResourceScope(resource string, namespaced bool) {
+	if namespaced {
+		return
+	}
+	if namespaceExplicit && namespace != "" {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %s is cluster-scoped, ignoring --namespace=%s\n"), resource, namespace)
+	}
+	namespace = ""
+}
+
+// afterCreate runs the optional post-create steps
+func afterCreate(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, created *unstructured.Unstructured) error {
+	if showStatus {
+		if err := printStatusSummary(k8sClient, gvr, created); err != nil {
+			return err
+		}
+	}
+	if showEvents {
+		if err := streamEvents(k8sClient, created); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// createFromTemplate fetches an existing resource, opens it in an editor, and creates a new one
-func createFromTemplate(k8sClient *client.K8sClient, gvr schema.GroupVersionResource) error {
-	fmt.Fprintf(os.Stderr, "Using %s as template...\n", fromResource)
+// submitResource creates obj after the pre-flight checks and confirmations
+func submitResource(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if err := checkBeforeCreate(k8sClient, gvr, obj); err != nil {
+		recordAudit(k8sClient, gvr, namespace, obj.GetName(), audit.OperationCreate, err)
+		return nil, err
+	}
+	return createChecked(k8sClient, gvr, obj)
+}
 
-	// Get the full resource
-	templateObj, err := k8sClient.GetResource(gvr, namespace, fromResource)
+// checkBeforeCreate runs the pre-flight checks and confirmations of creating
+// obj, recording a server dry-run in the artifacts bundle
+func checkBeforeCreate(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	if runArtifacts != nil {
+		preflight, err := k8sClient.DryRunCreateResource(gvr, namespace, obj.DeepCopy())
+		runArtifacts.WritePreflight(preflight, err)
+	}
+
+	if err := runPreflightChecks(k8sClient, gvr, obj, true); err != nil {
+		return err
+	}
+	return confirmProtected(k8sClient, namespace)
+}
+
+// createChecked creates obj, which passed checkBeforeCreate, recording the
+// response in the artifacts bundle, history and audit log. It is safe for
+// concurrent use by batch workers.
+func createChecked(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	submitted := obj.DeepCopy()
+	labelSession(submitted)
+	created, err := k8sClient.CreateResource(gvr, namespace, submitted.DeepCopy())
+	recordAudit(k8sClient, gvr, namespace, obj.GetName(), audit.OperationCreate, err)
 	if err != nil {
-		return fmt.Errorf("failed to get template resource %q: %w", fromResource, err)
+		return nil, err
 	}
 
-	// Clean up the template for creating a new resource
-	cleanedObj := cleanTemplateForCreation(templateObj, name, namespace)
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	sessionCreated++
+	runArtifacts.WriteResponse(created)
+	recordHistory(k8sClient, gvr, submitted, created)
+	return created, nil
+}
 
-	// Apply any --set values
-	if len(setValues) > 0 {
-		flagValues, err := prompt.ParseSetValues(setValues)
-		if err != nil {
-			return fmt.Errorf("failed to parse --set values: %w", err)
-		}
-		applySetValues(cleanedObj, flagValues)
+// createSubresource collects values for a subresource request body and posts it to the parent object
+func createSubresource(k8sClient *client.K8sClient, resourceType string) error {
+	sub, err := k8sClient.ResolveSubresource(resourceType)
+	if err != nil {
+		return i18n.Errorf("failed to resolve subresource %q: %w", resourceType, err)
 	}
 
-	// Convert to YAML
-	yamlBytes, err := yaml.Marshal(cleanedObj.Object)
+	if forObject == "" {
+		return i18n.Errorf("--for is required when creating subresource %s", sub.Name())
+	}
+
+	applyResourceScope(sub.Name(), sub.Namespaced)
+	fmt.Fprintf(os.Stderr, i18n.T("Creating %s for %s/%s\n"), sub.Kind, sub.Parent.Resource, forObject)
+
+	resourceSchema, err := k8sClient.GetSubresourceSchema(sub)
 	if err != nil {
-		return fmt.Errorf("failed to marshal template: %w", err)
+		fmt.Fprint(os.Stderr, i18n.T("Warning: Could not fetch full schema, using basic fields\n"))
 	}
 
-	// If dry-run, just print and exit
-	if dryRun {
-		fmt.Print(string(yamlBytes))
-		return nil
+	// The request body is named after the parent object
+	values, err := prompt.CollectFieldValues(resourceSchema, forObject, setValues)
+	if err != nil {
+		return i18n.Errorf("failed to collect field values: %w", err)
 	}
 
-	// Write to temp file
-	tmpFile, err := os.CreateTemp("", "kubectl-create-resource-*.yaml")
+	manifest, err := generator.GenerateManifest(sub.Parent, namespace, values)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return i18n.Errorf("failed to generate manifest: %w", err)
 	}
-	tmpPath := tmpFile.Name()
-	defer os.Remove(tmpPath)
+	manifest.SetAPIVersion(schema.GroupVersion{Group: sub.Group, Version: sub.Version}.String())
+	manifest.SetKind(sub.Kind)
 
-	if _, err := tmpFile.Write(yamlBytes); err != nil {
-		tmpFile.Close()
-		return fmt.Errorf("failed to write temp file: %w", err)
+	if serverDryRun {
+		return i18n.Errorf("--dry-run=server is not supported for subresources")
+	}
+	if dryRun {
+		return generator.PrintManifest(manifest, output)
 	}
-	tmpFile.Close()
 
-	// Open in editor
-	editor := getEditor()
-	fmt.Fprintf(os.Stderr, "Opening %s in %s...\n", tmpPath, editor)
+	if err := confirmProtected(k8sClient, namespace); err != nil {
+		return err
+	}
+	created, err := k8sClient.CreateSubresource(sub, namespace, forObject, manifest)
+	recordAudit(k8sClient, sub.Parent, namespace, forObject+"/"+sub.Subresource, audit.OperationCreate, err)
+	if err != nil {
+		return i18n.Errorf("failed to create subresource: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("%s/%s %s created\n"), sub.Parent.Resource, forObject, sub.Subresource)
+
+	// Subresource responses often carry the result (e.g., the issued token)
+	return generator.PrintManifest(created, "yaml")
+}
+
+// createFromTemplate fetches an existing resource, opens it in an editor, and creates a new one
+func createFromTemplate(k8sClient *client.K8sClient, gvr schema.GroupVersionResource) error {
+	if !shortcuts.IsEmpty() {
+		return i18n.Errorf("--image, --port, --env and --command cannot be combined with --from, use --set instead")
+	}
 
-	cmd := exec.Command(editor, tmpPath)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	fmt.Fprintf(os.Stderr, i18n.T("Using %s as template...\n"), strings.Join(fromSources, " + "))
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("editor exited with error: %w", err)
+	cleanedObj, err := loadTemplate(k8sClient, gvr)
+	if err != nil {
+		return err
+	}
+	if nameSuffix != "" {
+		suffixed, err := suffixName(gvr, cleanedObj.GetName())
+		if err != nil {
+			return err
+		}
+		cleanedObj.SetName(suffixed)
+	}
+	resolvedName, err := resolveNameConflict(k8sClient, gvr, cleanedObj.GetName())
+	if err != nil {
+		return err
+	}
+	cleanedObj.SetName(resolvedName)
+	if err := prepareWithPreset(k8sClient, gvr, nil); err != nil {
+		return err
 	}
 
-	// Read back the edited file
-	editedBytes, err := os.ReadFile(tmpPath)
+	// Apply configured value sources, then any --set values
+	presets, err := resolveValueSources(gvr)
+	if err != nil {
+		return err
+	}
+	applySetValues(cleanedObj, presets)
+	fileValues, err := loadValuesFiles()
+	if err != nil {
+		return err
+	}
+	applySetValues(cleanedObj, fileValues)
+	refValues, err := resolveSetFrom(k8sClient)
+	if err != nil {
+		return err
+	}
+	applySetValues(cleanedObj, refValues)
+	setFiles, err := loadSetFiles()
 	if err != nil {
-		return fmt.Errorf("failed to read edited file: %w", err)
+		return err
 	}
+	applySetValues(cleanedObj, setFiles)
 
-	// Parse the edited YAML
-	var editedObj unstructured.Unstructured
-	if err := yaml.Unmarshal(editedBytes, &editedObj.Object); err != nil {
-		return fmt.Errorf("failed to parse edited YAML: %w", err)
+	if len(setValues) > 0 {
+		flagValues, err := prompt.ParseSetValues(setValues)
+		if err != nil {
+			return i18n.Errorf("failed to parse --set values: %w", err)
+		}
+		applySetValues(cleanedObj, flagValues)
 	}
 
-	// Create the resource
-	created, err := k8sClient.CreateResource(gvr, namespace, &editedObj)
+	if err := generator.ApplyDataSources(cleanedObj, gvr, dataSources); err != nil {
+		return err
+	}
+	if err := applyRequiredMetadata(gvr, cleanedObj, true); err != nil {
+		return err
+	}
+	if err := checkSelectorLabels(k8sClient, gvr, cleanedObj); err != nil {
+		return err
+	}
+	bundle, err := bundleWithPreset(gvr, cleanedObj)
 	if err != nil {
-		return fmt.Errorf("failed to create resource: %w", err)
+		return err
 	}
+	printPresetSummary(gvr, cleanedObj)
+	verifyReferences(k8sClient, cleanedObj)
 
-	fmt.Printf("%s/%s created\n", gvr.Resource, created.GetName())
-	return nil
+	// Convert to YAML
+	yamlBytes, err := yaml.Marshal(cleanedObj.Object)
+	if err != nil {
+		return i18n.Errorf("failed to marshal template: %w", err)
+	}
+
+	runArtifacts.WriteManifest(cleanedObj)
+	if err := writeRBAC(gvr); err != nil {
+		return err
+	}
+
+	// If dry-run, just print and exit
+	if dryRun && !serverDryRun && len(bundle) == 0 {
+		err := runPreflightChecks(k8sClient, gvr, cleanedObj, false)
+		recordAudit(k8sClient, gvr, namespace, cleanedObj.GetName(), audit.OperationDryRun, err)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(yamlBytes))
+		return nil
+	}
+	if dryRun {
+		return printDryRun(k8sClient, gvr, cleanedObj, bundle...)
+	}
+
+	// Without a terminal there's no editor, so create the template as modified by
+	// --set, as do scripted runs
+	editedObj := cleanedObj
+	if prompt.IsTerminal() && !prompt.Scripted() {
+		// Open in editor until the manifest is valid
+		editedObj, err = editManifest(k8sClient, gvr, cleanedObj)
+		if err != nil {
+			return err
+		}
+		runArtifacts.WriteManifest(editedObj)
+	}
+
+	// Create the resource
+	if err := createInTargets(k8sClient, gvr, editedObj); err != nil {
+		return err
+	}
+	if err := finishWithPreset(k8sClient, gvr, editedObj, bundle); err != nil {
+		return err
+	}
+	return createRBACFor(k8sClient, gvr)
 }
 
 // cleanTemplateForCreation removes fields that shouldn't be copied to a new resource
@@ -312,15 +1076,143 @@ func cleanTemplateForCreation(obj *unstructured.Unstructured, newName, newNamesp
 			}
 		}
 
-		// Set namespace if provided
+		// Set namespace if provided, otherwise drop it (cluster-scoped resources)
 		if newNamespace != "" {
 			metadata["namespace"] = newNamespace
+		} else {
+			delete(metadata, "namespace")
 		}
 	}
+	dropSessionLabel(newObj)
 
 	return newObj
 }
 
+// suffixName applies --name-suffix to name and validates the result
+func suffixName(gvr schema.GroupVersionResource, base string) (string, error) {
+	suffixed, err := generator.AppendNameSuffix(base, nameSuffix)
+	if err != nil {
+		return "", err
+	}
+	if err := prompt.ValidateName(suffixed, prompt.NameRuleFor(gvr.Group, gvr.Resource)); err != nil {
+		return "", i18n.Errorf("name with suffix is invalid: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Using name %s\n"), suffixed)
+	return suffixed, nil
+}
+
+// newClient creates the Kubernetes client for this run, backed by local schema
+// files with --offline, with the types of any --crd files added. With target
+// contexts, the client is for the first one.
+func newClient() (*client.K8sClient, error) {
+	var k8sClient *client.K8sClient
+	var err error
+	if offline {
+		k8sClient, err = client.NewOfflineClient(schemaFile)
+	} else if len(targetContexts) > 0 {
+		k8sClient, err = client.NewK8sClientWithOptions(connectionOptions(targetContexts[0]))
+	} else {
+		k8sClient, err = client.NewK8sClientWithOptions(connectionOptions(kubeContext))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user := k8sClient.Impersonating(); user != "" {
+		fmt.Fprintf(os.Stderr, i18n.T("Impersonating %s\n"), user)
+	}
+
+	// The same path may be given twice (completion parses the flags twice too)
+	loaded := make(map[string]bool)
+	for _, path := range crdFiles {
+		if loaded[path] {
+			continue
+		}
+		loaded[path] = true
+		if err := k8sClient.AddCRDs(path); err != nil {
+			return nil, i18n.Errorf("failed to load --crd %s: %w", path, err)
+		}
+	}
+	return k8sClient, nil
+}
+
+// connectionOptions returns the connection settings from the command line for a
+// kubeconfig context ("" for the current context)
+func connectionOptions(contextName string) client.ConnectionOptions {
+	return client.ConnectionOptions{
+		Kubeconfig: kubeconfig,
+		Context:    contextName,
+
+		Server:                server,
+		Token:                 token,
+		CertificateAuthority:  certificateAuthority,
+		InsecureSkipTLSVerify: insecureSkipTLSVerify,
+
+		QPS:   kubeAPIQPS,
+		Burst: kubeAPIBurst,
+
+		FastDiscovery: fastDiscovery,
+
+		AsUser:   asUser,
+		AsGroups: asGroups,
+		AsUID:    asUID,
+	}
+}
+
+// validateOfflineFlags checks the --offline/--schema-file combination; creating is
+// disabled offline, so --offline turns on --dry-run
+func validateOfflineFlags(cmd *cobra.Command) error {
+	if schemaFile != "" && !offline {
+		return i18n.Errorf("--schema-file requires --offline")
+	}
+	if !offline {
+		return nil
+	}
+	if schemaFile == "" && len(crdFiles) == 0 {
+		return i18n.Errorf("--offline requires --schema-file or --crd")
+	}
+	if cmd.Flags().Changed("dry-run") && !dryRun {
+		return i18n.Errorf("resources cannot be created in offline mode, use --dry-run")
+	}
+	if serverDryRun {
+		return i18n.Errorf("--dry-run=server needs a cluster and cannot be used with --offline")
+	}
+	if showEvents || showStatus {
+		return i18n.Errorf("--show-events and --status need a cluster and cannot be used with --offline")
+	}
+	if len(setFromValues) > 0 {
+		return i18n.Errorf("--set-from reads from the cluster and cannot be used with --offline")
+	}
+	dryRun = true
+	return nil
+}
+
+// loadConfig loads the config file from --config or the default location
+func loadConfig() (*config.Config, error) {
+	return config.Load(configPath)
+}
+
+// resolveValueSources evaluates the configured value sources that apply to gvr
+func resolveValueSources(gvr schema.GroupVersionResource) (map[string]interface{}, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	valueSources := cfg.ValueSourcesFor(gvr)
+	if len(valueSources) == 0 {
+		return nil, nil
+	}
+
+	values, err := sources.NewResolver().Resolve(valueSources)
+	if err != nil {
+		return nil, i18n.Errorf("failed to resolve value sources: %w", err)
+	}
+	for path, val := range values {
+		fmt.Fprintf(os.Stderr, i18n.T("Resolved %s=%v from value source\n"), path, val)
+	}
+	return values, nil
+}
+
 // applySetValues applies --set flag values to an unstructured object
 func applySetValues(obj *unstructured.Unstructured, values map[string]interface{}) {
 	for path, value := range values {
@@ -353,23 +1245,6 @@ func splitPathParts(path string) []string {
 	return parts
 }
 
-// getEditor returns the editor to use, from $EDITOR or defaults
-func getEditor() string {
-	if editor := os.Getenv("EDITOR"); editor != "" {
-		return editor
-	}
-	if editor := os.Getenv("VISUAL"); editor != "" {
-		return editor
-	}
-	// Try common editors
-	for _, editor := range []string{"vim", "vi", "nano"} {
-		if _, err := exec.LookPath(editor); err == nil {
-			return editor
-		}
-	}
-	return "vi"
-}
-
 // The following are kept for interface compatibility but delegate to packages
 
 func discoverResourceTypes() ([]discovery.ResourceType, error) {
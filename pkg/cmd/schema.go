@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var schemaOutput string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema <resource-type>",
+	Short: "Print the schema of a resource type as JSON or YAML for tooling",
+	Long: `Print the schema this tool builds its prompts from: every field with its path,
+type, description, whether it is required, its default and allowed values, nested
+objects and array items. IDE plugins, form generators and web UIs can build their
+own creation forms on top of it.
+
+Examples:
+  # Schema of a deployment as JSON
+  kubectl create-resource schema deployment -o json
+
+  # Schema of a CRD from a local file, without a cluster
+  kubectl create-resource schema queue --offline --crd=queue-crd.yaml -o yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSchema,
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "json",
+		"output format (json or yaml)")
+
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if err := validateOfflineFlags(cmd); err != nil {
+		return err
+	}
+	if schemaOutput != "json" && schemaOutput != "yaml" {
+		return i18n.Errorf("unsupported output format: %s (use yaml or json)", schemaOutput)
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	gvr, err := k8sClient.ResolveResourceType(args[0])
+	if err != nil {
+		return i18n.Errorf("failed to resolve resource type %q: %w", args[0], err)
+	}
+	resourceSchema, err := k8sClient.GetResourceSchema(gvr)
+	if err != nil {
+		return i18n.Errorf("failed to get schema for %s: %w", formatGVR(gvr), err)
+	}
+
+	doc := client.NewSchemaDocument(gvr, resourceSchema, k8sClient.IsNamespaced(gvr))
+
+	if schemaOutput == "yaml" {
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return i18n.Errorf("failed to marshal to YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return i18n.Errorf("failed to marshal to JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
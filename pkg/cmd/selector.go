@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// selectorCheckedGroups are the API groups whose workloads the API server
+// rejects when their selector doesn't match their pod template. Custom
+// resources only get a warning, as their controllers may not require it.
+var selectorCheckedGroups = map[string]bool{"apps": true, "batch": true}
+
+// checkSelectorLabels checks that the matchLabels of the selector of a workload
+// are all set, with the same values, on the labels of its pod template, which
+// the API server requires with a cryptic error. Mismatches are fixed by setting
+// the selector's labels on the template after confirmation; otherwise they end
+// the run, with the --set flags that fix them, or only warn for dry runs and
+// custom resources.
+func checkSelectorLabels(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	resourceSchema, err := k8sClient.GetResourceSchema(gvr)
+	if err != nil {
+		// The known workloads are found by kind
+		resourceSchema = &client.ResourceSchema{GVK: obj.GroupVersionKind()}
+	}
+	_, template, ok := prompt.PodSpecOf(resourceSchema)
+	if !ok || template == nil || template.SelectorPath == "" {
+		return nil
+	}
+	matchLabels, _, _ := unstructured.NestedStringMap(obj.Object, append(splitPath(template.SelectorPath), "matchLabels")...)
+	labelsPath := append(splitPath(template.Path), "metadata", "labels")
+	labels, _, _ := unstructured.NestedStringMap(obj.Object, labelsPath...)
+
+	var mismatches, flags []string
+	for _, key := range sortedLabelKeys(matchLabels) {
+		want := matchLabels[key]
+		got, set := labels[key]
+		if set && got == want {
+			continue
+		}
+		if set {
+			mismatches = append(mismatches, i18n.T("%s=%s (template: %s)", key, want, got))
+		} else {
+			mismatches = append(mismatches, i18n.T("%s=%s (template: unset)", key, want))
+		}
+		flags = append(flags, fmt.Sprintf("--set=%s.metadata.labels.%s=%s", template.Path, key, want))
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Warning: the selector of %s %s doesn't match the labels of its pod template: %s\n"),
+		obj.GetKind(), obj.GetName(), strings.Join(mismatches, ", "))
+	if prompt.CanPrompt() && prompt.Confirm("fix-selector-labels", i18n.T("Set the selector's labels on the pod template")) {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for key, val := range matchLabels {
+			labels[key] = val
+		}
+		return unstructured.SetNestedStringMap(obj.Object, labels, labelsPath...)
+	}
+	runArtifacts.Warn(fmt.Sprintf("the selector doesn't match the labels of the pod template: %s", strings.Join(mismatches, ", ")))
+	if dryRun || !selectorCheckedGroups[gvr.Group] {
+		return nil
+	}
+	return i18n.Errorf("the selector of %s %s doesn't match the labels of its pod template, which the API server rejects; set them with %s",
+		obj.GetKind(), obj.GetName(), strings.Join(flags, " "))
+}
+
+// sortedLabelKeys returns the keys of labels in order
+func sortedLabelKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	rpcserver "github.com/gshaibi/kubectl-create-resource/pkg/server"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var listenAddress string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve discovery, schemas, manifest generation and creation over a local JSON-RPC API",
+	Long: `Serve the discovery, schema, generate and create steps over a local JSON-RPC 2.0
+API, so editors and GUIs can drive the flow programmatically. Requests are POSTed
+to /rpc; the methods are ListResourceTypes, GetSchema, GenerateManifest and
+CreateResource.
+
+The API acts with your kubeconfig credentials, so it only listens on a unix
+socket (readable only by you) or a loopback address. On a loopback address,
+requests must also carry the bearer token printed at startup. Requests must be
+sent with Content-Type: application/json, a localhost Host and no Origin, which
+keeps web pages open in a browser from calling the API.
+
+Examples:
+  # Serve on a unix socket
+  kubectl create-resource serve --listen=unix:///tmp/kcr.sock
+
+  # Call a method
+  curl --unix-socket /tmp/kcr.sock http://localhost/rpc -H 'Content-Type: application/json' \
+    -d '{"jsonrpc": "2.0", "id": 1, "method": "GetSchema", "params": {"type": "deployment"}}'`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&listenAddress, "listen", "",
+		"address to listen on: unix:///path/to.sock or localhost:port")
+	serveCmd.MarkFlagRequired("listen")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if err := validateOfflineFlags(cmd); err != nil {
+		return err
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	listener, err := rpcserver.Listen(listenAddress)
+	if err != nil {
+		return err
+	}
+	srv := rpcserver.New(k8sClient)
+	// Required labels and annotations take their configured defaults, or must be
+	// part of the request values
+	srv.Prepare = func(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+		return applyRequiredMetadata(gvr, obj, false)
+	}
+	// Protected contexts and namespaces can't be confirmed over the API
+	srv.Authorize = func(namespace string) error {
+		return refuseProtected(k8sClient, namespace)
+	}
+	srv.Audit = func(gvr schema.GroupVersionResource, namespace, name string, dryRun bool, err error) {
+		operation := audit.OperationCreate
+		if dryRun {
+			operation = audit.OperationServerDryRun
+		}
+		recordAudit(k8sClient, gvr, namespace, name, operation, err)
+	}
+
+	// Close the listener on interrupt, which also removes a unix socket
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, i18n.T("Serving %s on %s (POST %s)\n"), strings.Join(srv.Methods(), ", "), listenAddress, rpcserver.Path)
+	// Other local users can reach a TCP port, so it also takes a token
+	if listener.Addr().Network() == "tcp" {
+		if srv.Token, err = rpcserver.NewToken(); err != nil {
+			listener.Close()
+			return err
+		}
+		fmt.Fprintf(os.Stderr, i18n.T("Send each request with the header: Authorization: Bearer %s\n"), srv.Token)
+	}
+	return srv.Serve(listener)
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// tokenSecretTimeout is how long to wait for the token controller to fill in
+// the token of a token Secret
+const tokenSecretTimeout = 15 * time.Second
+
+// tokenNone is the value of --with-token for not getting a token without being asked
+const tokenNone = "none"
+
+// Formats of the token printed by the serviceaccount preset (--token-format)
+const (
+	tokenFormatToken      = "token"
+	tokenFormatKubeconfig = "kubeconfig"
+)
+
+var (
+	withToken     string
+	tokenDuration time.Duration
+	tokenFormat   string
+)
+
+func init() {
+	typePresets[schema.GroupResource{Resource: "serviceaccounts"}] = preset{
+		flags:   []string{"with-token", "token-duration", "token-format"},
+		prepare: prepareServiceAccount,
+		created: issueServiceAccountToken,
+	}
+
+	rootCmd.Flags().StringVar(&withToken, "with-token", "",
+		"with serviceaccount, also get a token from a long-lived token Secret (secret) or a short-lived TokenRequest (request), or none without asking")
+	rootCmd.Flags().DurationVar(&tokenDuration, "token-duration", time.Hour,
+		"with --with-token=request, how long the token is valid")
+	rootCmd.Flags().StringVar(&tokenFormat, "token-format", tokenFormatToken,
+		"with --with-token, print the token (token) or a kubeconfig using it (kubeconfig)")
+}
+
+// prepareServiceAccount checks the token flags, or asks whether to get a
+// token when --with-token isn't given
+func prepareServiceAccount(_ *client.K8sClient, _ *prompt.CollectedValues) error {
+	switch tokenFormat {
+	case tokenFormatToken, tokenFormatKubeconfig:
+	default:
+		return i18n.Errorf("invalid --token-format %q, must be token or kubeconfig", tokenFormat)
+	}
+	if tokenDuration < 10*time.Minute {
+		return i18n.Errorf("--token-duration must be at least 10m")
+	}
+
+	if withToken == "" && !example && !dryRun && prompt.CanPrompt() {
+		picked, err := prompt.PickServiceAccountToken()
+		if err != nil {
+			return err
+		}
+		withToken = picked
+	}
+	switch withToken {
+	case prompt.TokenNone, tokenNone:
+		withToken = prompt.TokenNone
+		return nil
+	case prompt.TokenSecret, prompt.TokenRequest:
+	default:
+		return i18n.Errorf("invalid --with-token %q, must be secret, request or none", withToken)
+	}
+
+	if count > 1 || len(targetContexts) > 0 {
+		return i18n.Errorf("--with-token cannot be combined with --count, --contexts or --all-contexts")
+	}
+	if dryRun {
+		fmt.Fprint(os.Stderr, i18n.T("Warning: no token is issued with --dry-run\n"))
+	}
+	return nil
+}
+
+// issueServiceAccountToken gets a token for the service account created from
+// manifest, as chosen with --with-token, and prints it
+func issueServiceAccountToken(k8sClient *client.K8sClient, manifest *unstructured.Unstructured) error {
+	var token string
+	var err error
+	switch withToken {
+	case prompt.TokenSecret:
+		token, err = createTokenSecret(k8sClient, manifest.GetName())
+	case prompt.TokenRequest:
+		token, err = requestToken(k8sClient, manifest.GetName())
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if tokenFormat == tokenFormatKubeconfig {
+		return printTokenKubeconfig(k8sClient, manifest.GetName(), token)
+	}
+	fmt.Println(token)
+	return nil
+}
+
+// createTokenSecret creates a long-lived token Secret for the service account
+// and waits for the token controller to fill in its token
+func createTokenSecret(k8sClient *client.K8sClient, account string) (string, error) {
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "kubernetes.io/service-account-token",
+	}}
+	secret.SetName(account + "-token")
+	secret.SetNamespace(namespace)
+	secret.SetAnnotations(map[string]string{"kubernetes.io/service-account.name": account})
+
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	created, err := submitResource(k8sClient, secretsGVR, secret)
+	if err != nil {
+		return "", i18n.Errorf("failed to create the token secret: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("%s/%s created\n"), secretsGVR.Resource, created.GetName())
+
+	filled, err := k8sClient.WaitFor(secretsGVR, namespace, created.GetName(), tokenSecretTimeout, func(obj *unstructured.Unstructured) bool {
+		token, _, _ := unstructured.NestedString(obj.Object, "data", "token")
+		return token != ""
+	})
+	if err != nil {
+		return "", err
+	}
+	encoded, _, _ := unstructured.NestedString(filled.Object, "data", "token")
+	if encoded == "" {
+		return "", i18n.Errorf("the token of secret %s was not filled in within %s, is the token controller running?", created.GetName(), tokenSecretTimeout)
+	}
+	token, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", i18n.Errorf("failed to decode the token of secret %s: %w", created.GetName(), err)
+	}
+	return string(token), nil
+}
+
+// requestToken issues a token for the service account with a TokenRequest
+func requestToken(k8sClient *client.K8sClient, account string) (string, error) {
+	sub, err := k8sClient.ResolveSubresource("serviceaccounts/token")
+	if err != nil {
+		return "", i18n.Errorf("failed to resolve subresource %q: %w", "serviceaccounts/token", err)
+	}
+	request := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "authentication.k8s.io/v1",
+		"kind":       "TokenRequest",
+		"spec": map[string]interface{}{
+			"expirationSeconds": int64(tokenDuration.Seconds()),
+		},
+	}}
+
+	issued, err := k8sClient.CreateSubresource(sub, namespace, account, request)
+	recordAudit(k8sClient, sub.Parent, namespace, account+"/"+sub.Subresource, audit.OperationCreate, err)
+	if err != nil {
+		return "", i18n.Errorf("failed to request a token: %w", err)
+	}
+	token, _, _ := unstructured.NestedString(issued.Object, "status", "token")
+	if token == "" {
+		return "", i18n.Errorf("the token request returned no token")
+	}
+	expires, _, _ := unstructured.NestedString(issued.Object, "status", "expirationTimestamp")
+	fmt.Fprintf(os.Stderr, i18n.T("Token issued, valid until %s\n"), expires)
+	return token, nil
+}
+
+// printTokenKubeconfig prints a kubeconfig connecting to the cluster of
+// k8sClient as the service account, in its namespace
+func printTokenKubeconfig(k8sClient *client.K8sClient, account, token string) error {
+	ca, insecure, err := k8sClient.CertificateAuthority()
+	if err != nil {
+		return err
+	}
+
+	name := account
+	if contextName := clientContext(k8sClient); contextName != "" {
+		name += "@" + contextName
+	}
+	config := clientcmdapi.NewConfig()
+	config.Clusters[name] = &clientcmdapi.Cluster{
+		Server:                   k8sClient.Server(),
+		CertificateAuthorityData: ca,
+		InsecureSkipTLSVerify:    insecure,
+	}
+	config.AuthInfos[name] = &clientcmdapi.AuthInfo{Token: token}
+	config.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name, Namespace: namespace}
+	config.CurrentContext = name
+
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		return i18n.Errorf("failed to write the kubeconfig: %w", err)
+	}
+	fmt.Print(string(data))
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+// sessionEnabled reports whether to offer creating more resources after the
+// first one. Only fully interactive runs in a terminal get a session, so
+// scripted and flag-driven runs still create a single resource and exit.
+func sessionEnabled() bool {
+	if dryRun || runArtifacts != nil || len(targetContexts) > 0 {
+		return false
+	}
+	if name != "" || len(setValues) > 0 || len(fromSources) > 0 || !shortcuts.IsEmpty() || !dataSources.IsEmpty() {
+		return false
+	}
+	// Answers files are about a single resource
+	if answersFile != "" || recordAnswersFile != "" {
+		return false
+	}
+	return prompt.IsTerminal() && !prompt.Scripted()
+}
+
+// runSession keeps creating resources with the same client (and its discovery
+// and schema caches) until the user quits. The first resource of the session
+// was already created as resourceType.
+func runSession(k8sClient *client.K8sClient, resourceType string) error {
+	initialNamespace := namespace
+
+	for {
+		switch prompt.PromptNextAction(resourceType) {
+		case prompt.SessionQuit:
+			return nil
+		case prompt.SessionOtherType:
+			// Quitting the picker ends the session
+			picked, err := pickResourceType(k8sClient)
+			if prompt.IsInterrupt(err) {
+				return nil
+			}
+			if err != nil {
+				return i18n.Errorf("failed to pick a resource type: %w", err)
+			}
+			resourceType = picked
+		}
+
+		// Each resource starts from the command-line namespace and prompts for its name
+		namespace = initialNamespace
+		name = ""
+
+		if err := createResourceWithClient(k8sClient, resourceType); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v\n"), err)
+		}
+	}
+}
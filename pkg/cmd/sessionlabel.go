@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// sessionLabel is the label set on the resources created in one invocation,
+// with the ID of the invocation as value
+const sessionLabel = "create-resource.kubectl.io/session"
+
+var noSessionLabel bool
+
+var (
+	// sessionID identifies the invocation in the labels of what it creates
+	sessionID = string(uuid.NewUUID())
+
+	// sessionCreated counts the resources created, guarded by recordMu
+	sessionCreated int
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noSessionLabel, "no-session-label", false,
+		"don't label created resources with the ID of the invocation ("+sessionLabel+")")
+}
+
+// labelSession sets the session label on obj, which is about to be created
+func labelSession(obj *unstructured.Unstructured) {
+	if noSessionLabel {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[sessionLabel] = sessionID
+	obj.SetLabels(labels)
+}
+
+// printSession prints the session ID, and how to delete what was created with
+// it, once the invocation created resources
+func printSession() {
+	if noSessionLabel || sessionCreated == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Session %s, delete the resources it created with:\n"), sessionID)
+	fmt.Fprintf(os.Stderr, "  kubectl create-resource cleanup -A -l %s=%s\n", sessionLabel, sessionID)
+}
+
+// dropSessionLabel removes the session label of an existing resource from obj,
+// a copy to create again, so the copy isn't cleaned up with that session
+func dropSessionLabel(obj *unstructured.Unstructured) {
+	labels := obj.GetLabels()
+	if _, ok := labels[sessionLabel]; !ok {
+		return
+	}
+	delete(labels, sessionLabel)
+	if len(labels) == 0 {
+		labels = nil
+	}
+	obj.SetLabels(labels)
+}
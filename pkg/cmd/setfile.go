@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+)
+
+var setFileValues []string
+
+func init() {
+	rootCmd.Flags().StringArrayVar(&setFileValues, "set-file", []string{},
+		"set a field to the contents of a file, for scripts, certificates and other long text (e.g., --set-file=spec.script=./init.sh)")
+}
+
+// loadSetFiles reads the --set-file files into the values of their fields. The
+// contents are taken as text, keeping line breaks.
+func loadSetFiles() (map[string]interface{}, error) {
+	if len(setFileValues) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]interface{}, len(setFileValues))
+	for _, s := range setFileValues {
+		path, file, ok := strings.Cut(s, "=")
+		path = strings.TrimSpace(path)
+		if !ok || path == "" || file == "" {
+			return nil, i18n.Errorf("invalid --set-file %q (expected path=file)", s)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, i18n.Errorf("failed to read --set-file %s: %w", path, err)
+		}
+		values[path] = string(data)
+	}
+	return values, nil
+}
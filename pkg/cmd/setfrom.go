@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var setFromValues []string
+
+// valueRef is a --set-from reference to a key of a Secret or ConfigMap
+type valueRef struct {
+	path string
+	kind string // secret or configmap
+	name string
+	key  string
+}
+
+func init() {
+	rootCmd.Flags().StringArrayVar(&setFromValues, "set-from", []string{},
+		"set a field from a key of an existing Secret or ConfigMap in the namespace (e.g., --set-from=spec.password=secret:db/password or =configmap:settings/mode)")
+}
+
+// parseValueRef parses path=secret:name/key or path=configmap:name/key
+func parseValueRef(s string) (valueRef, error) {
+	invalid := i18n.Errorf("invalid --set-from %q (expected path=secret:name/key or path=configmap:name/key)", s)
+	path, ref, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(path) == "" {
+		return valueRef{}, invalid
+	}
+	kind, source, ok := strings.Cut(ref, ":")
+	if !ok {
+		return valueRef{}, invalid
+	}
+	name, key, ok := strings.Cut(source, "/")
+	if !ok || name == "" || key == "" {
+		return valueRef{}, invalid
+	}
+	kind = strings.ToLower(kind)
+	if kind != "secret" && kind != "configmap" {
+		return valueRef{}, invalid
+	}
+	return valueRef{path: strings.TrimSpace(path), kind: kind, name: name, key: key}, nil
+}
+
+// resolveSetFrom reads the --set-from values from the cluster. Secret values
+// are not echoed.
+func resolveSetFrom(k8sClient *client.K8sClient) (map[string]interface{}, error) {
+	if len(setFromValues) == 0 {
+		return nil, nil
+	}
+	refs := make([]valueRef, 0, len(setFromValues))
+	for _, s := range setFromValues {
+		ref, err := parseValueRef(s)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	values := make(map[string]interface{}, len(refs))
+	for _, ref := range refs {
+		value, err := ref.read(k8sClient)
+		if err != nil {
+			return nil, i18n.Errorf("failed to resolve --set-from %s: %w", ref.path, err)
+		}
+		values[ref.path] = value
+		fmt.Fprintf(os.Stderr, i18n.T("Resolved %s from %s %s/%s\n"), ref.path, ref.kind, ref.name, ref.key)
+	}
+	return values, nil
+}
+
+// read returns the value of the referenced key, decoding base64 data
+func (r valueRef) read(k8sClient *client.K8sClient) (string, error) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	if r.kind == "secret" {
+		gvr.Resource = "secrets"
+	}
+	obj, err := k8sClient.GetResource(gvr, namespace, r.name)
+	if err != nil {
+		return "", err
+	}
+
+	// Secrets only have base64 data; config maps have text data and base64 binaryData
+	encoded, found, _ := unstructured.NestedString(obj.Object, "data", r.key)
+	if r.kind == "configmap" {
+		if found {
+			return encoded, nil
+		}
+		encoded, found, _ = unstructured.NestedString(obj.Object, "binaryData", r.key)
+	}
+	if !found {
+		return "", i18n.Errorf("%s %s has no key %q", r.kind, r.name, r.key)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", i18n.Errorf("%s %s key %q is not valid base64: %w", r.kind, r.name, r.key, err)
+	}
+	return string(decoded), nil
+}
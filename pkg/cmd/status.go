@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// printStatusSummary waits briefly for the controller to report status and prints
+// a concise summary, using the CRD's printer columns when it declares any
+func printStatusSummary(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, created *unstructured.Unstructured) error {
+	fmt.Fprintf(os.Stderr, i18n.T("Waiting up to %s for status...\n"), statusTimeout)
+
+	obj, err := k8sClient.WaitForStatus(gvr, created.GetNamespace(), created.GetName(), statusTimeout)
+	if err != nil {
+		return i18n.Errorf("failed to get status: %w", err)
+	}
+
+	fmt.Printf(i18n.T("\nStatus of %s/%s:\n"), gvr.Resource, obj.GetName())
+
+	columns, err := k8sClient.GetPrinterColumns(gvr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Note: could not read printer columns: %v\n"), err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	printed := false
+	for _, col := range columns {
+		if col.Priority > 0 || strings.EqualFold(col.Name, "Age") {
+			continue
+		}
+		if val := evalJSONPath(obj.Object, col.JSONPath); val != "" {
+			fmt.Fprintf(w, "  %s:\t%s\n", col.Name, val)
+			printed = true
+		}
+	}
+
+	// Fall back to the common status conventions
+	if !printed {
+		if phase, ok, _ := unstructured.NestedString(obj.Object, "status", "phase"); ok {
+			fmt.Fprintf(w, "  Phase:\t%s\n", phase)
+			printed = true
+		}
+		if replicas, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); ok {
+			ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+			fmt.Fprintf(w, "  Ready:\t%d/%d\n", ready, replicas)
+			printed = true
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		line := fmt.Sprintf("  Condition %v:\t%v", cond["type"], cond["status"])
+		if reason, ok := cond["reason"].(string); ok && reason != "" {
+			line += " (" + reason + ")"
+		}
+		if message, ok := cond["message"].(string); ok && message != "" {
+			line += " " + message
+		}
+		fmt.Fprintln(w, line)
+		printed = true
+	}
+
+	if !printed {
+		fmt.Fprintln(w, "  No status reported yet")
+	}
+	return nil
+}
+
+// evalJSONPath evaluates a CRD printer column JSONPath against obj
+func evalJSONPath(obj map[string]interface{}, path string) string {
+	jp := jsonpath.New("column").AllowMissingKeys(true)
+	if err := jp.Parse("{" + path + "}"); err != nil {
+		return ""
+	}
+	var b strings.Builder
+	if err := jp.Execute(&b, obj); err != nil {
+		return ""
+	}
+	return b.String()
+}
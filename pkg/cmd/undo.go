@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/audit"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/history"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var undoYes bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Delete the most recently created resource recorded in the history",
+	Long: `Delete the most recently created resource recorded in the history, after confirmation.
+
+The resource is deleted from the cluster it was created in, and only if it is still
+the same object (matched by UID). Running undo again deletes the one before it.`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+func init() {
+	undoCmd.Flags().BoolVarP(&undoYes, "yes", "y", false,
+		"delete without asking for confirmation")
+
+	rootCmd.AddCommand(undoCmd)
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	path := history.DefaultPath()
+	entry, err := history.Last(path)
+	if err != nil {
+		return err
+	}
+
+	// Delete in the cluster the resource was created in
+	k8sClient, err := client.NewK8sClientWithOptions(connectionOptions(entry.Context))
+	if err != nil {
+		return i18n.Errorf("failed to create kubernetes client: %w", err)
+	}
+	if entry.Server != "" && k8sClient.Server() != entry.Server {
+		return i18n.Errorf("%s/%s was created in %s, but the current context points to %s",
+			entry.Resource, entry.Name, entry.Server, k8sClient.Server())
+	}
+
+	target := fmt.Sprintf("%s/%s", formatGVR(entry.GVR()), entry.Name)
+	if entry.Namespace != "" {
+		target += fmt.Sprintf(" in namespace %s", entry.Namespace)
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Last created (history entry %d, %s): %s\n"),
+		entry.ID, entry.Time.Local().Format("2006-01-02 15:04"), target)
+
+	if !undoYes {
+		if !prompt.CanPrompt() {
+			return i18n.Errorf("refusing to delete without confirmation, use --yes")
+		}
+		if !prompt.Confirm("delete", i18n.T("Delete %s", target)) {
+			return i18n.Errorf("aborted")
+		}
+	}
+
+	err = k8sClient.DeleteResource(entry.GVR(), entry.Namespace, entry.Name, types.UID(entry.UID))
+	recordAudit(k8sClient, entry.GVR(), entry.Namespace, entry.Name, audit.OperationDelete, err)
+	switch {
+	case apierrors.IsNotFound(err):
+		fmt.Fprintf(os.Stderr, i18n.T("%s no longer exists\n"), target)
+	case apierrors.IsConflict(err):
+		return i18n.Errorf("%s was replaced by another object with the same name, not deleting it", target)
+	case err != nil:
+		return i18n.Errorf("failed to delete %s: %w", target, err)
+	default:
+		fmt.Printf(i18n.T("%s/%s deleted\n"), entry.Resource, entry.Name)
+	}
+
+	return history.MarkDeleted(path, entry.ID)
+}
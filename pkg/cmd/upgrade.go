@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/update"
+	"github.com/gshaibi/kubectl-create-resource/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradeCheck   bool
+	upgradeInstall bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Check for a newer release and print how to upgrade",
+	Long: `Check GitHub for a newer release and print how to upgrade. This is the only
+command that contacts GitHub, and only when run explicitly.
+
+With --install, the release archive for this platform is downloaded, verified
+against the published checksums and replaces the running binary (not for krew
+installs, use kubectl krew upgrade there).
+
+Examples:
+  # Check for a newer version
+  kubectl create-resource upgrade --check
+
+  # Download and install it
+  kubectl create-resource upgrade --install`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeCheck, "check", false,
+		"only check for a newer release and print upgrade instructions (the default)")
+	upgradeCmd.Flags().BoolVar(&upgradeInstall, "install", false,
+		"download the newer release and replace this binary")
+
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	if upgradeCheck && upgradeInstall {
+		return i18n.Errorf("--check and --install cannot be used together")
+	}
+
+	current := version.Get().Version
+	release, err := update.Latest()
+	if err != nil {
+		return err
+	}
+
+	newer, err := update.IsNewer(current, release.TagName)
+	if err != nil {
+		// Development builds can't be compared, but the latest release is still useful
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: %v\n"), err)
+		fmt.Printf(i18n.T("Latest release: %s (%s)\n"), release.TagName, release.HTMLURL)
+		return nil
+	}
+	if !newer {
+		fmt.Printf(i18n.T("kubectl-create-resource %s is up to date\n"), current)
+		return nil
+	}
+
+	exePath, err := os.Executable()
+	if err == nil {
+		exePath, err = filepath.EvalSymlinks(exePath)
+	}
+	if err != nil {
+		return i18n.Errorf("failed to locate the running binary: %w", err)
+	}
+	krew := update.InstalledWithKrew(exePath)
+
+	fmt.Printf(i18n.T("A newer version is available: %s (installed: %s)\n"), release.TagName, current)
+	fmt.Printf(i18n.T("Release notes: %s\n"), release.HTMLURL)
+
+	if !upgradeInstall {
+		fmt.Println(i18n.T("\nTo upgrade:"))
+		if krew {
+			fmt.Println(i18n.T("  kubectl krew upgrade create-resource"))
+		} else {
+			fmt.Println(i18n.T("  kubectl create-resource upgrade --install"))
+			fmt.Println("  or: go install github.com/gshaibi/kubectl-create-resource/cmd/kubectl-create-resource@" + release.TagName)
+		}
+		return nil
+	}
+
+	if krew {
+		return i18n.Errorf("this binary is managed by krew, upgrade it with: kubectl krew upgrade create-resource")
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Downloading %s...\n"), update.AssetName())
+	if err := update.Install(release, exePath); err != nil {
+		return err
+	}
+	fmt.Printf(i18n.T("Upgraded %s to %s\n"), exePath, release.TagName)
+	return nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+var (
+	valuesFiles []string
+	allowEnv    bool
+)
+
+func init() {
+	rootCmd.Flags().StringArrayVar(&valuesFiles, "values", []string{},
+		"YAML or JSON file of field values, by path or nested (repeatable, later files win; --set takes precedence)")
+	rootCmd.Flags().BoolVar(&allowEnv, "allow-env", false,
+		"replace ${VAR} in --set values and values files with environment variables")
+}
+
+// expandSetValues replaces ${VAR} in the values of --set with --allow-env.
+// Without it, references are kept as text, with a warning since they were
+// likely meant to be expanded.
+func expandSetValues() error {
+	for i, sv := range setValues {
+		key, value, ok := strings.Cut(sv, "=")
+		if !ok || !prompt.HasEnvReference(value) {
+			continue
+		}
+		if !allowEnv {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: --set %s contains ${...}, pass --allow-env to expand environment variables\n"), key)
+			continue
+		}
+		expanded, err := prompt.ExpandEnv(value)
+		if err != nil {
+			return i18n.Errorf("invalid --set %s: %w", key, err)
+		}
+		setValues[i] = key + "=" + expanded
+	}
+	return nil
+}
+
+// loadValuesFiles reads the --values files, later files overriding earlier ones
+func loadValuesFiles() (map[string]interface{}, error) {
+	if len(valuesFiles) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]interface{})
+	for _, path := range valuesFiles {
+		fileValues, err := prompt.ParseValuesFile(path, allowEnv)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range fileValues {
+			values[k] = v
+		}
+	}
+	return values, nil
+}
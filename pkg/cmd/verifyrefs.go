@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// verifyRefs enables verifyReferences
+var verifyRefs bool
+
+func init() {
+	rootCmd.Flags().BoolVar(&verifyRefs, "verify-refs", false,
+		"check that the objects the manifest references (secretName, serviceName, className fields, ...) exist, and warn about those that don't")
+}
+
+// verifyReferences checks every object reference found in manifest against
+// the cluster: namespaced objects in the namespace of the resource, classes
+// and other cluster-scoped objects by name. Missing objects are warnings, as
+// they may be created afterwards.
+func verifyReferences(k8sClient *client.K8sClient, manifest *unstructured.Unstructured) {
+	if !verifyRefs {
+		return
+	}
+	refs := prompt.FindReferences(manifest.Object)
+	missing := 0
+	for _, ref := range refs {
+		ns := ""
+		if k8sClient.IsNamespaced(ref.Type) {
+			ns = namespace
+		}
+		_, err := k8sClient.GetResource(ref.Type, ns, ref.Name)
+		switch {
+		case err == nil:
+			continue
+		case apierrors.IsNotFound(err) && ns != "":
+			missing++
+			warning := fmt.Sprintf("%s: %s/%s doesn't exist in namespace %s", ref.Path, ref.Type.Resource, ref.Name, ns)
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: %s\n"), warning)
+			runArtifacts.Warn(warning)
+		case apierrors.IsNotFound(err):
+			missing++
+			warning := fmt.Sprintf("%s: %s/%s doesn't exist", ref.Path, ref.Type.Resource, ref.Name)
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: %s\n"), warning)
+			runArtifacts.Warn(warning)
+		default:
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: %s: could not check %s/%s: %v\n"), ref.Path, ref.Type.Resource, ref.Name, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("Verified %d references, %d missing\n"), len(refs), missing)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/version"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var versionOutput string
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the plugin version, git commit and supported Kubernetes version",
+	Args:  cobra.NoArgs,
+	RunE:  runVersion,
+}
+
+func init() {
+	versionCmd.Flags().StringVarP(&versionOutput, "output", "o", "",
+		"output format (yaml or json)")
+
+	rootCmd.AddCommand(versionCmd)
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	info := version.Get()
+
+	switch versionOutput {
+	case "json":
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return i18n.Errorf("failed to marshal to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		data, err := yaml.Marshal(info)
+		if err != nil {
+			return i18n.Errorf("failed to marshal to YAML: %w", err)
+		}
+		fmt.Print(string(data))
+	case "":
+		fmt.Printf(i18n.T("kubectl-create-resource %s\n"), info.Version)
+		if info.GitCommit != "" {
+			fmt.Printf(i18n.T("  Git commit:  %s\n"), info.GitCommit)
+		}
+		if info.BuildDate != "" {
+			fmt.Printf(i18n.T("  Build date:  %s\n"), info.BuildDate)
+		}
+		if info.ClientGoVersion != "" {
+			fmt.Printf(i18n.T("  client-go:   %s (Kubernetes %s)\n"), info.ClientGoVersion, info.KubernetesVersion)
+		}
+		fmt.Printf(i18n.T("  Go:          %s %s\n"), info.GoVersion, info.Platform)
+	default:
+		return i18n.Errorf("unsupported output format: %s (use yaml or json)", versionOutput)
+	}
+	return nil
+}
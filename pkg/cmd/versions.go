@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// checkPinnedVersion handles a resource type pinned to a version other than the
+// preferred one (e.g., cronjobs.v1beta1.batch): it lists the served versions
+// and the storage version, and in a terminal offers to create the preferred
+// version instead. It returns the version to create, and the pinned version
+// when it was replaced.
+func checkPinnedVersion(k8sClient *client.K8sClient, resourceType string, gvr schema.GroupVersionResource) (schema.GroupVersionResource, string) {
+	if requested, _ := client.RequestedVersion(resourceType); requested == "" {
+		return gvr, ""
+	}
+	versions, err := k8sClient.ResourceVersions(gvr)
+	if err != nil || versions.Preferred == gvr.Version {
+		return gvr, ""
+	}
+
+	gr := gvr.GroupResource()
+	fmt.Fprintf(os.Stderr, i18n.T("Note: %s is served in versions %s, the preferred one is %s\n"),
+		gr, strings.Join(versions.Served, ", "), versions.Preferred)
+	if versions.Storage != "" {
+		fmt.Fprintf(os.Stderr, i18n.T("Note: %s objects are stored as %s, other versions are converted by the API server\n"),
+			gr, versions.Storage)
+	}
+	if !prompt.CanPrompt() {
+		return gvr, ""
+	}
+	if !prompt.Confirm("preferred-version", i18n.T("Create %s instead of %s", versions.Preferred, gvr.Version)) {
+		return gvr, ""
+	}
+
+	pinned := gvr.Version
+	gvr.Version = versions.Preferred
+	return gvr, pinned
+}
+
+// warnUnmatchedValues lists the --set fields that aren't in the schema of the
+// version used instead of the pinned one, since fields are carried over by path
+// and ones renamed or moved between versions need a new path
+func warnUnmatchedValues(resourceSchema *client.ResourceSchema, pinned string) {
+	for _, sv := range setValues {
+		path, _, _ := strings.Cut(sv, "=")
+		if strings.HasPrefix(path, "metadata.") {
+			continue
+		}
+		if _, ok := resourceSchema.FindField(path); !ok {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: --set %s isn't a field of %s, check where it moved from %s\n"),
+				path, resourceSchema.GVK.Version, pinned)
+		}
+	}
+}
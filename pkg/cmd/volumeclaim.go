@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Fields of PersistentVolumeClaims set by the persistentvolumeclaim preset
+const (
+	storageClassPath = "spec.storageClassName"
+	claimSizePath    = "spec.resources.requests.storage"
+	accessModesPath  = "spec.accessModes"
+)
+
+// defaultClaimSize is the size of claims when it isn't given or asked
+const defaultClaimSize = "1Gi"
+
+// accessModes are the access modes of PersistentVolumeClaims, which
+// --access-mode also takes by the short names kubectl get prints
+var accessModes = []string{"ReadWriteOnce", "ReadOnlyMany", "ReadWriteMany", "ReadWriteOncePod"}
+var accessModeShortNames = map[string]string{
+	"RWO":  "ReadWriteOnce",
+	"ROX":  "ReadOnlyMany",
+	"RWX":  "ReadWriteMany",
+	"RWOP": "ReadWriteOncePod",
+}
+
+// storageClasses are the StorageClasses of claims, marked as the default with
+// either annotation
+var storageClasses = classType{
+	gvr:             schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"},
+	controllerField: "provisioner",
+	defaultAnnotations: []string{
+		"storageclass.kubernetes.io/is-default-class",
+		"storageclass.beta.kubernetes.io/is-default-class",
+	},
+}
+
+var (
+	storageClass     string
+	claimSize        string
+	claimAccessModes []string
+)
+
+func init() {
+	typePresets[schema.GroupResource{Resource: "persistentvolumeclaims"}] = preset{
+		flags:   []string{"storage-class", "size", "access-mode"},
+		fields:  []string{storageClassPath, claimSizePath, accessModesPath},
+		prepare: prepareVolumeClaim,
+	}
+
+	rootCmd.Flags().StringVar(&storageClass, "storage-class", "",
+		"with persistentvolumeclaim, the StorageClass of the claim (picked from a list in a terminal)")
+	rootCmd.Flags().StringVar(&claimSize, "size", "",
+		"with persistentvolumeclaim, the storage requested (e.g., --size=10Gi; default 1Gi)")
+	rootCmd.Flags().StringArrayVar(&claimAccessModes, "access-mode", []string{},
+		"with persistentvolumeclaim, an access mode of the claim: ReadWriteOnce (RWO), ReadOnlyMany (ROX), ReadWriteMany (RWX) or ReadWriteOncePod (RWOP)")
+}
+
+// prepareVolumeClaim sets the storage class, size and access modes of the
+// claim from their flags, or asks for those not set yet. A claim needs a
+// size and access modes, which default to 1Gi and ReadWriteOnce otherwise.
+func prepareVolumeClaim(k8sClient *client.K8sClient, values *prompt.CollectedValues) error {
+	if values == nil {
+		return rejectPresetFlagsWithFrom(schema.GroupResource{Resource: "persistentvolumeclaims"})
+	}
+	ask := !example && prompt.CanPrompt()
+
+	switch {
+	case storageClass != "":
+		values.Set(storageClassPath, storageClass, prompt.SourceFlag)
+	case !values.Has(storageClassPath) && ask:
+		classes, err := listClasses(k8sClient, storageClasses)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: failed to list storage classes: %v\n"), err)
+		}
+		if len(classes) > 0 {
+			name, err := prompt.PickClass(storageClassPath, i18n.T("Storage class"), classes)
+			if err != nil {
+				return err
+			}
+			values.Set(storageClassPath, name, prompt.SourcePrompt)
+		}
+	}
+
+	switch {
+	case claimSize != "":
+		if err := prompt.ValidateQuantity(claimSize); err != nil {
+			return i18n.Errorf("invalid --size: %w", err)
+		}
+		values.Set(claimSizePath, claimSize, prompt.SourceFlag)
+	case values.Has(claimSizePath):
+	case ask:
+		size, err := prompt.AskQuantity(claimSizePath, i18n.T("Size of the claim (e.g., 10Gi)"), defaultClaimSize, true)
+		if err != nil {
+			return err
+		}
+		values.Set(claimSizePath, size, prompt.SourcePrompt)
+	default:
+		values.Set(claimSizePath, defaultClaimSize, prompt.SourceDefault)
+	}
+
+	switch {
+	case len(claimAccessModes) > 0:
+		modes, err := parseAccessModes(claimAccessModes)
+		if err != nil {
+			return err
+		}
+		values.Set(accessModesPath, modes, prompt.SourceFlag)
+	case values.Has(accessModesPath):
+	case ask:
+		picked, err := prompt.ChooseMany(accessModesPath, i18n.T("Access modes"), accessModes, []bool{true})
+		if err != nil {
+			return err
+		}
+		if len(picked) == 0 {
+			return i18n.Errorf("a claim needs at least one access mode")
+		}
+		modes := make([]interface{}, len(picked))
+		for i, index := range picked {
+			modes[i] = accessModes[index]
+		}
+		values.Set(accessModesPath, modes, prompt.SourcePrompt)
+	default:
+		values.Set(accessModesPath, []interface{}{accessModes[0]}, prompt.SourceDefault)
+	}
+	return nil
+}
+
+// parseAccessModes converts the values of --access-mode, by name or short
+// name, to the access modes of a claim
+func parseAccessModes(names []string) ([]interface{}, error) {
+	var modes []interface{}
+	for _, name := range names {
+		mode, ok := accessModeShortNames[strings.ToUpper(name)]
+		if !ok {
+			i := slices.IndexFunc(accessModes, func(m string) bool { return strings.EqualFold(m, name) })
+			if i < 0 {
+				return nil, i18n.Errorf("invalid --access-mode %q, must be one of %s (or RWO, ROX, RWX, RWOP)", name, strings.Join(accessModes, ", "))
+			}
+			mode = accessModes[i]
+		}
+		if !slices.Contains(modes, interface{}(mode)) {
+			modes = append(modes, mode)
+		}
+	}
+	return modes, nil
+}
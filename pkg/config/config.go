@@ -0,0 +1,275 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// Config holds user and platform settings loaded from the config file
+type Config struct {
+	// ValueSources maps field paths to external value providers
+	ValueSources []ValueSource `json:"valueSources,omitempty"`
+
+	// RequiredLabels and RequiredAnnotations must be set on every created object;
+	// missing values are prompted for
+	RequiredLabels      []RequiredMetadata `json:"requiredLabels,omitempty"`
+	RequiredAnnotations []RequiredMetadata `json:"requiredAnnotations,omitempty"`
+
+	// Protected contexts and namespaces require a typed confirmation before creating
+	Protected Protected `json:"protected,omitempty"`
+
+	// Conditions make prompting for fields depend on the values collected before
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// Computed fields are set from other fields once the values are collected
+	Computed []ComputedField `json:"computed,omitempty"`
+}
+
+// ComputedField sets a field from the values of others after they are
+// collected and before the manifest is generated, e.g. a label from the name
+type ComputedField struct {
+	Resource string `json:"resource,omitempty"` // Resource the field applies to; empty matches all
+	Path     string `json:"path"`               // Field path to set (e.g., "metadata.labels.app")
+	From     string `json:"from,omitempty"`     // Field path to copy, with the fields within it (e.g., "spec.selector.matchLabels")
+	Value    string `json:"value,omitempty"`    // Text with {{path}} placeholders for field values (e.g., "{{metadata.name}}-data")
+	Override bool   `json:"override,omitempty"` // Replace a value that is already set
+}
+
+// Condition makes prompting for a field, and the fields within it, depend on
+// an expression over the values collected before it
+type Condition struct {
+	Resource string `json:"resource,omitempty"` // Resource the condition applies to; empty matches all
+	Path     string `json:"path"`               // Field path to prompt for conditionally (e.g., "spec.tls" or "spec.tls.*")
+	When     string `json:"when"`               // Expression that must hold (e.g., "spec.tlsEnabled == true")
+}
+
+// Protected lists the contexts and namespaces guarded against accidental creates.
+// Entries are globs (e.g., "prod-*").
+type Protected struct {
+	Contexts   []string `json:"contexts,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// RequiredMetadata is a label or annotation that objects must carry
+type RequiredMetadata struct {
+	Key         string   `json:"key"`                   // Label or annotation key (e.g., "example.com/team")
+	Resource    string   `json:"resource,omitempty"`    // Resource the requirement applies to; empty matches all
+	Description string   `json:"description,omitempty"` // Shown when prompting
+	Default     string   `json:"default,omitempty"`     // Pre-filled when prompting, and used when not interactive
+	Allowed     []string `json:"allowed,omitempty"`     // Allowed values, if restricted
+}
+
+// ValueSource resolves a single field path from an external provider
+type ValueSource struct {
+	Resource string      `json:"resource,omitempty"` // Resource the source applies to (e.g., "deployments" or "queues.example.com"); empty matches all
+	Path     string      `json:"path"`               // Field path to fill (e.g., "spec.version")
+	HTTP     *HTTPSource `json:"http,omitempty"`     // Fetch the value from an HTTP endpoint returning JSON
+}
+
+// HTTPSource describes an HTTP endpoint returning JSON
+type HTTPSource struct {
+	URL      string            `json:"url"`
+	JSONPath string            `json:"jsonPath,omitempty"` // JSONPath expression (e.g., "{.release.version}"); empty uses the whole body
+	Headers  map[string]string `json:"headers,omitempty"`  // Request headers; values may reference environment variables (${TOKEN})
+	Timeout  string            `json:"timeout,omitempty"`  // Request timeout (e.g., "5s")
+}
+
+// DefaultPath returns the default config file location
+func DefaultPath() string {
+	return filepath.Join(Dir(), "config.yaml")
+}
+
+// Dir returns the directory holding the config file and local state
+func Dir() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "kubectl-create-resource")
+	}
+	return filepath.Join(os.TempDir(), "kubectl-create-resource")
+}
+
+// Load reads the config file at path. If path is empty, the default location is
+// used and a missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	for i, vs := range cfg.ValueSources {
+		if vs.Path == "" {
+			return nil, fmt.Errorf("valueSources[%d]: path is required", i)
+		}
+		if vs.HTTP == nil || vs.HTTP.URL == "" {
+			return nil, fmt.Errorf("valueSources[%d]: http.url is required", i)
+		}
+	}
+
+	for i, r := range cfg.RequiredLabels {
+		if err := validateRequiredMetadata(r, true); err != nil {
+			return nil, fmt.Errorf("requiredLabels[%d]: %w", i, err)
+		}
+	}
+	for i, r := range cfg.RequiredAnnotations {
+		if err := validateRequiredMetadata(r, false); err != nil {
+			return nil, fmt.Errorf("requiredAnnotations[%d]: %w", i, err)
+		}
+	}
+
+	for i, c := range cfg.Conditions {
+		if c.Path == "" {
+			return nil, fmt.Errorf("conditions[%d]: path is required", i)
+		}
+		if c.When == "" {
+			return nil, fmt.Errorf("conditions[%d]: when is required", i)
+		}
+	}
+
+	for i, c := range cfg.Computed {
+		if c.Path == "" {
+			return nil, fmt.Errorf("computed[%d]: path is required", i)
+		}
+		if (c.From == "") == (c.Value == "") {
+			return nil, fmt.Errorf("computed[%d]: set one of from and value", i)
+		}
+	}
+
+	if err := validatePatterns("protected.contexts", cfg.Protected.Contexts); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns("protected.namespaces", cfg.Protected.Namespaces); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// validatePatterns checks the glob patterns of a config list
+func validatePatterns(field string, patterns []string) error {
+	for i, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s[%d]: invalid pattern %q", field, i, pattern)
+		}
+	}
+	return nil
+}
+
+// IsProtected reports whether creating in contextName and namespace ("" for
+// cluster-scoped resources) needs confirmation
+func (c *Config) IsProtected(contextName, namespace string) bool {
+	return matchesAny(c.Protected.Contexts, contextName) ||
+		(namespace != "" && matchesAny(c.Protected.Namespaces, namespace))
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRequiredMetadata checks the key, and for labels the default and
+// allowed values, of a requirement
+func validateRequiredMetadata(r RequiredMetadata, label bool) error {
+	if r.Key == "" {
+		return fmt.Errorf("key is required")
+	}
+	if errs := validation.IsQualifiedName(r.Key); len(errs) > 0 {
+		return fmt.Errorf("invalid key %q: %s", r.Key, strings.Join(errs, "; "))
+	}
+	if r.Default != "" && len(r.Allowed) > 0 && !slices.Contains(r.Allowed, r.Default) {
+		return fmt.Errorf("default %q is not one of the allowed values", r.Default)
+	}
+	if label {
+		for _, v := range append([]string{r.Default}, r.Allowed...) {
+			if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+				return fmt.Errorf("invalid label value %q: %s", v, strings.Join(errs, "; "))
+			}
+		}
+	}
+	return nil
+}
+
+// RequiredMetadataFor returns the required labels and annotations that apply to gvr
+func (c *Config) RequiredMetadataFor(gvr schema.GroupVersionResource) (labels, annotations []RequiredMetadata) {
+	for _, r := range c.RequiredLabels {
+		if MatchesResource(r.Resource, gvr) {
+			labels = append(labels, r)
+		}
+	}
+	for _, r := range c.RequiredAnnotations {
+		if MatchesResource(r.Resource, gvr) {
+			annotations = append(annotations, r)
+		}
+	}
+	return labels, annotations
+}
+
+// ConditionsFor returns the conditions that apply to gvr
+func (c *Config) ConditionsFor(gvr schema.GroupVersionResource) []Condition {
+	var result []Condition
+	for _, cond := range c.Conditions {
+		if MatchesResource(cond.Resource, gvr) {
+			result = append(result, cond)
+		}
+	}
+	return result
+}
+
+// ComputedFor returns the computed fields that apply to gvr, in order
+func (c *Config) ComputedFor(gvr schema.GroupVersionResource) []ComputedField {
+	var result []ComputedField
+	for _, f := range c.Computed {
+		if MatchesResource(f.Resource, gvr) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// ValueSourcesFor returns the value sources that apply to gvr
+func (c *Config) ValueSourcesFor(gvr schema.GroupVersionResource) []ValueSource {
+	var result []ValueSource
+	for _, vs := range c.ValueSources {
+		if MatchesResource(vs.Resource, gvr) {
+			result = append(result, vs)
+		}
+	}
+	return result
+}
+
+// MatchesResource checks whether a resource selector from the config matches gvr.
+// The selector may be empty or "*" (all), a plural resource name, or resource.group.
+func MatchesResource(selector string, gvr schema.GroupVersionResource) bool {
+	if selector == "" || selector == "*" {
+		return true
+	}
+	selector = strings.ToLower(selector)
+	if selector == gvr.Resource {
+		return true
+	}
+	return gvr.Group != "" && selector == gvr.Resource+"."+gvr.Group
+}
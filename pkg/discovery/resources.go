@@ -40,6 +40,21 @@ func DiscoverResourceTypes(kubeconfigPath string) ([]ResourceType, error) {
 		})
 	}
 
+	// Include create-capable subresources (e.g., serviceaccounts/token)
+	subresources, err := k8sClient.DiscoverSubresources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover subresources: %w", err)
+	}
+	for _, s := range subresources {
+		types = append(types, ResourceType{
+			Name:       s.Name(),
+			Group:      s.Parent.Group,
+			Version:    s.Parent.Version,
+			Kind:       s.Kind,
+			Namespaced: s.Namespaced,
+		})
+	}
+
 	// Sort by group then name
 	sort.Slice(types, func(i, j int) bool {
 		if types[i].Group != types[j].Group {
@@ -63,6 +78,10 @@ func FormatResourceType(rt ResourceType) string {
 	if rt.Group == "" {
 		return rt.Name
 	}
+	// Subresources keep the group on the parent (e.g., "deployments.apps/scale")
+	if parent, sub, ok := strings.Cut(rt.Name, "/"); ok {
+		return fmt.Sprintf("%s.%s/%s", parent, rt.Group, sub)
+	}
 	return fmt.Sprintf("%s.%s", rt.Name, rt.Group)
 }
 
@@ -111,3 +130,64 @@ func FindResourceType(types []ResourceType, name string) (*ResourceType, error)
 
 	return &matches[0], nil
 }
+
+// ListGroups returns the cluster's API groups, core first then alphabetical,
+// without fetching their resources
+func ListGroups(k8sClient *client.K8sClient) ([]client.GroupInfo, error) {
+	groups, err := k8sClient.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Name == "" {
+			return groups[j].Name != ""
+		}
+		if groups[j].Name == "" {
+			return false
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups, nil
+}
+
+// GroupResourceTypes fetches the resource types of a single group, sorted by name
+func GroupResourceTypes(k8sClient *client.K8sClient, group client.GroupInfo) ([]ResourceType, error) {
+	resources, subresources, err := k8sClient.DiscoverGroupResources(group)
+	if err != nil {
+		return nil, err
+	}
+
+	var types []ResourceType
+	for _, r := range resources {
+		types = append(types, ResourceType{
+			Name:       r.Name,
+			Group:      r.Group,
+			Version:    r.Version,
+			Kind:       r.Kind,
+			Namespaced: r.Namespaced,
+		})
+	}
+	for _, s := range subresources {
+		types = append(types, ResourceType{
+			Name:       s.Name(),
+			Group:      s.Parent.Group,
+			Version:    s.Parent.Version,
+			Kind:       s.Kind,
+			Namespaced: s.Namespaced,
+		})
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	return types, nil
+}
+
+// GroupDisplayName returns a display name for an API group
+func GroupDisplayName(group client.GroupInfo) string {
+	if group.Name == "" {
+		return fmt.Sprintf("Core API (%s)", group.Version)
+	}
+	return group.Name
+}
@@ -0,0 +1,317 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const defaultDockerServer = "https://index.docker.io/v1/"
+
+// DataSources holds kubectl create-style inputs for ConfigMap and Secret data
+// (kubectl create configmap, secret generic, secret docker-registry, secret tls)
+type DataSources struct {
+	Literals []string // --from-literal=key=value
+	Files    []string // --from-file=[key=]path
+	EnvFiles []string // --from-env-file=path
+	Type     string   // --type (secret type)
+
+	DockerServer   string
+	DockerUsername string
+	DockerPassword string
+	DockerEmail    string
+
+	Cert string // --cert (TLS certificate file)
+	Key  string // --key (TLS key file)
+}
+
+// IsEmpty reports whether no data source flags were given
+func (d DataSources) IsEmpty() bool {
+	return len(d.Literals) == 0 && len(d.Files) == 0 && len(d.EnvFiles) == 0 && d.Type == "" &&
+		!d.isDockerRegistry() && !d.isTLS()
+}
+
+// isDockerRegistry reports whether docker-registry flags were given
+func (d DataSources) isDockerRegistry() bool {
+	return d.DockerServer != "" || d.DockerUsername != "" || d.DockerPassword != "" || d.DockerEmail != ""
+}
+
+// isTLS reports whether TLS flags were given
+func (d DataSources) isTLS() bool {
+	return d.Cert != "" || d.Key != ""
+}
+
+// ApplyDataSources fills data (and type for Secrets) on a ConfigMap or Secret manifest
+func ApplyDataSources(obj *unstructured.Unstructured, gvr schema.GroupVersionResource, ds DataSources) error {
+	if ds.IsEmpty() {
+		return nil
+	}
+	if gvr.Group != "" || (gvr.Resource != "secrets" && gvr.Resource != "configmaps") {
+		return fmt.Errorf("--from-literal, --from-file, --from-env-file, --type, --docker-* and --cert/--key only apply to secrets and configmaps")
+	}
+
+	if gvr.Resource == "configmaps" {
+		return applyConfigMapData(obj, ds)
+	}
+	return applySecretData(obj, ds)
+}
+
+// applyConfigMapData sets data and binaryData on a ConfigMap
+func applyConfigMapData(obj *unstructured.Unstructured, ds DataSources) error {
+	if ds.isDockerRegistry() || ds.isTLS() || ds.Type != "" {
+		return fmt.Errorf("--type, --docker-* and --cert/--key only apply to secrets")
+	}
+
+	entries, err := collectEntries(ds)
+	if err != nil {
+		return err
+	}
+
+	data := nestedStringMap(obj, "data")
+	binaryData := nestedStringMap(obj, "binaryData")
+	for _, e := range entries {
+		if utf8.Valid(e.value) {
+			data[e.key] = string(e.value)
+		} else {
+			binaryData[e.key] = base64.StdEncoding.EncodeToString(e.value)
+		}
+	}
+
+	setIfNotEmpty(obj, "data", data)
+	setIfNotEmpty(obj, "binaryData", binaryData)
+	return nil
+}
+
+// applySecretData sets base64-encoded data and the type on a Secret
+func applySecretData(obj *unstructured.Unstructured, ds DataSources) error {
+	if ds.isDockerRegistry() && ds.isTLS() {
+		return fmt.Errorf("--docker-* and --cert/--key cannot be combined")
+	}
+
+	data := nestedStringMap(obj, "data")
+	secretType := ds.Type
+
+	switch {
+	case ds.isDockerRegistry():
+		config, err := dockerConfigJSON(ds)
+		if err != nil {
+			return err
+		}
+		data[".dockerconfigjson"] = base64.StdEncoding.EncodeToString(config)
+		if secretType == "" {
+			secretType = "kubernetes.io/dockerconfigjson"
+		}
+	case ds.isTLS():
+		if ds.Cert == "" || ds.Key == "" {
+			return fmt.Errorf("both --cert and --key are required for TLS secrets")
+		}
+		certPEM, err := os.ReadFile(ds.Cert)
+		if err != nil {
+			return fmt.Errorf("failed to read certificate: %w", err)
+		}
+		keyPEM, err := os.ReadFile(ds.Key)
+		if err != nil {
+			return fmt.Errorf("failed to read key: %w", err)
+		}
+		if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+			return fmt.Errorf("invalid certificate/key pair: %w", err)
+		}
+		data["tls.crt"] = base64.StdEncoding.EncodeToString(certPEM)
+		data["tls.key"] = base64.StdEncoding.EncodeToString(keyPEM)
+		if secretType == "" {
+			secretType = "kubernetes.io/tls"
+		}
+	}
+
+	entries, err := collectEntries(ds)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if _, exists := data[e.key]; exists {
+			return fmt.Errorf("duplicate key %q", e.key)
+		}
+		data[e.key] = base64.StdEncoding.EncodeToString(e.value)
+	}
+
+	setIfNotEmpty(obj, "data", data)
+	if secretType != "" {
+		obj.Object["type"] = secretType
+	} else if _, ok := obj.Object["type"]; !ok {
+		obj.Object["type"] = "Opaque"
+	}
+	return nil
+}
+
+// dataEntry is a single key/value pair read from a data source flag
+type dataEntry struct {
+	key   string
+	value []byte
+}
+
+// collectEntries reads literals, files and env files in kubectl's order
+func collectEntries(ds DataSources) ([]dataEntry, error) {
+	var entries []dataEntry
+	seen := make(map[string]bool)
+	add := func(key string, value []byte) error {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate key %q", key)
+		}
+		seen[key] = true
+		entries = append(entries, dataEntry{key: key, value: value})
+		return nil
+	}
+
+	for _, lit := range ds.Literals {
+		parts := strings.SplitN(lit, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid --from-literal %q (expected key=value)", lit)
+		}
+		if err := add(parts[0], []byte(parts[1])); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range ds.Files {
+		key, path := "", f
+		if parts := strings.SplitN(f, "=", 2); len(parts) == 2 {
+			key, path = parts[0], parts[1]
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --from-file %q: %w", path, err)
+		}
+
+		if info.IsDir() {
+			if key != "" {
+				return nil, fmt.Errorf("cannot give a key name for a directory path in --from-file %q", f)
+			}
+			dirEntries, err := os.ReadDir(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read directory %q: %w", path, err)
+			}
+			for _, de := range dirEntries {
+				if !de.Type().IsRegular() {
+					continue
+				}
+				content, err := os.ReadFile(filepath.Join(path, de.Name()))
+				if err != nil {
+					return nil, err
+				}
+				if err := add(de.Name(), content); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if key == "" {
+			key = filepath.Base(path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --from-file %q: %w", path, err)
+		}
+		if err := add(key, content); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, envFile := range ds.EnvFiles {
+		pairs, err := readEnvFile(envFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pairs {
+			if err := add(p[0], []byte(p[1])); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// readEnvFile parses KEY=VALUE lines; a bare KEY takes its value from the environment
+func readEnvFile(path string) ([][2]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --from-env-file %q: %w", path, err)
+	}
+
+	var pairs [][2]string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if key == "" {
+			return nil, fmt.Errorf("%s:%d: missing key", path, lineNum)
+		}
+		if len(parts) == 2 {
+			pairs = append(pairs, [2]string{key, parts[1]})
+		} else {
+			pairs = append(pairs, [2]string{key, os.Getenv(key)})
+		}
+	}
+	return pairs, scanner.Err()
+}
+
+// dockerConfigJSON builds a .dockerconfigjson payload like kubectl create secret docker-registry
+func dockerConfigJSON(ds DataSources) ([]byte, error) {
+	if ds.DockerUsername == "" || ds.DockerPassword == "" {
+		return nil, fmt.Errorf("--docker-username and --docker-password are required for docker-registry secrets")
+	}
+	server := ds.DockerServer
+	if server == "" {
+		server = defaultDockerServer
+	}
+
+	entry := map[string]string{
+		"username": ds.DockerUsername,
+		"password": ds.DockerPassword,
+		"auth":     base64.StdEncoding.EncodeToString([]byte(ds.DockerUsername + ":" + ds.DockerPassword)),
+	}
+	if ds.DockerEmail != "" {
+		entry["email"] = ds.DockerEmail
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{server: entry},
+	})
+}
+
+// nestedStringMap returns the map at key, creating it if missing
+func nestedStringMap(obj *unstructured.Unstructured, key string) map[string]interface{} {
+	if m, ok := obj.Object[key].(map[string]interface{}); ok {
+		return m
+	}
+	return make(map[string]interface{})
+}
+
+// setIfNotEmpty sets obj[key] only if m has entries
+func setIfNotEmpty(obj *unstructured.Unstructured, key string, m map[string]interface{}) {
+	if len(m) > 0 {
+		obj.Object[key] = m
+	}
+}
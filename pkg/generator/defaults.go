@@ -0,0 +1,331 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ManagedOnly returns a copy of obj with only the fields owned by a field
+// manager in managed (excluding subresources such as status). Fields the
+// server defaulted are not owned by anyone, so they are left out. apiVersion,
+// kind and metadata are always kept.
+func ManagedOnly(obj *unstructured.Unstructured, managed []metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+	owned := map[string]interface{}{}
+	for _, entry := range managed {
+		if entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		mergeFieldSets(owned, fields)
+	}
+
+	result := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range obj.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata":
+			result.Object[k] = runtime.DeepCopyJSONValue(v)
+			continue
+		}
+		if set, ok := owned["f:"+k].(map[string]interface{}); ok {
+			if filtered, keep := filterOwned(v, set); keep {
+				result.Object[k] = filtered
+			}
+		}
+	}
+	return result
+}
+
+// WithoutSchemaDefaults returns a copy of obj without the fields whose value is
+// the default declared in the resource schema. Objects left empty are removed too.
+func WithoutSchemaDefaults(obj *unstructured.Unstructured, fields []client.FieldSchema) *unstructured.Unstructured {
+	result := obj.DeepCopy()
+	for k, v := range result.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata":
+			continue
+		}
+		field := findField(fields, k)
+		if field == nil {
+			continue
+		}
+		if stripped, keep := withoutSchemaDefaults(v, *field); keep {
+			result.Object[k] = stripped
+		} else {
+			delete(result.Object, k)
+		}
+	}
+	return result
+}
+
+// StripDefaults returns a copy of full without the fields the server fills in
+// by itself. minimal is full without the fields suspected to be defaults, and
+// defaulted is the server's dry-run result for minimal: a field missing from
+// minimal is only dropped when the server put back the same value, so creating
+// the stripped object yields the same resource. metadata is kept as is.
+func StripDefaults(full, minimal, defaulted *unstructured.Unstructured) *unstructured.Unstructured {
+	result := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range full.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata":
+			result.Object[k] = runtime.DeepCopyJSONValue(v)
+			continue
+		}
+		mv, inMinimal := minimal.Object[k]
+		dv, inDefaulted := defaulted.Object[k]
+		if stripped, keep := stripDefaults(v, mv, dv, inMinimal, inDefaulted); keep {
+			result.Object[k] = stripped
+		}
+	}
+	return result
+}
+
+// CountFields returns the number of leaf fields in obj, for reporting how much
+// stripping removed
+func CountFields(obj *unstructured.Unstructured) int {
+	return countLeaves(obj.Object)
+}
+
+// stripDefaults implements StripDefaults for one value. keep is false when the
+// value was defaulted by the server.
+func stripDefaults(full, minimal, defaulted interface{}, inMinimal, inDefaulted bool) (interface{}, bool) {
+	switch f := full.(type) {
+	case map[string]interface{}:
+		m, _ := minimal.(map[string]interface{})
+		d, _ := defaulted.(map[string]interface{})
+		out := make(map[string]interface{})
+		for k, v := range f {
+			mv, mok := m[k]
+			dv, dok := d[k]
+			if stripped, keep := stripDefaults(v, mv, dv, inMinimal && mok, inDefaulted && dok); keep {
+				out[k] = stripped
+			}
+		}
+		if len(out) == 0 && !inMinimal {
+			// Everything inside was defaulted, or the map itself was
+			if len(f) > 0 || (inDefaulted && reflect.DeepEqual(f, defaulted)) {
+				return nil, false
+			}
+		}
+		return out, true
+
+	case []interface{}:
+		if !inMinimal {
+			if inDefaulted && reflect.DeepEqual(f, defaulted) {
+				return nil, false
+			}
+			return runtime.DeepCopyJSONValue(f), true
+		}
+		// Items are compared by position, which only holds when nothing was dropped
+		m, _ := minimal.([]interface{})
+		d, _ := defaulted.([]interface{})
+		if len(m) != len(f) || len(d) != len(f) {
+			return runtime.DeepCopyJSONValue(f), true
+		}
+		out := make([]interface{}, len(f))
+		for i := range f {
+			out[i], _ = stripDefaults(f[i], m[i], d[i], true, true)
+		}
+		return out, true
+
+	default:
+		if !inMinimal && inDefaulted && reflect.DeepEqual(f, defaulted) {
+			return nil, false
+		}
+		return f, true
+	}
+}
+
+// withoutSchemaDefaults implements WithoutSchemaDefaults for one value, modifying
+// it in place. keep is false when the value is the default.
+func withoutSchemaDefaults(v interface{}, field client.FieldSchema) (interface{}, bool) {
+	if field.Default != nil && sameJSON(v, field.Default) {
+		return nil, false
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			return val, true
+		}
+		for k, child := range val {
+			childField := findField(field.Properties, k)
+			if childField == nil {
+				continue
+			}
+			if stripped, keep := withoutSchemaDefaults(child, *childField); keep {
+				val[k] = stripped
+			} else {
+				delete(val, k)
+			}
+		}
+		return val, len(val) > 0
+
+	case []interface{}:
+		// Items are never removed, only the defaulted fields inside them
+		if field.Items != nil {
+			for i, item := range val {
+				if stripped, keep := withoutSchemaDefaults(item, *field.Items); keep {
+					val[i] = stripped
+				}
+			}
+		}
+		return val, true
+
+	default:
+		return v, true
+	}
+}
+
+// findField returns the field called name
+func findField(fields []client.FieldSchema, name string) *client.FieldSchema {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// sameJSON reports whether two values encode to the same JSON, so that schema
+// defaults (float64 numbers) compare equal to object values (int64 numbers)
+func sameJSON(a, b interface{}) bool {
+	aData, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bData, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aData) == string(bData)
+}
+
+// filterOwned keeps the parts of v listed in a managedFields field set
+func filterOwned(v interface{}, set map[string]interface{}) (interface{}, bool) {
+	if len(set) == 0 {
+		// A leaf in the field set owns the whole value
+		return runtime.DeepCopyJSONValue(v), true
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{})
+		for k, child := range val {
+			if childSet, ok := set["f:"+k].(map[string]interface{}); ok {
+				if filtered, keep := filterOwned(child, childSet); keep {
+					out[k] = filtered
+				}
+			}
+		}
+		return out, true
+
+	case []interface{}:
+		var out []interface{}
+		for i, item := range val {
+			itemSet, ok := listItemSet(set, i, item)
+			if !ok {
+				continue
+			}
+			if filtered, keep := filterOwned(item, itemSet); keep {
+				out = append(out, filtered)
+			}
+		}
+		return out, len(out) > 0
+
+	default:
+		return v, true
+	}
+}
+
+// listItemSet finds the field set of a list item, addressed by key fields
+// (k:{"name":"app"}), by value (v:"x") or by index (i:0)
+func listItemSet(set map[string]interface{}, index int, item interface{}) (map[string]interface{}, bool) {
+	for key, s := range set {
+		itemSet, _ := s.(map[string]interface{})
+		switch {
+		case strings.HasPrefix(key, "k:"):
+			var keys map[string]interface{}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(key, "k:")), &keys); err != nil {
+				continue
+			}
+			if fields, ok := item.(map[string]interface{}); ok && matchesKeys(fields, keys) {
+				return itemSet, true
+			}
+		case strings.HasPrefix(key, "v:"):
+			var value interface{}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(key, "v:")), &value); err != nil {
+				continue
+			}
+			if reflect.DeepEqual(normalizeNumber(value), normalizeNumber(item)) {
+				return itemSet, true
+			}
+		case key == "i:"+strconv.Itoa(index):
+			return itemSet, true
+		}
+	}
+	return nil, false
+}
+
+// matchesKeys reports whether fields has the values of a list item key
+func matchesKeys(fields, keys map[string]interface{}) bool {
+	for k, v := range keys {
+		if !reflect.DeepEqual(normalizeNumber(fields[k]), normalizeNumber(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeNumber makes JSON numbers (float64) comparable with unstructured integers (int64)
+func normalizeNumber(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	}
+	return v
+}
+
+// mergeFieldSets adds the fields of src to dst
+func mergeFieldSets(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcSet, _ := v.(map[string]interface{})
+		dstSet, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dstSet = make(map[string]interface{})
+			dst[k] = dstSet
+		}
+		mergeFieldSets(dstSet, srcSet)
+	}
+}
+
+// countLeaves counts the scalar values under v
+func countLeaves(v interface{}) int {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		n := 0
+		for _, child := range val {
+			n += countLeaves(child)
+		}
+		return n
+	case []interface{}:
+		n := 0
+		for _, child := range val {
+			n += countLeaves(child)
+		}
+		return n
+	default:
+		return 1
+	}
+}
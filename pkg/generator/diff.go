@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeType is the kind of difference found for a field
+type ChangeType string
+
+const (
+	FieldAdded   ChangeType = "added"
+	FieldChanged ChangeType = "changed"
+	FieldRemoved ChangeType = "removed"
+)
+
+// FieldChange is a difference between two objects at one field path
+type FieldChange struct {
+	Path string
+	Type ChangeType
+	Old  interface{} // Value in the first object (nil when added)
+	New  interface{} // Value in the second object (nil when removed)
+}
+
+// DiffObjects returns the leaf fields that differ between two unstructured
+// objects, sorted by path. List items are compared by index (e.g.,
+// "spec.containers[0].image"); paths under skip are ignored.
+func DiffObjects(before, after map[string]interface{}, skip ...string) []FieldChange {
+	oldFields := make(map[string]interface{})
+	newFields := make(map[string]interface{})
+	flattenLeaves(before, "", oldFields)
+	flattenLeaves(after, "", newFields)
+
+	var changes []FieldChange
+	for path, newVal := range newFields {
+		if skipped(path, skip) {
+			continue
+		}
+		oldVal, ok := oldFields[path]
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Path: path, Type: FieldAdded, New: newVal})
+		case !reflect.DeepEqual(normalizeNumber(oldVal), normalizeNumber(newVal)):
+			changes = append(changes, FieldChange{Path: path, Type: FieldChanged, Old: oldVal, New: newVal})
+		}
+	}
+	for path, oldVal := range oldFields {
+		if _, ok := newFields[path]; !ok && !skipped(path, skip) {
+			changes = append(changes, FieldChange{Path: path, Type: FieldRemoved, Old: oldVal})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// FormatChange renders a change as "+ path: value", "~ path: old -> new" or "- path: old"
+func FormatChange(c FieldChange) string {
+	switch c.Type {
+	case FieldAdded:
+		return fmt.Sprintf("+ %s: %s", c.Path, formatLeaf(c.New))
+	case FieldRemoved:
+		return fmt.Sprintf("- %s: %s", c.Path, formatLeaf(c.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, formatLeaf(c.Old), formatLeaf(c.New))
+	}
+}
+
+// flattenLeaves records the leaf values under v by path. Empty maps and lists
+// count as leaves, so adding "{}" is reported too.
+func flattenLeaves(v interface{}, path string, out map[string]interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 && path != "" {
+			out[path] = val
+			return
+		}
+		for k, child := range val {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			flattenLeaves(child, childPath, out)
+		}
+	case []interface{}:
+		if len(val) == 0 {
+			out[path] = val
+			return
+		}
+		for i, child := range val {
+			flattenLeaves(child, fmt.Sprintf("%s[%d]", path, i), out)
+		}
+	default:
+		out[path] = val
+	}
+}
+
+// skipped reports whether path is one of prefixes or below one
+func skipped(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if path == p || strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// formatLeaf renders a leaf value, quoting strings
+func formatLeaf(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", val)
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
@@ -26,8 +26,11 @@ func GenerateManifest(gvr schema.GroupVersionResource, namespace string, values
 	metadata["name"] = values.Name
 	
 	// Set namespace if provided and resource is namespaced
+	// (cluster-scoped resources are generated with an empty namespace)
 	if namespace != "" {
 		metadata["namespace"] = namespace
+	} else {
+		delete(metadata, "namespace")
 	}
 	
 	// Build the object
@@ -67,6 +70,46 @@ func PrintManifest(obj *unstructured.Unstructured, format string) error {
 	return nil
 }
 
+// PrintManifests prints several manifests as one stream that can be piped into
+// kubectl apply -f -: YAML documents separated by "---", or a JSON v1 List
+func PrintManifests(objs []*unstructured.Unstructured, format string) error {
+	if len(objs) == 1 {
+		return PrintManifest(objs[0], format)
+	}
+
+	switch format {
+	case "json":
+		items := make([]interface{}, 0, len(objs))
+		for _, obj := range objs {
+			items = append(items, obj.Object)
+		}
+		list := map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      items,
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "yaml", "":
+		for i, obj := range objs {
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return fmt.Errorf("failed to marshal to YAML: %w", err)
+			}
+			if i > 0 {
+				fmt.Println("---")
+			}
+			fmt.Print(string(data))
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s (use yaml or json)", format)
+	}
+	return nil
+}
+
 // gvrToAPIVersion converts a GVR to an API version string
 func gvrToAPIVersion(gvr schema.GroupVersionResource) string {
 	if gvr.Group == "" {
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// NameSuffixModes lists the supported --name-suffix values
+var NameSuffixModes = []string{"random", "timestamp", "gitsha"}
+
+// suffixAlphabet matches the characters used by the API server for generateName
+const suffixAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+
+// NameSuffix returns a suffix for the given mode: 5 random characters, a UTC
+// timestamp, or the short SHA of the current git commit
+func NameSuffix(mode string) (string, error) {
+	switch mode {
+	case "random":
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate random suffix: %w", err)
+		}
+		for i, b := range buf {
+			buf[i] = suffixAlphabet[int(b)%len(suffixAlphabet)]
+		}
+		return string(buf), nil
+	case "timestamp":
+		return time.Now().UTC().Format("20060102-150405"), nil
+	case "gitsha":
+		out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to get git commit for --name-suffix=gitsha: %w", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	default:
+		return "", fmt.Errorf("unsupported --name-suffix %q (use %s)", mode, strings.Join(NameSuffixModes, ", "))
+	}
+}
+
+// AppendNameSuffix appends a suffix for mode to name, separated by "-"
+func AppendNameSuffix(name, mode string) (string, error) {
+	if mode == "" {
+		return name, nil
+	}
+	suffix, err := NameSuffix(mode)
+	if err != nil {
+		return "", err
+	}
+	return name + "-" + suffix, nil
+}
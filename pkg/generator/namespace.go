@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Default NetworkPolicies of NamespaceDefaults
+const (
+	NetworkPolicyDenyIngress   = "deny-ingress"   // Deny all ingress to the namespace's pods
+	NetworkPolicySameNamespace = "same-namespace" // Allow ingress only from the namespace's pods
+	NetworkPolicyDenyAll       = "deny-all"       // Deny all ingress and egress
+)
+
+// NamespaceDefaults are the objects provisioned in a new namespace by the
+// namespace preset. Empty fields provision nothing.
+type NamespaceDefaults struct {
+	Quota           map[string]string // Hard limits of a ResourceQuota (e.g., requests.cpu: "4")
+	DefaultRequests map[string]string // Container default requests of a LimitRange
+	DefaultLimits   map[string]string // Container default limits of a LimitRange
+	NetworkPolicy   string            // One of the NetworkPolicy* constants
+}
+
+// GenerateNamespaceDefaults returns the ResourceQuota, LimitRange and
+// NetworkPolicy of d in namespace, each named default (the NetworkPolicy after
+// what it does)
+func GenerateNamespaceDefaults(namespace string, d NamespaceDefaults) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+
+	if len(d.Quota) > 0 {
+		quota := namespacedObject("v1", "ResourceQuota", "default", namespace)
+		quota.Object["spec"] = map[string]interface{}{"hard": stringMap(d.Quota)}
+		objs = append(objs, quota)
+	}
+
+	if len(d.DefaultRequests) > 0 || len(d.DefaultLimits) > 0 {
+		limit := map[string]interface{}{"type": "Container"}
+		if len(d.DefaultRequests) > 0 {
+			limit["defaultRequest"] = stringMap(d.DefaultRequests)
+		}
+		if len(d.DefaultLimits) > 0 {
+			limit["default"] = stringMap(d.DefaultLimits)
+		}
+		limitRange := namespacedObject("v1", "LimitRange", "default", namespace)
+		limitRange.Object["spec"] = map[string]interface{}{"limits": []interface{}{limit}}
+		objs = append(objs, limitRange)
+	}
+
+	if d.NetworkPolicy != "" {
+		spec := map[string]interface{}{"podSelector": map[string]interface{}{}}
+		name := "default-" + d.NetworkPolicy
+		switch d.NetworkPolicy {
+		case NetworkPolicyDenyIngress:
+			spec["policyTypes"] = []interface{}{"Ingress"}
+		case NetworkPolicySameNamespace:
+			name = "allow-same-namespace"
+			spec["policyTypes"] = []interface{}{"Ingress"}
+			spec["ingress"] = []interface{}{
+				map[string]interface{}{
+					"from": []interface{}{
+						map[string]interface{}{"podSelector": map[string]interface{}{}},
+					},
+				},
+			}
+		case NetworkPolicyDenyAll:
+			spec["policyTypes"] = []interface{}{"Ingress", "Egress"}
+		}
+		policy := namespacedObject("networking.k8s.io/v1", "NetworkPolicy", name, namespace)
+		policy.Object["spec"] = spec
+		objs = append(objs, policy)
+	}
+
+	return objs
+}
+
+// namespacedObject returns an empty object of kind in namespace
+func namespacedObject(apiVersion, kind, name, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+	}}
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	return obj
+}
+
+// stringMap converts m for unstructured objects
+func stringMap(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
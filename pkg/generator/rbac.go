@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RBACVerbs are the verbs GenerateRBAC grants on the resource type
+var RBACVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// GenerateRBAC returns a Role in namespace granting RBACVerbs on the resources
+// of gvr, and a RoleBinding of it to the service account saNamespace/saName.
+// For cluster-scoped types (an empty namespace) they are a ClusterRole and a
+// ClusterRoleBinding.
+func GenerateRBAC(gvr schema.GroupVersionResource, namespace, saNamespace, saName string) []*unstructured.Unstructured {
+	roleKind, bindingKind := "Role", "RoleBinding"
+	if namespace == "" {
+		roleKind, bindingKind = "ClusterRole", "ClusterRoleBinding"
+	}
+
+	// Named after the type, so the Role is shared by the accounts bound to it
+	roleName := gvr.Resource + "-editor"
+	if gvr.Group != "" {
+		roleName = gvr.Resource + "." + gvr.Group + "-editor"
+	}
+
+	verbs := make([]interface{}, len(RBACVerbs))
+	for i, verb := range RBACVerbs {
+		verbs[i] = verb
+	}
+	role := rbacObject(roleKind, roleName, namespace)
+	role.Object["rules"] = []interface{}{
+		map[string]interface{}{
+			"apiGroups": []interface{}{gvr.Group},
+			"resources": []interface{}{gvr.Resource},
+			"verbs":     verbs,
+		},
+	}
+
+	binding := rbacObject(bindingKind, roleName+"-"+saName, namespace)
+	binding.Object["roleRef"] = map[string]interface{}{
+		"apiGroup": "rbac.authorization.k8s.io",
+		"kind":     roleKind,
+		"name":     roleName,
+	}
+	binding.Object["subjects"] = []interface{}{
+		map[string]interface{}{
+			"kind":      "ServiceAccount",
+			"name":      saName,
+			"namespace": saNamespace,
+		},
+	}
+
+	return []*unstructured.Unstructured{role, binding}
+}
+
+// rbacObject returns an empty object of an rbac.authorization.k8s.io/v1 kind
+func rbacObject(kind, name, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       kind,
+	}}
+	obj.SetName(name)
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+	return obj
+}
@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ReadManifests reads the objects of a manifest stream, the counterpart of
+// PrintManifests: YAML documents separated by "---", JSON objects, and v1 Lists,
+// whose items are returned in their place. Empty documents are skipped.
+func ReadManifests(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for doc := 1; ; doc++ {
+		var content map[string]interface{}
+		if err := decoder.Decode(&content); err != nil {
+			if err == io.EOF {
+				return objs, nil
+			}
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %w", doc, err)
+			}
+			for i := range list.Items {
+				objs = append(objs, &list.Items[i])
+			}
+			continue
+		}
+		objs = append(objs, obj)
+	}
+}
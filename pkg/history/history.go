@@ -0,0 +1,206 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Entry records one created resource
+type Entry struct {
+	ID        int                    `json:"id"`
+	Time      time.Time              `json:"time"`
+	Context   string                 `json:"context,omitempty"` // Kubeconfig context, if one was selected explicitly
+	Server    string                 `json:"server,omitempty"`  // API server the resource was created in
+	Group     string                 `json:"group,omitempty"`
+	Version   string                 `json:"version"`
+	Resource  string                 `json:"resource"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Name      string                 `json:"name"`
+	UID       string                 `json:"uid,omitempty"`
+	Values    map[string]interface{} `json:"values,omitempty"`    // Collected field values (not recorded for templates)
+	Manifest  map[string]interface{} `json:"manifest,omitempty"`  // The manifest as submitted
+	DeletedAt *time.Time             `json:"deletedAt,omitempty"` // Set when the resource was deleted with undo
+}
+
+// GVR returns the resource type of the entry
+func (e Entry) GVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: e.Group, Version: e.Version, Resource: e.Resource}
+}
+
+// Object returns a copy of the submitted manifest
+func (e Entry) Object() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: e.Manifest}
+	return obj.DeepCopy()
+}
+
+// Redacted reports whether entries of the resource type gvr are recorded
+// without their manifest and values: Secrets, whose data would otherwise be
+// kept in the history file and offered as prompt defaults
+func Redacted(gvr schema.GroupVersionResource) bool {
+	return gvr.Group == "" && gvr.Resource == "secrets"
+}
+
+// DefaultPath returns the history file location
+func DefaultPath() string {
+	return filepath.Join(config.Dir(), "history.jsonl")
+}
+
+// Load reads all entries from the history file, oldest first. A missing file
+// yields no entries.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse history %s: %w", path, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Get returns the entry with the given ID
+func Get(path string, id int) (*Entry, error) {
+	entries, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("history entry %d not found", id)
+}
+
+// Last returns the most recent entry that hasn't been deleted
+func Last(path string) (*Entry, error) {
+	entries, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].DeletedAt == nil {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no created resources in history")
+}
+
+// LastValues returns the field values recorded for the most recent creation of
+// gvr, in any version, or nil if the type wasn't created before
+func LastValues(path string, gvr schema.GroupVersionResource) (map[string]interface{}, error) {
+	// Histories written before Secrets were redacted may still hold their values
+	if Redacted(gvr) {
+		return nil, nil
+	}
+	entries, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Group == gvr.Group && e.Resource == gvr.Resource && e.Values != nil {
+			return e.Values, nil
+		}
+	}
+	return nil, nil
+}
+
+// MarkDeleted records that the resource of entry id was deleted
+func MarkDeleted(path string, id int) error {
+	entries, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var buf []byte
+	for _, e := range entries {
+		if e.ID == id {
+			e.DeletedAt = &now
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		buf = append(buf, data...)
+		buf = append(buf, '\n')
+	}
+
+	// Replace the file atomically so an interrupted write can't lose the history
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// Append assigns the next ID to e and adds it to the history file
+func Append(path string, e Entry) (Entry, error) {
+	entries, err := Load(path)
+	if err != nil {
+		return e, err
+	}
+	e.ID = 1
+	if len(entries) > 0 {
+		e.ID = entries[len(entries)-1].ID + 1
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if Redacted(e.GVR()) {
+		e.Values = nil
+		e.Manifest = nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return e, fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	// Manifests may still hold credentials in fields of other types (e.g., a
+	// password in a custom resource), so history is private to the user
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return e, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return e, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return e, fmt.Errorf("failed to write history: %w", err)
+	}
+	return e, nil
+}
@@ -0,0 +1,103 @@
+// Package i18n translates user-facing messages. A message is identified by its
+// English text, which is also used when the selected language has no catalog
+// or no translation for it, so untranslated messages still read naturally.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// catalogs holds one JSON object per language, mapping English messages to
+// their translations (e.g., catalog/es.json)
+//
+//go:embed catalog/*.json
+var catalogs embed.FS
+
+// English is the language of the messages in the code
+const English = "en"
+
+// messages are the translations of the selected language (nil for English)
+var messages map[string]string
+
+// Languages returns the available languages, English included
+func Languages() []string {
+	langs := []string{English}
+	entries, _ := catalogs.ReadDir("catalog")
+	for _, e := range entries {
+		langs = append(langs, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Detect returns the language of the user's locale from LC_ALL, LC_MESSAGES or
+// LANG, in that order, or English
+func Detect() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(env); value != "" {
+			return value
+		}
+	}
+	return English
+}
+
+// SetLanguage selects the messages of lang, a language code or locale (e.g.,
+// "es" or "es_ES.UTF-8"). Unknown languages, and the C and POSIX locales,
+// select English.
+func SetLanguage(lang string) error {
+	messages = nil
+	code := normalize(lang)
+	if code == English {
+		return nil
+	}
+
+	data, err := catalogs.ReadFile(path.Join("catalog", code+".json"))
+	if err != nil {
+		return fmt.Errorf("no translations for language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	var catalog map[string]string
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("invalid catalog for language %q: %w", code, err)
+	}
+	messages = catalog
+	return nil
+}
+
+// normalize reduces a locale like "es_ES.UTF-8" to its language code
+func normalize(lang string) string {
+	code := strings.ToLower(lang)
+	if i := strings.IndexAny(code, "_.@-"); i >= 0 {
+		code = code[:i]
+	}
+	if code == "" || code == "c" || code == "posix" {
+		return English
+	}
+	return code
+}
+
+// translate returns the translation of msg, or msg itself
+func translate(msg string) string {
+	if translated, ok := messages[msg]; ok && translated != "" {
+		return translated
+	}
+	return msg
+}
+
+// T translates msg. With args, msg is a format string for fmt.Sprintf.
+func T(msg string, args ...interface{}) string {
+	if len(args) == 0 {
+		return translate(msg)
+	}
+	return fmt.Sprintf(translate(msg), args...)
+}
+
+// Errorf translates format and creates an error like fmt.Errorf, so %w wraps
+func Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(translate(format), args...)
+}
@@ -0,0 +1,205 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var templatesGVR = schema.GroupVersionResource{Group: "templates.gatekeeper.sh", Version: "v1", Resource: "constrainttemplates"}
+
+// constraintsGroupVersion serves the constraints created from templates
+var constraintsGroupVersion = schema.GroupVersion{Group: "constraints.gatekeeper.sh", Version: "v1beta1"}
+
+// violationPattern matches the "[constraint-name] message" lines of Gatekeeper
+// denials and warnings
+var violationPattern = regexp.MustCompile(`^\[([^\]]+)\]\s*(.*)$`)
+
+// Constraint is a Gatekeeper constraint that applies to an object
+type Constraint struct {
+	Kind              string // Constraint kind, from its template (e.g., K8sRequiredLabels)
+	Name              string
+	EnforcementAction string // deny, dryrun or warn
+}
+
+// Violation is a constraint the object violates
+type Violation struct {
+	Constraint string
+	Message    string
+	Denied     bool // false for warn constraints
+}
+
+// GatekeeperReport is the result of the Gatekeeper pre-check
+type GatekeeperReport struct {
+	Constraints []Constraint
+	Violations  []Violation
+}
+
+// CheckGatekeeper finds the Gatekeeper constraints matching obj and, when there
+// are any, runs obj through a server-side dry-run so Gatekeeper's webhook
+// evaluates them without creating anything. Denied dry-runs and warnings are
+// parsed into violations. Other dry-run errors (e.g., schema validation) are
+// returned as is.
+func CheckGatekeeper(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, namespace string) (*GatekeeperReport, error) {
+	templates, err := k8sClient.ListObjects(templatesGVR, "")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GatekeeperReport{}
+	for _, t := range templates {
+		kind, _, _ := unstructured.NestedString(t.Object, "spec", "crd", "spec", "names", "kind")
+		if kind == "" {
+			continue
+		}
+		constraintsGVR, err := k8sClient.ResourceForKind(constraintsGroupVersion.WithKind(kind))
+		if err != nil {
+			// The template's CRD isn't ready yet
+			continue
+		}
+		constraints, err := k8sClient.ListObjects(constraintsGVR, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range constraints {
+			if !constraintMatches(c.Object, obj, namespace) {
+				continue
+			}
+			action, _, _ := unstructured.NestedString(c.Object, "spec", "enforcementAction")
+			if action == "" {
+				action = "deny"
+			}
+			report.Constraints = append(report.Constraints, Constraint{Kind: kind, Name: c.GetName(), EnforcementAction: action})
+		}
+	}
+	if len(report.Constraints) == 0 {
+		return report, nil
+	}
+
+	warningsBefore := len(k8sClient.Warnings())
+	_, err = k8sClient.DryRunCreateResource(gvr, namespace, obj.DeepCopy())
+	for _, w := range k8sClient.Warnings()[warningsBefore:] {
+		report.Violations = append(report.Violations, parseViolations(w, false)...)
+	}
+	if err != nil {
+		var denied []Violation
+		if status, ok := err.(apierrors.APIStatus); ok && status.Status().Reason == metav1.StatusReasonForbidden ||
+			strings.Contains(err.Error(), "validation.gatekeeper.sh") {
+			denied = parseViolations(err.Error(), true)
+		}
+		if len(denied) == 0 {
+			return nil, fmt.Errorf("dry-run failed: %w", err)
+		}
+		report.Violations = append(report.Violations, denied...)
+	}
+	return report, nil
+}
+
+// parseViolations extracts the "[constraint] message" lines of a Gatekeeper
+// denial or warning; the first line is prefixed by the webhook name
+func parseViolations(text string, denied bool) []Violation {
+	var violations []Violation
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "denied the request: "); i >= 0 {
+			line = line[i+len("denied the request: "):]
+		}
+		if m := violationPattern.FindStringSubmatch(line); m != nil {
+			violations = append(violations, Violation{Constraint: m[1], Message: m[2], Denied: denied})
+		}
+	}
+	return violations
+}
+
+// constraintMatches evaluates the spec.match of a constraint against obj: kinds,
+// scope, namespaces, excludedNamespaces, name and labelSelector. A
+// namespaceSelector is left to Gatekeeper itself, so the constraint counts.
+func constraintMatches(constraint map[string]interface{}, obj *unstructured.Unstructured, namespace string) bool {
+	match, _, _ := unstructured.NestedMap(constraint, "spec", "match")
+	if match == nil {
+		return true
+	}
+	gvk := obj.GroupVersionKind()
+
+	if kinds, ok := match["kinds"].([]interface{}); ok && len(kinds) > 0 {
+		matched := false
+		for _, k := range kinds {
+			entry, _ := k.(map[string]interface{})
+			groups, _ := stringSlice(entry["apiGroups"])
+			kindNames, _ := stringSlice(entry["kinds"])
+			if (len(groups) == 0 || containsOrWildcard(groups, gvk.Group)) &&
+				(len(kindNames) == 0 || containsOrWildcard(kindNames, gvk.Kind)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	switch scope, _ := match["scope"].(string); scope {
+	case "Cluster":
+		if namespace != "" {
+			return false
+		}
+	case "Namespaced":
+		if namespace == "" {
+			return false
+		}
+	}
+
+	if namespace != "" {
+		if namespaces, ok := stringSlice(match["namespaces"]); ok && len(namespaces) > 0 && !matchesAnyGlob(namespaces, namespace) {
+			return false
+		}
+		if excluded, ok := stringSlice(match["excludedNamespaces"]); ok && matchesAnyGlob(excluded, namespace) {
+			return false
+		}
+	}
+
+	if name, ok := match["name"].(string); ok && name != "" && !matchesAnyGlob([]string{name}, obj.GetName()) {
+		return false
+	}
+
+	if raw, ok := match["labelSelector"].(map[string]interface{}); ok {
+		var selector metav1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &selector); err != nil || !selectorMatches(&selector, obj.GetLabels()) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAnyGlob reports whether s matches one of patterns, where Gatekeeper
+// allows a prefix or suffix "*" wildcard
+func matchesAnyGlob(patterns []string, s string) bool {
+	return slices.ContainsFunc(patterns, func(p string) bool {
+		ok, err := path.Match(p, s)
+		return err == nil && ok
+	})
+}
+
+// stringSlice converts an unstructured list of strings
+func stringSlice(v interface{}) ([]string, bool) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out, true
+}
@@ -0,0 +1,456 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	policiesGVR   = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingadmissionpolicies"}
+	bindingsGVR   = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingadmissionpolicybindings"}
+	namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+)
+
+// errParamsNotFound is returned when a binding's parameters don't exist and the
+// binding denies in that case
+var errParamsNotFound = errors.New("the parameters of the binding were not found")
+
+// Finding is a policy validation the object fails
+type Finding struct {
+	Policy  string
+	Binding string
+	Message string
+	Reason  string // e.g., Invalid or Forbidden
+}
+
+// Report is the result of checking an object against the ValidatingAdmissionPolicies
+type Report struct {
+	Denials  []Finding // Failures of bindings with the Deny action
+	Warnings []Finding // Failures of bindings with only the Warn action
+	Skipped  []string  // Policies that could not be evaluated locally, with the reason
+}
+
+// attributes describe the create request being checked
+type attributes struct {
+	gvr             schema.GroupVersionResource
+	namespaced      bool
+	name            string
+	objectLabels    map[string]string
+	namespaceLabels map[string]string
+}
+
+// CheckValidatingPolicies evaluates the ValidatingAdmissionPolicies bound to the
+// create of obj client-side, like the API server's admission would, to report
+// denials without a round-trip. The object is checked as generated, before any
+// server defaulting or mutation, and userInfo is not known locally. Policies using
+// Kubernetes-specific CEL libraries or the authorizer are reported as skipped.
+func CheckValidatingPolicies(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, namespace string) (*Report, error) {
+	policyObjs, err := k8sClient.ListObjects(policiesGVR, "")
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	if len(policyObjs) == 0 {
+		return report, nil
+	}
+	bindingObjs, err := k8sClient.ListObjects(bindingsGVR, "")
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := make(map[string][]admissionv1.ValidatingAdmissionPolicyBinding)
+	for _, u := range bindingObjs {
+		var b admissionv1.ValidatingAdmissionPolicyBinding
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &b); err != nil {
+			continue
+		}
+		bindings[b.Spec.PolicyName] = append(bindings[b.Spec.PolicyName], b)
+	}
+
+	attrs := &attributes{
+		gvr:          gvr,
+		namespaced:   namespace != "",
+		name:         obj.GetName(),
+		objectLabels: obj.GetLabels(),
+	}
+	var namespaceObject interface{}
+	if namespace != "" {
+		if ns, err := k8sClient.GetResource(namespacesGVR, "", namespace); err == nil {
+			attrs.namespaceLabels = ns.GetLabels()
+			namespaceObject = ns.Object
+		}
+	}
+
+	env, err := newEnv()
+	if err != nil {
+		return nil, err
+	}
+	activation := map[string]interface{}{
+		"object":          obj.Object,
+		"oldObject":       nil,
+		"namespaceObject": namespaceObject,
+		"request":         requestFor(gvr, obj, namespace),
+	}
+
+	for _, u := range policyObjs {
+		var p admissionv1.ValidatingAdmissionPolicy
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &p); err != nil {
+			continue
+		}
+		if p.Spec.MatchConstraints == nil || !matchResources(p.Spec.MatchConstraints, attrs) {
+			continue
+		}
+		policyBindings := bindings[p.Name]
+		if len(policyBindings) == 0 {
+			continue
+		}
+
+		compiled, err := compilePolicy(env, &p)
+		if err != nil {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s: %v", p.Name, err))
+			continue
+		}
+
+		for _, b := range policyBindings {
+			if !matchResources(b.Spec.MatchResources, attrs) {
+				continue
+			}
+			deny := slices.Contains(b.Spec.ValidationActions, admissionv1.Deny)
+			warn := slices.Contains(b.Spec.ValidationActions, admissionv1.Warn)
+			if !deny && !warn {
+				continue
+			}
+
+			var failures []Finding
+			params, err := resolveParams(k8sClient, &p, &b, namespace)
+			if errors.Is(err, errParamsNotFound) {
+				failures = append(failures, Finding{Message: err.Error(), Reason: string(metav1.StatusReasonInvalid)})
+			} else if err != nil {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("%s (binding %s): %v", p.Name, b.Name, err))
+				continue
+			}
+			for _, param := range params {
+				activation["params"] = param
+				found, err := compiled.evaluate(activation)
+				if err != nil {
+					report.Skipped = append(report.Skipped, fmt.Sprintf("%s (binding %s): %v", p.Name, b.Name, err))
+					break
+				}
+				failures = append(failures, found...)
+			}
+
+			for _, f := range failures {
+				f.Policy, f.Binding = p.Name, b.Name
+				if deny {
+					report.Denials = append(report.Denials, f)
+				} else {
+					report.Warnings = append(report.Warnings, f)
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+// newEnv returns a CEL environment with the variables of admission policies and
+// the CEL extension libraries the API server also provides
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("oldObject", cel.DynType),
+		cel.Variable("params", cel.DynType),
+		cel.Variable("namespaceObject", cel.DynType),
+		cel.Variable("request", cel.DynType),
+		cel.Variable("variables", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+		ext.Sets(),
+		ext.Lists(),
+		ext.Math(),
+		ext.Encoders(),
+		cel.OptionalTypes(),
+	)
+}
+
+// requestFor builds the request variable of a create of obj
+func requestFor(gvr schema.GroupVersionResource, obj *unstructured.Unstructured, namespace string) map[string]interface{} {
+	gvk := obj.GroupVersionKind()
+	return map[string]interface{}{
+		"operation": "CREATE",
+		"kind":      map[string]interface{}{"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind},
+		"resource":  map[string]interface{}{"group": gvr.Group, "version": gvr.Version, "resource": gvr.Resource},
+		"name":      obj.GetName(),
+		"namespace": namespace,
+		"dryRun":    false,
+		"userInfo":  map[string]interface{}{},
+	}
+}
+
+// matchResources reports whether the request matches m; a nil m matches everything
+func matchResources(m *admissionv1.MatchResources, a *attributes) bool {
+	if m == nil {
+		return true
+	}
+	if m.NamespaceSelector != nil && a.namespaced && !selectorMatches(m.NamespaceSelector, a.namespaceLabels) {
+		return false
+	}
+	if m.ObjectSelector != nil && !selectorMatches(m.ObjectSelector, a.objectLabels) {
+		return false
+	}
+
+	exact := m.MatchPolicy != nil && *m.MatchPolicy == admissionv1.Exact
+	if len(m.ResourceRules) > 0 {
+		matched := false
+		for _, r := range m.ResourceRules {
+			if ruleMatches(r, a, exact) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, r := range m.ExcludeResourceRules {
+		if ruleMatches(r, a, exact) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleMatches reports whether a rule covers the create. Without the Exact match
+// policy, rules for other versions of the resource match too.
+func ruleMatches(r admissionv1.NamedRuleWithOperations, a *attributes, exact bool) bool {
+	if !containsOrWildcard(operations(r.Operations), string(admissionv1.Create)) ||
+		!containsOrWildcard(r.APIGroups, a.gvr.Group) ||
+		(exact && !containsOrWildcard(r.APIVersions, a.gvr.Version)) {
+		return false
+	}
+	if !containsOrWildcard(r.Resources, a.gvr.Resource) && !slices.Contains(r.Resources, "*/*") {
+		return false
+	}
+	if r.Scope != nil {
+		switch *r.Scope {
+		case admissionv1.ClusterScope:
+			if a.namespaced {
+				return false
+			}
+		case admissionv1.NamespacedScope:
+			if !a.namespaced {
+				return false
+			}
+		}
+	}
+	return len(r.ResourceNames) == 0 || slices.Contains(r.ResourceNames, a.name)
+}
+
+// operations converts rule operations to strings
+func operations(ops []admissionv1.OperationType) []string {
+	out := make([]string, len(ops))
+	for i, op := range ops {
+		out[i] = string(op)
+	}
+	return out
+}
+
+// containsOrWildcard reports whether values contains v or "*"
+func containsOrWildcard(values []string, v string) bool {
+	return slices.Contains(values, v) || slices.Contains(values, "*")
+}
+
+// selectorMatches reports whether a label selector selects set; invalid selectors select nothing
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(set))
+}
+
+// resolveParams returns the parameter objects of a binding, or a single nil when
+// the policy has no paramKind. Each parameter object is checked separately.
+func resolveParams(k8sClient *client.K8sClient, p *admissionv1.ValidatingAdmissionPolicy, b *admissionv1.ValidatingAdmissionPolicyBinding, namespace string) ([]interface{}, error) {
+	if p.Spec.ParamKind == nil {
+		return []interface{}{nil}, nil
+	}
+	ref := b.Spec.ParamRef
+	if ref == nil {
+		return nil, fmt.Errorf("the policy has a paramKind but the binding has no paramRef")
+	}
+
+	gv, err := schema.ParseGroupVersion(p.Spec.ParamKind.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid paramKind: %w", err)
+	}
+	gvr, err := k8sClient.ResourceForKind(gv.WithKind(p.Spec.ParamKind.Kind))
+	if err != nil {
+		return nil, fmt.Errorf("unknown paramKind %s: %w", p.Spec.ParamKind.Kind, err)
+	}
+	paramNamespace := ref.Namespace
+	if paramNamespace == "" && k8sClient.IsNamespaced(gvr) {
+		paramNamespace = namespace
+	}
+
+	var params []interface{}
+	switch {
+	case ref.Name != "":
+		obj, err := k8sClient.GetResource(gvr, paramNamespace, ref.Name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get parameters: %w", err)
+		}
+		if err == nil {
+			params = append(params, obj.Object)
+		}
+	case ref.Selector != nil:
+		objs, err := k8sClient.ListObjects(gvr, paramNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list parameters: %w", err)
+		}
+		for _, obj := range objs {
+			if selectorMatches(ref.Selector, obj.GetLabels()) {
+				params = append(params, obj.Object)
+			}
+		}
+	}
+
+	if len(params) == 0 {
+		if ref.ParameterNotFoundAction != nil && *ref.ParameterNotFoundAction == admissionv1.AllowAction {
+			return nil, nil
+		}
+		return nil, errParamsNotFound
+	}
+	return params, nil
+}
+
+// compiledPolicy holds the CEL programs of a policy
+type compiledPolicy struct {
+	conditions  []namedProgram
+	variables   []namedProgram
+	validations []compiledValidation
+}
+
+type namedProgram struct {
+	name    string
+	program cel.Program
+}
+
+type compiledValidation struct {
+	expression string
+	program    cel.Program
+	message    string
+	messageExp cel.Program // nil without messageExpression
+	reason     string
+}
+
+// compilePolicy compiles the match conditions, variables and validations of p
+func compilePolicy(env *cel.Env, p *admissionv1.ValidatingAdmissionPolicy) (*compiledPolicy, error) {
+	compiled := &compiledPolicy{}
+	for _, c := range p.Spec.MatchConditions {
+		prg, err := compile(env, c.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("match condition %s: %w", c.Name, err)
+		}
+		compiled.conditions = append(compiled.conditions, namedProgram{name: c.Name, program: prg})
+	}
+	for _, v := range p.Spec.Variables {
+		prg, err := compile(env, v.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", v.Name, err)
+		}
+		compiled.variables = append(compiled.variables, namedProgram{name: v.Name, program: prg})
+	}
+	for _, v := range p.Spec.Validations {
+		prg, err := compile(env, v.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("validation %q: %w", v.Expression, err)
+		}
+		cv := compiledValidation{expression: v.Expression, program: prg, message: v.Message, reason: string(metav1.StatusReasonInvalid)}
+		if v.Reason != nil {
+			cv.reason = string(*v.Reason)
+		}
+		if v.MessageExpression != "" {
+			// An unusable messageExpression falls back to the message, like the API server
+			cv.messageExp, _ = compile(env, v.MessageExpression)
+		}
+		compiled.validations = append(compiled.validations, cv)
+	}
+	return compiled, nil
+}
+
+// compile compiles a CEL expression. Functions of the Kubernetes CEL libraries
+// (quantity, url, authorizer...) are not available locally and fail here.
+func compile(env *cel.Env, expression string) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		// Only the first issue, the rest repeat it with the expression
+		first, _, _ := strings.Cut(issues.Err().Error(), "\n")
+		return nil, fmt.Errorf("cannot be evaluated locally: %s", first)
+	}
+	return env.Program(ast)
+}
+
+// evaluate runs the policy against activation and returns the failed validations.
+// Nothing fails when a match condition is false.
+func (c *compiledPolicy) evaluate(activation map[string]interface{}) ([]Finding, error) {
+	variables := make(map[string]interface{})
+	activation["variables"] = variables
+
+	for _, cond := range c.conditions {
+		out, _, err := cond.program.Eval(activation)
+		if err != nil {
+			return nil, fmt.Errorf("match condition %s: %w", cond.name, err)
+		}
+		if out == types.False {
+			return nil, nil
+		}
+	}
+	for _, v := range c.variables {
+		out, _, err := v.program.Eval(activation)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", v.name, err)
+		}
+		variables[v.name] = out
+	}
+
+	var failures []Finding
+	for _, v := range c.validations {
+		out, _, err := v.program.Eval(activation)
+		if err != nil {
+			return nil, fmt.Errorf("validation %q: %w", v.expression, err)
+		}
+		if out != types.False {
+			continue
+		}
+		failures = append(failures, Finding{Message: v.messageFor(activation), Reason: v.reason})
+	}
+	return failures, nil
+}
+
+// messageFor returns the message of a failed validation
+func (v *compiledValidation) messageFor(activation map[string]interface{}) string {
+	if v.messageExp != nil {
+		if out, _, err := v.messageExp.Eval(activation); err == nil {
+			if msg, ok := out.Value().(string); ok && msg != "" {
+				return msg
+			}
+		}
+	}
+	if v.message != "" {
+		return v.message
+	}
+	return fmt.Sprintf("failed expression: %s", v.expression)
+}
@@ -0,0 +1,50 @@
+package preflight
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SpecHash hashes the content of obj: everything but apiVersion, kind, metadata
+// and status, so the spec of most types and e.g. the data of a ConfigMap. Maps
+// are encoded with sorted keys, so the hash doesn't depend on field order.
+func SpecHash(obj *unstructured.Unstructured) string {
+	content := make(map[string]interface{}, len(obj.Object))
+	for k, v := range obj.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		content[k] = v
+	}
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// FindDuplicates returns the names of the objects of gvr in namespace whose
+// spec hash is one of hashes, e.g. of the manifest as written and as the server
+// defaults it
+func FindDuplicates(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, namespace string, hashes ...string) ([]string, error) {
+	existing, err := k8sClient.ListObjects(gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for i := range existing {
+		hash := SpecHash(&existing[i])
+		for _, h := range hashes {
+			if hash == h {
+				names = append(names, existing[i].GetName())
+				break
+			}
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,146 @@
+package preflight
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Lint rules, which --lint-disable takes
+const (
+	RuleResourceLimits    = "resource-limits"
+	RuleLatestTag         = "latest-tag"
+	RuleProbes            = "probes"
+	RuleRecommendedLabels = "recommended-labels"
+)
+
+// LintRules lists the lint rules with what they check
+var LintRules = map[string]string{
+	RuleResourceLimits:    "containers set CPU and memory limits",
+	RuleLatestTag:         "images are pinned to a tag other than latest, or a digest",
+	RuleProbes:            "containers of long-running workloads have readiness and liveness probes",
+	RuleRecommendedLabels: "the recommended app.kubernetes.io labels are set",
+}
+
+// recommendedLabels are the labels recommended for every object
+// (https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/)
+var recommendedLabels = []string{
+	"app.kubernetes.io/name",
+	"app.kubernetes.io/instance",
+	"app.kubernetes.io/version",
+	"app.kubernetes.io/component",
+	"app.kubernetes.io/part-of",
+	"app.kubernetes.io/managed-by",
+}
+
+// longRunningKinds run pods that should be probed; Jobs run to completion
+var longRunningKinds = map[string]bool{
+	"Pod":         true,
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"ReplicaSet":  true,
+}
+
+// Lint runs the best-practice rules not in disabled on obj. The container rules
+// apply to Pods and to the pod templates of workloads.
+func Lint(obj *unstructured.Unstructured, disabled []string) []Finding {
+	enabled := func(rule string) bool {
+		for _, d := range disabled {
+			if d == rule {
+				return false
+			}
+		}
+		return true
+	}
+
+	var findings []Finding
+	add := func(rule, format string, args ...interface{}) {
+		findings = append(findings, Finding{Source: "lint " + rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if enabled(RuleRecommendedLabels) {
+		labels := obj.GetLabels()
+		var missing []string
+		for _, l := range recommendedLabels {
+			if _, ok := labels[l]; !ok {
+				missing = append(missing, l)
+			}
+		}
+		if len(missing) > 0 {
+			add(RuleRecommendedLabels, "%s %q has no %s labels", obj.GetKind(), obj.GetName(), strings.Join(missing, ", "))
+		}
+	}
+
+	spec, ok := lintPodSpec(obj)
+	if !ok {
+		return findings
+	}
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		if enabled(RuleResourceLimits) {
+			var missing []string
+			for _, r := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				if _, ok := c.Resources.Limits[r]; !ok {
+					missing = append(missing, string(r))
+				}
+			}
+			if len(missing) > 0 {
+				add(RuleResourceLimits, "container %s has no %s limit", c.Name, strings.Join(missing, " or "))
+			}
+		}
+		if enabled(RuleLatestTag) && unpinnedImage(c.Image) {
+			add(RuleLatestTag, "container %s uses image %q, pin a tag or digest", c.Name, c.Image)
+		}
+	}
+
+	if enabled(RuleProbes) && longRunningKinds[obj.GetKind()] {
+		for _, c := range spec.Containers {
+			var missing []string
+			if c.ReadinessProbe == nil {
+				missing = append(missing, "readiness")
+			}
+			if c.LivenessProbe == nil {
+				missing = append(missing, "liveness")
+			}
+			if len(missing) > 0 {
+				add(RuleProbes, "container %s has no %s probe", c.Name, strings.Join(missing, " or "))
+			}
+		}
+	}
+	return findings
+}
+
+// lintPodSpec returns the pod spec of a Pod, a workload or a CronJob
+func lintPodSpec(obj *unstructured.Unstructured) (*corev1.PodSpec, bool) {
+	paths := [][]string{{"spec", "template", "spec"}, {"spec", "jobTemplate", "spec", "template", "spec"}}
+	if obj.GetKind() == "Pod" {
+		paths = [][]string{{"spec"}}
+	}
+	for _, path := range paths {
+		raw, ok, _ := unstructured.NestedMap(obj.Object, path...)
+		if !ok {
+			continue
+		}
+		var spec corev1.PodSpec
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &spec); err != nil {
+			return nil, false
+		}
+		return &spec, true
+	}
+	return nil, false
+}
+
+// unpinnedImage reports whether an image has no tag, or the latest tag, and no digest
+func unpinnedImage(image string) bool {
+	if image == "" || strings.Contains(image, "@") {
+		return false
+	}
+	// A ":" after the last "/" starts the tag; before it, it is a registry port
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i < 0 || name[i+1:] == "latest"
+}
@@ -0,0 +1,287 @@
+// Package preflight checks a generated object against namespace policies
+// before it is created.
+package preflight
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	quotasGVR      = schema.GroupVersionResource{Version: "v1", Resource: "resourcequotas"}
+	limitRangesGVR = schema.GroupVersionResource{Version: "v1", Resource: "limitranges"}
+)
+
+// legacyCountResources are the core resources a quota can count by their plain name
+var legacyCountResources = map[string]bool{
+	"pods":                   true,
+	"services":               true,
+	"secrets":                true,
+	"configmaps":             true,
+	"persistentvolumeclaims": true,
+	"replicationcontrollers": true,
+	"resourcequotas":         true,
+}
+
+// Finding is a quota or limit range the object would hit
+type Finding struct {
+	Source  string // e.g., "ResourceQuota compute-resources"
+	Message string
+}
+
+// String formats the finding for printing
+func (f Finding) String() string {
+	return f.Source + ": " + f.Message
+}
+
+// CheckQuota compares what obj would consume in namespace with the remaining
+// ResourceQuota there, and its containers with the LimitRanges. For workloads,
+// the pods of all replicas are counted, with LimitRange defaults applied to
+// containers without requests or limits. Quotas with scopes are not evaluated.
+func CheckQuota(k8sClient *client.K8sClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, namespace string) ([]Finding, error) {
+	if namespace == "" {
+		return nil, nil
+	}
+
+	quotaObjs, err := k8sClient.ListObjects(quotasGVR, namespace)
+	if err != nil {
+		return nil, err
+	}
+	rangeObjs, err := k8sClient.ListObjects(limitRangesGVR, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var ranges []corev1.LimitRange
+	for _, u := range rangeObjs {
+		var lr corev1.LimitRange
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &lr); err == nil {
+			ranges = append(ranges, lr)
+		}
+	}
+
+	usage, findings := objectUsage(gvr, obj, ranges)
+
+	for _, u := range quotaObjs {
+		var quota corev1.ResourceQuota
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &quota); err != nil {
+			continue
+		}
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		for name, hard := range quota.Status.Hard {
+			need, ok := usage[name]
+			if !ok || need.IsZero() {
+				continue
+			}
+			used := quota.Status.Used[name]
+			total := used.DeepCopy()
+			total.Add(need)
+			if total.Cmp(hard) > 0 {
+				findings = append(findings, Finding{
+					Source: "ResourceQuota " + quota.Name,
+					Message: fmt.Sprintf("%s would reach %s, over the limit of %s (%s used, this adds %s)",
+						name, total.String(), hard.String(), used.String(), need.String()),
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// objectUsage returns what creating obj counts against quota, and the LimitRange
+// violations of its containers
+func objectUsage(gvr schema.GroupVersionResource, obj *unstructured.Unstructured, ranges []corev1.LimitRange) (corev1.ResourceList, []Finding) {
+	usage := corev1.ResourceList{}
+	countName := "count/" + gvr.Resource
+	if gvr.Group != "" {
+		countName += "." + gvr.Group
+	}
+	usage[corev1.ResourceName(countName)] = resource.MustParse("1")
+	if gvr.Group == "" && legacyCountResources[gvr.Resource] {
+		usage[corev1.ResourceName(gvr.Resource)] = resource.MustParse("1")
+	}
+
+	switch {
+	case gvr.Group == "" && gvr.Resource == "services":
+		switch serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type"); serviceType {
+		case "LoadBalancer":
+			usage[corev1.ResourceServicesLoadBalancers] = resource.MustParse("1")
+			usage[corev1.ResourceServicesNodePorts] = resource.MustParse("1")
+		case "NodePort":
+			usage[corev1.ResourceServicesNodePorts] = resource.MustParse("1")
+		}
+	case gvr.Group == "" && gvr.Resource == "persistentvolumeclaims":
+		storage, _, _ := unstructured.NestedString(obj.Object, "spec", "resources", "requests", "storage")
+		if q, err := resource.ParseQuantity(storage); err == nil {
+			usage[corev1.ResourceRequestsStorage] = q
+			if class, _, _ := unstructured.NestedString(obj.Object, "spec", "storageClassName"); class != "" {
+				prefix := class + ".storageclass.storage.k8s.io/"
+				usage[corev1.ResourceName(prefix+"requests.storage")] = q
+				usage[corev1.ResourceName(prefix+"persistentvolumeclaims")] = resource.MustParse("1")
+			}
+		}
+	}
+
+	podSpec, replicas, ok := podSpecOf(obj)
+	if !ok || replicas == 0 {
+		return usage, nil
+	}
+	requests, limits, findings := podResources(podSpec, ranges)
+	usage[corev1.ResourcePods] = *resource.NewQuantity(replicas, resource.DecimalSI)
+	for name, q := range requests {
+		total := multiply(q, replicas)
+		usage[corev1.ResourceName("requests."+string(name))] = total
+		// cpu, memory and ephemeral-storage also stand for their requests
+		if !strings.Contains(string(name), "/") {
+			usage[name] = total
+		}
+	}
+	for name, q := range limits {
+		usage[corev1.ResourceName("limits."+string(name))] = multiply(q, replicas)
+	}
+	return usage, findings
+}
+
+// podSpecOf returns the pod spec of a Pod or of the template of a workload, with
+// the number of pods it makes (replicas or parallelism, 1 when unset). CronJobs
+// don't create pods right away, so they have none.
+func podSpecOf(obj *unstructured.Unstructured) (*corev1.PodSpec, int64, bool) {
+	var raw map[string]interface{}
+	replicas := int64(1)
+	if obj.GetKind() == "Pod" {
+		raw, _, _ = unstructured.NestedMap(obj.Object, "spec")
+	} else {
+		raw, _, _ = unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+		if n, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); ok {
+			replicas = n
+		} else if n, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "parallelism"); ok {
+			replicas = n
+		}
+	}
+	if raw == nil {
+		return nil, 0, false
+	}
+	var spec corev1.PodSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &spec); err != nil {
+		return nil, 0, false
+	}
+	return &spec, replicas, true
+}
+
+// podResources returns the effective requests and limits of a pod: the sum over
+// its containers, or the largest init container when that is more. LimitRange
+// defaults are applied, and LimitRange min/max violations reported.
+func podResources(spec *corev1.PodSpec, ranges []corev1.LimitRange) (corev1.ResourceList, corev1.ResourceList, []Finding) {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	var findings []Finding
+
+	for _, c := range spec.Containers {
+		req, lim, found := containerResources(c, ranges)
+		findings = append(findings, found...)
+		addResources(requests, req)
+		addResources(limits, lim)
+	}
+	for _, c := range spec.InitContainers {
+		req, lim, found := containerResources(c, ranges)
+		findings = append(findings, found...)
+		maxResources(requests, req)
+		maxResources(limits, lim)
+	}
+	return requests, limits, findings
+}
+
+// containerResources applies the Container defaults of the LimitRanges to a
+// container (requests also default to limits, like the API server does) and
+// checks it against their min and max
+func containerResources(c corev1.Container, ranges []corev1.LimitRange) (corev1.ResourceList, corev1.ResourceList, []Finding) {
+	requests := c.Resources.Requests.DeepCopy()
+	limits := c.Resources.Limits.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	if limits == nil {
+		limits = corev1.ResourceList{}
+	}
+
+	var findings []Finding
+	for _, lr := range ranges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for name, q := range item.Default {
+				if _, ok := limits[name]; !ok {
+					limits[name] = q
+				}
+			}
+			for name, q := range item.DefaultRequest {
+				if _, ok := requests[name]; !ok {
+					requests[name] = q
+				}
+			}
+		}
+	}
+	for name, q := range limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = q
+		}
+	}
+
+	for _, lr := range ranges {
+		source := "LimitRange " + lr.Name
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for name, max := range item.Max {
+				if lim, ok := limits[name]; ok && lim.Cmp(max) > 0 {
+					findings = append(findings, Finding{Source: source, Message: fmt.Sprintf("container %s has a %s limit of %s, over the maximum of %s", c.Name, name, lim.String(), max.String())})
+				} else if !ok {
+					findings = append(findings, Finding{Source: source, Message: fmt.Sprintf("container %s needs a %s limit (maximum %s)", c.Name, name, max.String())})
+				}
+			}
+			for name, min := range item.Min {
+				if req, ok := requests[name]; ok && req.Cmp(min) < 0 {
+					findings = append(findings, Finding{Source: source, Message: fmt.Sprintf("container %s requests %s %s, under the minimum of %s", c.Name, req.String(), name, min.String())})
+				} else if !ok {
+					findings = append(findings, Finding{Source: source, Message: fmt.Sprintf("container %s needs a %s request (minimum %s)", c.Name, name, min.String())})
+				}
+			}
+		}
+	}
+	return requests, limits, findings
+}
+
+// addResources adds src to dst
+func addResources(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		total := dst[name]
+		total.Add(q)
+		dst[name] = total
+	}
+}
+
+// maxResources raises dst to src where src is larger
+func maxResources(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		if cur, ok := dst[name]; !ok || q.Cmp(cur) > 0 {
+			dst[name] = q
+		}
+	}
+}
+
+// multiply returns q times n
+func multiply(q resource.Quantity, n int64) resource.Quantity {
+	if q.Format == resource.DecimalSI && q.MilliValue()%1000 != 0 {
+		return *resource.NewMilliQuantity(q.MilliValue()*n, q.Format)
+	}
+	return *resource.NewQuantity(q.Value()*n, q.Format)
+}
@@ -0,0 +1,125 @@
+// Package progress shows spinners and progress bars with the elapsed time on
+// stderr, so slow operations on big clusters don't look hung.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	// showAfter delays indicators so fast operations don't flash one
+	showAfter = 300 * time.Millisecond
+	// tick is the redraw interval
+	tick = 100 * time.Millisecond
+	// barWidth is the number of cells of a progress bar
+	barWidth = 30
+)
+
+var frames = []string{"|", "/", "-", "\\"}
+
+var (
+	// enabled is whether indicators are drawn; set with Enable
+	enabled = term.IsTerminal(int(os.Stderr.Fd()))
+
+	out io.Writer = os.Stderr
+)
+
+// Enable turns indicators on or off. They are only ever drawn when stderr is a
+// terminal, so piped and redirected output stays clean.
+func Enable(on bool) {
+	enabled = on && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// Indicator is a running spinner or progress bar
+type Indicator struct {
+	label string
+	total int
+
+	mu    sync.Mutex
+	done  int
+	start time.Time
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// Start shows a spinner with label until Stop is called
+func Start(label string) *Indicator {
+	return start(label, 0)
+}
+
+// StartBar shows a progress bar with label for total steps until Stop is called
+func StartBar(label string, total int) *Indicator {
+	return start(label, total)
+}
+
+func start(label string, total int) *Indicator {
+	ind := &Indicator{label: label, total: total, start: time.Now(), stop: make(chan struct{})}
+	if !enabled {
+		return ind
+	}
+
+	ind.wg.Add(1)
+	go func() {
+		defer ind.wg.Done()
+		timer := time.NewTimer(showAfter)
+		defer timer.Stop()
+		select {
+		case <-ind.stop:
+			return
+		case <-timer.C:
+		}
+
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			ind.draw(frame)
+			select {
+			case <-ind.stop:
+				fmt.Fprint(out, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return ind
+}
+
+// Increment advances a progress bar by one step. It is safe for concurrent use.
+func (ind *Indicator) Increment() {
+	ind.mu.Lock()
+	ind.done++
+	ind.mu.Unlock()
+}
+
+// Stop removes the indicator. It is safe to call more than once.
+func (ind *Indicator) Stop() {
+	select {
+	case <-ind.stop:
+	default:
+		close(ind.stop)
+	}
+	ind.wg.Wait()
+}
+
+// draw redraws the indicator line
+func (ind *Indicator) draw(frame int) {
+	elapsed := time.Since(ind.start).Truncate(100 * time.Millisecond)
+	if ind.total == 0 {
+		fmt.Fprintf(out, "\r\033[K%s %s (%s)", frames[frame%len(frames)], ind.label, elapsed)
+		return
+	}
+
+	ind.mu.Lock()
+	done := ind.done
+	ind.mu.Unlock()
+	filled := barWidth * done / ind.total
+	fmt.Fprintf(out, "\r\033[K%s [%s%s] %d/%d (%s)", ind.label,
+		strings.Repeat("#", filled), strings.Repeat("-", barWidth-filled), done, ind.total, elapsed)
+}
@@ -0,0 +1,231 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+	"sigs.k8s.io/yaml"
+)
+
+// AnswerPrompter answers questions from a map of question keys to answers, for
+// scripted runs and tests of interactive flows. Questions without an answer get
+// their default, as if Enter was pressed; the ones that must be answered and
+// have no default end the run, as do answers that aren't valid.
+//
+// Answers to repeated questions, such as the items of lists, can be given as a
+// list under the key without the index: spec.args: [a, b] answers spec.args[0]
+// and spec.args[1], and leaves spec.args[2] empty, which ends the list.
+//
+// With a fallback (see AskMissing), the questions without a valid answer are
+// asked by the fallback instead, for replaying answers in a terminal.
+type AnswerPrompter struct {
+	answers  map[string]interface{}
+	asked    map[string]bool
+	rejected map[string]bool // Answers to ask the fallback for instead
+	fallback Prompter
+}
+
+// indexedKey matches the key of a repeated question, e.g. spec.args[2]
+var indexedKey = regexp.MustCompile(`^(.*)\[(\d+)\]$`)
+
+// NewAnswerPrompter creates a Prompter answering from answers
+func NewAnswerPrompter(answers map[string]interface{}) *AnswerPrompter {
+	return &AnswerPrompter{answers: answers, asked: make(map[string]bool), rejected: make(map[string]bool)}
+}
+
+// AskMissing makes p ask the questions that have no answer, or whose answer
+// isn't valid, instead of taking the default or ending the run
+func (a *AnswerPrompter) AskMissing(p Prompter) {
+	a.fallback = p
+}
+
+// LoadAnswers reads answers from a YAML or JSON file mapping question keys to
+// answers (e.g., spec.replicas: 3)
+func LoadAnswers(path string) (*AnswerPrompter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("failed to read answers: %w", err)
+	}
+	var answers map[string]interface{}
+	if err := yaml.Unmarshal(data, &answers); err != nil {
+		return nil, i18n.Errorf("invalid answers in %s: %w", path, err)
+	}
+	if answers == nil {
+		answers = make(map[string]interface{})
+	}
+	return NewAnswerPrompter(answers), nil
+}
+
+// Unused returns the keys of the answers no question was asked for, sorted,
+// which are typically misspelled or for fields given with --set
+func (a *AnswerPrompter) Unused() []string {
+	var keys []string
+	for key := range a.answers {
+		if !a.asked[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// answer returns the answer to the question with key
+func (a *AnswerPrompter) answer(key string) (interface{}, bool) {
+	if a.rejected[key] {
+		return nil, false
+	}
+	if val, ok := a.answers[key]; ok {
+		a.asked[key] = true
+		return val, true
+	}
+	m := indexedKey.FindStringSubmatch(key)
+	if m == nil {
+		return nil, false
+	}
+	list, ok := a.answers[m[1]].([]interface{})
+	if !ok || a.rejected[m[1]] {
+		return nil, false
+	}
+	a.asked[m[1]] = true
+	i, _ := strconv.Atoi(m[2])
+	if i >= len(list) {
+		// The list is complete, even when the fallback could ask for more
+		return "", true
+	}
+	return list[i], true
+}
+
+// reject makes the fallback ask the question with key, whose answer turned out
+// not to be valid. It reports false without a fallback.
+func (a *AnswerPrompter) reject(key string) bool {
+	if a.fallback == nil {
+		return false
+	}
+	a.rejected[key] = true
+	return true
+}
+
+// invalid fails with msg, or shows it and asks the fallback for the question
+func (a *AnswerPrompter) invalid(key, msg string) bool {
+	if !a.reject(key) {
+		return false
+	}
+	fmt.Println(i18n.T("  %v, try again", msg))
+	return true
+}
+
+// value implements valueSource
+func (a *AnswerPrompter) value(key string) (interface{}, bool) {
+	return a.answer(key)
+}
+
+// Select implements Prompter, choosing the item equal to the answer (ignoring case)
+func (a *AnswerPrompter) Select(key string, sel promptui.Select) (int, string, error) {
+	items, ok := sel.Items.([]string)
+	if !ok {
+		return -1, "", &answerError{i18n.T("%s can't be answered by scripted answers", key)}
+	}
+	label := fmt.Sprint(sel.Label)
+
+	val, ok := a.answer(key)
+	if !ok && a.fallback != nil {
+		return a.fallback.Select(key, sel)
+	}
+	answer := answerString(val)
+	if answer == "" {
+		if sel.CursorPos >= 0 && sel.CursorPos < len(items) {
+			fmt.Printf("%s: %s\n", label, items[sel.CursorPos])
+			return sel.CursorPos, items[sel.CursorPos], nil
+		}
+		return -1, "", &answerError{i18n.T("no answer for %s", key)}
+	}
+	for i, item := range items {
+		if strings.EqualFold(item, answer) {
+			fmt.Printf("%s: %s\n", label, item)
+			return i, item, nil
+		}
+	}
+	msg := i18n.T("invalid answer for %s: %q is not one of %s", key, answer, strings.Join(items, ", "))
+	if a.invalid(key, msg) {
+		return a.fallback.Select(key, sel)
+	}
+	return -1, "", &answerError{msg}
+}
+
+// Prompt implements Prompter. Confirmations are accepted by yes, y or true.
+func (a *AnswerPrompter) Prompt(key string, p promptui.Prompt) (string, error) {
+	label := fmt.Sprint(p.Label)
+	val, ok := a.answer(key)
+	if !ok && a.fallback != nil {
+		return a.fallback.Prompt(key, p)
+	}
+
+	if p.IsConfirm {
+		switch strings.ToLower(answerString(val)) {
+		case "y", "yes", "true":
+			fmt.Printf("%s: %s\n", label, i18n.T("yes"))
+			return "y", nil
+		}
+		return "", promptui.ErrAbort
+	}
+
+	input := answerString(val)
+	if input == "" {
+		input = p.Default
+	}
+	if p.Validate != nil {
+		if err := p.Validate(input); err != nil {
+			if !ok {
+				return "", &answerError{i18n.T("no answer for %s (%v)", key, err)}
+			}
+			msg := i18n.T("invalid answer for %s: %v", key, err)
+			if a.invalid(key, msg) {
+				return a.fallback.Prompt(key, p)
+			}
+			return "", &answerError{msg}
+		}
+	}
+	fmt.Printf("%s: %s\n", label, input)
+	return input, nil
+}
+
+// answerString returns an answer as the text typed for it
+func answerString(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(val)
+}
+
+// answerError is a scripted answer that is missing or invalid. It ends the
+// run like interrupting a prompt, with its own message.
+type answerError struct {
+	msg string
+}
+
+// Error implements error
+func (e *answerError) Error() string {
+	return e.msg
+}
+
+// Is makes the error match promptui.ErrInterrupt
+func (e *answerError) Is(target error) bool {
+	return target == promptui.ErrInterrupt
+}
@@ -0,0 +1,388 @@
+package prompt_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/client/clienttest"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	"github.com/manifoldco/promptui"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// deploymentSchema returns the schema of apps/v1 Deployments
+func deploymentSchema(t *testing.T) *client.ResourceSchema {
+	t.Helper()
+	c := clienttest.NewClient(clienttest.Options{})
+	s, err := c.GetResourceSchema(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// queueSchema returns the schema of a custom resource with scalar and list fields
+func queueSchema() *client.ResourceSchema {
+	minimum := float64(1)
+	return &client.ResourceSchema{
+		GVK: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Queue"},
+		Fields: []client.FieldSchema{{
+			Path:     "spec",
+			Name:     "spec",
+			Type:     "object",
+			Required: true,
+			Properties: []client.FieldSchema{
+				{Path: "spec.weight", Name: "weight", Type: "integer", Required: true, Constraints: client.Constraints{Minimum: &minimum}},
+				{Path: "spec.paused", Name: "paused", Type: "boolean"},
+				{Path: "spec.topics", Name: "topics", Type: "array", Items: &client.FieldSchema{Type: "string"}},
+			},
+		}},
+	}
+}
+
+// answer makes answers answer the questions until the test ends
+func answer(t *testing.T, answers map[string]interface{}) *prompt.AnswerPrompter {
+	t.Helper()
+	p := prompt.NewAnswerPrompter(answers)
+	prompt.SetPrompter(p)
+	t.Cleanup(func() { prompt.SetPrompter(nil) })
+	return p
+}
+
+func TestAnswerPrompter(t *testing.T) {
+	p := answer(t, map[string]interface{}{
+		"metadata.name": "orders",
+		"spec.weight":   float64(2),
+		"spec.topics":   []interface{}{"created", "paid"},
+		"spec.priority": "high",
+	})
+
+	values, err := prompt.CollectFieldValues(queueSchema(), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values.Name != "orders" {
+		t.Errorf("name = %q, want orders", values.Name)
+	}
+	if values.Values["spec.weight"] != int64(2) {
+		t.Errorf("spec.weight = %#v, want 2", values.Values["spec.weight"])
+	}
+	// Unanswered questions get their default, as if Enter was pressed
+	if values.Values["spec.paused"] != false {
+		t.Errorf("spec.paused = %#v, want false", values.Values["spec.paused"])
+	}
+	topics, _ := values.Values["spec.topics"].([]interface{})
+	if !slices.Equal(topics, []interface{}{"created", "paid"}) {
+		t.Errorf("spec.topics = %#v, want [created paid]", values.Values["spec.topics"])
+	}
+	if unused := p.Unused(); !slices.Equal(unused, []string{"spec.priority"}) {
+		t.Errorf("Unused() = %v, want [spec.priority]", unused)
+	}
+}
+
+func TestAnswerPrompterContainerBuilder(t *testing.T) {
+	container := "spec.template.spec.containers[0]"
+	p := answer(t, map[string]interface{}{
+		"metadata.name":        "web",
+		container + ".image":   "nginx:1.25",
+		container + ".env":     []interface{}{"LOG_LEVEL=debug", "MODE=fast"},
+		container + ".ports":   []interface{}{float64(8080)},
+		"spec.template.unused": "x",
+	})
+
+	values, err := prompt.CollectFieldValues(deploymentSchema(t), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"metadata.name":                       "web",
+		container + ".name":                   "web",
+		container + ".image":                  "nginx:1.25",
+		container + ".env[0].name":            "LOG_LEVEL",
+		container + ".env[0].value":           "debug",
+		container + ".env[1].name":            "MODE",
+		container + ".env[1].value":           "fast",
+		container + ".ports[0].containerPort": int64(8080),
+		"spec.selector.matchLabels.app":       "web",
+		"spec.template.metadata.labels.app":   "web",
+	}
+	for path, val := range want {
+		if values.Values[path] != val {
+			t.Errorf("%s = %#v, want %#v", path, values.Values[path], val)
+		}
+	}
+	if source := values.Sources["spec.selector.matchLabels.app"]; source != prompt.SourceDefault {
+		t.Errorf("the selector's source = %q, want %q", source, prompt.SourceDefault)
+	}
+	if source := values.Sources[container+".image"]; source != prompt.SourcePrompt {
+		t.Errorf("the image's source = %q, want %q", source, prompt.SourcePrompt)
+	}
+	if _, ok := values.Values[container+".env[2].name"]; ok {
+		t.Error("the list of env vars should end after the answers")
+	}
+	if unused := p.Unused(); !slices.Equal(unused, []string{"spec.template.unused"}) {
+		t.Errorf("Unused() = %v, want [spec.template.unused]", unused)
+	}
+}
+
+func TestAnswerPrompterErrors(t *testing.T) {
+	container := "spec.template.spec.containers[0]"
+	deployments := deploymentSchema(t)
+	tests := []struct {
+		name    string
+		schema  *client.ResourceSchema
+		answers map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "missing required answer",
+			schema:  deployments,
+			answers: map[string]interface{}{"metadata.name": "web"},
+			wantErr: "no answer for " + container + ".image",
+		},
+		{
+			name:    "invalid name",
+			schema:  deployments,
+			answers: map[string]interface{}{"metadata.name": "Web_1", container + ".image": "nginx"},
+			wantErr: "invalid answer for metadata.name",
+		},
+		{
+			name:    "invalid list item",
+			schema:  deployments,
+			answers: map[string]interface{}{"metadata.name": "web", container + ".image": "nginx", container + ".env": []interface{}{"DEBUG"}},
+			wantErr: "invalid answer for " + container + ".env[0]",
+		},
+		{
+			name:    "invalid integer",
+			schema:  queueSchema(),
+			answers: map[string]interface{}{"metadata.name": "orders", "spec.weight": "heavy"},
+			wantErr: "invalid answer for spec.weight: must be integer",
+		},
+		{
+			name:    "below minimum",
+			schema:  queueSchema(),
+			answers: map[string]interface{}{"metadata.name": "orders", "spec.weight": float64(0)},
+			wantErr: "invalid answer for spec.weight: must be at least 1",
+		},
+		{
+			name:    "not a choice",
+			schema:  queueSchema(),
+			answers: map[string]interface{}{"metadata.name": "orders", "spec.weight": float64(1), "spec.paused": "maybe"},
+			wantErr: `invalid answer for spec.paused: "maybe" is not one of true, false`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer(t, tt.answers)
+			_, err := prompt.CollectFieldValues(tt.schema, "", nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CollectFieldValues() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnswerPrompterConfirm(t *testing.T) {
+	answer(t, map[string]interface{}{"create-anyway": true, "delete": "no"})
+	if !prompt.Confirm("create-anyway", "Create anyway") {
+		t.Error("create-anyway should be confirmed")
+	}
+	if prompt.Confirm("delete", "Delete") {
+		t.Error("delete should be declined")
+	}
+	if prompt.Confirm("preferred-version", "Create v1 instead") {
+		t.Error("a confirmation without an answer should be declined")
+	}
+}
+
+func TestAnswerPrompterAskMissing(t *testing.T) {
+	// The fallback stands in for the user answering in a terminal
+	p := answer(t, map[string]interface{}{
+		"metadata.name": "orders",
+		"spec.weight":   float64(0),
+	})
+	p.AskMissing(prompt.NewAnswerPrompter(map[string]interface{}{
+		"spec.weight": float64(3),
+		"spec.topics": []interface{}{"created"},
+	}))
+	prompt.RecordAnswers()
+
+	values, err := prompt.CollectFieldValues(queueSchema(), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The invalid answer and the missing ones are asked instead
+	if values.Values["spec.weight"] != int64(3) {
+		t.Errorf("spec.weight = %#v, want 3", values.Values["spec.weight"])
+	}
+	topics, _ := values.Values["spec.topics"].([]interface{})
+	if !slices.Equal(topics, []interface{}{"created"}) {
+		t.Errorf("spec.topics = %#v, want [created]", values.Values["spec.topics"])
+	}
+
+	recorded := prompt.RecordedAnswers()
+	if recorded["metadata.name"] != "orders" || recorded["spec.weight"] != "3" {
+		t.Errorf("recorded %v, want metadata.name orders and spec.weight 3", recorded)
+	}
+	if topics, _ := recorded["spec.topics"].([]interface{}); !slices.Equal(topics, []interface{}{"created"}) {
+		t.Errorf("recorded spec.topics = %#v, want [created]", recorded["spec.topics"])
+	}
+}
+
+func TestAnswerPrompterChooseMany(t *testing.T) {
+	answer(t, map[string]interface{}{
+		"spec.accessModes": []interface{}{"readwritemany", "ReadWriteOnce"},
+		"modes":            "ReadOnlyMany, ReadWriteMany",
+		"invalid":          []interface{}{"ReadWriteSometimes"},
+	})
+	items := []string{"ReadWriteOnce", "ReadOnlyMany", "ReadWriteMany"}
+
+	picked, err := prompt.ChooseMany("spec.accessModes", "Access modes", items, nil)
+	if err != nil || !slices.Equal(picked, []int{0, 2}) {
+		t.Errorf("ChooseMany() = %v, %v, want [0 2]", picked, err)
+	}
+	picked, err = prompt.ChooseMany("modes", "Access modes", items, nil)
+	if err != nil || !slices.Equal(picked, []int{1, 2}) {
+		t.Errorf("ChooseMany() of a comma-separated answer = %v, %v, want [1 2]", picked, err)
+	}
+	// Without an answer, the items selected to start with are taken
+	picked, err = prompt.ChooseMany("unanswered", "Access modes", items, []bool{true})
+	if err != nil || !slices.Equal(picked, []int{0}) {
+		t.Errorf("ChooseMany() without an answer = %v, %v, want [0]", picked, err)
+	}
+	if _, err := prompt.ChooseMany("invalid", "Access modes", items, nil); err == nil || !strings.Contains(err.Error(), "ReadWriteSometimes") {
+		t.Errorf("ChooseMany() error = %v, want the invalid item", err)
+	}
+}
+
+func TestAnswerPrompterPickOrEnter(t *testing.T) {
+	answer(t, map[string]interface{}{"service": "billing", "port": "http"})
+	services := []string{"api", "web"}
+
+	// Scripted answers give the value, listed or not
+	if got, err := prompt.PickOrEnter("service", "Backend service", services, "", nil); err != nil || got != "billing" {
+		t.Errorf("PickOrEnter() = %q, %v, want billing", got, err)
+	}
+	if got, err := prompt.PickOrEnter("missing", "Backend service", services, "web", nil); err != nil || got != "web" {
+		t.Errorf("PickOrEnter() without an answer = %q, %v, want the default web", got, err)
+	}
+	isNumber := func(s string) error {
+		if strings.Trim(s, "0123456789") != "" {
+			return errors.New("not a number")
+		}
+		return nil
+	}
+	if _, err := prompt.PickOrEnter("port", "Port", nil, "", isNumber); err == nil {
+		t.Error("PickOrEnter() should check the answer")
+	}
+}
+
+func TestAnswerPrompterConditions(t *testing.T) {
+	listener := &client.ResourceSchema{
+		GVK: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Listener"},
+		Fields: []client.FieldSchema{{
+			Path:     "spec",
+			Name:     "spec",
+			Type:     "object",
+			Required: true,
+			Properties: []client.FieldSchema{
+				{Path: "spec.certificate", Name: "certificate", Type: "string", Required: true},
+				{Path: "spec.port", Name: "port", Type: "integer"},
+				{Path: "spec.tlsEnabled", Name: "tlsEnabled", Type: "boolean"},
+			},
+		}},
+	}
+	when, err := prompt.ParseExpression("spec.tlsEnabled == true && spec.port != 80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt.SetConditions([]prompt.Condition{{Path: "spec.certificate", When: when}})
+	t.Cleanup(func() { prompt.SetConditions(nil) })
+
+	// The certificate comes before the values its condition reads, but is asked after them
+	p := answer(t, map[string]interface{}{"spec.certificate": "pem", "spec.port": float64(443), "spec.tlsEnabled": true})
+	values, err := prompt.CollectFieldValues(listener, "secure", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Values["spec.certificate"] != "pem" {
+		t.Errorf("spec.certificate = %#v, want pem", values.Values["spec.certificate"])
+	}
+
+	p = answer(t, map[string]interface{}{"spec.certificate": "pem", "spec.port": float64(80), "spec.tlsEnabled": true})
+	values, err = prompt.CollectFieldValues(listener, "plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := values.Values["spec.certificate"]; ok {
+		t.Errorf("spec.certificate = %#v, want it not asked for", values.Values["spec.certificate"])
+	}
+	if unused := p.Unused(); !slices.Equal(unused, []string{"spec.certificate"}) {
+		t.Errorf("Unused() = %v, want [spec.certificate]", unused)
+	}
+
+	for _, invalid := range []string{"spec.a ==", "spec.a && ", "(spec.a", "spec.a = 1", `spec.a == "x`} {
+		if _, err := prompt.ParseExpression(invalid); err == nil {
+			t.Errorf("ParseExpression(%q) succeeded, want an error", invalid)
+		}
+	}
+}
+
+// countingPrompter counts the questions asked by key before answering them
+type countingPrompter struct {
+	prompt.Prompter
+	asked map[string]int
+}
+
+func (c *countingPrompter) Select(key string, sel promptui.Select) (int, string, error) {
+	c.asked[key]++
+	return c.Prompter.Select(key, sel)
+}
+
+func (c *countingPrompter) Prompt(key string, p promptui.Prompt) (string, error) {
+	c.asked[key]++
+	return c.Prompter.Prompt(key, p)
+}
+
+func TestLifecycleFieldsAskedOnce(t *testing.T) {
+	bucket := &client.ResourceSchema{
+		GVK: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Bucket"},
+		Fields: []client.FieldSchema{{
+			Path:     "spec",
+			Name:     "spec",
+			Type:     "object",
+			Required: true,
+			Properties: []client.FieldSchema{
+				{Path: "spec.deletionPolicy", Name: "deletionPolicy", Type: "string"},
+				{Path: "spec.region", Name: "region", Type: "string"},
+			},
+		}},
+	}
+
+	// Skipping the lifecycle question leaves the field unset without asking again
+	p := &countingPrompter{
+		Prompter: prompt.NewAnswerPrompter(map[string]interface{}{"spec.deletionPolicy": "", "spec.region": "eu"}),
+		asked:    map[string]int{},
+	}
+	prompt.SetPrompter(p)
+	t.Cleanup(func() { prompt.SetPrompter(nil) })
+
+	values, err := prompt.CollectFieldValues(bucket, "logs", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := p.asked["spec.deletionPolicy"]; n != 1 {
+		t.Errorf("spec.deletionPolicy asked %d times, want 1", n)
+	}
+	if _, ok := values.Values["spec.deletionPolicy"]; ok {
+		t.Errorf("spec.deletionPolicy = %#v, want it unset", values.Values["spec.deletionPolicy"])
+	}
+	if values.Values["spec.region"] != "eu" {
+		t.Errorf("spec.region = %#v, want eu", values.Values["spec.region"])
+	}
+}
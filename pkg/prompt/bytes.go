@@ -0,0 +1,76 @@
+package prompt
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+)
+
+// encodeBytes is whether values of binary (format: byte) fields are given as
+// plain text or @file and encoded here, rather than already in base64
+var encodeBytes = true
+
+// DisableByteEncoding takes the values of binary fields as already encoded
+// in base64
+func DisableByteEncoding() {
+	encodeBytes = false
+}
+
+// isBytes reports whether field holds binary data, base64-encoded in manifests
+func isBytes(field *client.FieldSchema) bool {
+	return field.Type == "string" && field.Format == "byte"
+}
+
+// encodeByteValue encodes val, the value of the binary field at path, in base64.
+// "@file" encodes the contents of file.
+func encodeByteValue(path string, val interface{}) (interface{}, error) {
+	if !encodeBytes {
+		return val, nil
+	}
+	// --set parses "123" and "true" as numbers and booleans
+	s := fmt.Sprintf("%v", val)
+	data, err := readBytesValue(s)
+	if err != nil {
+		return nil, i18n.Errorf("invalid value for %s: %w", path, err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// readBytesValue returns the bytes of a text or @file value
+func readBytesValue(s string) ([]byte, error) {
+	if file, ok := strings.CutPrefix(s, "@"); ok && file != "" {
+		return os.ReadFile(file)
+	}
+	return []byte(s), nil
+}
+
+// promptBytes prompts for the value of a binary field as text or @file and
+// returns it encoded in base64. A default, already encoded, is kept as it is.
+func promptBytes(key, label string, field client.FieldSchema, defaultVal interface{}) (string, error) {
+	if !encodeBytes {
+		return promptString(key, label+i18n.T(" (base64)"), defaultVal, field.Required, nil)
+	}
+	defaultStr := ""
+	if defaultVal != nil {
+		defaultStr = fmt.Sprintf("%v", defaultVal)
+	}
+
+	label += i18n.T(" (text or @file, encoded in base64)")
+	check := func(s string) error {
+		_, err := readBytesValue(s)
+		return err
+	}
+	result, err := promptString(key, label, defaultVal, field.Required, check)
+	if err != nil || result == "" || (defaultStr != "" && result == defaultStr) {
+		return result, err
+	}
+	data, err := readBytesValue(result)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
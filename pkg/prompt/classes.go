@@ -0,0 +1,55 @@
+package prompt
+
+import (
+	"fmt"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// Class is a class a resource can be of, such as the StorageClass of a claim
+// or the IngressClass of an Ingress
+type Class struct {
+	Name       string
+	Controller string // The provisioner or controller implementing the class
+	Default    bool   // Annotated as the default class of the cluster
+}
+
+// PickClass lets the user choose one of classes, starting at the default
+// class. Scripted answers name the class.
+func PickClass(key, label string, classes []Class) (string, error) {
+	if source, ok := prompter.(valueSource); ok {
+		if answer, ok := source.value(key); ok {
+			name := answerString(answer)
+			recordAnswer(key, name)
+			return name, nil
+		}
+	}
+
+	items := make([]string, len(classes))
+	cursor := 0
+	for i, class := range classes {
+		items[i] = fmt.Sprintf("%s (%s)", class.Name, class.Controller)
+		if class.Default {
+			items[i] = i18n.T("%s (%s, default)", class.Name, class.Controller)
+			cursor = i
+		}
+	}
+	sel := promptui.Select{
+		Label:             label,
+		Items:             items,
+		Size:              15,
+		CursorPos:         cursor,
+		Searcher:          containsSearcher(items),
+		StartInSearchMode: len(items) > 15,
+	}
+	i, _, err := prompter.Select(key, sel)
+	if err != nil {
+		if IsInterrupt(err) {
+			return "", interrupted(err)
+		}
+		return "", err
+	}
+	recordAnswer(key, classes[i].Name)
+	return classes[i].Name, nil
+}
@@ -0,0 +1,32 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/manifoldco/promptui"
+)
+
+// ColorDisabled reports whether the environment asks for plain output: NO_COLOR
+// is set (see https://no-color.org) or the terminal is dumb
+func ColorDisabled() bool {
+	return os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb"
+}
+
+// DisableColor renders prompts without colors or text styles. It replaces the
+// styling functions of promptui's templates, ours included, and its icons.
+func DisableColor() {
+	for name := range promptui.FuncMap {
+		promptui.FuncMap[name] = plain
+	}
+	promptui.IconInitial = "?"
+	promptui.IconGood = "✔"
+	promptui.IconWarn = "⚠"
+	promptui.IconBad = "✗"
+	promptui.IconSelect = ">"
+}
+
+// plain is a template styling function that leaves text unstyled
+func plain(v interface{}) string {
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,307 @@
+package prompt
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+)
+
+// Condition makes asking for the field at Path, and the fields within it,
+// depend on the values collected before it, e.g. prompting for spec.tls only
+// when spec.tlsEnabled == true
+type Condition struct {
+	Path string
+	When *Expression
+}
+
+// conditions are the conditions of the fields of the resource type being
+// created (see SetConditions)
+var conditions []Condition
+
+// SetConditions makes the prompts for the fields of the schema skip the paths
+// of conditions, and the fields within them, whose expressions don't hold for
+// the values collected so far. Conditional fields are asked after the other
+// fields of their object, whose values their expressions usually read, and
+// aren't checked to be set when their expressions don't hold.
+func SetConditions(c []Condition) {
+	conditions = c
+}
+
+// conditionsHold reports whether the conditions of the field at path, and of
+// the objects it is in, hold for values
+func conditionsHold(path string, values map[string]interface{}) bool {
+	for _, c := range conditions {
+		if coversPath(c.Path, path) && !c.When.Eval(values) {
+			return false
+		}
+	}
+	return true
+}
+
+// conditionalLast returns fields with the ones that have conditions of their
+// own after the others, so the values the conditions read are collected first
+func conditionalLast(fields []client.FieldSchema) []client.FieldSchema {
+	if len(conditions) == 0 {
+		return fields
+	}
+	sorted := append([]client.FieldSchema{}, fields...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return !conditional(sorted[i].Path) && conditional(sorted[j].Path)
+	})
+	return sorted
+}
+
+// conditional reports whether a condition applies to the field at path itself
+func conditional(path string) bool {
+	for _, c := range conditions {
+		if strings.TrimSuffix(c.Path, ".*") == path {
+			return true
+		}
+	}
+	return false
+}
+
+// coversPath reports whether the condition path, which may end in .* for the
+// fields within an object, applies to the field at path
+func coversPath(conditionPath, path string) bool {
+	conditionPath = strings.TrimSuffix(conditionPath, ".*")
+	return path == conditionPath || strings.HasPrefix(path, conditionPath+".") || strings.HasPrefix(path, conditionPath+"[")
+}
+
+// Expression is a condition on collected values, e.g.
+//
+//	spec.tlsEnabled == true
+//	!spec.storage.existingClaim
+//	spec.mode == "cluster" && (spec.replicas != 1 || spec.ha)
+//
+// A field path alone holds when the field is set to a value other than false,
+// 0 or "". == and != compare the value of a path to a literal: true, false, a
+// number, or a string, quoted or not. Unset fields equal nothing, so != holds
+// for them. ! negates, && and || combine expressions, and parentheses group.
+type Expression struct {
+	source string
+	eval   evalFunc
+}
+
+// String returns the expression as written
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression for values, collected values keyed by path
+func (e *Expression) Eval(values map[string]interface{}) bool {
+	return e.eval(values)
+}
+
+// ParseExpression parses a condition expression (see Expression)
+func ParseExpression(source string) (*Expression, error) {
+	tokens, err := tokenizeExpression(source)
+	if err != nil {
+		return nil, i18n.Errorf("invalid expression %q: %w", source, err)
+	}
+	p := &expressionParser{tokens: tokens}
+	eval, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = i18n.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, i18n.Errorf("invalid expression %q: %w", source, err)
+	}
+	return &Expression{source: source, eval: eval}, nil
+}
+
+// expressionToken is an operator, a parenthesis, a path or a literal of an
+// expression. Quoted strings are literals only.
+type expressionToken struct {
+	text   string
+	quoted bool
+}
+
+// expressionOperators are the operators of expressions, longest first
+var expressionOperators = []string{"==", "!=", "&&", "||", "!", "(", ")"}
+
+// tokenizeExpression splits source into tokens
+func tokenizeExpression(source string) ([]expressionToken, error) {
+	var tokens []expressionToken
+	rest := source
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			return tokens, nil
+		}
+		if quote := rest[0]; quote == '"' || quote == '\'' {
+			end := strings.IndexByte(rest[1:], quote)
+			if end < 0 {
+				return nil, i18n.Errorf("unterminated string")
+			}
+			tokens = append(tokens, expressionToken{text: rest[1 : end+1], quoted: true})
+			rest = rest[end+2:]
+			continue
+		}
+		operator := ""
+		for _, op := range expressionOperators {
+			if strings.HasPrefix(rest, op) {
+				operator = op
+				break
+			}
+		}
+		if operator != "" {
+			tokens = append(tokens, expressionToken{text: operator})
+			rest = rest[len(operator):]
+			continue
+		}
+		end := strings.IndexFunc(rest, func(r rune) bool {
+			return unicode.IsSpace(r) || strings.ContainsRune("=!&|()\"'", r)
+		})
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, i18n.Errorf("unexpected %q", rest[:1])
+		}
+		tokens = append(tokens, expressionToken{text: rest[:end]})
+		rest = rest[end:]
+	}
+}
+
+// expressionParser parses tokens by recursive descent, || binding loosest
+type expressionParser struct {
+	tokens []expressionToken
+	pos    int
+}
+
+// evalFunc evaluates a parsed expression for collected values
+type evalFunc func(values map[string]interface{}) bool
+
+// accept advances past the current token if it is one of the operators ops
+func (p *expressionParser) accept(ops ...string) bool {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *expressionParser) parseOr() (evalFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(values map[string]interface{}) bool { return l(values) || right(values) }
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (evalFunc, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(values map[string]interface{}) bool { return l(values) && right(values) }
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseUnary() (evalFunc, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(values map[string]interface{}) bool { return !operand(values) }, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, i18n.Errorf("missing )")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *expressionParser) parseComparison() (evalFunc, error) {
+	path, ok := p.operand()
+	if !ok || path.quoted {
+		return nil, i18n.Errorf("expected a field path")
+	}
+	negate := false
+	switch {
+	case p.accept("=="):
+	case p.accept("!="):
+		negate = true
+	default:
+		return func(values map[string]interface{}) bool { return truthy(values, path.text) }, nil
+	}
+	literal, ok := p.operand()
+	if !ok {
+		return nil, i18n.Errorf("expected a value after the comparison with %s", path.text)
+	}
+	return func(values map[string]interface{}) bool {
+		val, ok := values[path.text]
+		return (ok && fmt.Sprint(val) == literal.text) != negate
+	}, nil
+}
+
+// operand returns the path or literal at the current token and advances past
+// it. ok is false at operators and at the end.
+func (p *expressionParser) operand() (expressionToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return expressionToken{}, false
+	}
+	t := p.tokens[p.pos]
+	if !t.quoted && slices.Contains(expressionOperators, t.text) {
+		return expressionToken{}, false
+	}
+	p.pos++
+	return t, true
+}
+
+// truthy reports whether the field at path is set, or an object within it,
+// to a value other than false, 0 or ""
+func truthy(values map[string]interface{}, path string) bool {
+	val, ok := values[path]
+	if !ok {
+		return hasValue(values, path)
+	}
+	switch v := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
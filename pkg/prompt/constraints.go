@@ -0,0 +1,112 @@
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+)
+
+// checkConstraints checks val against the pattern, length, range and item
+// count limits of field. The error names the limit that isn't met.
+func checkConstraints(field client.FieldSchema, val interface{}) error {
+	switch v := val.(type) {
+	case []interface{}:
+		if field.MinItems != nil && int64(len(v)) < *field.MinItems {
+			return i18n.Errorf("must have at least %d items", *field.MinItems)
+		}
+		if field.Items != nil {
+			for i, item := range v {
+				if err := checkConstraints(*field.Items, item); err != nil {
+					return fmt.Errorf("[%d]: %w", i, err)
+				}
+			}
+		}
+		return nil
+	case map[string]interface{}, nil:
+		return nil
+	}
+
+	// --set values that look like numbers are strings in string fields
+	if s, ok := val.(string); ok || field.Type == "string" {
+		if !ok {
+			s = fmt.Sprintf("%v", val)
+		}
+		return checkString(field.Constraints, s)
+	}
+	if n, ok := toFloat(val); ok {
+		return checkNumber(field.Constraints, n)
+	}
+	return nil
+}
+
+// checkString checks the pattern and length limits of a string
+func checkString(c client.Constraints, s string) error {
+	if c.Pattern != "" {
+		// Patterns RE2 can't compile (e.g., with lookarounds) are left to the API server
+		if re, err := regexp.Compile(c.Pattern); err == nil && !re.MatchString(s) {
+			return i18n.Errorf("must match the pattern %s", c.Pattern)
+		}
+	}
+	n := int64(utf8.RuneCountInString(s))
+	if c.MinLength != nil && n < *c.MinLength {
+		return i18n.Errorf("must be at least %d characters long", *c.MinLength)
+	}
+	if c.MaxLength != nil && n > *c.MaxLength {
+		return i18n.Errorf("must be at most %d characters long", *c.MaxLength)
+	}
+	return nil
+}
+
+// checkNumber checks the range of a number
+func checkNumber(c client.Constraints, n float64) error {
+	if c.Minimum != nil && n < *c.Minimum {
+		return i18n.Errorf("must be at least %v", *c.Minimum)
+	}
+	if c.Maximum != nil && n > *c.Maximum {
+		return i18n.Errorf("must be at most %v", *c.Maximum)
+	}
+	return nil
+}
+
+// toFloat converts the numbers of parsed values to float64
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// checkSetValues checks the --set values of fields in schema against their
+// constraints. Values of fields the schema doesn't describe aren't checked.
+func checkSetValues(schema *client.ResourceSchema, flagValues map[string]interface{}) error {
+	paths := make([]string, 0, len(flagValues))
+	for path := range flagValues {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		val := flagValues[path]
+		field, ok := schema.FindField(path)
+		if !ok {
+			continue
+		}
+		// An indexed path (args[0]) sets an item, not the array
+		if strings.HasSuffix(path, "]") && field.Items != nil {
+			field = field.Items
+		}
+		if err := checkConstraints(*field, val); err != nil {
+			return i18n.Errorf("invalid value for --set %s: %w", path, err)
+		}
+	}
+	return nil
+}
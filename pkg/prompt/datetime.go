@@ -0,0 +1,152 @@
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+)
+
+// isTime reports whether field holds a time (format: date-time) or a date
+// (format: date)
+func isTime(field *client.FieldSchema) bool {
+	return field.Type == "string" && (field.Format == "date-time" || field.Format == "date")
+}
+
+// Layouts of absolute times, besides RFC 3339, read in the local time zone
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Layouts of the time of day after "today", "tomorrow" and "yesterday"
+var clockLayouts = []string{"15:04", "15:04:05"}
+
+// relativeTime matches offsets from now such as +2h, -30m, +1d and +1w2d3h
+var relativeTime = regexp.MustCompile(`^([+-])(?:(\d+)w)?(?:(\d+)d)?(.*)$`)
+
+// parseTime reads a time given as RFC 3339 or one of localTimeLayouts, as
+// "now", as an offset from now (+2h, -1d), or as a day word with an optional
+// time of day ("tomorrow 9:00", "today", "9:00")
+func parseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	lower := strings.ToLower(s)
+	if lower == "now" {
+		return now, nil
+	}
+	if m := relativeTime.FindStringSubmatch(lower); m != nil {
+		if t, ok := offsetTime(now, m); ok {
+			return t, nil
+		}
+	}
+
+	day, clock, _ := strings.Cut(lower, " ")
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch day {
+	case "today":
+	case "tomorrow":
+		midnight = midnight.AddDate(0, 0, 1)
+	case "yesterday":
+		midnight = midnight.AddDate(0, 0, -1)
+	default:
+		// A time of day alone is today
+		clock = lower
+	}
+	clock = strings.TrimSpace(clock)
+	if clock == "" {
+		return midnight, nil
+	}
+	for _, layout := range clockLayouts {
+		// Set the wall-clock time, which adding it to midnight misses on days
+		// with a DST change
+		if c, err := time.Parse(layout, clock); err == nil {
+			return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), c.Hour(), c.Minute(), c.Second(), 0, now.Location()), nil
+		}
+	}
+	return time.Time{}, i18n.Errorf("%q is not a time, use RFC 3339 (e.g., 2025-05-01T09:00:00Z), now, +2h, -1d or tomorrow 9:00", s)
+}
+
+// offsetTime adds the offset matched by relativeTime to now
+func offsetTime(now time.Time, m []string) (time.Time, bool) {
+	var offset time.Duration
+	if m[4] != "" {
+		d, err := time.ParseDuration(m[4])
+		if err != nil {
+			return time.Time{}, false
+		}
+		offset = d
+	} else if m[2] == "" && m[3] == "" {
+		return time.Time{}, false
+	}
+	weeks, _ := strconv.Atoi(m[2])
+	days, _ := strconv.Atoi(m[3])
+	if m[1] == "-" {
+		return now.AddDate(0, 0, -(7*weeks + days)).Add(-offset), true
+	}
+	return now.AddDate(0, 0, 7*weeks+days).Add(offset), true
+}
+
+// formatTime writes t as the API server reads it: RFC 3339 in UTC for
+// date-time fields (like metav1.Time) and a full date for date fields
+func formatTime(t time.Time, format string) string {
+	if format == "date" {
+		return t.Format(time.DateOnly)
+	}
+	return t.UTC().Truncate(time.Second).Format(time.RFC3339)
+}
+
+// normalizeTimeValue converts val, given for the time field at path, to RFC 3339
+func normalizeTimeValue(path, format string, val interface{}) (interface{}, error) {
+	t, err := parseTime(fmt.Sprintf("%v", val), time.Now())
+	if err != nil {
+		return nil, i18n.Errorf("invalid value for %s: %w", path, err)
+	}
+	return formatTime(t, format), nil
+}
+
+// promptTime prompts for a time, absolute or relative to now, and returns it
+// in RFC 3339. A default is kept as it is.
+func promptTime(key, label string, field client.FieldSchema, defaultVal interface{}) (string, error) {
+	defaultStr := ""
+	if defaultVal != nil {
+		defaultStr = fmt.Sprintf("%v", defaultVal)
+	}
+
+	label += i18n.T(" (e.g., now, +2h, tomorrow 9:00)")
+	check := func(s string) error {
+		t, err := parseTime(s, time.Now())
+		if err != nil {
+			return err
+		}
+		return checkConstraints(field, formatTime(t, field.Format))
+	}
+	result, err := promptString(key, label, defaultVal, field.Required, check)
+	if err != nil || result == "" || (defaultStr != "" && result == defaultStr) {
+		return result, err
+	}
+	t, err := parseTime(result, time.Now())
+	if err != nil {
+		return "", err
+	}
+	value := formatTime(t, field.Format)
+	if value != result {
+		fmt.Println(i18n.T("  %s is %s", result, value))
+	}
+	return value, nil
+}
@@ -0,0 +1,36 @@
+package prompt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+)
+
+func TestParseTimeAcrossDSTChange(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	// Clocks go forward at 02:00 on 2026-03-08 and back at 02:00 on 2026-11-01
+	tests := []struct {
+		now   time.Time
+		input string
+		want  time.Time
+	}{
+		{time.Date(2026, 3, 7, 20, 0, 0, 0, newYork), "tomorrow 9:00", time.Date(2026, 3, 8, 9, 0, 0, 0, newYork)},
+		{time.Date(2026, 3, 8, 1, 0, 0, 0, newYork), "9:00", time.Date(2026, 3, 8, 9, 0, 0, 0, newYork)},
+		{time.Date(2026, 3, 9, 8, 0, 0, 0, newYork), "yesterday 18:30:15", time.Date(2026, 3, 8, 18, 30, 15, 0, newYork)},
+		{time.Date(2026, 11, 1, 1, 0, 0, 0, newYork), "today 9:00", time.Date(2026, 11, 1, 9, 0, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		got, err := prompt.ParseTime(tt.input, tt.now)
+		if err != nil {
+			t.Errorf("ParseTime(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) at %s = %s, want %s", tt.input, tt.now, got, tt.want)
+		}
+	}
+}
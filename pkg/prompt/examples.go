@@ -0,0 +1,126 @@
+package prompt
+
+import (
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+)
+
+// exampleStrings are example values for string fields with well-known names
+var exampleStrings = map[string]string{
+	"image":     "nginx",
+	"namespace": "default",
+	"url":       "https://example.com",
+	"endpoint":  "https://example.com",
+	"host":      "example.com",
+	"hostname":  "example.com",
+	"email":     "user@example.com",
+	"schedule":  "*/5 * * * *",
+	"cron":      "*/5 * * * *",
+	"path":      "/",
+}
+
+// ExampleValues returns representative values for the required fields of a
+// resource (recursively, and spec when it has required fields), keyed by field
+// path: enums take their first value, defaults are kept, well-known fields like
+// image get a realistic value, and other fields "example", 1 or false. The result
+// is a starting manifest for an unfamiliar type that doesn't need prompting.
+func ExampleValues(schema *client.ResourceSchema, name string) map[string]interface{} {
+	values := make(map[string]interface{})
+	if schema == nil {
+		return values
+	}
+	for _, f := range schema.Fields {
+		if f.Name == "metadata" {
+			continue
+		}
+		if f.Required || (f.Name == "spec" && hasRequired(f.Properties)) {
+			addExample(values, f, f.Name, name)
+		}
+	}
+	return values
+}
+
+// addExample adds an example value for field at path, descending into the
+// required fields of objects and list items
+func addExample(values map[string]interface{}, field client.FieldSchema, path, name string) {
+	if len(field.Enum) > 0 {
+		values[path] = field.Enum[0]
+		return
+	}
+	if field.Default != nil {
+		values[path] = field.Default
+		return
+	}
+
+	// A union takes the example of its first variant
+	if field.Type == "" && len(field.Variants) > 0 {
+		variant := field.Variants[0]
+		variant.Name = field.Name
+		addExample(values, variant, path, name)
+		return
+	}
+
+	switch field.Type {
+	case "object":
+		if len(field.Properties) == 0 {
+			// A map: one example entry
+			values[path+".example"] = "example"
+			return
+		}
+		required := field.Properties
+		if len(field.Variants) > 0 {
+			// The fields of the first variant of a union are required too
+			required = append(append([]client.FieldSchema(nil), required...), field.Variants[0].Properties...)
+		}
+		added := false
+		for _, p := range required {
+			if p.Required {
+				addExample(values, p, path+"."+p.Name, name)
+				added = true
+			}
+		}
+		if !added {
+			values[path] = map[string]interface{}{}
+		}
+	case "array":
+		if field.Items == nil {
+			values[path+"[0]"] = "example"
+			return
+		}
+		item := *field.Items
+		item.Name = field.Name
+		addExample(values, item, path+"[0]", name)
+	case "integer", "number":
+		if strings.HasSuffix(strings.ToLower(field.Name), "port") {
+			values[path] = int64(80)
+		} else {
+			values[path] = int64(1)
+		}
+	case "boolean":
+		values[path] = false
+	default:
+		values[path] = exampleString(field.Name, name)
+	}
+}
+
+// exampleString returns an example value for a string field
+func exampleString(fieldName, name string) string {
+	if v, ok := exampleStrings[strings.ToLower(fieldName)]; ok {
+		return v
+	}
+	if fieldName == "name" && name != "" {
+		return name
+	}
+	return "example"
+}
+
+// hasRequired reports whether any of fields is required
+func hasRequired(fields []client.FieldSchema) bool {
+	for _, f := range fields {
+		if f.Required {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,5 @@
+package prompt
+
+// ParseTime exposes parseTime, which reads times relative to a given now, to
+// the tests
+var ParseTime = parseTime
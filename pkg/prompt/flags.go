@@ -1,9 +1,10 @@
 package prompt
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
 )
 
 // ParseSetValues parses --set flag values into a map
@@ -17,14 +18,14 @@ func ParseSetValues(setValues []string) (map[string]interface{}, error) {
 	for _, sv := range setValues {
 		parts := strings.SplitN(sv, "=", 2)
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid --set format: %q (expected key=value)", sv)
+			return nil, i18n.Errorf("invalid --set format: %q (expected key=value)", sv)
 		}
 		
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
 		
 		if key == "" {
-			return nil, fmt.Errorf("empty key in --set: %q", sv)
+			return nil, i18n.Errorf("empty key in --set: %q", sv)
 		}
 		
 		// Parse the value to appropriate type
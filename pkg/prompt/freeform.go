@@ -0,0 +1,152 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+	"sigs.k8s.io/yaml"
+)
+
+// Items of the free-form field menu
+const (
+	freeformSkip   = "(skip)"
+	freeformInline = "Type YAML or JSON here"
+	freeformEditor = "Open an editor"
+)
+
+// editFragment opens the initial content in an editor and returns it saved
+var editFragment func(path string, content []byte) ([]byte, error)
+
+// SetFragmentEditor enables entering the value of free-form fields in an editor
+// with edit. Without it the value can only be typed at the prompt.
+func SetFragmentEditor(edit func(path string, content []byte) ([]byte, error)) {
+	editFragment = edit
+}
+
+// isFreeform reports whether field is a subtree of unknown structure
+// (x-kubernetes-preserve-unknown-fields without declared properties)
+func isFreeform(field client.FieldSchema) bool {
+	return field.PreserveUnknownFields && len(field.Properties) == 0 &&
+		(field.Type == "object" || field.Type == "")
+}
+
+// promptFreeform asks for the value of a free-form field as YAML or JSON, typed
+// at the prompt or written in an editor, since there are no fields to prompt for
+func promptFreeform(field client.FieldSchema, values *CollectedValues) error {
+	indent, name := values.outline.indent(), values.outline.next(field.Path)
+	if field.Description != "" {
+		printDescription(indent, field.Description)
+	}
+	fmt.Println(indent + i18n.T("%s takes any fields, its structure isn't in the schema", field.Path))
+
+	// Scripted answers give the value itself, as YAML or as structured answers
+	if source, ok := prompter.(valueSource); ok {
+		if answer, ok := source.value(field.Path); ok {
+			val, err := parseFragment([]byte(answerString(answer)), field.Type)
+			if err == nil {
+				values.setAnswer(field.Path, val)
+				recordAnswer(field.Path, val)
+				return nil
+			}
+			if err := retry(field.Path, err); err != nil {
+				return err
+			}
+		} else if Scripted() {
+			if field.Required {
+				return &answerError{i18n.T("no answer for %s", field.Path)}
+			}
+			return nil
+		}
+	}
+
+	var items []string
+	if !field.Required {
+		items = append(items, i18n.T(freeformSkip))
+	}
+	items = append(items, i18n.T(freeformInline))
+	if editFragment != nil {
+		items = append(items, i18n.T(freeformEditor))
+	}
+
+	label := indent + name
+	if field.Required {
+		label += " *"
+	}
+	_, choice, err := runSelect(field.Path+"#input", promptui.Select{Label: label, Items: items})
+	if err != nil {
+		if IsInterrupt(err) {
+			return interrupted(err)
+		}
+		return nil
+	}
+
+	var content []byte
+	for {
+		switch choice {
+		case i18n.T(freeformSkip):
+			return nil
+		case i18n.T(freeformEditor):
+			content, err = editFragment(field.Path, content)
+		default:
+			content, err = readFragment()
+		}
+		if err != nil {
+			if IsInterrupt(err) {
+				return interrupted(err)
+			}
+			return err
+		}
+		if strings.TrimSpace(string(content)) == "" {
+			if field.Required {
+				fmt.Println(i18n.T("  %v, try again", i18n.T("required")))
+				continue
+			}
+			return nil
+		}
+
+		val, err := parseFragment(content, field.Type)
+		if err != nil {
+			fmt.Println(i18n.T("  %v, try again", err))
+			continue
+		}
+		values.setAnswer(field.Path, val)
+		// Replays give the value, not how it was entered
+		forgetAnswer(field.Path + "#input")
+		recordAnswer(field.Path, val)
+		return nil
+	}
+}
+
+// readFragment reads lines of YAML or JSON up to an empty line
+func readFragment() ([]byte, error) {
+	fmt.Println(i18n.T("  Enter the value, then an empty line to finish:"))
+	var b strings.Builder
+	for {
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			if b.Len() > 0 {
+				return []byte(b.String()), nil
+			}
+			return nil, promptui.ErrInterrupt
+		}
+		if strings.TrimSpace(line) == "" {
+			return []byte(b.String()), nil
+		}
+		b.WriteString(strings.TrimRight(line, "\r\n") + "\n")
+	}
+}
+
+// parseFragment parses a YAML or JSON value, which must be an object for object fields
+func parseFragment(content []byte, fieldType string) (interface{}, error) {
+	var val interface{}
+	if err := yaml.Unmarshal(content, &val); err != nil {
+		return nil, i18n.Errorf("invalid YAML or JSON: %w", err)
+	}
+	if _, ok := val.(map[string]interface{}); fieldType == "object" && !ok {
+		return nil, i18n.Errorf("expected an object with fields")
+	}
+	return normalizeValue(val, false)
+}
@@ -0,0 +1,222 @@
+package prompt
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// guidedFields are the fields the guided flow of the resource type asks for
+// itself (see SetGuidedFields)
+var guidedFields []string
+
+// SetGuidedFields makes the prompts for the fields of the schema skip paths
+// and the fields within them, which a guided flow asks for instead. They
+// aren't checked to be set either, as the guided flow fills them in.
+func SetGuidedFields(paths []string) {
+	guidedFields = paths
+}
+
+// guidedField reports whether a guided flow asks for the field at path
+func guidedField(path string) bool {
+	for _, p := range guidedFields {
+		if path == p || strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// otherChoice is the item of PickOrEnter for entering a value not listed
+const otherChoice = "(enter another)"
+
+// Choose asks to pick one of items, starting at cursor, returning its index.
+// It is the select of the guided flows of resource types.
+func Choose(key, label string, items []string, cursor int) (int, error) {
+	sel := promptui.Select{
+		Label:             label,
+		Items:             items,
+		Size:              15,
+		CursorPos:         cursor,
+		Searcher:          containsSearcher(items),
+		StartInSearchMode: len(items) > 15,
+	}
+	i, _, err := runSelect(key, sel)
+	if err != nil && IsInterrupt(err) {
+		return -1, interrupted(err)
+	}
+	return i, err
+}
+
+// AskText asks for a line of text, which check, if not nil, validates.
+// Entering nothing returns defaultVal, or "" unless required.
+func AskText(key, label, defaultVal string, required bool, check func(string) error) (string, error) {
+	text, err := promptString(key, label, defaultVal, required, check)
+	if err != nil && IsInterrupt(err) {
+		return "", interrupted(err)
+	}
+	return text, err
+}
+
+// AskQuantity asks for a resource quantity (e.g., 500m or 10Gi)
+func AskQuantity(key, label, defaultVal string, required bool) (string, error) {
+	return AskText(key, label, defaultVal, required, ValidateQuantity)
+}
+
+// ValidateQuantity checks that s is a resource quantity
+func ValidateQuantity(s string) error {
+	if _, err := resource.ParseQuantity(s); err != nil {
+		return i18n.Errorf("%q is not a quantity (e.g., 500m, 2, 10Gi)", s)
+	}
+	return nil
+}
+
+// PickOrEnter asks to pick one of names, starting at defaultVal, or to enter
+// another value, which check, if not nil, validates. Without names to pick
+// from the value is entered as text. Scripted answers give the value itself.
+func PickOrEnter(key, label string, names []string, defaultVal string, check func(string) error) (string, error) {
+	if source, ok := prompter.(valueSource); ok {
+		if _, answered := source.value(key); answered || Scripted() {
+			names = nil
+		}
+	}
+	if len(names) == 0 {
+		return AskText(key, label, defaultVal, true, check)
+	}
+
+	items := append(slices.Clone(names), i18n.T(otherChoice))
+	i, err := Choose(key, label, items, max(slices.Index(names, defaultVal), 0))
+	if err != nil {
+		return "", err
+	}
+	if i == len(names) {
+		return AskText(key, label, defaultVal, true, check)
+	}
+	return names[i], nil
+}
+
+// ChooseMany asks to pick any number of items, starting with those selected,
+// returning their indexes in order. Each pick toggles an item until Done.
+// Scripted answers list the items, as a list or separated by commas.
+func ChooseMany(key, label string, items []string, selected []bool) ([]int, error) {
+	chosen := make([]bool, len(items))
+	copy(chosen, selected)
+
+	if source, ok := prompter.(valueSource); ok {
+		if answer, ok := source.value(key); ok {
+			picked, err := matchItems(items, answer)
+			if err == nil {
+				recordAnswer(key, pickedItems(items, picked))
+				return picked, nil
+			}
+			if err := retry(key, err); err != nil {
+				return nil, err
+			}
+		} else if Scripted() {
+			return chosenIndexes(chosen), nil
+		}
+	}
+
+	cursor := 0
+	for {
+		options := []string{i18n.T("Done")}
+		for i, item := range items {
+			mark := "[ ] "
+			if chosen[i] {
+				mark = "[x] "
+			}
+			options = append(options, mark+item)
+		}
+		sel := promptui.Select{
+			Label:     label + " " + i18n.T("(pick to toggle)"),
+			Items:     options,
+			Size:      len(options),
+			CursorPos: cursor,
+		}
+		i, _, err := prompter.Select(key, sel)
+		if err != nil {
+			if IsInterrupt(err) {
+				return nil, interrupted(err)
+			}
+			return nil, err
+		}
+		if i == 0 {
+			break
+		}
+		chosen[i-1] = !chosen[i-1]
+		cursor = i
+	}
+	picked := chosenIndexes(chosen)
+	recordAnswer(key, pickedItems(items, picked))
+	return picked, nil
+}
+
+// matchItems returns the indexes of the items listed by answer (ignoring case)
+func matchItems(items []string, answer interface{}) ([]int, error) {
+	var names []string
+	switch v := answer.(type) {
+	case []interface{}:
+		for _, name := range v {
+			names = append(names, answerString(name))
+		}
+	default:
+		for _, name := range strings.Split(answerString(v), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	chosen := make([]bool, len(items))
+	for _, name := range names {
+		i := slices.IndexFunc(items, func(item string) bool { return strings.EqualFold(item, name) })
+		if i < 0 {
+			return nil, i18n.Errorf("%q is not one of %s", name, strings.Join(items, ", "))
+		}
+		chosen[i] = true
+	}
+	return chosenIndexes(chosen), nil
+}
+
+// chosenIndexes returns the indexes of the true elements of chosen
+func chosenIndexes(chosen []bool) []int {
+	var indexes []int
+	for i, c := range chosen {
+		if c {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// pickedItems returns the items at indexes, as recorded answers
+func pickedItems(items []string, indexes []int) []interface{} {
+	picked := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		picked[i] = items[index]
+	}
+	return picked
+}
+
+// Set sets the value at path for the guided flows of resource types. Values
+// entered at prompts are also answers.
+func (v *CollectedValues) Set(path string, val interface{}, source Source) {
+	if source == SourcePrompt {
+		v.setAnswer(path, val)
+		return
+	}
+	v.setValue(path, val, source)
+}
+
+// Has reports whether there's a value at path or within it
+func (v *CollectedValues) Has(path string) bool {
+	for p := range v.Values {
+		if p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+)
+
+// lifecycleFields maps field names (lowercased) that conventionally control
+// deletion and retention behavior to an explanation shown before prompting
+var lifecycleFields = map[string]string{
+	"deletionpolicy":                "What the controller does with the backing resource when this object is deleted (typically Delete, Retain or Orphan)",
+	"reclaimpolicy":                 "What happens to provisioned storage when the claim is released (Delete removes the data, Retain keeps it)",
+	"persistentvolumereclaimpolicy": "What happens to the volume when its claim is released (Delete removes the data, Retain keeps it)",
+	"terminationpolicy":             "How dependent resources and data are handled when this object is terminated",
+	"retainpolicy":                  "Which resources are kept after this object is deleted",
+	"retain":                        "Whether the backing resource is kept after this object is deleted",
+	"preserveondelete":              "Whether the backing resource is kept after this object is deleted",
+	"deletionprotection":            "When enabled, the controller refuses to delete the backing resource",
+	"prune":                         "Whether resources removed from this object are garbage collected",
+}
+
+// lifecyclePrompted holds the paths asked for in the lifecycle section, which
+// the general prompt pass skips whether or not they were answered
+var lifecyclePrompted map[string]bool
+
+// FindLifecycleFields returns the schema fields that control deletion or retention
+func FindLifecycleFields(fields []client.FieldSchema) []client.FieldSchema {
+	var result []client.FieldSchema
+	for _, field := range fields {
+		if strings.HasPrefix(field.Path, "metadata.") || strings.Contains(field.Path, "[") {
+			continue
+		}
+		if _, ok := lifecycleFields[strings.ToLower(field.Name)]; ok && field.Type != "object" {
+			result = append(result, field)
+			continue
+		}
+		if field.Type == "object" && len(field.Properties) > 0 {
+			result = append(result, FindLifecycleFields(field.Properties)...)
+		}
+	}
+	return result
+}
+
+// promptForLifecycleFields prompts for deletion/retention fields in a dedicated
+// section so users make an explicit choice at creation time. The paths asked
+// for are recorded in lifecyclePrompted, and answered ones added to flagValues,
+// so the general prompt pass skips them.
+func promptForLifecycleFields(fields []client.FieldSchema, values *CollectedValues, flagValues map[string]interface{}) error {
+	lifecyclePrompted = map[string]bool{}
+	lifecycle := FindLifecycleFields(fields)
+
+	var pending []client.FieldSchema
+	for _, field := range lifecycle {
+		if _, ok := flagValues[field.Path]; !ok {
+			pending = append(pending, field)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	fmt.Println("\n" + i18n.T("Lifecycle (what happens when this resource is deleted):"))
+	for _, field := range pending {
+		fmt.Printf("  %s: %s\n", field.Path, i18n.T(lifecycleFields[strings.ToLower(field.Name)]))
+		if field.Default != nil {
+			fmt.Println(i18n.T("  (schema default: %v)", field.Default))
+		}
+
+		lifecyclePrompted[field.Path] = true
+		val, err := promptForField(field.Path, field, nil, values.last[field.Path])
+		if err != nil {
+			if IsInterrupt(err) {
+				return interrupted(err)
+			}
+			continue
+		}
+
+		if val != nil && val != "" {
+			values.setAnswer(field.Path, val)
+			flagValues[field.Path] = val
+		}
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// Answers of a long text prompt that switch to another way of entering it
+const (
+	editorAnswer  = "!"
+	heredocAnswer = "<<"
+)
+
+// editText opens the initial text in an editor and returns it saved
+var editText func(path string, content []byte) ([]byte, error)
+
+// SetTextEditor enables entering the value of long text fields in an editor
+// with edit
+func SetTextEditor(edit func(path string, content []byte) ([]byte, error)) {
+	editText = edit
+}
+
+// longTextHints are words of the names and descriptions of string fields that
+// usually hold several lines
+var longTextHints = []string{
+	"script", "certificate", "pem", "private key", "ca bundle", "cabundle",
+	"config file", "configuration file", "file content", "contents", "multi-line",
+	"multiline", "dockerfile", "policy document",
+}
+
+// isLongText reports whether field is a string that likely holds several lines
+// of text, such as a script, a certificate or a configuration file
+func isLongText(field client.FieldSchema) bool {
+	if field.Type != "string" || len(field.Enum) > 0 || field.Format != "" {
+		return false
+	}
+	text := strings.ToLower(field.Name + " " + field.Description)
+	for _, hint := range longTextHints {
+		if strings.Contains(text, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// promptLongText prompts for a string that may span lines. The answer is the
+// value itself, "!" to write it in an editor, or "<<END" to type lines up to
+// one that is END.
+func promptLongText(key, label string, field client.FieldSchema, defaultVal interface{}) (string, error) {
+	if editText != nil {
+		label += i18n.T(" (! for an editor, <<EOF for several lines)")
+	} else {
+		label += i18n.T(" (<<EOF for several lines)")
+	}
+	check := func(s string) error {
+		if s == editorAnswer || strings.HasPrefix(s, heredocAnswer) {
+			return nil
+		}
+		return checkConstraints(field, s)
+	}
+
+	for {
+		result, err := promptString(key, label, defaultVal, field.Required, check)
+		if err != nil {
+			return "", err
+		}
+		// Scripted answers are the text itself
+		if Scripted() {
+			return result, checkConstraints(field, result)
+		}
+
+		var text string
+		switch {
+		case result == editorAnswer && editText != nil:
+			var initial []byte
+			if defaultVal != nil {
+				initial = []byte(fmt.Sprintf("%v", defaultVal))
+			}
+			edited, err := editText(field.Path, initial)
+			if err != nil {
+				fmt.Println(i18n.T("  %v, try again", err))
+				continue
+			}
+			text = string(edited)
+		case strings.HasPrefix(result, heredocAnswer):
+			end := strings.TrimSpace(strings.TrimPrefix(result, heredocAnswer))
+			if end == "" {
+				end = "EOF"
+			}
+			text, err = readHeredoc(end)
+			if err != nil {
+				return "", err
+			}
+		default:
+			return result, nil
+		}
+
+		if text == "" && field.Required {
+			fmt.Println(i18n.T("  %v, try again", i18n.T("required")))
+			continue
+		}
+		if err := checkConstraints(field, text); text != "" && err != nil {
+			fmt.Println(i18n.T("  %v, try again", err))
+			continue
+		}
+		// Replays give the text, not the editor or lines it was entered in
+		recordAnswer(key, text)
+		return text, nil
+	}
+}
+
+// readHeredoc reads lines up to one that is end, like a shell here-document
+func readHeredoc(end string) (string, error) {
+	fmt.Println(i18n.T("  Enter the lines, then %s to finish:", end))
+	var b strings.Builder
+	for {
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			if b.Len() > 0 {
+				return b.String(), nil
+			}
+			return "", promptui.ErrInterrupt
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == end {
+			return b.String(), nil
+		}
+		b.WriteString(line + "\n")
+	}
+}
@@ -0,0 +1,53 @@
+package prompt
+
+import (
+	"fmt"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// PromptMetadataValue asks for the value of a required label or annotation
+// (kind is "label" or "annotation"). With allowed values the user picks one,
+// otherwise the input is checked with validate.
+func PromptMetadataValue(kind, key, description, defaultVal string, allowed []string, validate func(string) error) (string, error) {
+	if description != "" {
+		fmt.Printf("  %s\n", description)
+	}
+	label := fmt.Sprintf("%s %s *", kind, key)
+	question := "metadata." + kind + "s." + key
+
+	if len(allowed) > 0 {
+		cursor := 0
+		for i, v := range allowed {
+			if v == defaultVal {
+				cursor = i
+			}
+		}
+		sel := promptui.Select{
+			Label:     label,
+			Items:     allowed,
+			CursorPos: cursor,
+		}
+		_, value, err := runSelect(question, sel)
+		return value, err
+	}
+
+	p := promptui.Prompt{
+		Label:   label,
+		Default: defaultVal,
+		Validate: func(input string) error {
+			if input == "" {
+				return i18n.Errorf("required")
+			}
+			return validate(input)
+		},
+		Templates: &promptui.PromptTemplates{
+			Prompt:  "{{ . }}: ",
+			Valid:   "{{ . | green }}: ",
+			Invalid: "{{ . | red }}: ",
+			Success: "{{ . | bold }}: ",
+		},
+	}
+	return runPrompt(question, p)
+}
@@ -0,0 +1,188 @@
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NameRule is the naming constraint the API server applies to a resource's name
+type NameRule int
+
+const (
+	// NameSubdomain is an RFC 1123 subdomain (most resources)
+	NameSubdomain NameRule = iota
+	// NameLabel is an RFC 1123 label (e.g., namespaces)
+	NameLabel
+	// NameDNS1035Label is an RFC 1035 label, which must start with a letter (e.g., services)
+	NameDNS1035Label
+	// NamePathSegment is any name usable as a URL path segment (e.g., RBAC roles like "system:viewer")
+	NamePathSegment
+)
+
+// nameRules maps core/RBAC resources (plural names and lowercased kinds) with
+// non-default naming constraints
+var nameRules = map[string]NameRule{
+	"namespaces":          NameLabel,
+	"namespace":           NameLabel,
+	"services":            NameDNS1035Label,
+	"service":             NameDNS1035Label,
+	"roles":               NamePathSegment,
+	"role":                NamePathSegment,
+	"clusterroles":        NamePathSegment,
+	"clusterrole":         NamePathSegment,
+	"rolebindings":        NamePathSegment,
+	"rolebinding":         NamePathSegment,
+	"clusterrolebindings": NamePathSegment,
+	"clusterrolebinding":  NamePathSegment,
+}
+
+// NameRuleFor returns the naming rule for a resource, given its API group and
+// either its plural resource name or its kind
+func NameRuleFor(group, resourceOrKind string) NameRule {
+	if group != "" && group != "rbac.authorization.k8s.io" {
+		return NameSubdomain
+	}
+	if rule, ok := nameRules[strings.ToLower(resourceOrKind)]; ok {
+		return rule
+	}
+	return NameSubdomain
+}
+
+// ValidateName checks name against rule, returning an error with a suggested fix
+func ValidateName(name string, rule NameRule) error {
+	var errs []string
+	switch rule {
+	case NameLabel:
+		errs = validation.IsDNS1123Label(name)
+	case NameDNS1035Label:
+		errs = validation.IsDNS1035Label(name)
+	case NamePathSegment:
+		errs = path.IsValidPathSegmentName(name)
+		if name == "" {
+			errs = append(errs, "must not be empty")
+		}
+	default:
+		errs = validation.IsDNS1123Subdomain(name)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	msg := i18n.T("invalid name %q: %s", name, strings.Join(errs, "; "))
+	if suggestion := SuggestName(name, rule); suggestion != "" && suggestion != name {
+		msg += i18n.T(" (try %q)", suggestion)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+var (
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+	repeatedDashes   = regexp.MustCompile(`-{2,}`)
+)
+
+// SuggestName sanitizes name into one that satisfies rule, or "" if nothing usable remains
+func SuggestName(name string, rule NameRule) string {
+	if rule == NamePathSegment {
+		s := strings.NewReplacer("/", "-", "%", "-").Replace(name)
+		if s == "." || s == ".." {
+			return ""
+		}
+		return s
+	}
+
+	s := strings.ToLower(strings.TrimSpace(name))
+	s = strings.ReplaceAll(s, "_", "-")
+	s = invalidNameChars.ReplaceAllString(s, "-")
+	if rule != NameSubdomain {
+		// Labels can't contain dots
+		s = strings.ReplaceAll(s, ".", "-")
+	}
+	s = repeatedDashes.ReplaceAllString(s, "-")
+
+	maxLen := validation.DNS1123SubdomainMaxLength
+	if rule != NameSubdomain {
+		maxLen = validation.DNS1123LabelMaxLength
+	}
+	if rule == NameDNS1035Label {
+		s = strings.TrimLeft(s, "0123456789-")
+	}
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	s = strings.Trim(s, "-.")
+
+	if s == "" {
+		return ""
+	}
+	return s
+}
+
+// nameResolver checks the name of the resource being created against existing
+// objects (see SetNameResolver)
+var nameResolver func(name string) (string, error)
+
+// SetNameResolver makes resolve check the name of the resource as soon as it is
+// known, before the other questions, returning the name to use instead when an
+// object has it. A nil resolve leaves names unchecked.
+func SetNameResolver(resolve func(name string) (string, error)) {
+	nameResolver = resolve
+}
+
+// resolveName returns the name to use for name, per the name resolver
+func resolveName(name string) (string, error) {
+	if nameResolver == nil {
+		return name, nil
+	}
+	resolved, err := nameResolver(name)
+	if IsInterrupt(err) {
+		return "", interrupted(err)
+	}
+	return resolved, err
+}
+
+// promptForName prompts for a resource name, validating it as it is typed. The
+// validation message includes a sanitized suggestion, and defaultName (if any)
+// is pre-filled after sanitizing.
+func promptForName(rule NameRule, defaultName string) (string, error) {
+	return PromptForName(rule, defaultName, nil)
+}
+
+// PromptForName is promptForName where check, if not nil, also validates the
+// name, e.g. rejecting the names of existing objects
+func PromptForName(rule NameRule, defaultName string, check func(string) error) (string, error) {
+	fmt.Printf("  %s\n", i18n.T("Name of the resource"))
+	if defaultName != "" {
+		defaultName = SuggestName(defaultName, rule)
+	}
+
+	prompt := promptui.Prompt{
+		Label:   "metadata.name *",
+		Default: defaultName,
+		Validate: func(input string) error {
+			if input == "" {
+				return i18n.Errorf("required")
+			}
+			if err := ValidateName(input, rule); err != nil {
+				return err
+			}
+			if check != nil {
+				return check(input)
+			}
+			return nil
+		},
+		Templates: &promptui.PromptTemplates{
+			Prompt:  "{{ . }}: ",
+			Valid:   "{{ . | green }}: ",
+			Invalid: "{{ . | red }}: ",
+			Success: "{{ . | bold }}: ",
+		},
+	}
+
+	return runPrompt("metadata.name", prompt)
+}
@@ -0,0 +1,106 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+)
+
+// outline tracks where prompting is in the schema tree, so each field is shown
+// under a header of its section (the object it belongs to), indented by depth
+// and numbered out of the fields to prompt for
+type outline struct {
+	section string // Path of the object whose fields are being prompted for
+	depth   int
+	done    int
+	total   int
+}
+
+// enter starts the section of the object at path, printing its header
+func (o *outline) enter(path string) (leave func()) {
+	section, depth := o.section, o.depth
+	fmt.Printf("\n%s── %s ──\n", o.indent(), path)
+	o.section, o.depth = path, depth+1
+	return func() {
+		o.section, o.depth = section, depth
+	}
+}
+
+// indent returns the indentation of the current section
+func (o *outline) indent() string {
+	return strings.Repeat("  ", o.depth)
+}
+
+// next counts a field as prompted for, returning its name relative to the
+// section, with its number (e.g., [4/12] replicas)
+func (o *outline) next(path string) string {
+	o.done++
+	if o.total < o.done {
+		o.total = o.done
+	}
+	name := path
+	if o.section != "" {
+		name = strings.TrimPrefix(path, o.section+".")
+	}
+	return fmt.Sprintf("[%d/%d] %s", o.done, o.total, name)
+}
+
+// promptsField reports whether promptForFields prompts for field: required
+// fields and those under spec, which weren't given with flags or asked for in
+// the lifecycle section
+func promptsField(field client.FieldSchema, flagValues map[string]interface{}) bool {
+	if _, ok := flagValues[field.Path]; ok || lifecyclePrompted[field.Path] {
+		return false
+	}
+	if strings.HasPrefix(field.Path, "metadata.") || guidedField(field.Path) {
+		return false
+	}
+	return field.Required || strings.HasPrefix(field.Path, "spec.")
+}
+
+// countPrompts returns the number of questions promptForFields asks for
+// fields, not counting the fields of the variants of unions, which depend on
+// the variant picked
+func countPrompts(fields []client.FieldSchema, flagValues map[string]interface{}) int {
+	n := 0
+	for _, field := range fields {
+		if !promptsField(field, flagValues) {
+			continue
+		}
+		switch {
+		case isFreeform(field):
+			if !hasValue(flagValues, field.Path) {
+				n++
+			}
+		case hasStructuredVariants(field):
+			if !hasValue(flagValues, field.Path) {
+				n += countPrompts(variantlessFields(field), flagValues)
+			}
+		case field.Type == "object" && len(field.Properties) > 0:
+			n += countPrompts(field.Properties, flagValues)
+		case field.Type == "object":
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// sectionOrder returns fields with the ones asked at the top of their section
+// first, and the objects with sections of their own after them, so a section's
+// fields aren't split by those of its subsections
+func sectionOrder(fields []client.FieldSchema) []client.FieldSchema {
+	sorted := append([]client.FieldSchema{}, fields...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return !hasSection(sorted[i]) && hasSection(sorted[j])
+	})
+	return sorted
+}
+
+// hasSection reports whether the fields of field are prompted for under a
+// header of their own
+func hasSection(field client.FieldSchema) bool {
+	return field.Type == "object" && len(field.Properties) > 0 && !hasStructuredVariants(field)
+}
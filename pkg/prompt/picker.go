@@ -0,0 +1,115 @@
+package prompt
+
+import (
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// backItem is shown at the top of a group's resource list
+const backItem = ".. (back to groups)"
+
+// PickResourceType lets the user choose a group, then one of its resource types.
+// Resources are loaded lazily per group so the picker appears immediately even on
+// clusters with many CRDs. loadResources returns the resource names of a group
+// and the type strings to return for them (e.g., "deployments.apps").
+func PickResourceType(groups []string, loadResources func(groupIndex int) (names []string, types []string, err error)) (string, error) {
+	for {
+		groupPrompt := promptui.Select{
+			Label:             i18n.T("API group"),
+			Items:             groups,
+			Size:              15,
+			Searcher:          containsSearcher(groups),
+			StartInSearchMode: len(groups) > 15,
+		}
+		groupIndex, _, err := runSelect("api-group", groupPrompt)
+		if err != nil {
+			return "", err
+		}
+
+		// Scripted answers would pick the same group again
+		names, types, err := loadResources(groupIndex)
+		if err == nil && len(names) == 0 {
+			err = i18n.Errorf("%s has no resource types that support create", groups[groupIndex])
+		}
+		if err != nil {
+			if err := retry("api-group", err); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		items := append([]string{backItem}, names...)
+		resourcePrompt := promptui.Select{
+			Label:    i18n.T("Resource type in %s", groups[groupIndex]),
+			Items:    items,
+			Size:     15,
+			Searcher: containsSearcher(items),
+		}
+		index, _, err := runSelect("resource-type", resourcePrompt)
+		if err != nil {
+			return "", err
+		}
+		if index == 0 {
+			if Scripted() {
+				return "", &answerError{i18n.T("no answer for %s", "resource-type")}
+			}
+			continue
+		}
+		return types[index-1], nil
+	}
+}
+
+// containsSearcher returns a case-insensitive substring searcher over items
+func containsSearcher(items []string) func(input string, index int) bool {
+	return func(input string, index int) bool {
+		return strings.Contains(strings.ToLower(items[index]), strings.ToLower(input))
+	}
+}
+
+// PickNamespace lets the user choose one of namespaces, starting at current.
+// Without namespaces to choose from (e.g., when they can't be listed) the
+// namespace is entered as text with current as the default.
+func PickNamespace(namespaces []string, current string) (string, error) {
+	if len(namespaces) == 0 {
+		return promptString("metadata.namespace", "namespace", current, true, nil)
+	}
+
+	cursor := 0
+	for i, ns := range namespaces {
+		if ns == current {
+			cursor = i
+		}
+	}
+	prompt := promptui.Select{
+		Label:             i18n.T("Namespace"),
+		Items:             namespaces,
+		Size:              15,
+		CursorPos:         cursor,
+		Searcher:          containsSearcher(namespaces),
+		StartInSearchMode: len(namespaces) > 15,
+	}
+	_, ns, err := runSelect("metadata.namespace", prompt)
+	return ns, err
+}
+
+// PickContext lets the user choose one of the kubeconfig contexts, starting at current
+func PickContext(contexts []string, current string) (string, error) {
+	cursor := 0
+	for i, c := range contexts {
+		if c == current {
+			cursor = i
+		}
+	}
+	prompt := promptui.Select{
+		Label:             i18n.T("Kubeconfig context"),
+		Items:             contexts,
+		Size:              15,
+		CursorPos:         cursor,
+		Searcher:          containsSearcher(contexts),
+		StartInSearchMode: len(contexts) > 15,
+	}
+	_, picked, err := runSelect("context", prompt)
+	return picked, err
+}
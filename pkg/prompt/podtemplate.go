@@ -0,0 +1,281 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// PodTemplate is a PodTemplateSpec found in a schema, with its sibling selector
+type PodTemplate struct {
+	Path             string // Path of the PodTemplateSpec (e.g., "spec.template")
+	SelectorPath     string // Path of a required sibling label selector, if any
+	RestartOnFailure bool   // Whether the pods run to completion (Jobs), restricting restartPolicy
+}
+
+// FindPodTemplates returns the paths of PodTemplateSpec fields in a schema
+func FindPodTemplates(fields []client.FieldSchema) []string {
+	var paths []string
+	for _, t := range findPodTemplates(fields, false) {
+		paths = append(paths, t.Path)
+	}
+	return paths
+}
+
+// findPodTemplates walks the schema looking for $refs to PodTemplateSpec
+func findPodTemplates(fields []client.FieldSchema, inJob bool) []PodTemplate {
+	var result []PodTemplate
+	for _, field := range fields {
+		if strings.Contains(field.Path, "[") {
+			continue
+		}
+		if field.Ref == client.PodTemplateSpecRef {
+			t := PodTemplate{Path: field.Path, RestartOnFailure: inJob}
+			for _, sibling := range fields {
+				if sibling.Name == "selector" && sibling.Required && sibling.Type == "object" {
+					t.SelectorPath = sibling.Path
+				}
+			}
+			result = append(result, t)
+			continue
+		}
+		if len(field.Properties) > 0 {
+			childInJob := inJob || strings.HasSuffix(field.Ref, ".JobTemplateSpec") || strings.HasSuffix(field.Ref, ".JobSpec")
+			result = append(result, findPodTemplates(field.Properties, childInJob)...)
+		}
+	}
+	return result
+}
+
+// isJobKind reports whether pods of this kind run to completion
+func isJobKind(kind string) bool {
+	return strings.EqualFold(kind, "Job") || strings.EqualFold(kind, "CronJob")
+}
+
+// promptForPodTemplates runs the container builder for every pod template in the
+// schema. The template (and auto-wired selector) subtrees are added to flagValues
+// so the general prompt pass skips them.
+func promptForPodTemplates(schema *client.ResourceSchema, values *CollectedValues, flagValues map[string]interface{}) error {
+	inJob := isJobKind(schema.GVK.Kind)
+	for _, t := range findPodTemplates(schema.Fields, inJob) {
+		// Leave templates configured via --set alone
+		if hasPathUnder(flagValues, t.Path) {
+			fmt.Println(i18n.T("Skipping container builder for %s (set via flags)", t.Path))
+			flagValues[t.Path] = true
+			continue
+		}
+
+		if err := buildPodTemplate(t, values, flagValues); err != nil {
+			return err
+		}
+		flagValues[t.Path] = true
+		if t.SelectorPath != "" {
+			flagValues[t.SelectorPath] = true
+		}
+	}
+	return nil
+}
+
+// buildPodTemplate guides the user through a single-container pod template
+func buildPodTemplate(t PodTemplate, values *CollectedValues, flagValues map[string]interface{}) error {
+	fmt.Println("\n" + i18n.T("Container builder for %s:", t.Path))
+
+	container := t.Path + ".spec.containers[0]"
+	ask := func(field client.FieldSchema, path string) (interface{}, error) {
+		val, err := promptForField(path, field, nil, values.last[path])
+		if IsInterrupt(err) {
+			return nil, interrupted(err)
+		}
+		if err != nil {
+			return nil, nil
+		}
+		return val, nil
+	}
+
+	defaultName := values.Name
+	if defaultName == "" {
+		defaultName = "app"
+	}
+	nameVal, err := ask(client.FieldSchema{Path: i18n.T("container name"), Type: "string", Default: defaultName, Required: true}, container+".name")
+	if err != nil {
+		return err
+	}
+	containerName := fmt.Sprintf("%v", nameVal)
+	values.setAnswer(container+".name", containerName)
+
+	image, err := ask(client.FieldSchema{Path: i18n.T("image"), Type: "string", Required: true, Description: i18n.T("Container image (e.g., nginx:1.25)")}, container+".image")
+	if err != nil {
+		return err
+	}
+	values.setAnswer(container+".image", image)
+
+	command, err := ask(client.FieldSchema{Path: i18n.T("command (optional, space separated)"), Type: "string"}, container+".command")
+	if err != nil {
+		return err
+	}
+	if cmd, ok := command.(string); ok && cmd != "" {
+		for i, part := range strings.Fields(cmd) {
+			values.setAnswer(fmt.Sprintf("%s.command[%d]", container, i), part)
+		}
+	}
+
+	if err := promptRepeated(container+".env", i18n.T("env var (NAME=value)"), func(i int, input string) error {
+		parts := strings.SplitN(input, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return i18n.Errorf("expected NAME=value")
+		}
+		values.setAnswer(fmt.Sprintf("%s.env[%d].name", container, i), parts[0])
+		values.setAnswer(fmt.Sprintf("%s.env[%d].value", container, i), parts[1])
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := promptRepeated(container+".ports", i18n.T("container port"), func(i int, input string) error {
+		port := parseValue(input)
+		if _, ok := port.(int64); !ok {
+			return i18n.Errorf("must be integer")
+		}
+		values.setAnswer(fmt.Sprintf("%s.ports[%d].containerPort", container, i), port)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, r := range []string{"requests.cpu", "requests.memory", "limits.cpu", "limits.memory"} {
+		val, err := ask(client.FieldSchema{Path: "resources." + r + i18n.T(" (optional, e.g. 100m / 128Mi)"), Type: "string"}, container+".resources."+r)
+		if err != nil {
+			return err
+		}
+		if str, ok := val.(string); ok && str != "" {
+			values.setAnswer(container+".resources."+r, str)
+		}
+	}
+
+	if err := promptRepeated(container+".volumeMounts", i18n.T("volume mount (name:mountPath)"), func(i int, input string) error {
+		parts := strings.SplitN(input, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return i18n.Errorf("expected name:mountPath")
+		}
+		volume := fmt.Sprintf("%s.spec.volumes[%d]", t.Path, i)
+		sourceType, sourceName, err := promptVolumeSource(volume, parts[0])
+		if err != nil {
+			return err
+		}
+		values.setAnswer(fmt.Sprintf("%s.volumeMounts[%d].name", container, i), parts[0])
+		values.setAnswer(fmt.Sprintf("%s.volumeMounts[%d].mountPath", container, i), parts[1])
+		values.setAnswer(volume+".name", parts[0])
+		if sourceType == "emptyDir" {
+			values.setAnswer(volume+".emptyDir", map[string]interface{}{})
+		} else {
+			values.setAnswer(volume+"."+volumeSourceNameFields[sourceType], sourceName)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if t.RestartOnFailure {
+		prompt := promptui.Select{
+			Label: "restartPolicy",
+			Items: []string{"Never", "OnFailure"},
+		}
+		_, policy, err := runSelect(t.Path+".spec.restartPolicy", prompt)
+		if err != nil {
+			return interrupted(err)
+		}
+		values.setAnswer(t.Path+".spec.restartPolicy", policy)
+	}
+
+	// Wire labels so the selector matches the pods
+	labelValue := values.Name
+	if labelValue == "" {
+		labelValue = containerName
+	}
+	if _, ok := flagValues[t.Path+".metadata.labels.app"]; !ok {
+		values.setDefault(t.Path+".metadata.labels.app", labelValue)
+	}
+	if t.SelectorPath != "" && !hasPathUnder(flagValues, t.SelectorPath) {
+		values.setDefault(t.SelectorPath+".matchLabels.app", labelValue)
+		fmt.Println(i18n.T("Set %s.matchLabels.app=%s to match the pod labels", t.SelectorPath, labelValue))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// volumeSourceNameFields are the fields naming the object a volume source
+// refers to, by source type
+var volumeSourceNameFields = map[string]string{
+	"configMap":             "configMap.name",
+	"secret":                "secret.secretName",
+	"persistentVolumeClaim": "persistentVolumeClaim.claimName",
+}
+
+// promptVolumeSource asks where the data of the volume at path comes from
+func promptVolumeSource(path, volumeName string) (string, string, error) {
+	sourceTypes := []string{"emptyDir", "configMap", "secret", "persistentVolumeClaim"}
+	prompt := promptui.Select{
+		Label: i18n.T("source for volume %s", volumeName),
+		Items: sourceTypes,
+	}
+	_, sourceType, err := runSelect(path, prompt)
+	if err != nil {
+		return "", "", interrupted(err)
+	}
+	if sourceType == "emptyDir" {
+		return sourceType, "", nil
+	}
+
+	key := path + "." + volumeSourceNameFields[sourceType]
+	sourceName, ok, err := promptReference(key, sourceType+" name", volumeSourceTypes[sourceType], nil, true)
+	if !ok {
+		sourceName, err = promptString(key, sourceType+" name", nil, true, nil)
+	}
+	if err != nil {
+		return "", "", interrupted(err)
+	}
+	return sourceType, sourceName, nil
+}
+
+// promptRepeated prompts for entries until an empty line, calling add for
+// each, and asks for entry i with key[i]
+func promptRepeated(key, label string, add func(i int, input string) error) error {
+	fmt.Println(i18n.T("%s (empty line to finish):", label))
+	for i := 0; ; {
+		prompt := promptui.Prompt{
+			Label: fmt.Sprintf("  [%d]", i),
+		}
+		question := fmt.Sprintf("%s[%d]", key, i)
+		result, err := runPrompt(question, prompt)
+		if err != nil {
+			if IsInterrupt(err) {
+				return interrupted(err)
+			}
+			return nil
+		}
+		if result == "" {
+			return nil
+		}
+		if err := add(i, result); err != nil {
+			if err := retry(question, err); err != nil {
+				return err
+			}
+			continue
+		}
+		i++
+	}
+}
+
+// hasPathUnder reports whether m contains path or any path nested below it
+func hasPathUnder(m map[string]interface{}, path string) bool {
+	for k := range m {
+		if k == path || strings.HasPrefix(k, path+".") || strings.HasPrefix(k, path+"[") {
+			return true
+		}
+	}
+	return false
+}
@@ -7,13 +7,54 @@ import (
 	"strings"
 
 	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
 	"github.com/manifoldco/promptui"
 )
 
+// Source is where a collected value came from
+type Source string
+
+const (
+	SourceFlag     Source = "flag"     // --set and other flags, or configured value sources
+	SourcePrompt   Source = "prompt"   // Entered at a prompt
+	SourceTemplate Source = "template" // The template the values start from
+	SourceDefault  Source = "default"  // A default accepted at a prompt, or derived from other values
+)
+
 // CollectedValues holds the values collected from user input
 type CollectedValues struct {
-	Name   string
-	Values map[string]interface{}
+	Name    string
+	Values  map[string]interface{}
+	Answers map[string]interface{} // Subset of Values entered at interactive prompts
+	Sources map[string]Source      // Where each of Values came from
+
+	last    map[string]interface{} // Values of the previous creation of the type, used as prompt defaults
+	outline *outline               // Where prompting is in the schema
+}
+
+// setAnswer records a value entered at a prompt
+func (v *CollectedValues) setAnswer(path string, val interface{}) {
+	v.setValue(path, val, SourcePrompt)
+	if v.Answers == nil {
+		v.Answers = make(map[string]interface{})
+	}
+	v.Answers[path] = val
+}
+
+// setDefault records a value the user accepted or that follows from their
+// answers, like setAnswer
+func (v *CollectedValues) setDefault(path string, val interface{}) {
+	v.setAnswer(path, val)
+	v.Sources[path] = SourceDefault
+}
+
+// setValue sets the value at path, recording where it came from
+func (v *CollectedValues) setValue(path string, val interface{}, source Source) {
+	v.Values[path] = val
+	if v.Sources == nil {
+		v.Sources = make(map[string]Source)
+	}
+	v.Sources[path] = source
 }
 
 // CollectFieldValues collects field values through interactive prompts and/or flags
@@ -23,9 +64,30 @@ func CollectFieldValues(schema *client.ResourceSchema, name string, setValues []
 
 // CollectFieldValuesWithTemplate collects field values using an optional template for defaults
 func CollectFieldValuesWithTemplate(schema *client.ResourceSchema, name string, setValues []string, templateValues map[string]interface{}) (*CollectedValues, error) {
+	return collectFieldValues(schema, name, setValues, templateValues, nil, nil)
+}
+
+// CollectFieldValuesWithPresets collects field values where presets (e.g., from
+// configured value sources) behave like --set values of lower priority
+func CollectFieldValuesWithPresets(schema *client.ResourceSchema, name string, setValues []string, presets map[string]interface{}) (*CollectedValues, error) {
+	return collectFieldValues(schema, name, setValues, nil, presets, nil)
+}
+
+// CollectFieldValuesWithLastValues is CollectFieldValuesWithPresets where the
+// prompts default to the values used the last time the type was created
+func CollectFieldValuesWithLastValues(schema *client.ResourceSchema, name string, setValues []string, presets, last map[string]interface{}) (*CollectedValues, error) {
+	return collectFieldValues(schema, name, setValues, nil, presets, last)
+}
+
+// collectFieldValues implements the CollectFieldValues variants
+func collectFieldValues(schema *client.ResourceSchema, name string, setValues []string, templateValues, presets, last map[string]interface{}) (*CollectedValues, error) {
 	values := &CollectedValues{
-		Name:   name,
-		Values: make(map[string]interface{}),
+		Name:    name,
+		Values:  make(map[string]interface{}),
+		Answers: make(map[string]interface{}),
+		Sources: make(map[string]Source),
+		last:    last,
+		outline: &outline{},
 	}
 
 	// Parse --set values first (highest priority)
@@ -33,49 +95,103 @@ func CollectFieldValuesWithTemplate(schema *client.ResourceSchema, name string,
 	if err != nil {
 		return nil, err
 	}
+	if schema != nil {
+		if err := convertValues(schema, flagValues); err != nil {
+			return nil, err
+		}
+		if err := checkSetValues(schema, flagValues); err != nil {
+			return nil, err
+		}
+	}
+
+	// Presets fill in anything not given via --set and are never prompted for
+	for k, v := range presets {
+		if _, ok := flagValues[k]; !ok {
+			v, err := convertValue(schema, k, v)
+			if err != nil {
+				return nil, err
+			}
+			flagValues[k] = v
+		}
+	}
 
 	// Start with template values as base (if provided)
 	if templateValues != nil {
 		for k, v := range templateValues {
-			values.Values[k] = v
+			values.setValue(k, v, SourceTemplate)
 		}
-		fmt.Printf("Loaded %d fields from template\n", len(templateValues))
+		fmt.Println(i18n.T("Loaded %d fields from template", len(templateValues)))
 	}
 
 	// Override with flag values (flags take precedence over template)
 	for k, v := range flagValues {
-		values.Values[k] = v
+		values.setValue(k, v, SourceFlag)
 	}
 
+	// Without a terminal nothing is prompted for and missing values are errors
+	interactive := CanPrompt()
+
 	// If name not provided via flag, prompt for it
+	rule := NameSubdomain
+	if schema != nil {
+		rule = NameRuleFor(schema.GVK.Group, schema.GVK.Kind)
+	}
 	if values.Name == "" {
 		nameVal, ok := values.Values["metadata.name"]
 		if ok {
 			values.Name = fmt.Sprintf("%v", nameVal)
+		} else if !interactive {
+			return nil, i18n.Errorf("a name is required when not running in a terminal, pass it after the resource type")
 		} else {
-			promptedName, err := promptForField(client.FieldSchema{
-				Path:        "metadata.name",
-				Name:        "name",
-				Type:        "string",
-				Description: "Name of the resource",
-				Required:    true,
-			}, nil)
+			promptedName, err := promptForName(rule, "")
 			if err != nil {
 				return nil, err
 			}
-			values.Name = promptedName.(string)
+			values.Name = promptedName
+			values.setAnswer("metadata.name", values.Name)
 		}
 	}
+	if err := ValidateName(values.Name, rule); err != nil {
+		return nil, err
+	}
+	// Check the name before the other questions, so a taken one doesn't end the
+	// run after them
+	values.Name, err = resolveName(values.Name)
+	if err != nil {
+		return nil, err
+	}
 	values.Values["metadata.name"] = values.Name
+	if _, ok := values.Sources["metadata.name"]; !ok {
+		values.Sources["metadata.name"] = SourceFlag // The name argument
+	}
 
 	// If we have template values, prompt user to confirm/modify each spec field
-	if templateValues != nil {
+	if !interactive {
+		if schema != nil {
+			if err := checkRequiredValues(schema.Fields, values.Values); err != nil {
+				return nil, err
+			}
+		}
+	} else if templateValues != nil {
 		err = promptForTemplateFields(values, flagValues)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		// Prompt for fields from the schema (original behavior)
+		// Ask about deletion/retention behavior up front
+		err = promptForLifecycleFields(schema.Fields, values, flagValues)
+		if err != nil {
+			return nil, err
+		}
+
+		// Guide pod-bearing workloads through the container builder
+		err = promptForPodTemplates(schema, values, flagValues)
+		if err != nil {
+			return nil, err
+		}
+
+		// Prompt for fields from the schema, section by section
+		values.outline.total = countPrompts(schema.Fields, flagValues)
 		err = promptForFields(schema.Fields, values, flagValues)
 		if err != nil {
 			return nil, err
@@ -85,6 +201,37 @@ func CollectFieldValuesWithTemplate(schema *client.ResourceSchema, name string,
 	return values, nil
 }
 
+// convertValue converts val, given for the field at path with --set or a
+// preset, to the form it takes in manifests: values of binary fields are
+// encoded in base64 and times are normalized to RFC 3339
+func convertValue(schema *client.ResourceSchema, path string, val interface{}) (interface{}, error) {
+	if schema == nil {
+		return val, nil
+	}
+	field, ok := schema.FindField(path)
+	switch {
+	case !ok:
+		return val, nil
+	case isBytes(field):
+		return encodeByteValue(path, val)
+	case isTime(field):
+		return normalizeTimeValue(path, field.Format, val)
+	}
+	return val, nil
+}
+
+// convertValues converts the values of fields in values in place
+func convertValues(schema *client.ResourceSchema, values map[string]interface{}) error {
+	for path, val := range values {
+		converted, err := convertValue(schema, path, val)
+		if err != nil {
+			return err
+		}
+		values[path] = converted
+	}
+	return nil
+}
+
 // promptForTemplateFields prompts user to confirm/modify fields from template
 func promptForTemplateFields(values *CollectedValues, flagValues map[string]interface{}) error {
 	// Get all spec fields from current values
@@ -98,7 +245,7 @@ func promptForTemplateFields(values *CollectedValues, flagValues map[string]inte
 	// Sort for consistent ordering
 	sort.Strings(specFields)
 
-	fmt.Println("\nTemplate fields (press Enter to keep, or type new value):")
+	fmt.Println("\n" + i18n.T("Template fields (press Enter to keep, or type new value):"))
 	
 	for _, path := range specFields {
 		// Skip if already set via flag
@@ -116,16 +263,16 @@ func promptForTemplateFields(values *CollectedValues, flagValues map[string]inte
 			Required: false,
 		}
 
-		newVal, err := promptForField(field, currentVal)
+		newVal, err := promptForField(path, field, currentVal, nil)
 		if err != nil {
-			if err == promptui.ErrInterrupt {
-				return fmt.Errorf("interrupted")
+			if IsInterrupt(err) {
+				return interrupted(err)
 			}
 			continue
 		}
 
 		if newVal != nil && newVal != "" {
-			values.Values[path] = newVal
+			values.setAnswer(path, newVal)
 		}
 	}
 
@@ -148,52 +295,83 @@ func inferType(val interface{}) string {
 	}
 }
 
-// promptForFields recursively prompts for fields
+// promptForFields recursively prompts for fields, the ones of each object
+// under a header of its own after the fields of the enclosing object
 func promptForFields(fields []client.FieldSchema, values *CollectedValues, flagValues map[string]interface{}) error {
-	for _, field := range fields {
-		// Skip if already set via flags
-		if _, ok := flagValues[field.Path]; ok {
+	o := values.outline
+	for _, field := range conditionalLast(sectionOrder(fields)) {
+		// Skip fields set via flags, metadata (already handled), and optional
+		// fields outside spec
+		if !promptsField(field, flagValues) {
 			continue
 		}
-
-		// Skip metadata fields (already handled)
-		if strings.HasPrefix(field.Path, "metadata.") {
+		// and those whose configured conditions don't hold
+		if !conditionsHold(field.Path, values.Values) {
 			continue
 		}
 
-		// For required fields or spec fields, prompt the user
-		if field.Required || strings.HasPrefix(field.Path, "spec.") {
-			// Handle nested objects with properties
-			if field.Type == "object" && len(field.Properties) > 0 {
-				// Recursively prompt for nested required fields
-				err := promptForFields(field.Properties, values, flagValues)
-				if err != nil {
-					return err
-				}
+		// Subtrees of unknown structure are entered as YAML or JSON
+		if isFreeform(field) {
+			if hasValue(flagValues, field.Path) {
 				continue
 			}
+			if err := promptFreeform(field, values); err != nil {
+				return err
+			}
+			continue
+		}
 
-			// Skip complex types without a clear prompting strategy
-			if field.Type == "object" && len(field.Properties) == 0 {
-				// This might be a map type - show info but skip interactive prompt
-				if field.Required {
-					fmt.Printf("Note: %s is required but is a complex type. Use --set=%s.key=value\n", field.Path, field.Path)
-				}
+		// Unions of objects ask which variant to fill in first
+		if hasStructuredVariants(field) {
+			if hasValue(flagValues, field.Path) {
 				continue
 			}
+			if err := promptForVariants(field, values, flagValues); err != nil {
+				return err
+			}
+			continue
+		}
 
-			// Prompt for the field
-			val, err := promptForField(field, nil)
+		// Handle nested objects with properties
+		if field.Type == "object" && len(field.Properties) > 0 {
+			// Recursively prompt for nested required fields, under a header
+			// when there's something to ask
+			leave := func() {}
+			if countPrompts(field.Properties, flagValues) > 0 {
+				leave = o.enter(field.Path)
+			}
+			err := promptForFields(field.Properties, values, flagValues)
+			leave()
 			if err != nil {
-				if err == promptui.ErrInterrupt {
-					return fmt.Errorf("interrupted")
-				}
-				// Skip fields where user just pressed enter (empty optional fields)
-				continue
+				return err
 			}
+			continue
+		}
 
-			if val != nil && val != "" {
-				values.Values[field.Path] = val
+		// Skip complex types without a clear prompting strategy
+		if field.Type == "object" && len(field.Properties) == 0 {
+			// This might be a map type - show info but skip interactive prompt
+			if field.Required {
+				fmt.Println(i18n.T("Note: %s is required but is a complex type. Use --set=%s.key=value", field.Path, field.Path))
+			}
+			continue
+		}
+
+		// Prompt for the field
+		val, err := promptForFieldAt(field.Path, field, o.indent(), o.next(field.Path), nil, values.last[field.Path])
+		if err != nil {
+			if IsInterrupt(err) {
+				return interrupted(err)
+			}
+			// Skip fields where user just pressed enter (empty optional fields)
+			continue
+		}
+
+		if val != nil && val != "" {
+			if field.Default != nil && fmt.Sprint(val) == fmt.Sprint(field.Default) {
+				values.setDefault(field.Path, val)
+			} else {
+				values.setAnswer(field.Path, val)
 			}
 		}
 	}
@@ -201,11 +379,17 @@ func promptForFields(fields []client.FieldSchema, values *CollectedValues, flagV
 	return nil
 }
 
-// promptForField prompts the user for a field value
-// templateDefault is used as the default value if provided (overrides schema default)
-func promptForField(field client.FieldSchema, templateDefault interface{}) (interface{}, error) {
+// promptForField prompts the user for a field value, asking the question with key
+// templateDefault is used as the default value if provided (overrides schema default),
+// otherwise lastVal, the value used the last time the type was created
+func promptForField(key string, field client.FieldSchema, templateDefault, lastVal interface{}) (interface{}, error) {
+	return promptForFieldAt(key, field, "", field.Path, templateDefault, lastVal)
+}
+
+// promptForFieldAt is promptForField showing the field as name, indented by indent
+func promptForFieldAt(key string, field client.FieldSchema, indent, name string, templateDefault, lastVal interface{}) (interface{}, error) {
 	// Build a clear label
-	label := field.Path
+	label := indent + name
 	if field.Required {
 		label += " *"
 	}
@@ -215,34 +399,66 @@ func promptForField(field client.FieldSchema, templateDefault interface{}) (inte
 	if templateDefault != nil {
 		defaultVal = templateDefault
 		// Show current value in label
-		label += fmt.Sprintf(" [current: %v]", templateDefault)
+		label += i18n.T(" [current: %v]", templateDefault)
+	} else if lastVal != nil && field.Type != "array" {
+		// Arrays are entered item by item and have no default
+		defaultVal = lastVal
+		label += i18n.T(" [last: %v]", lastVal)
 	}
 
 	// Print description separately so prompt label stays clean
 	if field.Description != "" {
-		desc := field.Description
-		if len(desc) > 80 {
-			desc = desc[:77] + "..."
-		}
-		fmt.Printf("  %s\n", desc)
+		printDescription(indent, field.Description)
 	}
 
 	switch field.Type {
 	case "boolean":
-		return promptBoolean(label, defaultVal)
+		return promptBoolean(key, label, defaultVal)
 	case "integer":
-		return promptInteger(label, defaultVal, field.Required)
+		return promptInteger(key, label, defaultVal, field.Required, func(n int64) error {
+			return checkConstraints(field, n)
+		})
 	case "number":
-		return promptNumber(label, defaultVal, field.Required)
+		return promptNumber(key, label, defaultVal, field.Required, func(n float64) error {
+			return checkConstraints(field, n)
+		})
 	case "array":
-		return promptArray(label, field.Items)
+		return promptArray(key, label, field)
 	default: // string and others
-		return promptString(label, defaultVal, field.Required)
+		if len(field.Variants) > 0 || field.Format == "int-or-string" {
+			return promptScalarVariant(key, label, field, defaultVal)
+		}
+		if isBytes(&field) {
+			return promptBytes(key, label, field, defaultVal)
+		}
+		if isTime(&field) {
+			return promptTime(key, label, field, defaultVal)
+		}
+		if isLongText(field) {
+			return promptLongText(key, label, field, defaultVal)
+		}
+		if gvr, ok := referenceType(field.Path); ok {
+			if val, ok, err := promptReference(key, label, gvr, defaultVal, field.Required); ok {
+				return val, err
+			}
+		}
+		return promptString(key, label, defaultVal, field.Required, func(s string) error {
+			return checkConstraints(field, s)
+		})
 	}
 }
 
-// promptString prompts for a string value
-func promptString(label string, defaultVal interface{}, required bool) (string, error) {
+// printDescription prints a field description above its prompt, shortened to a
+// line and indented like the prompt
+func printDescription(indent, desc string) {
+	if len(desc) > 80 {
+		desc = desc[:77] + "..."
+	}
+	fmt.Printf("%s  %s\n", indent, desc)
+}
+
+// promptString prompts for a string value, which check, if not nil, validates
+func promptString(key, label string, defaultVal interface{}, required bool, check func(string) error) (string, error) {
 	defaultStr := ""
 	if defaultVal != nil {
 		defaultStr = fmt.Sprintf("%v", defaultVal)
@@ -250,7 +466,10 @@ func promptString(label string, defaultVal interface{}, required bool) (string,
 
 	validateFunc := func(input string) error {
 		if required && input == "" && defaultStr == "" {
-			return fmt.Errorf("required")
+			return i18n.Errorf("required")
+		}
+		if input != "" && check != nil {
+			return check(input)
 		}
 		return nil
 	}
@@ -267,7 +486,7 @@ func promptString(label string, defaultVal interface{}, required bool) (string,
 		},
 	}
 
-	result, err := prompt.Run()
+	result, err := runPrompt(key, prompt)
 	if err != nil {
 		return "", err
 	}
@@ -278,8 +497,8 @@ func promptString(label string, defaultVal interface{}, required bool) (string,
 	return result, nil
 }
 
-// promptInteger prompts for an integer value
-func promptInteger(label string, defaultVal interface{}, required bool) (int64, error) {
+// promptInteger prompts for an integer value, which check validates
+func promptInteger(key, label string, defaultVal interface{}, required bool, check func(int64) error) (int64, error) {
 	defaultStr := ""
 	if defaultVal != nil {
 		defaultStr = fmt.Sprintf("%v", defaultVal)
@@ -291,15 +510,15 @@ func promptInteger(label string, defaultVal interface{}, required bool) (int64,
 		Validate: func(input string) error {
 			if input == "" {
 				if required && defaultStr == "" {
-					return fmt.Errorf("required")
+					return i18n.Errorf("required")
 				}
 				return nil
 			}
-			_, err := strconv.ParseInt(input, 10, 64)
+			n, err := strconv.ParseInt(input, 10, 64)
 			if err != nil {
-				return fmt.Errorf("must be integer")
+				return i18n.Errorf("must be integer")
 			}
-			return nil
+			return check(n)
 		},
 		Templates: &promptui.PromptTemplates{
 			Prompt:  "{{ . }}: ",
@@ -309,7 +528,7 @@ func promptInteger(label string, defaultVal interface{}, required bool) (int64,
 		},
 	}
 
-	result, err := prompt.Run()
+	result, err := runPrompt(key, prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -325,8 +544,8 @@ func promptInteger(label string, defaultVal interface{}, required bool) (int64,
 	return strconv.ParseInt(result, 10, 64)
 }
 
-// promptNumber prompts for a float value
-func promptNumber(label string, defaultVal interface{}, required bool) (float64, error) {
+// promptNumber prompts for a float value, which check validates
+func promptNumber(key, label string, defaultVal interface{}, required bool, check func(float64) error) (float64, error) {
 	defaultStr := ""
 	if defaultVal != nil {
 		defaultStr = fmt.Sprintf("%v", defaultVal)
@@ -338,19 +557,19 @@ func promptNumber(label string, defaultVal interface{}, required bool) (float64,
 		Validate: func(input string) error {
 			if input == "" {
 				if required && defaultStr == "" {
-					return fmt.Errorf("this field is required")
+					return i18n.Errorf("this field is required")
 				}
 				return nil
 			}
-			_, err := strconv.ParseFloat(input, 64)
+			n, err := strconv.ParseFloat(input, 64)
 			if err != nil {
-				return fmt.Errorf("must be a valid number")
+				return i18n.Errorf("must be a valid number")
 			}
-			return nil
+			return check(n)
 		},
 	}
 
-	result, err := prompt.Run()
+	result, err := runPrompt(key, prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -367,7 +586,7 @@ func promptNumber(label string, defaultVal interface{}, required bool) (float64,
 }
 
 // promptBoolean prompts for a boolean value
-func promptBoolean(label string, defaultVal interface{}) (bool, error) {
+func promptBoolean(key, label string, defaultVal interface{}) (bool, error) {
 	items := []string{"true", "false"}
 	index := 1 // default to false
 	if defaultVal == true {
@@ -380,7 +599,7 @@ func promptBoolean(label string, defaultVal interface{}) (bool, error) {
 		CursorPos: index,
 	}
 
-	_, result, err := prompt.Run()
+	_, result, err := runSelect(key, prompt)
 	if err != nil {
 		return false, err
 	}
@@ -388,40 +607,128 @@ func promptBoolean(label string, defaultVal interface{}) (bool, error) {
 	return result == "true", nil
 }
 
-// promptArray prompts for array values
-func promptArray(label string, items *client.FieldSchema) ([]interface{}, error) {
-	fmt.Printf("%s (enter values one per line, empty line to finish):\n", label)
+// promptArray prompts for array values, asking for item i with key[i]
+func promptArray(key, label string, field client.FieldSchema) ([]interface{}, error) {
+	fmt.Println(i18n.T("%s (enter values one per line, empty line to finish):", label))
 
+	items := field.Items
 	var values []interface{}
 	for {
 		prompt := promptui.Prompt{
 			Label: fmt.Sprintf("  [%d]", len(values)),
 		}
 
-		result, err := prompt.Run()
+		question := fmt.Sprintf("%s[%d]", key, len(values))
+		result, err := runPrompt(question, prompt)
 		if err != nil {
-			if err == promptui.ErrInterrupt {
+			if IsInterrupt(err) {
 				return nil, err
 			}
 			break
 		}
 
 		if result == "" {
+			// Optional arrays may be left empty
+			if len(values) > 0 || field.Required {
+				if err := checkConstraints(field, values); err != nil {
+					if err := retry(key, err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+			}
 			break
 		}
 
 		// Convert based on item type
+		var val interface{} = result
 		if items != nil && items.Type == "integer" {
-			val, err := strconv.ParseInt(result, 10, 64)
+			n, err := strconv.ParseInt(result, 10, 64)
 			if err != nil {
-				fmt.Println("  Invalid integer, try again")
+				if err := retry(question, i18n.Errorf("must be integer")); err != nil {
+					return nil, err
+				}
 				continue
 			}
-			values = append(values, val)
-		} else {
-			values = append(values, result)
+			val = n
 		}
+		if items != nil {
+			if err := checkConstraints(*items, val); err != nil {
+				if err := retry(question, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		values = append(values, val)
 	}
 
 	return values, nil
 }
+
+// checkRequiredValues fails listing the required fields without a value or
+// schema default, for runs that can't prompt for them
+func checkRequiredValues(fields []client.FieldSchema, values map[string]interface{}) error {
+	var missing []string
+	var walk func(fields []client.FieldSchema)
+	walk = func(fields []client.FieldSchema) {
+		for _, f := range fields {
+			if strings.HasPrefix(f.Path, "metadata.") || f.Path == "metadata" || guidedField(f.Path) {
+				continue
+			}
+			if !conditionsHold(f.Path, values) {
+				continue
+			}
+			if !hasValue(values, f.Path) {
+				// The spec is always filled in, as when prompting
+				required := f.Required || f.Path == "spec"
+				switch {
+				case !required || f.Default != nil:
+				case f.Type == "object" && len(f.Properties) > 0:
+					// List the fields to set rather than the object
+					walk(f.Properties)
+				case f.Type == "array" && f.Items != nil && len(f.Items.Properties) > 0:
+					// At least one item with its required fields
+					before := len(missing)
+					for _, item := range f.Items.Properties {
+						if item.Required {
+							missing = append(missing, f.Path+"[0]."+item.Name)
+						}
+					}
+					if len(missing) == before {
+						missing = append(missing, f.Path+"[0]")
+					}
+				default:
+					missing = append(missing, f.Path)
+				}
+				continue
+			}
+			// Objects that are set need their own required fields
+			walk(f.Properties)
+		}
+	}
+	walk(fields)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T("required fields are missing and can't be prompted for without a terminal:"))
+	for _, path := range missing {
+		fmt.Fprintf(&b, "\n  --set %s=<value>", path)
+	}
+	return fmt.Errorf("%s", b.String())
+}
+
+// hasValue reports whether values sets path or a field below it
+func hasValue(values map[string]interface{}, path string) bool {
+	if _, ok := values[path]; ok {
+		return true
+	}
+	for k := range values {
+		if strings.HasPrefix(k, path+".") || strings.HasPrefix(k, path+"[") {
+			return true
+		}
+	}
+	return false
+}
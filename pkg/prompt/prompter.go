@@ -0,0 +1,137 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// Prompter asks the questions of interactive runs. Each question has a key: the
+// path of the field it asks for (e.g., spec.replicas, or spec.args[0] for the
+// items of a list), or a name for questions that aren't about a field (e.g.,
+// resource-type, or spec.source#variant for which variant of a union to set).
+type Prompter interface {
+	// Select asks to choose one of the items of sel, returning its index and item
+	Select(key string, sel promptui.Select) (int, string, error)
+
+	// Prompt asks the question of p, returning the answer. Confirmations (p.IsConfirm)
+	// return promptui.ErrAbort when declined.
+	Prompt(key string, p promptui.Prompt) (string, error)
+}
+
+// prompter asks the questions; set with SetPrompter or EnableSimple
+var prompter Prompter = terminalPrompter{}
+
+// SetPrompter makes p ask the questions instead of the terminal, e.g. an
+// AnswerPrompter for scripted runs. A nil p asks in the terminal again.
+func SetPrompter(p Prompter) {
+	if p == nil {
+		p = terminalPrompter{}
+	}
+	prompter = p
+}
+
+// ActivePrompter returns the Prompter asking the questions
+func ActivePrompter() Prompter {
+	return prompter
+}
+
+// Scripted reports whether questions are answered by a Prompter set with
+// SetPrompter rather than by the user. Answers replayed in a terminal, which
+// asks for what they're missing, aren't scripted.
+func Scripted() bool {
+	switch p := prompter.(type) {
+	case terminalPrompter, simplePrompter:
+		return false
+	case *AnswerPrompter:
+		return p.fallback == nil
+	}
+	return true
+}
+
+// CanPrompt reports whether questions can be answered: by the user in a
+// terminal, or by a Prompter set with SetPrompter
+func CanPrompt() bool {
+	switch prompter.(type) {
+	case terminalPrompter, simplePrompter:
+		return IsTerminal()
+	}
+	return true
+}
+
+// runSelect asks sel with the prompter, recording the item chosen
+func runSelect(key string, sel promptui.Select) (int, string, error) {
+	i, item, err := prompter.Select(key, sel)
+	if err == nil {
+		recordAnswer(key, item)
+	}
+	return i, item, err
+}
+
+// runPrompt asks p with the prompter, recording the answer
+func runPrompt(key string, p promptui.Prompt) (string, error) {
+	result, err := prompter.Prompt(key, p)
+	switch {
+	case p.IsConfirm && (err == nil || errors.Is(err, promptui.ErrAbort)):
+		recordAnswer(key, err == nil)
+	case err == nil:
+		recordAnswer(key, result)
+	}
+	return result, err
+}
+
+// terminalPrompter asks with promptui's selects and prompts
+type terminalPrompter struct{}
+
+// Select implements Prompter
+func (terminalPrompter) Select(_ string, sel promptui.Select) (int, string, error) {
+	return sel.Run()
+}
+
+// Prompt implements Prompter
+func (terminalPrompter) Prompt(_ string, p promptui.Prompt) (string, error) {
+	return p.Run()
+}
+
+// valueSource is a Prompter with whole values for fields that are otherwise
+// entered over several questions or picked from lists, such as the objects of
+// free-form fields and references to existing objects
+type valueSource interface {
+	value(key string) (interface{}, bool)
+}
+
+// retry shows why the answer to the question with key isn't valid so it is
+// asked again, or fails scripted runs, where the answer would be the same
+func retry(key string, err error) error {
+	if IsInterrupt(err) {
+		return err
+	}
+	if Scripted() {
+		return &answerError{i18n.T("invalid answer for %s: %v", key, err)}
+	}
+	// Replayed answers would be the same, so the question is asked instead
+	if a, ok := prompter.(*AnswerPrompter); ok {
+		a.reject(key)
+	}
+	fmt.Println(i18n.T("  %v, try again", err))
+	return nil
+}
+
+// IsInterrupt reports whether err ends the prompting rather than being a
+// failure: the user interrupted a prompt with Ctrl+C or Ctrl+D, or scripted
+// answers are missing or invalid
+func IsInterrupt(err error) bool {
+	return errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF)
+}
+
+// interrupted returns the error ending the run after IsInterrupt(err), keeping
+// the message of scripted answers that are missing or invalid
+func interrupted(err error) error {
+	var answerErr *answerError
+	if errors.As(err, &answerErr) {
+		return err
+	}
+	return i18n.Errorf("interrupted")
+}
@@ -0,0 +1,87 @@
+package prompt
+
+import (
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"sigs.k8s.io/yaml"
+)
+
+// recorded holds the answers given during the run, keyed like the answers of
+// an AnswerPrompter; nil unless RecordAnswers was called
+var recorded map[string]interface{}
+
+// RecordAnswers starts recording the answers to the questions asked, for
+// replaying them with an AnswerPrompter
+func RecordAnswers() {
+	recorded = make(map[string]interface{})
+}
+
+// recordAnswer records val as the answer to the question with key
+func recordAnswer(key string, val interface{}) {
+	if recorded != nil {
+		recorded[key] = val
+	}
+}
+
+// forgetAnswer drops the answer recorded for the question with key, when the
+// value it led to is recorded instead
+func forgetAnswer(key string) {
+	delete(recorded, key)
+}
+
+// RecordedAnswers returns the answers recorded so far. The answers to repeated
+// questions (spec.args[0], spec.args[1], ...) are a list under the key without
+// the index, without the empty answer that ended the list.
+func RecordedAnswers() map[string]interface{} {
+	answers := make(map[string]interface{})
+	lists := make(map[string]map[int]interface{})
+	for key, val := range recorded {
+		m := indexedKey.FindStringSubmatch(key)
+		if m == nil {
+			answers[key] = val
+			continue
+		}
+		i, _ := strconv.Atoi(m[2])
+		if lists[m[1]] == nil {
+			lists[m[1]] = make(map[int]interface{})
+		}
+		lists[m[1]][i] = val
+	}
+
+	for key, items := range lists {
+		indexes := make([]int, 0, len(items))
+		for i := range items {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		list := []interface{}{}
+		for _, i := range indexes {
+			if items[i] == "" {
+				break
+			}
+			list = append(list, items[i])
+		}
+		answers[key] = list
+	}
+	return answers
+}
+
+// WriteRecordedAnswers writes the recorded answers to a YAML file that
+// LoadAnswers reads. It reports false when no question was answered.
+func WriteRecordedAnswers(path string) (bool, error) {
+	answers := RecordedAnswers()
+	if len(answers) == 0 {
+		return false, nil
+	}
+	data, err := yaml.Marshal(answers)
+	if err != nil {
+		return false, i18n.Errorf("failed to record answers: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return false, i18n.Errorf("failed to record answers: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,223 @@
+package prompt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Items of the reference picker besides the listed objects
+const (
+	otherReference = "(enter another name)"
+	noReference    = "(none)"
+)
+
+var (
+	secrets                = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	configMaps             = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	services               = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	serviceAccounts        = schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	persistentVolumeClaims = schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}
+)
+
+// referenceFields maps field names that hold the name of another object to its type
+var referenceFields = map[string]schema.GroupVersionResource{
+	"secretname":         secrets,
+	"configmapname":      configMaps,
+	"serviceaccountname": serviceAccounts,
+	"claimname":          persistentVolumeClaims,
+	"storageclassname":   {Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"},
+	"priorityclassname":  {Group: "scheduling.k8s.io", Version: "v1", Resource: "priorityclasses"},
+	"ingressclassname":   {Group: "networking.k8s.io", Version: "v1", Resource: "ingressclasses"},
+	"runtimeclassname":   {Group: "node.k8s.io", Version: "v1", Resource: "runtimeclasses"},
+	"gatewayclassname":   {Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gatewayclasses"},
+	"servicename":        services,
+}
+
+// referenceParents maps the objects whose "name" field references another
+// object (e.g., envFrom[].secretRef.name) to its type
+var referenceParents = map[string]schema.GroupVersionResource{
+	"secretref":       secrets,
+	"secretkeyref":    secrets,
+	"configmap":       configMaps,
+	"configmapref":    configMaps,
+	"configmapkeyref": configMaps,
+	"service":         services,
+}
+
+// referenceSuffixes map the endings of the names of custom resource fields to
+// the type they reference, for fields such as tlsSecretName, or the name of
+// objects such as credentialsSecretRef (checked against the parent's name)
+var referenceSuffixes = []struct {
+	suffix string
+	parent bool
+	gvr    schema.GroupVersionResource
+}{
+	{suffix: "secretname", gvr: secrets},
+	{suffix: "configmapname", gvr: configMaps},
+	{suffix: "secretref", parent: true, gvr: secrets},
+	{suffix: "secretkeyref", parent: true, gvr: secrets},
+	{suffix: "configmapref", parent: true, gvr: configMaps},
+	{suffix: "configmapkeyref", parent: true, gvr: configMaps},
+}
+
+// volumeSourceTypes are the types referenced by the volume sources of the container builder
+var volumeSourceTypes = map[string]schema.GroupVersionResource{
+	"configMap":             configMaps,
+	"secret":                secrets,
+	"persistentVolumeClaim": persistentVolumeClaims,
+}
+
+var (
+	// listReferences lists the names of the objects of a type in the target namespace
+	listReferences func(gvr schema.GroupVersionResource) ([]string, error)
+
+	// referenceNames caches the listed names per type for the run
+	referenceNames = map[schema.GroupVersionResource][]string{}
+)
+
+// SetReferenceLister enables pickers for fields referencing other objects,
+// populated by list. Without a lister references are entered as free text.
+func SetReferenceLister(list func(gvr schema.GroupVersionResource) ([]string, error)) {
+	listReferences = list
+	referenceNames = map[schema.GroupVersionResource][]string{}
+}
+
+// referenceType returns the type of object a field path references, if any
+func referenceType(path string) (schema.GroupVersionResource, bool) {
+	segments := strings.Split(path, ".")
+	for i := range segments {
+		segments[i] = strings.ToLower(strings.SplitN(segments[i], "[", 2)[0])
+	}
+	name := segments[len(segments)-1]
+	if gvr, ok := referenceFields[name]; ok {
+		return gvr, true
+	}
+	if name == "name" && len(segments) > 1 {
+		if gvr, ok := referenceParents[segments[len(segments)-2]]; ok {
+			return gvr, true
+		}
+	}
+	for _, s := range referenceSuffixes {
+		switch {
+		case !s.parent && strings.HasSuffix(name, s.suffix):
+			return s.gvr, true
+		case s.parent && name == "name" && len(segments) > 1 && strings.HasSuffix(segments[len(segments)-2], s.suffix):
+			return s.gvr, true
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// ObjectReference is a field of a manifest naming another object
+type ObjectReference struct {
+	Path string                      // Path of the field (e.g., spec.tls[0].secretName)
+	Type schema.GroupVersionResource // Type of the object
+	Name string                      // Name of the object
+}
+
+// FindReferences returns the fields of a manifest that name other objects,
+// such as Secrets and ConfigMaps, sorted by path. Metadata and status are
+// left out.
+func FindReferences(obj map[string]interface{}) []ObjectReference {
+	var refs []ObjectReference
+	for key, value := range obj {
+		if key != "metadata" && key != "status" {
+			refs = appendReferences(refs, key, value)
+		}
+	}
+	slices.SortFunc(refs, func(a, b ObjectReference) int { return strings.Compare(a.Path, b.Path) })
+	return refs
+}
+
+// appendReferences appends the references found in value, at path, to refs
+func appendReferences(refs []ObjectReference, path string, value interface{}) []ObjectReference {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			refs = appendReferences(refs, path+"."+key, child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			refs = appendReferences(refs, fmt.Sprintf("%s[%d]", path, i), child)
+		}
+	case string:
+		if gvr, ok := referenceType(path); ok && v != "" {
+			refs = append(refs, ObjectReference{Path: path, Type: gvr, Name: v})
+		}
+	}
+	return refs
+}
+
+// referenceCandidates lists the objects of gvr, or returns nil when they can't
+// be listed (e.g., offline or forbidden) or there are none
+func referenceCandidates(gvr schema.GroupVersionResource) []string {
+	if listReferences == nil {
+		return nil
+	}
+	if names, ok := referenceNames[gvr]; ok {
+		return names
+	}
+	names, err := listReferences(gvr)
+	if err != nil {
+		names = nil
+	}
+	slices.Sort(names)
+	referenceNames[gvr] = names
+	return names
+}
+
+// promptReference lets the user pick one of the existing objects of gvr, or
+// enter another name. ok is false when there is nothing to pick from, and for
+// scripted answers, which name the object whether it exists or not.
+func promptReference(key, label string, gvr schema.GroupVersionResource, defaultVal interface{}, required bool) (value string, ok bool, err error) {
+	if source, ok := prompter.(valueSource); ok {
+		if _, answered := source.value(key); answered || Scripted() {
+			return "", false, nil
+		}
+	}
+	names := referenceCandidates(gvr)
+	if len(names) == 0 {
+		return "", false, nil
+	}
+
+	items := append([]string{}, names...)
+	if !required {
+		items = append(items, i18n.T(noReference))
+	}
+	items = append(items, i18n.T(otherReference))
+
+	cursor := 0
+	if defaultVal != nil {
+		if i := slices.Index(names, fmt.Sprintf("%v", defaultVal)); i >= 0 {
+			cursor = i
+		}
+	}
+
+	prompt := promptui.Select{
+		Label:             fmt.Sprintf("%s (%s)", label, gvr.Resource),
+		Items:             items,
+		Size:              10,
+		CursorPos:         cursor,
+		Searcher:          containsSearcher(items),
+		StartInSearchMode: len(items) > 10,
+	}
+	_, result, err := runSelect(key, prompt)
+	if err != nil {
+		return "", true, err
+	}
+
+	switch result {
+	case i18n.T(noReference):
+		recordAnswer(key, "")
+		return "", true, nil
+	case i18n.T(otherReference):
+		value, err := promptString(key, label, defaultVal, required, nil)
+		return value, true, err
+	}
+	return result, true, nil
+}
@@ -0,0 +1,164 @@
+package prompt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// reviewValueWidth is how much of a value the review shows
+const reviewValueWidth = 60
+
+// ReviewValues prints the collected values, numbered, with where each came
+// from, and asks for the number of one to change until the user continues, so
+// typos can be fixed without answering all the questions again. An answer of
+// /keyword searches all the fields of the schema for one to set instead.
+func ReviewValues(schema *client.ResourceSchema, values *CollectedValues) error {
+	for round := 0; ; round++ {
+		paths := make([]string, 0, len(values.Values))
+		for path := range values.Values {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		printReview(paths, values)
+
+		prompt := promptui.Prompt{
+			Label: i18n.T("Number of a value to change, /keyword to search the fields (Enter to continue)"),
+			Validate: func(input string) error {
+				if input == "" {
+					return nil
+				}
+				if keyword, ok := strings.CutPrefix(input, searchPrefix); ok {
+					if strings.TrimSpace(keyword) == "" {
+						return i18n.Errorf("type a word to search for after %s", searchPrefix)
+					}
+					return nil
+				}
+				if n, err := strconv.Atoi(input); err != nil || n < 1 || n > len(paths) {
+					return i18n.Errorf("must be a number from 1 to %d", len(paths))
+				}
+				return nil
+			},
+		}
+		key := fmt.Sprintf("review[%d]", round)
+		result, err := runPrompt(key, prompt)
+		// Replays review the values again
+		forgetAnswer(key)
+		if err != nil {
+			if IsInterrupt(err) {
+				return interrupted(err)
+			}
+			return nil
+		}
+		if result == "" {
+			return nil
+		}
+		if keyword, ok := strings.CutPrefix(result, searchPrefix); ok {
+			if err := searchAndChange(schema, values, keyword, round); err != nil {
+				return err
+			}
+			continue
+		}
+		n, _ := strconv.Atoi(result)
+		if err := changeValue(schema, values, paths[n-1]); err != nil {
+			return err
+		}
+	}
+}
+
+// printReview prints the values at paths as a numbered table
+func printReview(paths []string, values *CollectedValues) {
+	fmt.Println("\n" + i18n.T("Collected values:"))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  #\tPATH\tVALUE\tSOURCE")
+	for i, path := range paths {
+		source := values.Sources[path]
+		if source == "" {
+			source = SourceFlag
+		}
+		fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", i+1, path, reviewValue(values.Values[path]), i18n.T(string(source)))
+	}
+	w.Flush()
+}
+
+// reviewValue returns val on one line, shortened to reviewValueWidth
+func reviewValue(val interface{}) string {
+	var s string
+	switch v := val.(type) {
+	case string:
+		s = strconv.Quote(v)
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		} else {
+			s = string(data)
+		}
+	default:
+		s = fmt.Sprint(v)
+	}
+	if len(s) > reviewValueWidth {
+		s = s[:reviewValueWidth-3] + "..."
+	}
+	return s
+}
+
+// changeValue asks for a new value of the field at path
+func changeValue(schema *client.ResourceSchema, values *CollectedValues, path string) error {
+	current := values.Values[path]
+
+	if path == "metadata.name" {
+		rule := NameSubdomain
+		if schema != nil {
+			rule = NameRuleFor(schema.GVK.Group, schema.GVK.Kind)
+		}
+		name, err := promptForName(rule, values.Name)
+		if err != nil {
+			if IsInterrupt(err) {
+				return interrupted(err)
+			}
+			return nil
+		}
+		if name, err = resolveName(name); err != nil {
+			return err
+		}
+		values.Name = name
+		values.setAnswer(path, name)
+		return nil
+	}
+
+	if _, ok := current.(map[string]interface{}); ok {
+		fmt.Println(i18n.T("  %s is an object, set its fields with --set=%s.<field>=value", path, path))
+		return nil
+	}
+
+	field := client.FieldSchema{Type: inferType(current)}
+	if schema != nil {
+		if found, ok := schema.FindField(path); ok {
+			field = *found
+		}
+	}
+	field.Path, field.Name = path, path
+
+	val, err := promptForField(path, field, current, nil)
+	if err != nil {
+		if IsInterrupt(err) {
+			return interrupted(err)
+		}
+		return nil
+	}
+	// Enter keeps the value
+	if list, ok := val.([]interface{}); val == nil || val == "" || ok && len(list) == 0 {
+		return nil
+	}
+	values.setAnswer(path, val)
+	return nil
+}
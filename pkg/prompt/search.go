@@ -0,0 +1,98 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// searchPrefix starts a review answer that searches the fields of the schema
+const searchPrefix = "/"
+
+// searchDescriptionWidth is how much of a description search results show
+const searchDescriptionWidth = 70
+
+// PrintSearchResults prints fields found by a search as a numbered table of
+// their paths, types and the first sentence of their descriptions
+func PrintSearchResults(fields []client.FieldSchema) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  #\tPATH\tTYPE\tDESCRIPTION")
+	for i, f := range fields {
+		fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", i+1, f.Path, f.Type, firstSentence(f.Description))
+	}
+	w.Flush()
+}
+
+// firstSentence returns the first sentence of desc on one line, shortened to
+// searchDescriptionWidth
+func firstSentence(desc string) string {
+	desc = strings.Join(strings.Fields(desc), " ")
+	if i := strings.Index(desc, ". "); i >= 0 {
+		desc = desc[:i+1]
+	}
+	if len(desc) > searchDescriptionWidth {
+		desc = desc[:searchDescriptionWidth-3] + "..."
+	}
+	return desc
+}
+
+// searchAndChange searches the fields of schema for keyword and asks which of
+// the ones found to set, so fields that aren't prompted for, deep in a large
+// CRD, can be found and filled in during the review
+func searchAndChange(schema *client.ResourceSchema, values *CollectedValues, keyword string, round int) error {
+	if schema == nil {
+		fmt.Println(i18n.T("  The schema isn't available to search"))
+		return nil
+	}
+	found := schema.SearchFields(keyword)
+	if len(found) == 0 {
+		fmt.Println(i18n.T("  No fields match %q", keyword))
+		return nil
+	}
+
+	items := make([]string, 0, len(found)+1)
+	items = append(items, i18n.T("(none)"))
+	for _, f := range found {
+		item := f.Path
+		if desc := firstSentence(f.Description); desc != "" {
+			item += " - " + desc
+		}
+		items = append(items, item)
+	}
+	sel := promptui.Select{
+		Label:    i18n.T("Fields matching %q (pick one to set)", keyword),
+		Items:    items,
+		Size:     15,
+		Searcher: containsSearcher(items),
+	}
+	key := fmt.Sprintf("search[%d]", round)
+	i, _, err := runSelect(key, sel)
+	forgetAnswer(key)
+	if err != nil {
+		if IsInterrupt(err) {
+			return interrupted(err)
+		}
+		return nil
+	}
+	if i == 0 {
+		return nil
+	}
+
+	field := found[i-1]
+	switch {
+	case strings.Contains(field.Path, "[*]"):
+		// Fields of list items are set on an item
+		path := strings.ReplaceAll(field.Path, "[*]", "[0]")
+		fmt.Println(i18n.T("  %s is inside a list, set it with --set=%s=value", field.Path, path))
+		return nil
+	case field.Type == "object":
+		fmt.Println(i18n.T("  %s is an object, set its fields with --set=%s.<field>=value", field.Path, field.Path))
+		return nil
+	}
+	return changeValue(schema, values, field.Path)
+}
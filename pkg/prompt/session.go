@@ -0,0 +1,76 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
+)
+
+// SessionAction is what to do after a resource was created in an interactive session
+type SessionAction int
+
+const (
+	// SessionQuit ends the session
+	SessionQuit SessionAction = iota
+	// SessionSameType creates another resource of the same type
+	SessionSameType
+	// SessionOtherType picks a different resource type to create
+	SessionOtherType
+)
+
+// IsTerminal reports whether stdin and stdout are terminals, so the user can
+// answer prompts. Otherwise, e.g. in pipelines and CI, nothing is prompted for.
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// PromptNextAction asks whether to create another resource after resourceType
+// was created. Interrupting the prompt quits.
+func PromptNextAction(resourceType string) SessionAction {
+	fmt.Println()
+	prompt := promptui.Select{
+		Label: i18n.T("What next"),
+		Items: []string{
+			i18n.T("Create another %s", resourceType),
+			i18n.T("Create a different type"),
+			i18n.T("Quit"),
+		},
+	}
+	index, _, err := runSelect("next-action", prompt)
+	if err != nil {
+		return SessionQuit
+	}
+
+	switch index {
+	case 0:
+		return SessionSameType
+	case 1:
+		return SessionOtherType
+	default:
+		return SessionQuit
+	}
+}
+
+// Confirm asks a yes/no question, defaulting to no. Interrupting the prompt
+// answers no. key identifies the question in scripted answers (see Prompter).
+func Confirm(key, label string) bool {
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+	}
+	_, err := runPrompt(key, prompt)
+	return err == nil
+}
+
+// ConfirmTyped asks the user to type expected to proceed, like the guards of
+// destructive actions. Anything else, or interrupting the prompt, answers no.
+func ConfirmTyped(key, label, expected string) bool {
+	prompt := promptui.Prompt{
+		Label: label,
+	}
+	result, err := runPrompt(key, prompt)
+	return err == nil && result == expected
+}
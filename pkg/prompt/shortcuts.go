@@ -0,0 +1,148 @@
+package prompt
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+)
+
+// WorkloadShortcuts holds kubectl create deployment-style flags that map to the
+// first container of any resource with a pod template
+type WorkloadShortcuts struct {
+	Image   string   // --image
+	Ports   []string // --port
+	Env     []string // --env NAME=value
+	Command string   // --command (space separated)
+}
+
+// IsEmpty reports whether no shortcut flags were given
+func (w WorkloadShortcuts) IsEmpty() bool {
+	return w.Image == "" && len(w.Ports) == 0 && len(w.Env) == 0 && w.Command == ""
+}
+
+// knownPodTemplates is used when the schema could not be fetched
+var knownPodTemplates = map[string]PodTemplate{
+	"Deployment":  {Path: "spec.template", SelectorPath: "spec.selector"},
+	"StatefulSet": {Path: "spec.template", SelectorPath: "spec.selector"},
+	"DaemonSet":   {Path: "spec.template", SelectorPath: "spec.selector"},
+	"ReplicaSet":  {Path: "spec.template", SelectorPath: "spec.selector"},
+	"Job":         {Path: "spec.template", RestartOnFailure: true},
+	"CronJob":     {Path: "spec.jobTemplate.spec.template", RestartOnFailure: true},
+}
+
+// Values maps the shortcuts to field paths for the resource described by schema.
+// Pod labels and a required selector are wired to match.
+func (w WorkloadShortcuts) Values(schema *client.ResourceSchema, name string) (map[string]interface{}, error) {
+	if w.IsEmpty() {
+		return nil, nil
+	}
+	if w.Image == "" {
+		return nil, i18n.Errorf("--image is required when using --port, --env or --command")
+	}
+
+	podSpec, template, err := findPodSpec(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	containerName := imageBaseName(w.Image)
+	container := podSpec + ".containers[0]"
+	values := map[string]interface{}{
+		container + ".name":  containerName,
+		container + ".image": w.Image,
+	}
+
+	for i, p := range w.Ports {
+		port, ok := parseValue(p).(int64)
+		if !ok {
+			return nil, i18n.Errorf("invalid --port %q: must be integer", p)
+		}
+		values[fmt.Sprintf("%s.ports[%d].containerPort", container, i)] = port
+	}
+
+	for i, e := range w.Env {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, i18n.Errorf("invalid --env %q (expected NAME=value)", e)
+		}
+		values[fmt.Sprintf("%s.env[%d].name", container, i)] = parts[0]
+		values[fmt.Sprintf("%s.env[%d].value", container, i)] = parts[1]
+	}
+
+	for i, part := range strings.Fields(w.Command) {
+		values[fmt.Sprintf("%s.command[%d]", container, i)] = part
+	}
+
+	if template != nil {
+		labelValue := name
+		if labelValue == "" {
+			labelValue = containerName
+		}
+		values[template.Path+".metadata.labels.app"] = labelValue
+		if template.SelectorPath != "" {
+			values[template.SelectorPath+".matchLabels.app"] = labelValue
+		}
+		if template.RestartOnFailure {
+			values[template.Path+".spec.restartPolicy"] = "OnFailure"
+		}
+	}
+
+	return values, nil
+}
+
+// findPodSpec returns the path of the PodSpec in a resource, and its template if
+// the PodSpec is nested in one (Pods have the PodSpec at "spec" directly)
+func findPodSpec(schema *client.ResourceSchema) (string, *PodTemplate, error) {
+	if schema == nil {
+		return "", nil, i18n.Errorf("--image requires the resource schema")
+	}
+	podSpec, template, ok := PodSpecOf(schema)
+	if !ok {
+		return "", nil, i18n.Errorf("--image requires a resource with containers, %s has no pod template", schema.GVK.Kind)
+	}
+	return podSpec, template, nil
+}
+
+// PodSpecOf returns the path of the PodSpec in the resource described by
+// schema, and its template if the PodSpec is nested in one. It reports false
+// when the resource runs no pods.
+func PodSpecOf(schema *client.ResourceSchema) (string, *PodTemplate, bool) {
+	inJob := isJobKind(schema.GVK.Kind)
+	if templates := findPodTemplates(schema.Fields, inJob); len(templates) > 0 {
+		t := templates[0]
+		return t.Path + ".spec", &t, true
+	}
+
+	for _, field := range schema.Fields {
+		if field.Path != "spec" {
+			continue
+		}
+		for _, prop := range field.Properties {
+			if prop.Name == "containers" && prop.Type == "array" {
+				return "spec", nil, true
+			}
+		}
+	}
+
+	if strings.EqualFold(schema.GVK.Kind, "Pod") {
+		return "spec", nil, true
+	}
+	for kind, t := range knownPodTemplates {
+		if strings.EqualFold(kind, schema.GVK.Kind) {
+			return t.Path + ".spec", &t, true
+		}
+	}
+	return "", nil, false
+}
+
+// imageBaseName derives a container name from an image reference (e.g., "ghcr.io/org/app:1.0" -> "app")
+func imageBaseName(image string) string {
+	base := path.Base(image)
+	if i := strings.IndexAny(base, ":@"); i >= 0 {
+		base = base[:i]
+	}
+	return strings.ToLower(base)
+}
@@ -0,0 +1,141 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// stdin reads the answers of simple prompts
+var stdin = bufio.NewReader(os.Stdin)
+
+// EnableSimple turns on simple prompts: numbered choices and plain text
+// questions answered with a line of input, instead of select widgets and
+// prompts that move the cursor and redraw, which screen readers can't follow
+func EnableSimple() {
+	prompter = simplePrompter{}
+}
+
+// simplePrompter asks questions as plain lines of text
+type simplePrompter struct{}
+
+// Select asks for the choice of sel by number or name
+func (simplePrompter) Select(_ string, sel promptui.Select) (int, string, error) {
+	items, ok := sel.Items.([]string)
+	if !ok {
+		return sel.Run()
+	}
+	label := fmt.Sprint(sel.Label)
+	shown := allItems(len(items))
+	for {
+		fmt.Printf("%s:\n", label)
+		for _, i := range shown {
+			fmt.Printf("  %d) %s\n", i+1, items[i])
+		}
+		question := i18n.T("Enter a number or name")
+		if sel.CursorPos >= 0 && sel.CursorPos < len(items) {
+			question += fmt.Sprintf(" [%s]", items[sel.CursorPos])
+		}
+		input, err := readLine(question)
+		if err != nil {
+			return -1, "", err
+		}
+
+		if input == "" {
+			if sel.CursorPos >= 0 && sel.CursorPos < len(items) {
+				return sel.CursorPos, items[sel.CursorPos], nil
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(input); err == nil {
+			if n >= 1 && n <= len(items) {
+				return n - 1, items[n-1], nil
+			}
+			fmt.Println(i18n.T("  %d is not one of the choices, try again", n))
+			continue
+		}
+
+		// A name picks its item, otherwise the choices containing the input
+		// are listed, like searching in a select
+		var matches []int
+		for i, item := range items {
+			if strings.EqualFold(item, input) {
+				return i, item, nil
+			}
+			if strings.Contains(strings.ToLower(item), strings.ToLower(input)) {
+				matches = append(matches, i)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			fmt.Println(i18n.T("  No choice matches %q, try again", input))
+			shown = allItems(len(items))
+		case 1:
+			return matches[0], items[matches[0]], nil
+		default:
+			shown = matches
+		}
+	}
+}
+
+// Prompt asks p as a line of text, repeating the question until the answer is valid
+func (simplePrompter) Prompt(_ string, p promptui.Prompt) (string, error) {
+	label := fmt.Sprint(p.Label)
+	if p.IsConfirm {
+		input, err := readLine(label + " [y/N]")
+		if err != nil {
+			return "", err
+		}
+		if answer := strings.ToLower(input); answer == "y" || answer == "yes" {
+			return input, nil
+		}
+		return "", promptui.ErrAbort
+	}
+
+	if p.Default != "" {
+		label += fmt.Sprintf(" [%s]", p.Default)
+	}
+	for {
+		input, err := readLine(label)
+		if err != nil {
+			return "", err
+		}
+		if input == "" {
+			input = p.Default
+		}
+		if p.Validate != nil {
+			if err := p.Validate(input); err != nil {
+				fmt.Println(i18n.T("  %v, try again", err))
+				continue
+			}
+		}
+		return input, nil
+	}
+}
+
+// readLine prints question and reads a line of input. End of input
+// interrupts the prompt, as Ctrl+D does.
+func readLine(question string) (string, error) {
+	fmt.Printf("%s: ", question)
+	line, err := stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		fmt.Println()
+		return "", promptui.ErrInterrupt
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// allItems returns the indexes of n items
+func allItems(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
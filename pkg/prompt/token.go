@@ -0,0 +1,35 @@
+package prompt
+
+import (
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// Ways of getting a token for a service account
+const (
+	TokenNone    = ""
+	TokenSecret  = "secret"  // A long-lived token in a kubernetes.io/service-account-token Secret
+	TokenRequest = "request" // A short-lived token issued by a TokenRequest
+)
+
+// PickServiceAccountToken asks whether to also get a token for the service
+// account being created, returning TokenNone, TokenSecret or TokenRequest
+func PickServiceAccountToken() (string, error) {
+	kinds := []string{TokenNone, TokenSecret, TokenRequest}
+	sel := promptui.Select{
+		Label: i18n.T("Also get a token for the service account?"),
+		Items: []string{
+			i18n.T("No token"),
+			i18n.T("Long-lived token (a token Secret)"),
+			i18n.T("Short-lived token (a TokenRequest)"),
+		},
+	}
+	i, _, err := runSelect("token", sel)
+	if err != nil {
+		if IsInterrupt(err) {
+			return "", interrupted(err)
+		}
+		return "", err
+	}
+	return kinds[i], nil
+}
@@ -0,0 +1,128 @@
+package prompt
+
+import (
+	"math"
+	"os"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"sigs.k8s.io/yaml"
+)
+
+// ParseValuesFile reads a YAML or JSON values file into field paths, like
+// --set. Keys may be paths (spec.replicas: 3) or nested objects (spec: {replicas: 3});
+// lists are set as a whole. With expandEnv, ${VAR} in strings is replaced by
+// the environment variable VAR.
+func ParseValuesFile(path string, expandEnv bool) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("failed to read values file: %w", err)
+	}
+	var content map[string]interface{}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, i18n.Errorf("invalid values file %s: %w", path, err)
+	}
+
+	values := make(map[string]interface{})
+	if err := flattenValues(content, "", expandEnv, values); err != nil {
+		return nil, i18n.Errorf("invalid values file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flattenValues adds the leaves of m under prefix to values
+func flattenValues(m map[string]interface{}, prefix string, expandEnv bool, values map[string]interface{}) error {
+	for key, val := range m {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := val.(map[string]interface{}); ok && len(nested) > 0 {
+			if err := flattenValues(nested, path, expandEnv, values); err != nil {
+				return err
+			}
+			continue
+		}
+		normalized, err := normalizeValue(val, expandEnv)
+		if err != nil {
+			return i18n.Errorf("%s: %w", path, err)
+		}
+		values[path] = normalized
+	}
+	return nil
+}
+
+// normalizeValue turns whole numbers into integers, as --set does, and expands
+// environment variables in strings
+func normalizeValue(val interface{}, expandEnv bool) (interface{}, error) {
+	switch v := val.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v), nil
+		}
+		return v, nil
+	case string:
+		if !expandEnv {
+			return v, nil
+		}
+		return ExpandEnv(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized, err := normalizeValue(item, expandEnv)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = normalized
+		}
+		return items, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized, err := normalizeValue(item, expandEnv)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = normalized
+		}
+		return m, nil
+	}
+	return val, nil
+}
+
+// ExpandEnv replaces ${VAR} in s with the environment variable VAR; $${VAR}
+// stays a literal ${VAR}. Other uses of $ are left alone, and unset variables
+// are an error rather than silently empty.
+func ExpandEnv(s string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if start > 0 && s[start-1] == '$' {
+			b.WriteString(s[:start-1])
+			b.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return "", i18n.Errorf("unterminated ${ in %q", s)
+		}
+		name := s[start+2 : start+end]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", i18n.Errorf("environment variable %s is not set", name)
+		}
+		b.WriteString(s[:start])
+		b.WriteString(value)
+		s = s[start+end+1:]
+	}
+}
+
+// HasEnvReference reports whether s refers to an environment variable with ${VAR}
+func HasEnvReference(s string) bool {
+	return strings.Contains(s, "${")
+}
@@ -0,0 +1,168 @@
+package prompt
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// hasStructuredVariants reports whether field is a union with an object or
+// array variant, which needs the variant to be chosen before prompting
+func hasStructuredVariants(field client.FieldSchema) bool {
+	for _, v := range field.Variants {
+		if v.Type == "object" || v.Type == "array" || len(v.Properties) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// variantTypes describes the types a union field accepts (e.g., "integer or string")
+func variantTypes(field client.FieldSchema) string {
+	var types []string
+	for _, v := range field.Variants {
+		if v.Type != "" && !slices.Contains(types, v.Type) {
+			types = append(types, v.Type)
+		}
+	}
+	return strings.Join(types, i18n.T(" or "))
+}
+
+// promptScalarVariant prompts for a union of scalars such as IntOrString with a
+// single question, and converts the answer to the first variant it is valid for
+func promptScalarVariant(key, label string, field client.FieldSchema, defaultVal interface{}) (interface{}, error) {
+	if types := variantTypes(field); types != "" {
+		label += " (" + types + ")"
+	}
+	result, err := promptString(key, label, defaultVal, field.Required, func(s string) error {
+		return checkConstraints(field, parseVariantValue(s, field))
+	})
+	if err != nil || result == "" {
+		return result, err
+	}
+	return parseVariantValue(result, field), nil
+}
+
+// parseVariantValue converts s to the first variant of field that accepts it,
+// trying integers and numbers before strings: "8080" is a port number and
+// "http" a port name. Fields with the int-or-string format behave the same.
+func parseVariantValue(s string, field client.FieldSchema) interface{} {
+	variants := field.Variants
+	if field.Format == "int-or-string" && len(variants) == 0 {
+		variants = []client.FieldSchema{{Type: "integer"}, {Type: "string"}}
+	}
+	for _, v := range variants {
+		switch v.Type {
+		case "integer":
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return n
+			}
+		case "number":
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b
+			}
+		case "string":
+			return s
+		}
+	}
+	return s
+}
+
+// promptForVariants asks which variant of a union field to fill in, then
+// prompts for the fields of that variant and for the fields of the union that
+// belong to no variant
+func promptForVariants(field client.FieldSchema, values *CollectedValues, flagValues map[string]interface{}) error {
+	var items []string
+	skip := -1
+	if !field.Required {
+		skip = 0
+		items = append(items, i18n.T("(none)"))
+	}
+	for _, v := range field.Variants {
+		items = append(items, v.Name)
+	}
+
+	if field.Description != "" {
+		printDescription(values.outline.indent(), field.Description)
+	}
+	sel := promptui.Select{
+		Label: values.outline.indent() + i18n.T("%s: which one to set?", field.Path),
+		Items: items,
+	}
+	index, _, err := runSelect(field.Path+"#variant", sel)
+	if err != nil {
+		if IsInterrupt(err) {
+			return interrupted(err)
+		}
+		return nil
+	}
+	if index == skip {
+		return nil
+	}
+	if skip == 0 {
+		index--
+	}
+	variant := field.Variants[index]
+	variant.Path = field.Path
+	variant.Required = true
+
+	switch {
+	case variant.Type == "object" && len(variant.Properties) > 0:
+		values.outline.total += countPrompts(variant.Properties, flagValues)
+		if err := promptForFields(variant.Properties, values, flagValues); err != nil {
+			return err
+		}
+	case variant.Type == "object":
+		fmt.Println(i18n.T("Note: %s is required but is a complex type. Use --set=%s.key=value", field.Path, field.Path))
+		return nil
+	default:
+		values.outline.total++
+		o := values.outline
+		val, err := promptForFieldAt(field.Path, variant, o.indent(), o.next(field.Path), nil, values.last[field.Path])
+		if err != nil {
+			if IsInterrupt(err) {
+				return interrupted(err)
+			}
+			return nil
+		}
+		if val != nil && val != "" {
+			values.setAnswer(field.Path, val)
+		}
+		return nil
+	}
+
+	// The union's own fields that no variant is about
+	return promptForFields(variantlessFields(field), values, flagValues)
+}
+
+// variantlessFields returns the fields of a union that no variant is about
+func variantlessFields(field client.FieldSchema) []client.FieldSchema {
+	var rest []client.FieldSchema
+	for _, p := range field.Properties {
+		if !inAnyVariant(field.Variants, p.Name) {
+			rest = append(rest, p)
+		}
+	}
+	return rest
+}
+
+// inAnyVariant reports whether a variant has a property called name
+func inAnyVariant(variants []client.FieldSchema, name string) bool {
+	for _, v := range variants {
+		for _, p := range v.Properties {
+			if p.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,507 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	"github.com/gshaibi/kubectl-create-resource/pkg/generator"
+	"github.com/gshaibi/kubectl-create-resource/pkg/prompt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Path is the HTTP endpoint JSON-RPC requests are posted to
+const Path = "/rpc"
+
+// maxRequestBytes bounds the size of a request body
+const maxRequestBytes = 16 << 20
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeServerError    = -32000
+)
+
+// Server exposes discovery, schemas, manifest generation and creation of one
+// cluster as JSON-RPC 2.0 methods, so editors and GUIs can drive the flow
+// while this package owns the cluster interaction
+type Server struct {
+	client  *client.K8sClient
+	methods map[string]func(json.RawMessage) (interface{}, error)
+
+	// Prepare, if set, completes generated manifests (e.g., with the labels the
+	// config requires) before they are returned or created
+	Prepare func(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+
+	// Authorize, if set, is checked before creating (not dry-running) in a namespace
+	Authorize func(namespace string) error
+
+	// Audit, if set, is called after every create and dry-run create
+	Audit func(gvr schema.GroupVersionResource, namespace, name string, dryRun bool, err error)
+
+	// Token, if set, must be sent as "Authorization: Bearer <token>" with every
+	// request. Other local users and processes can reach a TCP port, unlike a
+	// unix socket readable only by the user.
+	Token string
+
+	// The client caches discovery and objects, so requests are served one at a time
+	mu sync.Mutex
+}
+
+// New creates a server backed by k8sClient
+func New(k8sClient *client.K8sClient) *Server {
+	s := &Server{client: k8sClient}
+	s.methods = map[string]func(json.RawMessage) (interface{}, error){
+		"ListResourceTypes": s.listResourceTypes,
+		"GetSchema":         s.getSchema,
+		"GenerateManifest":  s.generateManifest,
+		"CreateResource":    s.createResource,
+	}
+	return s
+}
+
+// Methods returns the names of the served methods
+func (s *Server) Methods() []string {
+	names := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Listen opens a local listener: unix:///path for a socket (readable only by the
+// user), or host:port on a loopback address
+func Listen(address string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(address, "unix://"); ok {
+		if path == "" {
+			return nil, fmt.Errorf("invalid listen address %q: missing socket path", address)
+		}
+		// A socket left behind by a previous run would make Listen fail
+		if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(path)
+		}
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+		}
+		if err := os.Chmod(path, 0o600); err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("failed to restrict %s: %w", path, err)
+		}
+		return listener, nil
+	}
+
+	host, _, err := net.SplitHostPort(strings.TrimPrefix(address, "tcp://"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen address %q (use unix:///path or localhost:port): %w", address, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("refusing to listen on %s: the API creates resources with your credentials, use a loopback address or a unix socket", host)
+	}
+	listener, err := net.Listen("tcp", strings.TrimPrefix(address, "tcp://"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	return listener, nil
+}
+
+// NewToken returns a random bearer token for Server.Token
+func NewToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Serve handles requests on listener until it fails or is closed
+func (s *Server) Serve(listener net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, s)
+	err := http.Serve(listener, mux)
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// paramsError marks errors in the request parameters
+type paramsError struct{ err error }
+
+func (e paramsError) Error() string { return e.err.Error() }
+
+// ServeHTTP handles a single JSON-RPC 2.0 request posted to Path
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "JSON-RPC requests must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if status, reason := s.checkRequest(r); status != http.StatusOK {
+		http.Error(w, reason, status)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+	if err != nil {
+		writeResponse(w, response{Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+		return
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeResponse(w, response{Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		writeResponse(w, response{ID: req.ID, Error: &rpcError{Code: codeInvalidRequest, Message: `expected "jsonrpc": "2.0" and a method`}})
+		return
+	}
+
+	method, ok := s.methods[req.Method]
+	if !ok {
+		writeResponse(w, response{ID: req.ID, Error: &rpcError{
+			Code:    codeMethodNotFound,
+			Message: fmt.Sprintf("unknown method %q", req.Method),
+			Data:    s.Methods(),
+		}})
+		return
+	}
+
+	s.mu.Lock()
+	result, err := method(req.Params)
+	s.mu.Unlock()
+
+	if err != nil {
+		writeResponse(w, response{ID: req.ID, Error: toRPCError(err)})
+		return
+	}
+	writeResponse(w, response{ID: req.ID, Result: result})
+}
+
+// checkRequest rejects requests web pages can make from the user's browser:
+// those with an Origin, a Content-Type a form or a text/plain fetch can send
+// without a CORS preflight, or a Host other than a loopback one (DNS
+// rebinding), and those without the token
+func (s *Server) checkRequest(r *http.Request) (int, string) {
+	if r.Header.Get("Origin") != "" {
+		return http.StatusForbidden, "cross-origin requests are not allowed"
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return http.StatusUnsupportedMediaType, "the Content-Type must be application/json"
+	}
+	if !loopbackHost(r.Host) {
+		return http.StatusForbidden, fmt.Sprintf("host %q is not a loopback address", r.Host)
+	}
+	if s.Token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+			return http.StatusUnauthorized, "missing or invalid bearer token"
+		}
+	}
+	return http.StatusOK, ""
+}
+
+// loopbackHost reports whether the Host header of a request names localhost or
+// a loopback address, with or without a port
+func loopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// toRPCError converts a method error, passing on the status of API errors
+func toRPCError(err error) *rpcError {
+	var pe paramsError
+	if errors.As(err, &pe) {
+		return &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	rpcErr := &rpcError{Code: codeServerError, Message: err.Error()}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		rpcErr.Data = status.Status()
+	}
+	return rpcErr
+}
+
+func writeResponse(w http.ResponseWriter, resp response) {
+	resp.JSONRPC = "2.0"
+	if resp.ID == nil {
+		resp.ID = json.RawMessage("null")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// decodeParams unmarshals the request parameters into params
+func decodeParams(raw json.RawMessage, params interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, params); err != nil {
+		return paramsError{fmt.Errorf("invalid params: %w", err)}
+	}
+	return nil
+}
+
+// ResourceType describes a create-capable resource type
+type ResourceType struct {
+	Name       string   `json:"name"`
+	Group      string   `json:"group,omitempty"`
+	Version    string   `json:"version"`
+	Kind       string   `json:"kind"`
+	Namespaced bool     `json:"namespaced"`
+	ShortNames []string `json:"shortNames,omitempty"`
+}
+
+func (s *Server) listResourceTypes(raw json.RawMessage) (interface{}, error) {
+	var params struct {
+		Group *string `json:"group"` // Only list types of this group ("" for the core group)
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	// Pick up the types installed since the previous request
+	s.client.Refresh()
+	resources, err := s.client.DiscoverResources()
+	if err != nil {
+		return nil, err
+	}
+	types := make([]ResourceType, 0, len(resources))
+	for _, r := range resources {
+		if params.Group != nil && r.Group != *params.Group {
+			continue
+		}
+		types = append(types, ResourceType{
+			Name:       r.Name,
+			Group:      r.Group,
+			Version:    r.Version,
+			Kind:       r.Kind,
+			Namespaced: r.Namespaced,
+			ShortNames: r.ShortNames,
+		})
+	}
+	return types, nil
+}
+
+// resolve resolves a resource type name like the command line does
+func (s *Server) resolve(resourceType string) (schema.GroupVersionResource, error) {
+	if resourceType == "" {
+		return schema.GroupVersionResource{}, paramsError{fmt.Errorf("type is required")}
+	}
+	gvr, err := s.client.ResolveResourceType(resourceType)
+	if err != nil {
+		// The type may have been installed since discovery was cached
+		s.client.Refresh()
+		gvr, err = s.client.ResolveResourceType(resourceType)
+	}
+	if err != nil {
+		return gvr, paramsError{err}
+	}
+	return gvr, nil
+}
+
+func (s *Server) getSchema(raw json.RawMessage) (interface{}, error) {
+	var params struct {
+		Type string `json:"type"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	gvr, err := s.resolve(params.Type)
+	if err != nil {
+		return nil, err
+	}
+	resourceSchema, err := s.client.GetResourceSchema(gvr)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewSchemaDocument(gvr, resourceSchema, s.client.IsNamespaced(gvr)), nil
+}
+
+// generateParams are the parameters of GenerateManifest
+type generateParams struct {
+	Type      string                 `json:"type"`
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace,omitempty"` // Default: "default" for namespaced types
+	Values    map[string]interface{} `json:"values,omitempty"`    // Field values by path (e.g., "spec.replicas": 3)
+}
+
+func (s *Server) generateManifest(raw json.RawMessage) (interface{}, error) {
+	var params generateParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	manifest, _, _, err := s.generate(params)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Object, nil
+}
+
+// generate builds the manifest for params, returning its resource type and namespace
+func (s *Server) generate(params generateParams) (*unstructured.Unstructured, schema.GroupVersionResource, string, error) {
+	gvr, err := s.resolve(params.Type)
+	if err != nil {
+		return nil, gvr, "", err
+	}
+	if params.Name == "" {
+		return nil, gvr, "", paramsError{fmt.Errorf("name is required")}
+	}
+	if err := prompt.ValidateName(params.Name, prompt.NameRuleFor(gvr.Group, gvr.Resource)); err != nil {
+		return nil, gvr, "", paramsError{err}
+	}
+
+	namespace := ""
+	if s.client.IsNamespaced(gvr) {
+		namespace = params.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+	}
+
+	values := &prompt.CollectedValues{Name: params.Name, Values: map[string]interface{}{}}
+	for path, v := range params.Values {
+		values.Values[path] = v
+	}
+	values.Values["metadata.name"] = params.Name
+
+	manifest, err := generator.GenerateManifest(gvr, namespace, values)
+	if err != nil {
+		return nil, gvr, "", err
+	}
+	// Prefer the kind from the schema over the one guessed from the resource name
+	if resourceSchema, err := s.client.GetResourceSchema(gvr); err == nil && resourceSchema.GVK.Kind != "" {
+		manifest.SetKind(resourceSchema.GVK.Kind)
+	}
+	if s.Prepare != nil {
+		if err := s.Prepare(gvr, manifest); err != nil {
+			return nil, gvr, "", paramsError{err}
+		}
+	}
+	return manifest, gvr, namespace, nil
+}
+
+func (s *Server) createResource(raw json.RawMessage) (interface{}, error) {
+	var params struct {
+		generateParams
+		Manifest map[string]interface{} `json:"manifest,omitempty"` // A complete manifest instead of type/name/values
+		DryRun   bool                   `json:"dryRun,omitempty"`   // Only submit with server-side dry-run
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	var obj *unstructured.Unstructured
+	var gvr schema.GroupVersionResource
+	var namespace string
+	var err error
+	if params.Manifest != nil {
+		obj = &unstructured.Unstructured{Object: params.Manifest}
+		gvr, err = s.resolveManifest(obj)
+		if err != nil {
+			return nil, err
+		}
+		if s.client.IsNamespaced(gvr) {
+			namespace = obj.GetNamespace()
+			if namespace == "" {
+				namespace = "default"
+			}
+		}
+	} else {
+		obj, gvr, namespace, err = s.generate(params.generateParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var created *unstructured.Unstructured
+	if params.DryRun {
+		created, err = s.client.DryRunCreateResource(gvr, namespace, obj)
+	} else {
+		if s.Authorize != nil {
+			if err := s.Authorize(namespace); err != nil {
+				return nil, err
+			}
+		}
+		created, err = s.client.CreateResource(gvr, namespace, obj)
+	}
+	if s.Audit != nil {
+		s.Audit(gvr, namespace, obj.GetName(), params.DryRun, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return created.Object, nil
+}
+
+// resolveManifest finds the resource type of a manifest from its apiVersion and kind
+func (s *Server) resolveManifest(obj *unstructured.Unstructured) (schema.GroupVersionResource, error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return schema.GroupVersionResource{}, paramsError{fmt.Errorf("manifest needs apiVersion and kind")}
+	}
+	// The type may have been installed since discovery was cached, so a miss
+	// refreshes it and looks again
+	for attempt := 0; attempt < 2; attempt++ {
+		if attempt > 0 {
+			s.client.Refresh()
+		}
+		resources, err := s.client.DiscoverResources()
+		if err != nil {
+			return schema.GroupVersionResource{}, err
+		}
+		for _, r := range resources {
+			if r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind {
+				return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Name}, nil
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, paramsError{fmt.Errorf("no create-capable resource type for %s", gvk)}
+}
@@ -0,0 +1,168 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/config"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// httpSource reads a value from a JSON HTTP endpoint
+type httpSource struct {
+	spec    config.HTTPSource
+	fetcher *httpFetcher
+}
+
+// Describe returns the endpoint URL
+func (s *httpSource) Describe() string {
+	return s.spec.URL
+}
+
+// Value fetches the endpoint and extracts the configured JSONPath
+func (s *httpSource) Value() (interface{}, error) {
+	body, err := s.fetcher.fetch(s.spec)
+	if err != nil {
+		return nil, err
+	}
+	val, err := extractJSONPath(body, s.spec.JSONPath)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeJSONValue(val), nil
+}
+
+// httpFetcher performs requests and caches decoded bodies per URL and headers
+type httpFetcher struct {
+	client *http.Client
+	cache  map[string]interface{}
+	mu     sync.Mutex
+}
+
+// newHTTPFetcher creates a new fetcher with an empty cache
+func newHTTPFetcher() *httpFetcher {
+	return &httpFetcher{
+		client: &http.Client{},
+		cache:  make(map[string]interface{}),
+	}
+}
+
+// fetch returns the decoded JSON body for spec, using the cache when possible
+func (f *httpFetcher) fetch(spec config.HTTPSource) (interface{}, error) {
+	headers := make(map[string]string, len(spec.Headers))
+	for k, v := range spec.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+
+	key := cacheKey(spec.URL, headers)
+	f.mu.Lock()
+	if body, ok := f.cache[key]; ok {
+		f.mu.Unlock()
+		return body, nil
+	}
+	f.mu.Unlock()
+
+	timeout := defaultHTTPTimeout
+	if spec.Timeout != "" {
+		d, err := time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %w", spec.Timeout, err)
+		}
+		timeout = d
+	}
+
+	req, err := http.NewRequest(http.MethodGet, spec.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := *f.client
+	client.Timeout = timeout
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	f.mu.Lock()
+	f.cache[key] = body
+	f.mu.Unlock()
+	return body, nil
+}
+
+// cacheKey builds a cache key from a URL and its (expanded) headers
+func cacheKey(url string, headers map[string]string) string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(url)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s: %s", k, headers[k])
+	}
+	return b.String()
+}
+
+// extractJSONPath evaluates a kubectl-style JSONPath expression against body
+func extractJSONPath(body interface{}, expr string) (interface{}, error) {
+	if expr == "" {
+		return body, nil
+	}
+	// Accept bare paths like ".version" as well as "{.version}"
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+
+	jp := jsonpath.New("value")
+	if err := jp.Parse(expr); err != nil {
+		return nil, fmt.Errorf("invalid jsonPath %q: %w", expr, err)
+	}
+
+	results, err := jp.FindResults(body)
+	if err != nil {
+		return nil, fmt.Errorf("jsonPath %q: %w", expr, err)
+	}
+	if len(results) == 0 || len(results[0]) == 0 {
+		return nil, fmt.Errorf("jsonPath %q matched nothing", expr)
+	}
+	return results[0][0].Interface(), nil
+}
+
+// normalizeJSONValue converts whole-number floats from JSON into int64,
+// matching how --set values are parsed
+func normalizeJSONValue(val interface{}) interface{} {
+	if f, ok := val.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return val
+}
@@ -0,0 +1,54 @@
+package sources
+
+import (
+	"fmt"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/config"
+)
+
+// Source produces a value for a field path at generation time
+type Source interface {
+	// Describe returns a short human-readable description of the source
+	Describe() string
+	// Value fetches the value
+	Value() (interface{}, error)
+}
+
+// Resolver builds sources from config and caches their responses for the run
+type Resolver struct {
+	http *httpFetcher
+}
+
+// NewResolver creates a new Resolver
+func NewResolver() *Resolver {
+	return &Resolver{
+		http: newHTTPFetcher(),
+	}
+}
+
+// SourceFor returns the Source for a configured value source
+func (r *Resolver) SourceFor(vs config.ValueSource) (Source, error) {
+	switch {
+	case vs.HTTP != nil:
+		return &httpSource{spec: *vs.HTTP, fetcher: r.http}, nil
+	default:
+		return nil, fmt.Errorf("value source for %s has no provider configured", vs.Path)
+	}
+}
+
+// Resolve evaluates all value sources and returns their values keyed by field path
+func (r *Resolver) Resolve(valueSources []config.ValueSource) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for _, vs := range valueSources {
+		src, err := r.SourceFor(vs)
+		if err != nil {
+			return nil, err
+		}
+		val, err := src.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s from %s: %w", vs.Path, src.Describe(), err)
+		}
+		result[vs.Path] = val
+	}
+	return result, nil
+}
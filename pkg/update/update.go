@@ -0,0 +1,245 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// ReleasesURL is the GitHub API endpoint of the latest release
+const ReleasesURL = "https://api.github.com/repos/gshaibi/kubectl-create-resource/releases/latest"
+
+const (
+	requestTimeout  = 10 * time.Second
+	downloadTimeout = 5 * time.Minute
+	binaryName      = "kubectl-create-resource"
+	checksumsAsset  = "checksums.txt"
+)
+
+// Release is a published release
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Latest fetches the latest release. $GITHUB_TOKEN is sent when set, to avoid
+// the rate limit of anonymous requests.
+func Latest() (*Release, error) {
+	req, err := http.NewRequest(http.MethodGet, ReleasesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := (&http.Client{Timeout: requestTimeout}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for releases: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to check for releases: %s returned %s", ReleasesURL, resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &release, nil
+}
+
+// IsNewer reports whether latest is a newer version than current. Development
+// builds (not a semantic version) are never considered outdated.
+func IsNewer(current, latest string) (bool, error) {
+	cur, err := utilversion.ParseSemantic(current)
+	if err != nil {
+		return false, fmt.Errorf("%s is not a release version", current)
+	}
+	lat, err := utilversion.ParseSemantic(latest)
+	if err != nil {
+		return false, fmt.Errorf("invalid release version %q: %w", latest, err)
+	}
+	return cur.LessThan(lat), nil
+}
+
+// InstalledWithKrew reports whether exePath is managed by krew, which must do
+// the upgrade itself to keep track of the installed version
+func InstalledWithKrew(exePath string) bool {
+	return strings.Contains(filepath.ToSlash(exePath), "/.krew/")
+}
+
+// AssetName returns the archive name of the release for this platform, as
+// published by the release workflow
+func AssetName() string {
+	ext := ".tar.gz"
+	if runtime.GOOS == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("%s-%s-%s%s", binaryName, runtime.GOOS, runtime.GOARCH, ext)
+}
+
+// asset returns the release asset called name
+func (r *Release) asset(name string) (*Asset, error) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release %s has no %s", r.TagName, name)
+}
+
+// Install downloads the archive of release for this platform, verifies it
+// against the release checksums and replaces the executable at exePath
+func Install(release *Release, exePath string) error {
+	name := AssetName()
+	archiveAsset, err := release.asset(name)
+	if err != nil {
+		return err
+	}
+	sumsAsset, err := release.asset(checksumsAsset)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := download(sumsAsset.URL)
+	if err != nil {
+		return err
+	}
+	want, err := checksumFor(checksums, name)
+	if err != nil {
+		return err
+	}
+
+	archive, err := download(archiveAsset.URL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, want, got)
+	}
+
+	binary, err := extractBinary(name, archive)
+	if err != nil {
+		return err
+	}
+	return replaceExecutable(exePath, binary)
+}
+
+// download fetches url into memory
+func download(url string) ([]byte, error) {
+	resp, err := (&http.Client{Timeout: downloadTimeout}).Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// checksumFor finds the checksum of name in a sha256sum listing
+func checksumFor(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s in %s", name, checksumsAsset)
+}
+
+// extractBinary returns the plugin binary from a release archive
+func extractBinary(name string, archive []byte) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		for _, f := range r.File {
+			if f.Name != binaryName+".exe" {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s does not contain %s.exe", name, binaryName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if filepath.Base(hdr.Name) == binaryName && hdr.Typeflag == tar.TypeReg {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s does not contain %s", name, binaryName)
+}
+
+// replaceExecutable writes binary next to exePath and renames it into place, so
+// a failed write leaves the current executable intact. A running executable
+// can't be overwritten on Windows, so it is moved aside first.
+func replaceExecutable(exePath string, binary []byte) error {
+	tmp := exePath + ".new"
+	if err := os.WriteFile(tmp, binary, 0o755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := exePath + ".old"
+		os.Remove(old)
+		if err := os.Rename(exePath, old); err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("failed to replace %s: %w", exePath, err)
+		}
+	}
+	if err := os.Rename(tmp, exePath); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", exePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Info describes the running build
+type Info struct {
+	Version           string `json:"version"`
+	GitCommit         string `json:"gitCommit,omitempty"`
+	BuildDate         string `json:"buildDate,omitempty"`
+	ClientGoVersion   string `json:"clientGoVersion,omitempty"`   // Version of k8s.io/client-go the binary was built with
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"` // Kubernetes minor version matching client-go
+	GoVersion         string `json:"goVersion"`
+	Platform          string `json:"platform"`
+}
+
+var (
+	version = "dev"
+	commit  string
+	date    string
+)
+
+// Set records the build information injected with -ldflags. Empty values keep
+// the defaults, which are completed from the Go build info.
+func Set(v, c, d string) {
+	if v != "" {
+		version = v
+	}
+	commit = c
+	date = d
+}
+
+// Get returns the information about the running build
+func Get() Info {
+	info := Info{
+		Version:   version,
+		GitCommit: commit,
+		BuildDate: date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	// go install records the module version and VCS details itself
+	if info.Version == "dev" && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+		info.Version = buildInfo.Main.Version
+	}
+	for _, s := range buildInfo.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.GitCommit == "" {
+				info.GitCommit = s.Value
+			}
+		case "vcs.time":
+			if info.BuildDate == "" {
+				info.BuildDate = s.Value
+			}
+		}
+	}
+	for _, dep := range buildInfo.Deps {
+		if dep.Path == "k8s.io/client-go" {
+			info.ClientGoVersion = dep.Version
+			info.KubernetesVersion = kubernetesVersion(dep.Version)
+		}
+	}
+	return info
+}
+
+// String returns the version with the short commit, e.g. "v0.2.0 (3f4b185)"
+func (i Info) String() string {
+	if i.GitCommit == "" {
+		return i.Version
+	}
+	c := i.GitCommit
+	if len(c) > 7 {
+		c = c[:7]
+	}
+	return fmt.Sprintf("%s (%s)", i.Version, c)
+}
+
+// kubernetesVersion maps a client-go version (v0.35.0) to the Kubernetes minor
+// version it was released with (1.35)
+func kubernetesVersion(clientGo string) string {
+	parts := strings.Split(strings.TrimPrefix(clientGo, "v"), ".")
+	if len(parts) < 2 || parts[0] != "0" {
+		return ""
+	}
+	return "1." + parts[1]
+}
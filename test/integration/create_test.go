@@ -0,0 +1,794 @@
+//go:build integration
+
+// Package integration runs kubectl-create-resource against a real API server
+// started by envtest. The API server and etcd binaries are located through
+// KUBEBUILDER_ASSETS (see make test-integration); the tests are skipped when
+// it isn't set.
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gshaibi/kubectl-create-resource/pkg/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+var (
+	binary     string            // The built plugin
+	kubeconfig string            // Admin kubeconfig for the envtest API server
+	dynClient  dynamic.Interface // Checks what the plugin created
+)
+
+var (
+	configMaps = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	namespaces = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	widgets    = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		fmt.Println("KUBEBUILDER_ASSETS is not set, skipping integration tests")
+		os.Exit(0)
+	}
+	os.Exit(run(m))
+}
+
+// run starts the API server, builds the plugin and runs the tests
+func run(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "kcr-integration")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{"testdata"},
+		ErrorIfCRDPathMissing: true,
+	}
+	config, err := env.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start envtest: %v\n", err)
+		return 1
+	}
+	defer env.Stop()
+
+	admin, err := env.AddUser(envtest.User{Name: "admin", Groups: []string{"system:masters"}}, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to add user: %v\n", err)
+		return 1
+	}
+	data, err := admin.KubeConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write kubeconfig: %v\n", err)
+		return 1
+	}
+	kubeconfig = filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(kubeconfig, data, 0o600); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if dynClient, err = dynamic.NewForConfig(config); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	binary = filepath.Join(dir, "kubectl-create-resource")
+	build := exec.Command("go", "build", "-o", binary, "../../cmd/kubectl-create-resource")
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build the plugin: %v\n", err)
+		return 1
+	}
+
+	// History and other state go to a throwaway home
+	os.Setenv("HOME", dir)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, ".config"))
+
+	return m.Run()
+}
+
+// runPlugin runs the plugin non-interactively with the admin kubeconfig
+func runPlugin(t *testing.T, kubeconfigPath string, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(binary, append([]string{"--kubeconfig", kubeconfigPath, "--no-color"}, args...)...)
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// createNamespace creates a namespace for a test
+func createNamespace(t *testing.T, name string) {
+	t.Helper()
+	if out, err := runPlugin(t, kubeconfig, "namespace", name); err != nil {
+		t.Fatalf("creating namespace %s: %v\n%s", name, err, out)
+	}
+}
+
+func TestCreateConfigMap(t *testing.T) {
+	createNamespace(t, "configmaps")
+
+	out, err := runPlugin(t, kubeconfig, "cm", "settings", "-n", "configmaps", "--from-literal=mode=fast")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	obj, err := dynClient.Resource(configMaps).Namespace("configmaps").Get(context.Background(), "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := obj.Object["data"].(map[string]interface{})["mode"]; mode != "fast" {
+		t.Errorf("data.mode = %v, want fast", mode)
+	}
+	if session := obj.GetLabels()["create-resource.kubectl.io/session"]; session == "" || !strings.Contains(out, session) {
+		t.Errorf("session label = %q, want the session printed\n%s", session, out)
+	}
+}
+
+func TestNameConflict(t *testing.T) {
+	createNamespace(t, "conflicts")
+
+	args := []string{"cm", "settings", "-n", "conflicts", "--from-literal=a=b"}
+	if out, err := runPlugin(t, kubeconfig, args...); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	// Without a terminal there's no prompting for another name
+	if out, err := runPlugin(t, kubeconfig, args...); err == nil || !strings.Contains(out, "already exists") {
+		t.Errorf("creating the name again = %v, want an error that it exists\n%s", err, out)
+	}
+
+	for _, want := range []string{"settings-2", "settings-3"} {
+		out, err := runPlugin(t, kubeconfig, append(args, "--on-name-conflict=suffix")...)
+		if err != nil {
+			t.Fatalf("create failed: %v\n%s", err, out)
+		}
+		if _, err := dynClient.Resource(configMaps).Namespace("conflicts").Get(context.Background(), want, metav1.GetOptions{}); err != nil {
+			t.Errorf("%s not created: %v", want, err)
+		}
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	createNamespace(t, "cleanup")
+
+	for _, args := range [][]string{
+		{"cm", "demo-a", "--set", "metadata.labels.app=demo"},
+		{"cm", "demo-b", "--set", "metadata.labels.app=demo"},
+		{"cm", "kept", "--set", "metadata.labels.app=other"},
+	} {
+		if out, err := runPlugin(t, kubeconfig, append(args, "-n", "cleanup", "--from-literal=a=b")...); err != nil {
+			t.Fatalf("create failed: %v\n%s", err, out)
+		}
+	}
+
+	// Without a terminal there's no confirming
+	if out, err := runPlugin(t, kubeconfig, "cleanup", "-l", "app=demo", "-n", "cleanup"); err == nil {
+		t.Errorf("cleanup without --yes succeeded, want an error\n%s", out)
+	}
+
+	out, err := runPlugin(t, kubeconfig, "cleanup", "-l", "app=demo", "-n", "cleanup", "--yes")
+	if err != nil {
+		t.Fatalf("cleanup failed: %v\n%s", err, out)
+	}
+	list, err := dynClient.Resource(configMaps).Namespace("cleanup").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, obj := range list.Items {
+		if obj.GetName() != "kube-root-ca.crt" {
+			left = append(left, obj.GetName())
+		}
+	}
+	if len(left) != 1 || left[0] != "kept" {
+		t.Errorf("config maps left = %v, want [kept]", left)
+	}
+}
+
+func TestCreateRBAC(t *testing.T) {
+	createNamespace(t, "rbac")
+
+	file := filepath.Join(t.TempDir(), "rbac.yaml")
+	for _, name := range []string{"first", "second"} {
+		out, err := runPlugin(t, kubeconfig, "cm", name, "-n", "rbac", "--from-literal=a=b",
+			"--with-rbac", file, "--create-rbac", "--rbac-service-account=ci:bot")
+		if err != nil {
+			t.Fatalf("create failed: %v\n%s", err, out)
+		}
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("--with-rbac file not written: %v", err)
+	}
+
+	roles := schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}
+	role, err := dynClient.Resource(roles).Namespace("rbac").Get(context.Background(), "configmaps-editor", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, _, _ := unstructured.NestedSlice(role.Object, "rules")
+	if len(rules) != 1 {
+		t.Errorf("rules = %v, want one rule", rules)
+	}
+
+	bindings := schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}
+	binding, err := dynClient.Resource(bindings).Namespace("rbac").Get(context.Background(), "configmaps-editor-bot", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subjects, _, _ := unstructured.NestedSlice(binding.Object, "subjects")
+	if len(subjects) != 1 || subjects[0].(map[string]interface{})["namespace"] != "ci" {
+		t.Errorf("subjects = %v, want the service account ci/bot", subjects)
+	}
+}
+
+func TestCreateNamespacePreset(t *testing.T) {
+	out, err := runPlugin(t, kubeconfig, "namespace", "team-a", "--pod-security=baseline",
+		"--quota=pods=10", "--default-limit=memory=256Mi", "--network-policy=deny-ingress")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	namespaces := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	ns, err := dynClient.Resource(namespaces).Get(context.Background(), "team-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level := ns.GetLabels()["pod-security.kubernetes.io/enforce"]; level != "baseline" {
+		t.Errorf("enforce label = %q, want baseline", level)
+	}
+
+	for _, gvr := range []schema.GroupVersionResource{
+		{Version: "v1", Resource: "resourcequotas"},
+		{Version: "v1", Resource: "limitranges"},
+	} {
+		if _, err := dynClient.Resource(gvr).Namespace("team-a").Get(context.Background(), "default", metav1.GetOptions{}); err != nil {
+			t.Errorf("%s/default not created: %v", gvr.Resource, err)
+		}
+	}
+	policies := schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+	if _, err := dynClient.Resource(policies).Namespace("team-a").Get(context.Background(), "default-deny-ingress", metav1.GetOptions{}); err != nil {
+		t.Errorf("networkpolicies/default-deny-ingress not created: %v", err)
+	}
+}
+
+func TestCreateIngress(t *testing.T) {
+	createNamespace(t, "ingress")
+
+	out, err := runPlugin(t, kubeconfig, "ingress", "shop", "-n", "ingress",
+		"--rule=shop.example.com/api*=api:8080,tls=shop-tls", "--rule=shop.example.com/=web:http")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	ingresses := schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+	ingress, err := dynClient.Resource(ingresses).Namespace("ingress").Get(context.Background(), "shop", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "rules")
+	if len(rules) != 1 {
+		t.Fatalf("rules = %v, want the paths of shop.example.com in one rule", rules)
+	}
+	paths, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "http", "paths")
+	if len(paths) != 2 || paths[0].(map[string]interface{})["pathType"] != "Prefix" {
+		t.Errorf("paths = %v, want the Prefix path /api and the Exact path /", paths)
+	}
+	tls, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "tls")
+	if len(tls) != 1 || tls[0].(map[string]interface{})["secretName"] != "shop-tls" {
+		t.Errorf("tls = %v, want shop.example.com with shop-tls", tls)
+	}
+}
+
+func TestCreateNetworkPolicy(t *testing.T) {
+	createNamespace(t, "netpol")
+
+	answers := filepath.Join(t.TempDir(), "answers.yaml")
+	data := `spec.podSelector: app=api
+spec.policyTypes: Ingress
+spec.ingress: [Pods of other namespaces]
+spec.ingress[0].from[0].namespaceSelector: team=web
+spec.ingress[0].ports: 8080, 9000-9100
+`
+	if err := os.WriteFile(answers, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runPlugin(t, kubeconfig, "networkpolicy", "api", "-n", "netpol", "--answers="+answers)
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "from all pods in namespaces with team=web on 8080/TCP, 9000-9100/TCP") {
+		t.Errorf("output = %s, want the allowed ingress summarized", out)
+	}
+
+	policies := schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+	policy, err := dynClient.Resource(policies).Namespace("netpol").Get(context.Background(), "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, _, _ := unstructured.NestedString(policy.Object, "spec", "podSelector", "matchLabels", "app")
+	ingress, _, _ := unstructured.NestedSlice(policy.Object, "spec", "ingress")
+	if app != "api" || len(ingress) != 1 {
+		t.Errorf("spec = %v, want ingress to app=api from namespaces with team=web", policy.Object["spec"])
+	}
+}
+
+func TestCreateHorizontalPodAutoscaler(t *testing.T) {
+	createNamespace(t, "autoscaling")
+
+	out, err := runPlugin(t, kubeconfig, "hpa", "web", "-n", "autoscaling",
+		"--scale-target=deploy/web", "--min=2", "--max=10", "--cpu=75%", "--memory=512Mi")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	autoscalers := schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}
+	hpa, err := dynClient.Resource(autoscalers).Namespace("autoscaling").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kind, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "kind")
+	apiVersion, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "apiVersion")
+	if kind != "Deployment" || apiVersion != "apps/v1" {
+		t.Errorf("scaleTargetRef = %s %s, want apps/v1 Deployment", apiVersion, kind)
+	}
+	metrics, _, _ := unstructured.NestedSlice(hpa.Object, "spec", "metrics")
+	if len(metrics) != 2 {
+		t.Errorf("metrics = %v, want the cpu utilization and the memory average value", metrics)
+	}
+
+	// The metrics of the preset are those of autoscaling/v2
+	if out, err := runPlugin(t, kubeconfig, "horizontalpodautoscalers.v1.autoscaling", "old", "-n", "autoscaling", "--max=3"); err == nil {
+		t.Errorf("--max with autoscaling/v1 succeeded, want an error\n%s", out)
+	}
+}
+
+func TestCreateRoleRules(t *testing.T) {
+	createNamespace(t, "roles")
+
+	answers := filepath.Join(t.TempDir(), "answers.yaml")
+	data := `rules[0].apiGroups: core
+rules[0].resources: pods, pods/log
+rules[0].verbs: get, list
+rules[0].resourceNames: ""
+rules[1].apiGroups: apps
+rules[1].resources: deployments
+rules[1].verbs: get
+rules[1].resourceNames: web
+`
+	if err := os.WriteFile(answers, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runPlugin(t, kubeconfig, "role", "reader", "-n", "roles", "--answers="+answers)
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	roles := schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}
+	role, err := dynClient.Resource(roles).Namespace("roles").Get(context.Background(), "reader", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, _, _ := unstructured.NestedSlice(role.Object, "rules")
+	if len(rules) != 2 {
+		t.Fatalf("rules = %v, want the rules of pods and deployments", rules)
+	}
+	resources, _, _ := unstructured.NestedStringSlice(rules[0].(map[string]interface{}), "resources")
+	names, _, _ := unstructured.NestedStringSlice(rules[1].(map[string]interface{}), "resourceNames")
+	if strings.Join(resources, ",") != "pods,pods/log" || strings.Join(names, ",") != "web" {
+		t.Errorf("rules = %v, want pods and pods/log, then deployment web", rules)
+	}
+}
+
+func TestCreateJobFromCronJob(t *testing.T) {
+	createNamespace(t, "jobs")
+
+	cronJobs := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
+	cronJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "CronJob",
+		"metadata":   map[string]interface{}{"name": "nightly", "namespace": "jobs"},
+		"spec": map[string]interface{}{
+			"schedule": "0 2 * * *",
+			"jobTemplate": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"team": "data"}},
+				"spec": map[string]interface{}{
+					"backoffLimit": int64(2),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"restartPolicy": "OnFailure",
+							"containers":    []interface{}{map[string]interface{}{"name": "report", "image": "busybox"}},
+						},
+					},
+				},
+			},
+		},
+	}}
+	created, err := dynClient.Resource(cronJobs).Namespace("jobs").Create(context.Background(), cronJob, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runPlugin(t, kubeconfig, "job", "nightly-now", "-n", "jobs", "--from=cronjob/nightly")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	jobs := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	job, err := dynClient.Resource(jobs).Namespace("jobs").Get(context.Background(), "nightly-now", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers, _, _ := unstructured.NestedSlice(job.Object, "spec", "template", "spec", "containers")
+	backoffLimit, _, _ := unstructured.NestedInt64(job.Object, "spec", "backoffLimit")
+	if len(containers) != 1 || backoffLimit != 2 {
+		t.Errorf("spec = %v, want the jobTemplate of the CronJob", job.Object["spec"])
+	}
+	if job.GetAnnotations()["cronjob.kubernetes.io/instantiate"] != "manual" || job.GetLabels()["team"] != "data" {
+		t.Errorf("metadata = %v, want the labels of the jobTemplate and the instantiate annotation", job.Object["metadata"])
+	}
+	if owners := job.GetOwnerReferences(); len(owners) != 1 || owners[0].UID != created.GetUID() {
+		t.Errorf("ownerReferences = %v, want the CronJob", owners)
+	}
+}
+
+func TestCreateGatewayAndRoute(t *testing.T) {
+	createNamespace(t, "gateways")
+
+	out, err := runPlugin(t, kubeconfig, "gateway", "shop", "-n", "gateways", "--gateway-class=example",
+		"--listener=HTTP:80", "--listener=HTTPS:443,hostname=shop.example.com,tls=shop-tls")
+	if err != nil {
+		t.Fatalf("create gateway failed: %v\n%s", err, out)
+	}
+	gatewaysGVR := schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
+	gateway, err := dynClient.Resource(gatewaysGVR).Namespace("gateways").Get(context.Background(), "shop", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	if len(listeners) != 2 {
+		t.Fatalf("listeners = %v, want http and https", listeners)
+	}
+	https := listeners[1].(map[string]interface{})
+	mode, _, _ := unstructured.NestedString(https, "tls", "mode")
+	if https["name"] != "https" || https["port"] != int64(443) || mode != "Terminate" {
+		t.Errorf("listener = %v, want https on 443 terminating TLS", https)
+	}
+
+	out, err = runPlugin(t, kubeconfig, "httproute", "shop", "-n", "gateways", "--parent=shop:https",
+		"--hostname=shop.example.com", "--route-rule=/api*=api:8080@90,api-canary:8080@10", "--route-rule=/=web:80")
+	if err != nil {
+		t.Fatalf("create httproute failed: %v\n%s", err, out)
+	}
+	routes := schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}
+	route, err := dynClient.Resource(routes).Namespace("gateways").Get(context.Background(), "shop", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if len(parents) != 1 || parents[0].(map[string]interface{})["sectionName"] != "https" {
+		t.Errorf("parentRefs = %v, want the https listener of shop", parents)
+	}
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if len(rules) != 2 {
+		t.Fatalf("rules = %v, want /api and /", rules)
+	}
+	pathType, _, _ := unstructured.NestedString(rules[0].(map[string]interface{})["matches"].([]interface{})[0].(map[string]interface{}), "path", "type")
+	backends, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "backendRefs")
+	if pathType != "PathPrefix" || len(backends) != 2 || backends[1].(map[string]interface{})["weight"] != int64(10) {
+		t.Errorf("rule = %v, want the prefix /api split between api and api-canary", rules[0])
+	}
+
+	// --listener only applies to gateways
+	if out, err := runPlugin(t, kubeconfig, "httproute", "other", "-n", "gateways", "--parent=shop", "--route-rule=/=web:80", "--listener=HTTP:80"); err == nil {
+		t.Errorf("--listener with an httproute succeeded, want an error\n%s", out)
+	}
+}
+
+func TestCreateCustomResource(t *testing.T) {
+	createNamespace(t, "widgets")
+
+	// The short name of the CRD resolves to its Kind, and --set values are converted per the schema
+	out, err := runPlugin(t, kubeconfig, "wd", "small", "-n", "widgets", "--set=spec.size=2", "--set=spec.color=red")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	obj, err := dynClient.Resource(widgets).Namespace("widgets").Get(context.Background(), "small", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetKind() != "Widget" {
+		t.Errorf("kind = %s, want Widget", obj.GetKind())
+	}
+	spec := obj.Object["spec"].(map[string]interface{})
+	if spec["size"] != int64(2) || spec["color"] != "red" {
+		t.Errorf("spec = %v, want size 2 and color red", spec)
+	}
+}
+
+func TestCreateFromMergedTemplates(t *testing.T) {
+	createNamespace(t, "merged")
+
+	if out, err := runPlugin(t, kubeconfig, "widget", "base", "-n", "merged", "--set=spec.size=1", "--set=spec.color=red"); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+	overlay := filepath.Join(t.TempDir(), "overlay.yaml")
+	if err := os.WriteFile(overlay, []byte("spec:\n  color: blue\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runPlugin(t, kubeconfig, "widget", "layered", "-n", "merged", "--from=base", "--from="+overlay)
+	if err != nil {
+		t.Fatalf("create from templates failed: %v\n%s", err, out)
+	}
+	obj, err := dynClient.Resource(widgets).Namespace("merged").Get(context.Background(), "layered", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := obj.Object["spec"].(map[string]interface{})
+	if spec["size"] != int64(1) || spec["color"] != "blue" {
+		t.Errorf("spec = %v, want size 1 from the base and color blue from the overlay", spec)
+	}
+}
+
+func TestComputedFields(t *testing.T) {
+	createNamespace(t, "computed")
+
+	cfg := filepath.Join(t.TempDir(), "config.yaml")
+	data := `computed:
+  - resource: widgets.example.com
+    path: metadata.labels.app
+    from: metadata.name
+  - path: metadata.annotations.summary
+    value: "{{spec.color}} x{{spec.size}}"
+`
+	if err := os.WriteFile(cfg, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runPlugin(t, kubeconfig, "widget", "derived", "-n", "computed", "--config", cfg,
+		"--set=spec.size=2", "--set=spec.color=red")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	obj, err := dynClient.Resource(widgets).Namespace("computed").Get(context.Background(), "derived", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app := obj.GetLabels()["app"]; app != "derived" {
+		t.Errorf("app label = %q, want derived", app)
+	}
+	if summary := obj.GetAnnotations()["summary"]; summary != "red x2" {
+		t.Errorf("summary annotation = %q, want red x2", summary)
+	}
+}
+
+func TestSelectorLabelsMismatch(t *testing.T) {
+	createNamespace(t, "selectors")
+
+	args := []string{"deployment", "web", "-n", "selectors",
+		"--set=spec.selector.matchLabels.app=web", "--set=spec.template.metadata.labels.app=webb",
+		"--set=spec.template.spec.containers[0].name=web", "--set=spec.template.spec.containers[0].image=nginx"}
+
+	// Dry runs only warn
+	out, err := runPlugin(t, kubeconfig, append(args, "--dry-run")...)
+	if err != nil {
+		t.Fatalf("dry-run failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "app=web (template: webb)") {
+		t.Errorf("output = %s, want a warning about the mismatched label", out)
+	}
+
+	// Creates fail before the API server's error, with the flag that fixes them
+	out, err = runPlugin(t, kubeconfig, args...)
+	if err == nil {
+		t.Fatalf("create with a mismatched selector succeeded, want an error\n%s", out)
+	}
+	if !strings.Contains(out, "--set=spec.template.metadata.labels.app=web") {
+		t.Errorf("output = %s, want the --set flag that fixes the labels", out)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	createNamespace(t, "inspect")
+
+	out, err := runPlugin(t, kubeconfig, "widget", "shown", "-n", "inspect", "--set=spec.size=3",
+		"--set=spec.color=blue", "--set=metadata.labels.team=a")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	out, err = runPlugin(t, kubeconfig, "inspect", "widget", "shown", "-n", "inspect")
+	if err != nil {
+		t.Fatalf("inspect failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"metadata.labels.team=a\n", "spec.color=blue\n", "spec.size=3\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "resourceVersion") || strings.Contains(out, "uid=") {
+		t.Errorf("output has server-generated metadata:\n%s", out)
+	}
+}
+
+func TestDiffInstances(t *testing.T) {
+	createNamespace(t, "diff-instances")
+
+	for _, args := range [][]string{
+		{"widget", "first", "--set=spec.size=1", "--set=spec.color=red", "--set=metadata.labels.tier=a"},
+		{"widget", "second", "--set=spec.size=2", "--set=spec.color=red", "--set=metadata.labels.tier=b"},
+	} {
+		if out, err := runPlugin(t, kubeconfig, append(args, "-n", "diff-instances")...); err != nil {
+			t.Fatalf("create failed: %v\n%s", err, out)
+		}
+	}
+
+	out, err := runPlugin(t, kubeconfig, "diff-instances", "widget", "first", "second", "-n", "diff-instances")
+	if err != nil {
+		t.Fatalf("diff-instances failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "spec.size") || strings.Contains(out, "spec.color") || strings.Contains(out, "metadata.labels.tier") {
+		t.Errorf("output = %s, want only spec.size", out)
+	}
+
+	out, err = runPlugin(t, kubeconfig, "diff-instances", "widget", "first", "second", "-n", "diff-instances", "--all")
+	if err != nil {
+		t.Fatalf("diff-instances --all failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "metadata.labels.tier") {
+		t.Errorf("output = %s, want the labels with --all", out)
+	}
+}
+
+func TestMissingReferentHint(t *testing.T) {
+	createNamespace(t, "referents")
+
+	out, err := runPlugin(t, kubeconfig, "widget", "linked", "-n", "referents", "--dry-run",
+		"--set=spec.size=1", "--set=spec.credentialsSecretRef.name=widget-creds")
+	if err != nil {
+		t.Fatalf("dry-run failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "secrets/widget-creds, referenced by spec.credentialsSecretRef.name, doesn't exist") {
+		t.Errorf("output = %s, want a hint about the missing Secret", out)
+	}
+}
+
+func TestVerifyRefs(t *testing.T) {
+	createNamespace(t, "verify-refs")
+
+	out, err := runPlugin(t, kubeconfig, "ingress", "shop", "-n", "verify-refs", "--dry-run", "--verify-refs",
+		"--ingress-class=missing", "--rule=shop.example.com/=web:80")
+	if err != nil {
+		t.Fatalf("dry-run failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"spec.ingressClassName: ingressclasses/missing doesn't exist",
+		"spec.rules[0].http.paths[0].backend.service.name: services/web doesn't exist in namespace verify-refs",
+		"Verified 2 references, 2 missing",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't mention %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCreateCustomResourceChecksSchema(t *testing.T) {
+	createNamespace(t, "invalid-widgets")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing required field", []string{"widgets.example.com", "empty", "-n", "invalid-widgets"}, "spec.size"},
+		{"below minimum", []string{"widget", "tiny", "-n", "invalid-widgets", "--set=spec.size=0"}, "spec.size"},
+		{"not in enum", []string{"widget", "pink", "-n", "invalid-widgets", "--set=spec.size=1", "--set=spec.color=pink"}, "spec.color"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runPlugin(t, kubeconfig, tt.args...)
+			if err == nil {
+				t.Fatalf("create succeeded, want an error\n%s", out)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output doesn't mention %s:\n%s", tt.want, out)
+			}
+		})
+	}
+
+	list, err := dynClient.Resource(widgets).Namespace("invalid-widgets").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("%d widgets created, want none", len(list.Items))
+	}
+}
+
+func TestNamespaceFromContext(t *testing.T) {
+	createNamespace(t, "team-a")
+
+	// A kubeconfig whose context sets the namespace
+	config, err := clientcmd.LoadFromFile(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Contexts[config.CurrentContext].Namespace = "team-a"
+	teamConfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := clientcmd.WriteToFile(*config, teamConfig); err != nil {
+		t.Fatal(err)
+	}
+	if got := client.ContextNamespace(teamConfig, ""); got != "team-a" {
+		t.Fatalf("ContextNamespace() = %q, want team-a", got)
+	}
+
+	out, err := runPlugin(t, teamConfig, "configmap", "from-context", "--from-literal=a=b")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+	if _, err := dynClient.Resource(configMaps).Namespace("team-a").Get(context.Background(), "from-context", metav1.GetOptions{}); err != nil {
+		t.Errorf("config map not created in the context namespace: %v", err)
+	}
+}
+
+func TestClusterScoped(t *testing.T) {
+	// -n is ignored for cluster-scoped types
+	out, err := runPlugin(t, kubeconfig, "ns", "cluster-scoped", "-n", "default")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+	obj, err := dynClient.Resource(namespaces).Get(context.Background(), "cluster-scoped", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetNamespace() != "" {
+		t.Errorf("namespace = %q, want none", obj.GetNamespace())
+	}
+}
+
+func TestClientAgainstAPIServer(t *testing.T) {
+	c, err := client.NewK8sClient(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		resourceType string
+		want         schema.GroupVersionResource
+	}{
+		{"deploy", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+		{"cronjobs.v1.batch", schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}},
+		{"Widget", widgets},
+		{"widgets.example.com", widgets},
+	}
+	for _, tt := range tests {
+		got, err := c.ResolveResourceType(tt.resourceType)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveResourceType(%q) = %v, %v, want %v", tt.resourceType, got, err, tt.want)
+		}
+	}
+
+	resourceSchema, err := c.GetResourceSchema(widgets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, ok := resourceSchema.FindField("spec.size")
+	if !ok || size.Type != "integer" || !size.Required || size.Minimum == nil || *size.Minimum != 1 {
+		t.Errorf("spec.size = %+v, want a required integer of at least 1", size)
+	}
+}